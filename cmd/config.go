@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+const configCommandUsage = `Usage: sql config <command> [flags]
+
+Commands:
+  init [-force]      Write a commented default config file
+  validate           Check the config file and its profiles for errors
+  show [-effective]  Print the config file. With -effective, print the result of merging it
+                     with the selected profile, SQL_* environment variables and any other flags given
+
+All commands accept -config=<path> to use a config file other than the default
+`
+
+// Entrypoint for `sql config`
+func RunConfigCommand(arguments []string) (exitCode int) {
+	return runConfigCommand(arguments, os.Stdout, os.Stderr)
+}
+
+func runConfigCommand(arguments []string, stdout io.Writer, stderr io.Writer) (exitCode int) {
+	if len(arguments) == 0 {
+		fmt.Fprint(stderr, configCommandUsage)
+		return 2
+	}
+
+	flagSet := flag.NewFlagSet(fmt.Sprint("config ", arguments[0]), flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+
+	switch arguments[0] {
+	case "init":
+		{
+			configPath := flagSet.String("config", config.GetDefaultPath(), configUsage)
+			force := flagSet.Bool("force", false, "Overwrite the config file if it already exists")
+			if err := flagSet.Parse(arguments[1:]); err != nil {
+				return 2
+			}
+
+			if err := config.WriteDefault(*configPath, *force); err != nil {
+				fmt.Fprintln(stderr, err.Error())
+				return 1
+			}
+
+			fmt.Fprintf(stdout, "Wrote default config to %s\n", *configPath)
+			return 0
+		}
+	case "validate":
+		{
+			configPath := flagSet.String("config", config.GetDefaultPath(), configUsage)
+			if err := flagSet.Parse(arguments[1:]); err != nil {
+				return 2
+			}
+
+			loadedConfig, err := config.Load(*configPath)
+			if err == nil {
+				err = loadedConfig.Validate()
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "Config %s is invalid:\n%s\n", *configPath, err.Error())
+				return 1
+			}
+
+			fmt.Fprintf(stdout, "Config %s is valid\n", *configPath)
+			return 0
+		}
+	case "show":
+		{
+			effective := flagSet.Bool("effective", false, "Show config merged with profile, environment variables and flags")
+
+			// All the regular flags are accepted, to show their effect when merged
+			parsedArgs, err := parseArgs(flagSet, arguments[1:])
+			if err != nil {
+				fmt.Fprintln(stderr, err.Error())
+				return 2
+			}
+
+			var output any = parsedArgs.Config.Redacted()
+			if *effective {
+				connection := config.ProfileFromConnOptions(&parsedArgs.ConnOptions).Redacted()
+
+				output = struct {
+					Connection    config.Profile `yaml:"connection"`
+					config.Config `yaml:",inline"`
+				}{connection, parsedArgs.Config.Redacted()}
+			}
+
+			encoded, err := yaml.Marshal(output)
+			if err != nil {
+				fmt.Fprintln(stderr, err.Error())
+				return 1
+			}
+
+			stdout.Write(encoded)
+			return 0
+		}
+	default:
+		{
+			fmt.Fprintf(stderr, "Unknown config command %s\n\n%s", arguments[0], configCommandUsage)
+			return 2
+		}
+	}
+}
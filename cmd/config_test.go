@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const testProfilesConfig = `
+profile: local
+profiles:
+  local:
+    flavor: mysql
+    host: localhost
+    user: root
+    password: secret
+share:
+  service: gist
+  token: secret-token
+`
+
+func TestConfigCommandInitValidate(t *testing.T) {
+	assert := assert.New(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runConfigCommand([]string{"init", "-config", configPath}, &stdout, &stderr)
+	assert.Equal(0, exitCode, stderr.String())
+	assert.FileExists(configPath)
+
+	exitCode = runConfigCommand([]string{"init", "-config", configPath}, &stdout, &stderr)
+	assert.Equal(1, exitCode, "should refuse to overwrite existing config")
+
+	exitCode = runConfigCommand([]string{"validate", "-config", configPath}, &stdout, &stderr)
+	assert.Equal(0, exitCode, stderr.String())
+
+	err := os.WriteFile(configPath, []byte("profile: missing\n"), 0o600)
+	assert.NoError(err)
+
+	stderr.Reset()
+	exitCode = runConfigCommand([]string{"validate", "-config", configPath}, &stdout, &stderr)
+	assert.Equal(1, exitCode)
+	assert.Contains(stderr.String(), "missing")
+}
+
+func TestConfigCommandShowEffective(t *testing.T) {
+	assert := assert.New(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(testProfilesConfig), 0o600)
+	assert.NoError(err)
+
+	t.Setenv(config.UserEnv, "env-user")
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runConfigCommand(
+		[]string{"show", "-effective", "-config", configPath, "-P", "3307", "-scroll-rows", "2"},
+		&stdout,
+		&stderr,
+	)
+	assert.Equal(0, exitCode, stderr.String())
+
+	output := stdout.String()
+	// From profile
+	assert.Contains(output, "host: localhost")
+	assert.Contains(output, "flavor: mysql")
+	// Environment overrides profile
+	assert.Contains(output, "user: env-user")
+	// From flags
+	assert.Contains(output, "port: 3307")
+	assert.Contains(output, "rows: 2")
+	// Never print passwords
+	assert.NotContains(output, "secret")
+	assert.Contains(output, config.RedactedValue)
+}
+
+func TestConfigCommandUnknown(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 2, runConfigCommand([]string{}, &stdout, &stderr))
+	assert.Equal(t, 2, runConfigCommand([]string{"foo"}, &stdout, &stderr))
+}
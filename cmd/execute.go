@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/format"
+	"golang.org/x/term"
+)
+
+// What running statements without the interactive application needs of the database client
+type StatementRunner interface {
+	Query(statement string) (*db.QueryResult, error)
+	Exec(statement string) (*db.ExecResult, error)
+	IsExecStatement(statement string) bool
+	SplitStatements(script string) []string
+}
+
+var _ StatementRunner = (*db.DBClient)(nil)
+
+// Read statements to run from stdin when it's piped rather than a terminal, unless they were given with -e
+// ex: echo "SELECT 1" | sql -psql
+func (args *Args) ReadStatements(stdin *os.File) error {
+	if args.Execute != "" || term.IsTerminal(int(stdin.Fd())) {
+		return nil
+	}
+
+	script, err := io.ReadAll(stdin)
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to read statements from stdin"),
+			err,
+		)
+	}
+
+	// Nothing was piped, such as when started from a script without a terminal, so start as usual
+	if strings.TrimSpace(string(script)) == "" {
+		return nil
+	}
+
+	args.Execute = string(script)
+	return nil
+}
+
+// Run each statement of script in turn, rendering results and writing failures to stderr
+// Stops at the first statement which fails unless stopOnError is false, either way the exit code is 1 if any did
+func RunStatements(runner StatementRunner, script string, renderer format.Renderer, stopOnError bool, stderr io.Writer) (exitCode int) {
+	// Whatever was rendered before a failure is still written out
+	defer func() {
+		if err := renderer.Flush(); err != nil {
+			fmt.Fprintln(stderr, err.Error())
+			exitCode = 1
+		}
+	}()
+
+	for _, statement := range runner.SplitStatements(script) {
+		if err := runStatement(runner, statement, renderer, stderr); err != nil {
+			fmt.Fprintln(stderr, err.Error())
+			exitCode = 1
+
+			if stopOnError {
+				return exitCode
+			}
+		}
+	}
+
+	return exitCode
+}
+
+func runStatement(runner StatementRunner, statement string, renderer format.Renderer, stderr io.Writer) error {
+	if runner.IsExecStatement(statement) {
+		execResult, err := runner.Exec(statement)
+		if err != nil {
+			return err
+		}
+
+		// Kept out of stdout, so it only holds results
+		fmt.Fprintln(stderr, execResult.Summary())
+		return nil
+	}
+
+	result, err := runner.Query(statement)
+	if err != nil {
+		return err
+	}
+	if result != nil && result.Status != "" {
+		fmt.Fprintln(stderr, result.Summary())
+		return nil
+	}
+	if result == nil || len(result.Columns) == 0 {
+		return nil
+	}
+
+	// Every row is written, not only the first page the interactive application shows
+	if _, err = result.FetchRows(0); err != nil {
+		return err
+	}
+
+	return renderer.Write(result)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+// Answers queries with canned results, failing statements it has no result for
+type fakeStatementRunner struct {
+	results map[string]*db.QueryResult
+	// Statements run, in order
+	statements []string
+}
+
+func (fake *fakeStatementRunner) Query(statement string) (*db.QueryResult, error) {
+	fake.statements = append(fake.statements, statement)
+
+	result, exists := fake.results[statement]
+	if !exists {
+		return nil, errors.New("Query Failed")
+	}
+
+	return result, nil
+}
+
+func (fake *fakeStatementRunner) Exec(statement string) (*db.ExecResult, error) {
+	fake.statements = append(fake.statements, statement)
+	return &db.ExecResult{Status: "Query OK, 1 row affected", RowsAffected: 1}, nil
+}
+
+func (fake *fakeStatementRunner) IsExecStatement(statement string) bool {
+	return strings.HasPrefix(statement, "UPDATE")
+}
+
+func (fake *fakeStatementRunner) SplitStatements(script string) (statements []string) {
+	for _, statement := range strings.Split(script, ";") {
+		if statement = strings.TrimSpace(statement); statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+var usersResult = &db.QueryResult{
+	Columns: []string{"id", "name"},
+	Rows: []map[string]*db.NullString{
+		{
+			"id":   {NullString: sql.NullString{String: "1", Valid: true}},
+			"name": {NullString: sql.NullString{String: "Ünal", Valid: true}},
+		},
+		{
+			"id":   {NullString: sql.NullString{String: "2", Valid: true}},
+			"name": {},
+		},
+	},
+}
+
+func TestRunStatements(t *testing.T) {
+	var tests = []struct {
+		Name             string
+		Script           string
+		Format           format.Format
+		StopOnError      bool
+		ExpectedExitCode int
+		ExpectedStdout   string
+		ExpectedStderr   string
+		ExpectedRun      []string
+	}{
+		{
+			Name:   "Table",
+			Script: "SELECT * FROM users",
+			Format: format.Table,
+			ExpectedStdout: "+----+------+\n" +
+				"| id | name |\n" +
+				"+----+------+\n" +
+				"| 1  | Ünal |\n" +
+				"| 2  | NULL |\n" +
+				"+----+------+\n",
+			ExpectedRun: []string{"SELECT * FROM users"},
+		},
+		{
+			Name:           "CSV",
+			Script:         "SELECT * FROM users;",
+			Format:         format.CSV,
+			ExpectedStdout: "id,name\n1,Ünal\n2,NULL\n",
+			ExpectedRun:    []string{"SELECT * FROM users"},
+		},
+		{
+			Name:           "JSON",
+			Script:         "SELECT * FROM users;",
+			Format:         format.JSON,
+			ExpectedStdout: `[{"id":"1","name":"Ünal"},{"id":"2","name":null}]` + "\n",
+			ExpectedRun:    []string{"SELECT * FROM users"},
+		},
+		{
+			Name:           "Exec status goes to stderr",
+			Script:         "UPDATE users SET name = 'Ana' WHERE id = 2; SELECT * FROM users",
+			Format:         format.CSV,
+			ExpectedStdout: "id,name\n1,Ünal\n2,NULL\n",
+			ExpectedStderr: "Query OK, 1 row affected (0.00s)\n",
+			ExpectedRun:    []string{"UPDATE users SET name = 'Ana' WHERE id = 2", "SELECT * FROM users"},
+		},
+		{
+			Name:           "Status without columns goes to stderr",
+			Script:         "SET @answer = 42; SELECT * FROM users",
+			Format:         format.CSV,
+			ExpectedStdout: "id,name\n1,Ünal\n2,NULL\n",
+			ExpectedStderr: "Statement executed (0.00s)\n",
+			ExpectedRun:    []string{"SET @answer = 42", "SELECT * FROM users"},
+		},
+		{
+			Name:             "Stop on error",
+			Script:           "SELECT * FROM missing; SELECT * FROM users",
+			Format:           format.CSV,
+			StopOnError:      true,
+			ExpectedExitCode: 1,
+			ExpectedStderr:   "Query Failed\n",
+			ExpectedRun:      []string{"SELECT * FROM missing"},
+		},
+		{
+			Name:             "Continue after error",
+			Script:           "SELECT * FROM missing; SELECT * FROM users",
+			Format:           format.CSV,
+			ExpectedExitCode: 1,
+			ExpectedStdout:   "id,name\n1,Ünal\n2,NULL\n",
+			ExpectedStderr:   "Query Failed\n",
+			ExpectedRun:      []string{"SELECT * FROM missing", "SELECT * FROM users"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			runner := &fakeStatementRunner{results: map[string]*db.QueryResult{
+				"SELECT * FROM users": usersResult,
+				"SET @answer = 42":    {Rows: []map[string]*db.NullString{}, Status: "Statement executed"},
+			}}
+
+			var stdout, stderr bytes.Buffer
+			renderer, err := format.NewRenderer(&stdout, test.Format)
+			if !assert.NoError(err) {
+				return
+			}
+			exitCode := RunStatements(runner, test.Script, renderer, test.StopOnError, &stderr)
+
+			assert.Equal(test.ExpectedExitCode, exitCode)
+			assert.Equal(test.ExpectedStdout, stdout.String())
+			assert.Equal(test.ExpectedStderr, stderr.String())
+			assert.Equal(test.ExpectedRun, runner.statements)
+		})
+	}
+}
+
+func TestParseExecuteArgs(t *testing.T) {
+	assert := assert.New(t)
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	parsedArgs, err := parseArgs(
+		flag.NewFlagSet("sql", flag.ContinueOnError),
+		[]string{"-psql", "-config", configPath, "-e", "SELECT 1", "-format", "json"},
+	)
+	assert.NoError(err)
+	assert.Equal("SELECT 1", parsedArgs.Execute)
+	assert.Equal(format.JSON, parsedArgs.OutputFormat)
+
+	_, err = parseArgs(
+		flag.NewFlagSet("sql", flag.ContinueOnError),
+		[]string{"-psql", "-config", configPath, "-format", "xml"},
+	)
+	assert.ErrorContains(err, "Format must be one of")
+}
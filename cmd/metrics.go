@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/web"
+)
+
+// Where ServeMetrics reads the stats it serves, usually a *db.DBClient
+type sessionStatsSource interface {
+	GetSessionStats() db.SessionStats
+}
+
+// Expose session stats over HTTP, as expvar style JSON at /debug/vars and in Prometheus text format at /metrics
+// Meant for long-lived shared sessions, so it's only started when an address is given
+// Only loopback addresses are accepted unless allowRemote is set, as anyone who can reach the address sees the stats
+func ServeMetrics(address string, allowRemote bool, dbClient *db.DBClient) error {
+	if !allowRemote && !isLoopbackAddress(address) {
+		return fmt.Errorf(
+			"Refusing to serve metrics on %s, which isn't a loopback address. Pass -metrics-allow-remote to serve them anyway",
+			address,
+		)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to listen for metrics on %s", address),
+			err,
+		)
+	}
+
+	// Resolve port 0 to the one picked, as that's what requests will be addressed to
+	host, _, _ := net.SplitHostPort(address)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	go http.Serve(listener, newMetricsHandler(dbClient, net.JoinHostPort(host, port)))
+	return nil
+}
+
+// Only the session stats are served, never the global expvar set, which includes the command line and so any password
+// Requests for other host names are refused, the same as the web view, so other sites can't read them through DNS rebinding
+func newMetricsHandler(source sessionStatsSource, listenAddress string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/vars", func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(writer).Encode(map[string]db.SessionStats{
+			"session": source.GetSessionStats(),
+		})
+	})
+	mux.HandleFunc("/metrics", func(writer http.ResponseWriter, _ *http.Request) {
+		writer.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		stats := source.GetSessionStats()
+		writePrometheusMetrics(writer, &stats)
+	})
+
+	return web.RequireListenHost(listenAddress, mux)
+}
+
+// Whether the address only listens on this machine, ex: 127.0.0.1:9090 or localhost:9090, but not :9090
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func writePrometheusMetrics(writer io.Writer, stats *db.SessionStats) {
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value float64
+	}{
+		{"sql_queries_total", "counter", "Queries run this session", float64(stats.Queries)},
+		{"sql_query_errors_total", "counter", "Queries which failed this session", float64(stats.Errors)},
+		{"sql_rows_fetched_total", "counter", "Rows returned by queries this session", float64(stats.RowsFetched)},
+		{"sql_query_seconds_total", "counter", "Time spent running queries this session", stats.TotalQueryTime.Seconds()},
+	}
+
+	for _, metric := range metrics {
+		fmt.Fprintf(writer, "# HELP %s %s\n", metric.name, metric.help)
+		fmt.Fprintf(writer, "# TYPE %s %s\n", metric.name, metric.kind)
+		fmt.Fprintf(writer, "%s %g\n", metric.name, metric.value)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStatsSource struct {
+	stats db.SessionStats
+}
+
+func (fake *fakeStatsSource) GetSessionStats() db.SessionStats {
+	return fake.stats
+}
+
+func TestMetricsHandler(t *testing.T) {
+	assert := assert.New(t)
+	handler := newMetricsHandler(&fakeStatsSource{db.SessionStats{Queries: 3, Errors: 1}}, "127.0.0.1:9090")
+	newRequest := func(host string, path string) *http.Request {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Host = host
+		return request
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newRequest("127.0.0.1:9090", "/debug/vars"))
+	assert.Equal(http.StatusOK, recorder.Code)
+	assert.Contains(recorder.Body.String(), `"session":{`)
+	assert.Contains(recorder.Body.String(), `"queries":3`)
+	// The global expvar set would include the command line, and any password given with -p
+	assert.NotContains(recorder.Body.String(), "cmdline")
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newRequest("localhost:9090", "/metrics"))
+	assert.Contains(recorder.Body.String(), "sql_queries_total 3")
+	assert.Contains(recorder.Body.String(), "sql_query_errors_total 1")
+
+	// A name pointed at the loopback address by another site, to read the stats through DNS rebinding
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newRequest("attacker.example.com:9090", "/debug/vars"))
+	assert.Equal(http.StatusForbidden, recorder.Code)
+	assert.NotContains(recorder.Body.String(), `"session"`)
+}
+
+func TestIsLoopbackAddress(t *testing.T) {
+	var tests = []struct {
+		Address    string
+		IsLoopback bool
+	}{
+		{"127.0.0.1:9090", true},
+		{"localhost:9090", true},
+		{"[::1]:9090", true},
+		{":9090", false},
+		{"0.0.0.0:9090", false},
+		{"192.168.1.10:9090", false},
+		{"9090", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.IsLoopback, isLoopbackAddress(test.Address), test.Address)
+	}
+}
+
+func TestServeMetricsRemote(t *testing.T) {
+	err := ServeMetrics("0.0.0.0:0", false, nil)
+	assert.ErrorContains(t, err, "Refusing to serve metrics on 0.0.0.0:0")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"golang.org/x/term"
+)
+
+var passwordFlags = []string{"-p", "--p", "-password", "--password"}
+
+// Treat -p without a value, being last or followed by another flag, as -ask-pass, like the mysql client does
+// A password starting with - can still be given as -p=-password
+func replaceBarePasswordFlag(arguments []string) []string {
+	replacedArguments := slices.Clone(arguments)
+
+	for idx, argument := range replacedArguments {
+		// Everything after -- is positional
+		if argument == "--" {
+			break
+		}
+
+		isLast := idx == len(replacedArguments)-1
+		if slices.Contains(passwordFlags, argument) && (isLast || strings.HasPrefix(replacedArguments[idx+1], "-")) {
+			replacedArguments[idx] = "-ask-pass"
+		}
+	}
+
+	return replacedArguments
+}
+
+// Prompt for the password on the terminal without echoing it, when asked to
+// Keeps the password out of the process list and shell history, unlike passing it as an argument
+func (args *Args) ReadPassword(stdin *os.File, prompt io.Writer) error {
+	if !args.AskPassword {
+		return nil
+	}
+
+	password, err := promptSecret(stdin, prompt, "password", config.PasswordEnv)
+	if err != nil {
+		return err
+	}
+
+	args.ConnOptions.Password = password
+	return nil
+}
+
+// Read the passphrase saved queries are encrypted with from the environment, or prompt for it
+// Only the interactive application saves queries, so it isn't needed when running statements with -e
+func (args *Args) ReadHistoryPassphrase(stdin *os.File, prompt io.Writer) error {
+	historyConfig := &args.Config.History
+	if !historyConfig.Persist || historyConfig.Encryption != config.HistoryEncryptionPassphrase || args.Execute != "" {
+		return nil
+	}
+
+	if passphrase := os.Getenv(config.HistoryPassphraseEnv); passphrase != "" {
+		historyConfig.Passphrase = passphrase
+		return nil
+	}
+
+	passphrase, err := promptSecret(stdin, prompt, "history passphrase", config.HistoryPassphraseEnv)
+	if err != nil {
+		return err
+	}
+
+	historyConfig.Passphrase = passphrase
+	return nil
+}
+
+// Prompt for a secret on the terminal without echoing it
+func promptSecret(stdin *os.File, prompt io.Writer, name string, envName string) (string, error) {
+	fd := int(stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("Can't prompt for the %s without a terminal, set %s instead", name, envName)
+	}
+
+	fmt.Fprint(prompt, strings.ToUpper(name[:1]), name[1:], ": ")
+	secret, err := term.ReadPassword(fd)
+	fmt.Fprintln(prompt)
+	if err != nil {
+		return "", errors.Join(
+			fmt.Errorf("Failed to read %s", name),
+			err,
+		)
+	}
+
+	return string(secret), nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArgsAskPassword(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	testCases := []struct {
+		name             string
+		args             []string
+		expectedAsk      bool
+		expectedPassword string
+	}{
+		{name: "Password given", args: []string{"-mysql", "-p", "secret"}, expectedPassword: "secret"},
+		{name: "Password given with =", args: []string{"-mysql", "-p=-secret"}, expectedPassword: "-secret"},
+		{name: "Bare -p last", args: []string{"-mysql", "-p"}, expectedAsk: true},
+		{name: "Bare --password before a flag", args: []string{"--password", "-mysql"}, expectedAsk: true},
+		{name: "--ask-pass", args: []string{"-psql", "--ask-pass"}, expectedAsk: true},
+		{name: "Not asked", args: []string{"-psql"}},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			flagSet := flag.NewFlagSet("sql", flag.ContinueOnError)
+			parsedArgs, err := parseArgs(flagSet, append([]string{"-config", configPath}, test.args...))
+			assert.NoError(err)
+			assert.Equal(test.expectedAsk, parsedArgs.AskPassword)
+			assert.Equal(test.expectedPassword, parsedArgs.ConnOptions.Password)
+		})
+	}
+}
+
+func TestReadHistoryPassphrase(t *testing.T) {
+	assert := assert.New(t)
+
+	// Not a terminal, so it can't be prompted for
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if !assert.NoError(err) {
+		return
+	}
+	defer stdin.Close()
+
+	args := Args{Config: config.Default()}
+	args.Config.History.Encryption = config.HistoryEncryptionPassphrase
+
+	t.Setenv(config.HistoryPassphraseEnv, "")
+	err = args.ReadHistoryPassphrase(stdin, io.Discard)
+	assert.ErrorContains(err, config.HistoryPassphraseEnv)
+
+	t.Setenv(config.HistoryPassphraseEnv, "correct horse")
+	assert.NoError(args.ReadHistoryPassphrase(stdin, io.Discard))
+	assert.Equal("correct horse", args.Config.History.Passphrase)
+
+	// Not needed when queries aren't saved
+	args = Args{Config: config.Default()}
+	args.Config.History.Encryption = config.HistoryEncryptionPassphrase
+	args.Config.History.Persist = false
+	t.Setenv(config.HistoryPassphraseEnv, "")
+	assert.NoError(args.ReadHistoryPassphrase(stdin, io.Discard))
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/logging"
+)
+
+const serveCommandUsage = `Usage: sql serve -socket=<path> (-mysql OR -psql) (... connection options)
+
+Serve a database session over JSON-RPC on a unix socket, so editors and scripts can reuse it without reconnecting.
+Accepts the same connection flags, config and profiles as the interactive application.
+
+Methods:
+  Session.Query       {"statement": "SELECT 1"}
+  Session.Describe    {"table": "users"}
+  Session.ShowTables  {}
+`
+
+// Entrypoint for `sql serve`
+func RunServeCommand(arguments []string) (exitCode int) {
+	return runServeCommand(arguments, os.Stdout, os.Stderr)
+}
+
+func runServeCommand(arguments []string, stdout io.Writer, stderr io.Writer) (exitCode int) {
+	flagSet := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	flagSet.Usage = func() {
+		fmt.Fprint(stderr, serveCommandUsage)
+	}
+
+	socketPath := flagSet.String("socket", "", "Path of the unix socket to listen on")
+
+	parsedArgs, err := parseArgs(flagSet, arguments)
+	if err != nil {
+		return 2
+	}
+	for _, notice := range parsedArgs.Notices {
+		fmt.Fprintln(stderr, notice)
+	}
+
+	err = parsedArgs.ConnOptions.Validate()
+	if *socketPath == "" {
+		err = errors.Join(err, errors.New("Socket path is required"))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Unable to proceed with specified arguments: \n%s\n\n%s", err.Error(), serveCommandUsage)
+		return 2
+	}
+
+	// Otherwise slog writes to stderr, mixed in with what's served
+	logFile, err := logging.Setup(parsedArgs.LogLevel, parsedArgs.LogPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+	defer logFile.Close()
+
+	if err = parsedArgs.ReadPassword(os.Stdin, stderr); err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	connManager, err := conn.CreateConnectionManager(&parsedArgs.ConnOptions, context.Background())
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	dbClient, err := db.CreateDBClient(connManager)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+	defer dbClient.Destroy()
+
+	dbClient.SetTransformsEnabled(parsedArgs.Config.Transform)
+	dbClient.SetIdentifierQuoting(parsedArgs.Config.QuoteIdentifiers)
+	dbClient.SetResultCacheTTL(parsedArgs.Config.ResultCacheTTL())
+	dbClient.SetSchemaCachePath(parsedArgs.GetSchemaCachePath())
+	if policyHook := parsedArgs.Config.CreatePolicyHook(); policyHook != nil {
+		dbClient.SetPolicyHook(policyHook)
+	}
+	if webhookNotifier := parsedArgs.Config.CreateWebhookNotifier(); webhookNotifier != nil {
+		dbClient.SetWebhook(webhookNotifier)
+		defer webhookNotifier.Wait()
+	}
+
+	listener, err := listenUnixSocket(*socketPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	// Close the listener on interrupt, so the socket file is cleaned up
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	fmt.Fprintf(stdout, "Serving on %s\n", *socketPath)
+	serveSession(listener, &SessionService{db: dbClient})
+
+	return 0
+}
+
+// Listen on a unix socket only the current user can connect to, as it shares an authenticated session
+// A socket left behind by a previous run is replaced
+func listenUnixSocket(socketPath string) (net.Listener, error) {
+	if info, err := os.Stat(socketPath); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s already exists and is not a socket", socketPath)
+		}
+
+		if err := os.Remove(socketPath); err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("Failed to remove existing socket %s", socketPath),
+				err,
+			)
+		}
+	}
+
+	// The socket is created with umask permissions, so create it inside a
+	// private directory and only move it into place once restricted
+	privateDirectory, err := os.MkdirTemp(filepath.Dir(socketPath), ".sql-socket-")
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to create a private directory for %s", socketPath),
+			err,
+		)
+	}
+	defer os.RemoveAll(privateDirectory)
+
+	privateSocketPath := filepath.Join(privateDirectory, "sql.sock")
+	listener, err := net.Listen("unix", privateSocketPath)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to listen on %s", socketPath),
+			err,
+		)
+	}
+	unixListener := listener.(*net.UnixListener)
+	unixListener.SetUnlinkOnClose(false)
+
+	if err := os.Chmod(privateSocketPath, 0o600); err != nil {
+		listener.Close()
+		return nil, errors.Join(
+			fmt.Errorf("Failed to restrict permissions of %s", socketPath),
+			err,
+		)
+	}
+
+	if err := os.Rename(privateSocketPath, socketPath); err != nil {
+		listener.Close()
+		return nil, errors.Join(
+			fmt.Errorf("Failed to move socket into place at %s", socketPath),
+			err,
+		)
+	}
+
+	return &socketListener{UnixListener: unixListener, socketPath: socketPath}, nil
+}
+
+// Removes the socket from its final path on close, since the listener only
+// knows the path it was created at
+type socketListener struct {
+	*net.UnixListener
+	socketPath string
+}
+
+func (listener *socketListener) Close() error {
+	err := listener.UnixListener.Close()
+	os.Remove(listener.socketPath)
+	return err
+}
+
+// Accept JSON-RPC connections until the listener is closed
+func serveSession(listener net.Listener, service *SessionService) {
+	server := rpc.NewServer()
+	server.RegisterName("Session", service)
+
+	for {
+		connection, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go server.ServeCodec(jsonrpc.NewServerCodec(connection))
+	}
+}
+
+// Methods callable over JSON-RPC, all sharing one database session
+type SessionService struct {
+	// Statements run one at a time, as the session has a single connection
+	mutex sync.Mutex
+	db    *db.DBClient
+}
+
+type QueryArgs struct {
+	Statement string `json:"statement"`
+}
+
+type DescribeArgs struct {
+	Table string `json:"table"`
+}
+
+type ShowTablesArgs struct{}
+
+type QueryReply struct {
+	Columns []string                    `json:"columns"`
+	Rows    []map[string]*db.NullString `json:"rows"`
+}
+
+// Run any statement, the same as typing it in the interactive application
+func (service *SessionService) Query(args *QueryArgs, reply *QueryReply) error {
+	return service.query(args.Statement, reply)
+}
+
+func (service *SessionService) Describe(args *DescribeArgs, reply *QueryReply) error {
+	if args.Table == "" {
+		return errors.New("Table is required")
+	}
+
+	return service.query(fmt.Sprint("DESCRIBE ", args.Table), reply)
+}
+
+func (service *SessionService) ShowTables(_ *ShowTablesArgs, reply *QueryReply) error {
+	return service.query("SHOW TABLES", reply)
+}
+
+func (service *SessionService) query(statement string, reply *QueryReply) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	result, err := service.db.Query(statement)
+	if err != nil {
+		return err
+	}
+
+	reply.Columns = []string{}
+	reply.Rows = []map[string]*db.NullString{}
+	if result != nil {
+		reply.Columns = result.Columns
+		reply.Rows = result.Rows
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeCommandRequiresSocket(t *testing.T) {
+	assert := assert.New(t)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := runServeCommand(
+		[]string{"-mysql", "-config", filepath.Join(t.TempDir(), "config.yaml")},
+		&stdout,
+		&stderr,
+	)
+	assert.Equal(2, exitCode)
+	assert.Contains(stderr.String(), "Socket path is required")
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	assert := assert.New(t)
+	socketPath := filepath.Join(t.TempDir(), "sql.sock")
+
+	listener, err := listenUnixSocket(socketPath)
+	assert.NoError(err)
+
+	info, err := os.Stat(socketPath)
+	assert.NoError(err)
+	assert.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	// Leave the socket file behind, as a crashed server would
+	assert.NoError(listener.(*socketListener).UnixListener.Close())
+
+	listener, err = listenUnixSocket(socketPath)
+	assert.NoError(err, "stale socket should be replaced")
+	assert.NoError(listener.Close())
+
+	_, err = os.Stat(socketPath)
+	assert.True(os.IsNotExist(err), "socket should be removed on close")
+
+	entries, err := os.ReadDir(filepath.Dir(socketPath))
+	assert.NoError(err)
+	assert.Empty(entries, "private directory should be cleaned up")
+
+	regularFilePath := filepath.Join(t.TempDir(), "not-a-socket")
+	assert.NoError(os.WriteFile(regularFilePath, []byte{}, 0o600))
+
+	_, err = listenUnixSocket(regularFilePath)
+	assert.Error(err, "should not remove files other than sockets")
+}
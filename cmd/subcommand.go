@@ -0,0 +1,23 @@
+package cmd
+
+// Subcommands run instead of the interactive application, ex: `sql config init`
+var subcommands = map[string]func(arguments []string) (exitCode int){
+	"config": RunConfigCommand,
+	"serve":  RunServeCommand,
+	"web":    RunWebCommand,
+}
+
+// Run a subcommand if one is named by the first argument
+// When no subcommand matches, isSubcommand is false and the interactive application should start
+func RunSubcommand(arguments []string) (exitCode int, isSubcommand bool) {
+	if len(arguments) == 0 {
+		return 0, false
+	}
+
+	subcommand, isSubcommand := subcommands[arguments[0]]
+	if !isSubcommand {
+		return 0, false
+	}
+
+	return subcommand(arguments[1:]), true
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/logging"
+	"github.com/azvaliev/sql/internal/pkg/ui"
+	"github.com/azvaliev/sql/internal/pkg/web"
+)
+
+const webCommandUsage = `Usage: sql web [-listen=127.0.0.1:8080] (-mysql OR -psql) (... connection options)
+
+Start the interactive application, also serving a read-only view of its results and schema in the browser.
+Accepts the same connection flags, config and profiles as the interactive application.
+`
+
+// Entrypoint for `sql web`
+func RunWebCommand(arguments []string) (exitCode int) {
+	return runWebCommand(arguments, os.Stderr)
+}
+
+func runWebCommand(arguments []string, stderr io.Writer) (exitCode int) {
+	flagSet := flag.NewFlagSet("web", flag.ContinueOnError)
+	flagSet.SetOutput(stderr)
+	flagSet.Usage = func() {
+		fmt.Fprint(stderr, webCommandUsage)
+	}
+
+	listenAddress := flagSet.String("listen", "127.0.0.1:8080", "Address to serve the browser view on")
+
+	parsedArgs, err := parseArgs(flagSet, arguments)
+	if err != nil {
+		return 2
+	}
+	for _, notice := range parsedArgs.Notices {
+		fmt.Fprintln(stderr, notice)
+	}
+
+	err = errors.Join(
+		parsedArgs.ConnOptions.Validate(),
+		parsedArgs.Config.Validate(),
+	)
+	if err != nil {
+		fmt.Fprintf(stderr, "Unable to proceed with specified arguments: \n%s\n\n%s", err.Error(), webCommandUsage)
+		return 2
+	}
+
+	// Otherwise slog writes to stderr, drawing over the application
+	logFile, err := logging.Setup(parsedArgs.LogLevel, parsedArgs.LogPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+	defer logFile.Close()
+
+	if err = parsedArgs.ReadPassword(os.Stdin, stderr); err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	if err = parsedArgs.ReadHistoryPassphrase(os.Stdin, stderr); err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	connManager, err := conn.CreateConnectionManager(&parsedArgs.ConnOptions, context.Background())
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	dbClient, err := db.CreateDBClient(connManager)
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+	defer dbClient.Destroy()
+
+	dbClient.SetTransformsEnabled(parsedArgs.Config.Transform)
+	dbClient.SetIdentifierQuoting(parsedArgs.Config.QuoteIdentifiers)
+	dbClient.SetRowLimit(parsedArgs.Config.RowLimit)
+	dbClient.SetResultCacheTTL(parsedArgs.Config.ResultCacheTTL())
+	dbClient.SetSchemaCachePath(parsedArgs.GetSchemaCachePath())
+	if policyHook := parsedArgs.Config.CreatePolicyHook(); policyHook != nil {
+		dbClient.SetPolicyHook(policyHook)
+	}
+	if webhookNotifier := parsedArgs.Config.CreateWebhookNotifier(); webhookNotifier != nil {
+		dbClient.SetWebhook(webhookNotifier)
+		defer webhookNotifier.Wait()
+	}
+
+	app := ui.Init(dbClient, parsedArgs.Config)
+	if err = web.Serve(*listenAddress, app); err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	if err = app.Run(); err != nil {
+		fmt.Fprintln(stderr, err.Error())
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,181 @@
+// Bundles what's needed to act on an issue into a zip: version info, the config without secrets,
+// recent internal logs and the latest statements without their values
+package bugreport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"gopkg.in/yaml.v3"
+)
+
+// How much of the end of the log file is included, enough for what led up to the report
+const logTailSize = 256 * 1024
+
+// Bundle names sort by when they were written, ex: sql-bugreport-20240131-154502-1234.zip
+const fileTimeFormat = "20060102-150405"
+
+var _ db.BugReporter = (*Reporter)(nil)
+
+type Reporter struct {
+	// Hidden when the reporter is made, so secrets never reach a bundle
+	config config.Config
+	flavor string
+	// Empty when logging is off
+	logPath   string
+	directory string
+}
+
+// Bundles are written to directory, logs are read from logPath unless it's empty
+func NewReporter(cfg config.Config, flavor string, logPath string, directory string) *Reporter {
+	return &Reporter{
+		config:    cfg.Redacted(),
+		flavor:    flavor,
+		logPath:   logPath,
+		directory: directory,
+	}
+}
+
+// Write a bundle for \bugreport, returning where it was written
+func (reporter *Reporter) WriteReport(statements []db.RecentStatement) (string, error) {
+	return reporter.write(statements, "")
+}
+
+// Write a bundle after recovering from a panic, including what was recovered and the stack it was raised on
+func (reporter *Reporter) WriteCrashReport(statements []db.RecentStatement, recovered any, stack []byte) (string, error) {
+	return reporter.write(statements, fmt.Sprintf("panic: %v\n\n%s", recovered, stack))
+}
+
+func (reporter *Reporter) write(statements []db.RecentStatement, crash string) (string, error) {
+	if err := os.MkdirAll(reporter.directory, 0o700); err != nil {
+		return "", errors.Join(
+			errors.New("Failed to create bug report directory"),
+			err,
+		)
+	}
+
+	// Only the user can read it, as CreateTemp makes it
+	bundleFile, err := os.CreateTemp(reporter.directory, fmt.Sprintf("sql-bugreport-%s-*.zip", time.Now().Format(fileTimeFormat)))
+	if err != nil {
+		return "", errors.Join(
+			errors.New("Failed to create bug report"),
+			err,
+		)
+	}
+	defer bundleFile.Close()
+
+	bundle := zip.NewWriter(bundleFile)
+	if err = reporter.writeBundle(bundle, statements, crash); err == nil {
+		err = bundle.Close()
+	}
+	if err != nil {
+		return "", errors.Join(
+			errors.New("Failed to write bug report"),
+			err,
+		)
+	}
+
+	return bundleFile.Name(), bundleFile.Close()
+}
+
+func (reporter *Reporter) writeBundle(bundle *zip.Writer, statements []db.RecentStatement, crash string) error {
+	if err := writeBundleFile(bundle, "version.txt", []byte(getVersionInfo(reporter.flavor))); err != nil {
+		return err
+	}
+
+	encodedConfig, err := yaml.Marshal(reporter.config)
+	if err != nil {
+		return err
+	}
+	if err = writeBundleFile(bundle, "config.yaml", encodedConfig); err != nil {
+		return err
+	}
+
+	encodedStatements, err := json.MarshalIndent(statements, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = writeBundleFile(bundle, "statements.json", encodedStatements); err != nil {
+		return err
+	}
+
+	if err = writeBundleFile(bundle, "sql.log", reporter.readLogTail()); err != nil {
+		return err
+	}
+
+	if crash != "" {
+		return writeBundleFile(bundle, "crash.txt", []byte(crash))
+	}
+
+	return nil
+}
+
+func writeBundleFile(bundle *zip.Writer, name string, contents []byte) error {
+	file, err := bundle.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(contents)
+	return err
+}
+
+// Build, Go version and platform, along with the flavor of database connected to
+func getVersionInfo(flavor string) string {
+	var versionInfo strings.Builder
+
+	version, revision := "unknown", "unknown"
+	goVersion := runtime.Version()
+	if buildInfo, isAvailable := debug.ReadBuildInfo(); isAvailable {
+		version = buildInfo.Main.Version
+		goVersion = buildInfo.GoVersion
+
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				revision = setting.Value
+			}
+		}
+	}
+
+	fmt.Fprintf(&versionInfo, "version: %s\n", version)
+	fmt.Fprintf(&versionInfo, "revision: %s\n", revision)
+	fmt.Fprintf(&versionInfo, "go: %s\n", goVersion)
+	fmt.Fprintf(&versionInfo, "platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&versionInfo, "flavor: %s\n", flavor)
+
+	return versionInfo.String()
+}
+
+// The end of the log file, or why there's nothing to include
+func (reporter *Reporter) readLogTail() []byte {
+	if reporter.logPath == "" {
+		return []byte("Logging was off, start with -log-level=debug to include logs\n")
+	}
+
+	logFile, err := os.Open(reporter.logPath)
+	if err != nil {
+		return []byte(fmt.Sprintf("Failed to read logs: %s\n", err.Error()))
+	}
+	defer logFile.Close()
+
+	if info, err := logFile.Stat(); err == nil && info.Size() > logTailSize {
+		_, _ = logFile.Seek(-logTailSize, io.SeekEnd)
+	}
+
+	contents, err := io.ReadAll(logFile)
+	if err != nil {
+		return []byte(fmt.Sprintf("Failed to read logs: %s\n", err.Error()))
+	}
+
+	return contents
+}
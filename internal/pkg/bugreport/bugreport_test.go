@@ -0,0 +1,81 @@
+package bugreport
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// Every file in the bundle by name
+func readBundle(t *testing.T, path string) map[string]string {
+	bundle, err := zip.OpenReader(path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	defer bundle.Close()
+
+	files := map[string]string{}
+	for _, file := range bundle.File {
+		reader, err := file.Open()
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		contents, err := io.ReadAll(reader)
+		reader.Close()
+		assert.NoError(t, err)
+
+		files[file.Name] = string(contents)
+	}
+
+	return files
+}
+
+func TestWriteReport(t *testing.T) {
+	assert := assert.New(t)
+	directory := t.TempDir()
+
+	logPath := filepath.Join(directory, "sql.log")
+	assert.NoError(os.WriteFile(logPath, []byte("level=INFO msg=Connected\n"), 0o600))
+
+	cfg := config.Default()
+	cfg.Profiles = map[string]config.Profile{"local": {Password: "hunter2"}}
+
+	reporter := NewReporter(cfg, "postgres", logPath, filepath.Join(directory, "reports"))
+	path, err := reporter.WriteReport([]db.RecentStatement{
+		{RanAt: time.Now(), Statement: "SELECT * FROM users WHERE id = ?", Duration: time.Millisecond},
+	})
+	if !assert.NoError(err) {
+		return
+	}
+
+	files := readBundle(t, path)
+	assert.Contains(files["version.txt"], "flavor: postgres")
+	assert.Contains(files["config.yaml"], config.RedactedValue)
+	assert.NotContains(files["config.yaml"], "hunter2")
+	assert.Contains(files["statements.json"], "SELECT * FROM users WHERE id = ?")
+	assert.Equal("level=INFO msg=Connected\n", files["sql.log"])
+	assert.NotContains(files, "crash.txt")
+}
+
+func TestWriteCrashReport(t *testing.T) {
+	assert := assert.New(t)
+
+	reporter := NewReporter(config.Default(), "mysql", "", t.TempDir())
+	path, err := reporter.WriteCrashReport(nil, "index out of range", []byte("goroutine 1 [running]:"))
+	if !assert.NoError(err) {
+		return
+	}
+
+	files := readBundle(t, path)
+	assert.Contains(files["crash.txt"], "panic: index out of range")
+	assert.Contains(files["crash.txt"], "goroutine 1 [running]:")
+	assert.Contains(files["sql.log"], "Logging was off")
+}
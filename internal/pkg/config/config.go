@@ -0,0 +1,574 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/history"
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/azvaliev/sql/internal/pkg/keyring"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/azvaliev/sql/internal/pkg/migration"
+	"github.com/azvaliev/sql/internal/pkg/policy"
+	"github.com/azvaliev/sql/internal/pkg/share"
+	"github.com/azvaliev/sql/internal/pkg/webhook"
+)
+
+// Settings for the interactive application, independent of the database connection
+type Config struct {
+	// Profile to connect with when none is specified
+	Profile string `yaml:"profile"`
+	// Rewrite special statements such as DESCRIBE for the connected flavor
+	Transform bool `yaml:"transform"`
+	// Show the connection and whether a query is running in the terminal/tmux window title
+	TerminalTitle bool `yaml:"terminal_title"`
+	// Template for the line above the query text area, with PromptVariables available
+	Prompt string `yaml:"prompt"`
+	// Uppercase keywords such as select or from in the query text area as they're typed
+	UppercaseKeywords bool `yaml:"uppercase_keywords"`
+	// Complete keywords, table and column names in the query text area on Tab
+	Autocomplete bool `yaml:"autocomplete"`
+	// When table and column names are quoted in generated statements, see lexer.IdentifierQuoting
+	QuoteIdentifiers lexer.IdentifierQuoting `yaml:"quote_identifiers"`
+	// Rows of a result read at a time, with more read as they're scrolled to, 0 to read every row up front
+	RowLimit int `yaml:"row_limit"`
+	// When several statements are sent at once, stop at the first which fails rather than running the rest
+	StopOnError bool `yaml:"stop_on_error"`
+	// Ask before running a statement EXPLAIN estimates will read more rows than this, 0 to disable
+	ExplainGuardRows int64 `yaml:"explain_guard_rows"`
+	// Reuse the result of an identical read-only statement run within this many seconds, 0 to disable
+	ResultCacheSeconds int                `yaml:"result_cache_seconds"`
+	Keymap             KeymapConfig       `yaml:"keymap"`
+	Scroll             ScrollConfig       `yaml:"scroll"`
+	Scrollback         ScrollbackConfig   `yaml:"scrollback"`
+	WideResults        WideResultsConfig  `yaml:"wide_results"`
+	Display            DisplayConfig      `yaml:"display"`
+	History            HistoryConfig      `yaml:"history"`
+	CSV                CSVConfig          `yaml:"csv"`
+	Telemetry          TelemetryConfig    `yaml:"telemetry"`
+	Share              ShareConfig        `yaml:"share"`
+	Webhook            WebhookConfig      `yaml:"webhook"`
+	Policy             PolicyConfig       `yaml:"policy"`
+	Migrations         MigrationsConfig   `yaml:"migrations"`
+	Profiles           map[string]Profile `yaml:"profiles"`
+}
+
+// Queries sent, which Up and Down go back through and which are saved between sessions
+type HistoryConfig struct {
+	// Queries Up and Down go back through
+	Size int `yaml:"size"`
+	// Save queries to a file, so they're available in later sessions
+	Persist bool `yaml:"persist"`
+	// File queries are saved to, empty for <user config dir>/sql/history
+	Path string `yaml:"path"`
+	// Saved queries kept, the oldest are dropped on startup once exceeded. 0 for no limit
+	MaxEntries int `yaml:"max_entries"`
+	// Saved queries older than this are dropped on startup, 0 for no limit
+	MaxAgeDays int `yaml:"max_age_days"`
+	// How saved queries are encrypted, see HistoryEncryption
+	Encryption HistoryEncryption `yaml:"encryption"`
+	// Read from the environment or asked for on startup when Encryption is HistoryEncryptionPassphrase, never from the file
+	Passphrase string `yaml:"-"`
+}
+
+// Where the key saved queries are encrypted with comes from
+type HistoryEncryption string
+
+const (
+	// Saved as plain text
+	HistoryEncryptionNone HistoryEncryption = ""
+	// A random key, kept in the operating system's keyring
+	HistoryEncryptionKeyring HistoryEncryption = "keyring"
+	// Derived from a passphrase given on startup
+	HistoryEncryptionPassphrase HistoryEncryption = "passphrase"
+)
+
+// Keyring entry the history encryption key is kept under
+const (
+	keyringService        = "sql"
+	historyKeyringAccount = "history"
+)
+
+// How results are written by Copy as CSV
+type CSVConfig struct {
+	// A single character separating values, ex: ; or \t
+	Delimiter string `yaml:"delimiter"`
+	// Start with a line of column names
+	Header bool `yaml:"header"`
+}
+
+type ScrollConfig struct {
+	// Rows moved per scroll step
+	Rows int `yaml:"rows"`
+	// Columns moved per scroll step, when scrolling horizontally through tables
+	Columns int `yaml:"columns"`
+	// Mouse wheel and trackpad scrolling starts slow and speeds up as events arrive in quick succession
+	Accelerate bool `yaml:"accelerate"`
+}
+
+// How many results are kept in memory, older results collapse to their query once exceeded
+type ScrollbackConfig struct {
+	// Most result blocks kept, 0 for no limit
+	MaxBlocks int `yaml:"max_blocks"`
+	// Most result rows kept across all blocks, 0 for no limit
+	MaxRows int `yaml:"max_rows"`
+}
+
+// Results much wider than the terminal are shown a page of records at a time, each column on its own line
+type WideResultsConfig struct {
+	// Show results as records once a table would be this many times wider than the terminal, 0 to always show tables
+	WidthFactor int `yaml:"width_factor"`
+	// Records shown per page
+	RecordsPerPage int `yaml:"records_per_page"`
+}
+
+// How results are laid out, trading spacing for more output on screen, and how NULL is shown
+type DisplayConfig struct {
+	// Show the query above its result. Failed queries are always shown
+	EchoQueries bool `yaml:"echo_queries"`
+	// Leave out the blank lines between results
+	CompactSpacing bool `yaml:"compact_spacing"`
+	// Draw result tables without lines between rows, so each row takes one line rather than two
+	CompactTables bool `yaml:"compact_tables"`
+	// Shown for NULL in result tables, set apart from text by its style, ex: NULL, ∅ or empty
+	NullToken string `yaml:"null_token"`
+	// Show each column's type in a row below the header of result tables, ex: bigint
+	ColumnTypes bool `yaml:"column_types"`
+}
+
+// How the query text area is edited, and which keys run the application's actions
+type KeymapConfig struct {
+	// emacs, vim or default, see keymap.Mode
+	Mode keymap.Mode `yaml:"mode"`
+	// Key for an action, replacing its default keys, ex: focus_results: Ctrl+G
+	Bindings map[string]string `yaml:"bindings"`
+}
+
+// Anonymous feature usage counts, never including query text or connection details
+type TelemetryConfig struct {
+	// Off unless explicitly turned on
+	Enabled bool `yaml:"enabled"`
+	// URL usage is POSTed to as JSON when exiting
+	Endpoint string `yaml:"endpoint"`
+}
+
+// Where result exports are uploaded by the Share action
+type ShareConfig struct {
+	// gist or privatebin, empty to disable sharing
+	Service share.Service `yaml:"service"`
+	// API URL for gists, defaults to GitHub's. URL of the instance for privatebin
+	Endpoint string `yaml:"endpoint"`
+	// Required for gists, sent as a bearer token
+	Token string `yaml:"token"`
+}
+
+// Notification when slow statements finish, such as long analytics queries left running
+type WebhookConfig struct {
+	// JSON is POSTed here, such as a Slack incoming webhook. Empty to disable
+	URL string `yaml:"url"`
+	// Only statements running at least this many seconds are notified
+	ThresholdSeconds int `yaml:"threshold_seconds"`
+}
+
+// Hook asked about each statement before it's sent, which can allow, deny or rewrite it
+type PolicyConfig struct {
+	// Run through sh with the statement as JSON on stdin, see policy.CommandHook. Empty to disable
+	Command string `yaml:"command"`
+	// Statements are refused when the command takes longer than this
+	TimeoutSeconds int `yaml:"timeout_seconds"`
+}
+
+// Where schema changes made interactively are offered to be saved as migrations
+type MigrationsConfig struct {
+	// Directory migration files are written to, empty to never offer
+	Directory string `yaml:"directory"`
+	// golang-migrate or goose
+	Format migration.Format `yaml:"format"`
+}
+
+func Default() Config {
+	return Config{
+		Profile:       "",
+		Transform:     true,
+		TerminalTitle: true,
+		Prompt:        "",
+		// Off, so queries are left exactly as typed unless asked for
+		UppercaseKeywords: false,
+		// Tab still inserts a tab when there's no word before the cursor to complete
+		Autocomplete: true,
+		// Quoting everything is always correct, if noisier
+		QuoteIdentifiers: lexer.QuoteAlways,
+		// Plenty for a screen, while keeping the terminal responsive on tables of millions of rows
+		RowLimit: 500,
+		// A script carrying on past a failed statement could run later statements against a half applied change
+		StopOnError: true,
+		// Off, as every statement would need an extra round trip for the EXPLAIN
+		ExplainGuardRows: 0,
+		// Off, as results going stale without warning would be surprising
+		ResultCacheSeconds: 0,
+		Keymap: KeymapConfig{
+			Mode:     keymap.Emacs,
+			Bindings: nil,
+		},
+		Scroll: ScrollConfig{
+			Rows:       5,
+			Columns:    2,
+			Accelerate: false,
+		},
+		// Generous enough to never matter in a typical session, while keeping one left open for days bounded
+		Scrollback: ScrollbackConfig{
+			MaxBlocks: 500,
+			MaxRows:   100000,
+		},
+		// A table up to a few screens wide is still quicker to scan by scrolling than record by record
+		WideResults: WideResultsConfig{
+			WidthFactor:    3,
+			RecordsPerPage: 5,
+		},
+		// Spaced out, as it's easier to tell results apart at a glance
+		Display: DisplayConfig{
+			EchoQueries:    true,
+			CompactSpacing: false,
+			CompactTables:  false,
+			NullToken:      db.DefaultNullToken,
+			ColumnTypes:    false,
+		},
+		// Plenty to go back through, while a long lived history file stays quick to load
+		History: HistoryConfig{
+			Size:       100,
+			Persist:    true,
+			Path:       "",
+			MaxEntries: 10000,
+			MaxAgeDays: 0,
+		},
+		CSV: CSVConfig{
+			Delimiter: ",",
+			Header:    true,
+		},
+		Telemetry: TelemetryConfig{
+			Enabled:  false,
+			Endpoint: "",
+		},
+		Share: ShareConfig{
+			Service:  "",
+			Endpoint: "",
+			Token:    "",
+		},
+		Webhook: WebhookConfig{
+			URL:              "",
+			ThresholdSeconds: 60,
+		},
+		// Long enough for a script to start, short enough not to hold up every statement
+		Policy: PolicyConfig{
+			Command:        "",
+			TimeoutSeconds: 5,
+		},
+		Migrations: MigrationsConfig{
+			Directory: "",
+			Format:    migration.GolangMigrate,
+		},
+	}
+}
+
+func (config *Config) Validate() error {
+	if config.Scroll.Rows < 1 {
+		return errors.New("Scroll rows must be at least 1")
+	}
+	if config.Scroll.Columns < 1 {
+		return errors.New("Scroll columns must be at least 1")
+	}
+
+	if config.Scrollback.MaxBlocks < 0 || config.Scrollback.MaxRows < 0 {
+		return errors.New("Scrollback limits must not be negative")
+	}
+
+	if config.WideResults.WidthFactor < 0 {
+		return errors.New("Wide results width factor must not be negative")
+	}
+	if config.WideResults.RecordsPerPage < 1 {
+		return errors.New("Wide results records per page must be at least 1")
+	}
+
+	if config.History.Size < 1 {
+		return errors.New("History size must be at least 1")
+	}
+	if config.History.MaxEntries < 0 || config.History.MaxAgeDays < 0 {
+		return errors.New("History limits must not be negative")
+	}
+	switch config.History.Encryption {
+	case HistoryEncryptionNone, HistoryEncryptionKeyring, HistoryEncryptionPassphrase:
+	default:
+		return fmt.Errorf(
+			"Unknown history encryption %s, expected %s or %s",
+			config.History.Encryption,
+			HistoryEncryptionKeyring,
+			HistoryEncryptionPassphrase,
+		)
+	}
+
+	if utf8.RuneCountInString(config.CSV.Delimiter) != 1 {
+		return fmt.Errorf("CSV delimiter must be a single character, got %q", config.CSV.Delimiter)
+	}
+	if err := config.CSVOptions().Validate(); err != nil {
+		return err
+	}
+
+	if err := lexer.ValidateIdentifierQuoting(config.QuoteIdentifiers); err != nil {
+		return err
+	}
+
+	if config.RowLimit < 0 {
+		return errors.New("Row limit must not be negative")
+	}
+
+	if config.ResultCacheSeconds < 0 {
+		return errors.New("Result cache seconds must not be negative")
+	}
+
+	if config.ExplainGuardRows < 0 {
+		return errors.New("Explain guard rows must not be negative")
+	}
+
+	if _, err := config.ParsePrompt(); err != nil {
+		return err
+	}
+
+	if _, err := config.BuildKeymap(); err != nil {
+		return err
+	}
+
+	if config.Telemetry.Enabled && config.Telemetry.Endpoint == "" {
+		return errors.New("Telemetry endpoint must be set to enable telemetry")
+	}
+
+	if config.Webhook.ThresholdSeconds < 0 {
+		return errors.New("Webhook threshold seconds must not be negative")
+	}
+
+	if config.Policy.Command != "" && config.Policy.TimeoutSeconds < 1 {
+		return errors.New("Policy timeout seconds must be at least 1")
+	}
+
+	if config.Share.Service != "" {
+		if err := share.Validate(config.Share.Service, config.Share.Endpoint, config.Share.Token); err != nil {
+			return err
+		}
+	}
+
+	if config.Migrations.Directory != "" {
+		if err := migration.Validate(config.Migrations.Format); err != nil {
+			return err
+		}
+	}
+
+	if config.Profile != "" {
+		if _, exists := config.Profiles[config.Profile]; !exists {
+			return fmt.Errorf("Default profile %s is not defined", config.Profile)
+		}
+	}
+
+	var profileErrors []error
+	for name, profile := range config.Profiles {
+		if err := profile.Validate(); err != nil {
+			profileErrors = append(profileErrors, fmt.Errorf("Profile %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(profileErrors...)
+}
+
+// Replaces passwords and other secrets when printing config
+const RedactedValue = "********"
+
+// Copy of config with profile passwords, the share token and the webhook URL hidden, for printing or sharing
+// The webhook URL is hidden as services such as Slack put the secret in it, and additional options such as sslpassword are too
+func (config Config) Redacted() Config {
+	if config.Share.Token != "" {
+		config.Share.Token = RedactedValue
+	}
+	if config.Webhook.URL != "" {
+		config.Webhook.URL = RedactedValue
+	}
+
+	redactedProfiles := make(map[string]Profile, len(config.Profiles))
+	for name, profile := range config.Profiles {
+		redactedProfiles[name] = profile.Redacted()
+	}
+
+	if config.Profiles != nil {
+		config.Profiles = redactedProfiles
+	}
+
+	return config
+}
+
+// Copy of profile with its password and additional options holding secrets hidden
+func (profile Profile) Redacted() Profile {
+	if profile.Password != "" {
+		profile.Password = RedactedValue
+	}
+
+	if profile.AdditionalOptions != nil {
+		// Copied, as the map is shared with the original
+		redactedOptions := make(map[string]string, len(profile.AdditionalOptions))
+		for key, value := range profile.AdditionalOptions {
+			if value != "" && isSecretOption(key) {
+				value = RedactedValue
+			}
+			redactedOptions[key] = value
+		}
+		profile.AdditionalOptions = redactedOptions
+	}
+
+	return profile
+}
+
+// Whether an additional option holds a secret by its name, ex: password, sslpassword or auth_token
+func isSecretOption(key string) bool {
+	lowerKey := strings.ToLower(key)
+	return strings.Contains(lowerKey, "password") ||
+		strings.Contains(lowerKey, "secret") ||
+		strings.Contains(lowerKey, "token")
+}
+
+// How long results are reused for, 0 when disabled
+func (config *Config) ResultCacheTTL() time.Duration {
+	return time.Duration(config.ResultCacheSeconds) * time.Second
+}
+
+// Options for Copy as CSV, the delimiter is expected to have been validated as a single character
+func (config *Config) CSVOptions() db.CSVOptions {
+	delimiter, _ := utf8.DecodeRuneInString(config.CSV.Delimiter)
+
+	return db.CSVOptions{
+		Delimiter: delimiter,
+		NoHeader:  !config.CSV.Header,
+	}
+}
+
+// Notifier for the configured webhook, nil when disabled
+func (config *Config) CreateWebhookNotifier() *webhook.Notifier {
+	if config.Webhook.URL == "" {
+		return nil
+	}
+
+	return webhook.NewNotifier(config.Webhook.URL, time.Duration(config.Webhook.ThresholdSeconds)*time.Second)
+}
+
+// Hook for the configured policy command, nil when disabled
+func (config *Config) CreatePolicyHook() policy.Hook {
+	if config.Policy.Command == "" {
+		return nil
+	}
+
+	return policy.NewCommandHook(config.Policy.Command, time.Duration(config.Policy.TimeoutSeconds)*time.Second)
+}
+
+// Where queries are saved and how many are kept, an empty path when they aren't saved
+func (config *Config) GetHistoryFile() (path string, retention history.Retention) {
+	if !config.History.Persist {
+		return "", retention
+	}
+
+	path = config.History.Path
+	if path == "" {
+		path = GetDefaultHistoryPath()
+	}
+
+	return path, history.Retention{
+		MaxEntries: config.History.MaxEntries,
+		MaxAge:     time.Duration(config.History.MaxAgeDays) * 24 * time.Hour,
+	}
+}
+
+// Passphrase saved queries are encrypted with, empty when they're saved as plain text
+// With the keyring, a random one is stored there the first time
+func (config *Config) GetHistoryPassphrase() (string, error) {
+	switch config.History.Encryption {
+	case HistoryEncryptionKeyring:
+		{
+			passphrase, err := keyring.GetOrCreate(keyringService, historyKeyringAccount)
+			if err != nil {
+				return "", errors.Join(
+					errors.New("Failed to read the history key from the keyring"),
+					err,
+				)
+			}
+
+			return passphrase, nil
+		}
+	case HistoryEncryptionPassphrase:
+		{
+			if config.History.Passphrase == "" {
+				return "", fmt.Errorf("History encryption needs a passphrase, set %s", HistoryPassphraseEnv)
+			}
+
+			return config.History.Passphrase, nil
+		}
+	default:
+		{
+			return "", nil
+		}
+	}
+}
+
+// Writer for the configured migrations directory, nil when disabled
+func (config *Config) CreateMigrationWriter() *migration.Writer {
+	if config.Migrations.Directory == "" {
+		return nil
+	}
+
+	return migration.NewWriter(config.Migrations.Directory, config.Migrations.Format)
+}
+
+// Build the keymap from the configured mode and bindings, reporting any conflicting keys
+func (config *Config) BuildKeymap() (*keymap.Keymap, error) {
+	builtKeymap, err := keymap.Build(config.Keymap.Mode, config.Keymap.Bindings)
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Invalid keymap"),
+			err,
+		)
+	}
+
+	return builtKeymap, nil
+}
+
+// Values available to the prompt template, ex: {{.User}}@{{.Host}}:{{.Database}}
+type PromptVariables struct {
+	User     string
+	Host     string
+	Port     uint
+	Database string
+	// MySQL or PostgreSQL
+	Flavor string
+	// TX while in a transaction, empty otherwise
+	TxState string
+}
+
+// Parse the prompt template, nil when no prompt is configured
+// Also checks it only refers to known variables, by rendering it once
+func (config *Config) ParsePrompt() (*template.Template, error) {
+	if config.Prompt == "" {
+		return nil, nil
+	}
+
+	prompt, err := template.New("prompt").Parse(config.Prompt)
+	if err == nil {
+		err = prompt.Execute(io.Discard, PromptVariables{})
+	}
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Invalid prompt template"),
+			err,
+		)
+	}
+
+	return prompt, nil
+}
@@ -0,0 +1,261 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/share"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValidate(t *testing.T) {
+	var tests = []struct {
+		Name        string
+		Modify      func(cfg *config.Config)
+		ExpectError bool
+	}{
+		{
+			Name:        "Defaults",
+			Modify:      func(cfg *config.Config) {},
+			ExpectError: false,
+		},
+		{
+			Name: "Zero scroll rows",
+			Modify: func(cfg *config.Config) {
+				cfg.Scroll.Rows = 0
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Negative scroll columns",
+			Modify: func(cfg *config.Config) {
+				cfg.Scroll.Columns = -1
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Negative result cache seconds",
+			Modify: func(cfg *config.Config) {
+				cfg.ResultCacheSeconds = -1
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "History encrypted with a passphrase",
+			Modify: func(cfg *config.Config) {
+				cfg.History.Encryption = config.HistoryEncryptionPassphrase
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Unknown history encryption",
+			Modify: func(cfg *config.Config) {
+				cfg.History.Encryption = "rot13"
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Conflicting keybinding",
+			Modify: func(cfg *config.Config) {
+				cfg.Keymap.Bindings = map[string]string{"open_bookmarks": "Ctrl+T"}
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Prompt template",
+			Modify: func(cfg *config.Config) {
+				cfg.Prompt = "{{.User}}@{{.Host}}:{{.Database}} [{{.Flavor}}] {{.TxState}}"
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Prompt template with unknown variable",
+			Modify: func(cfg *config.Config) {
+				cfg.Prompt = "{{.Schema}}"
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Malformed prompt template",
+			Modify: func(cfg *config.Config) {
+				cfg.Prompt = "{{.User"
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Telemetry enabled without endpoint",
+			Modify: func(cfg *config.Config) {
+				cfg.Telemetry.Enabled = true
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Telemetry enabled with endpoint",
+			Modify: func(cfg *config.Config) {
+				cfg.Telemetry.Enabled = true
+				cfg.Telemetry.Endpoint = "https://telemetry.example.com/usage"
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Unknown migrations format",
+			Modify: func(cfg *config.Config) {
+				cfg.Migrations.Directory = "migrations"
+				cfg.Migrations.Format = "flyway"
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Negative row limit",
+			Modify: func(cfg *config.Config) {
+				cfg.RowLimit = -1
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Unknown identifier quoting",
+			Modify: func(cfg *config.Config) {
+				cfg.QuoteIdentifiers = "sometimes"
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Negative scrollback limit",
+			Modify: func(cfg *config.Config) {
+				cfg.Scrollback.MaxRows = -1
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "No records per page",
+			Modify: func(cfg *config.Config) {
+				cfg.WideResults.RecordsPerPage = 0
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Wide results always shown as tables",
+			Modify: func(cfg *config.Config) {
+				cfg.WideResults.WidthFactor = 0
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Tab CSV delimiter",
+			Modify: func(cfg *config.Config) {
+				cfg.CSV.Delimiter = "\t"
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Several character CSV delimiter",
+			Modify: func(cfg *config.Config) {
+				cfg.CSV.Delimiter = ", "
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Quote CSV delimiter",
+			Modify: func(cfg *config.Config) {
+				cfg.CSV.Delimiter = `"`
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Negative webhook threshold",
+			Modify: func(cfg *config.Config) {
+				cfg.Webhook.ThresholdSeconds = -1
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Policy command without timeout",
+			Modify: func(cfg *config.Config) {
+				cfg.Policy.Command = "./check-statement.sh"
+				cfg.Policy.TimeoutSeconds = 0
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Policy timeout without command",
+			Modify: func(cfg *config.Config) {
+				cfg.Policy.TimeoutSeconds = 0
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "Unknown share service",
+			Modify: func(cfg *config.Config) {
+				cfg.Share.Service = "pastebin"
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "Gist sharing without token",
+			Modify: func(cfg *config.Config) {
+				cfg.Share.Service = share.Gist
+			},
+			ExpectError: true,
+		},
+		{
+			Name: "PrivateBin sharing with endpoint",
+			Modify: func(cfg *config.Config) {
+				cfg.Share.Service = share.PrivateBin
+				cfg.Share.Endpoint = "https://paste.example.com"
+			},
+			ExpectError: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			cfg := config.Default()
+			test.Modify(&cfg)
+
+			err := cfg.Validate()
+			if test.ExpectError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+		})
+	}
+}
+
+func TestConfigRedacted(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.Default()
+	cfg.Share.Token = "ghp_secret"
+	cfg.Webhook.URL = "https://hooks.slack.com/services/secret"
+	cfg.Profiles = map[string]config.Profile{
+		"local": {User: "root", Password: "hunter2"},
+		"peer":  {User: "app"},
+		"tls": {User: "app", AdditionalOptions: map[string]string{
+			"sslmode":     "verify-full",
+			"sslpassword": "keypass",
+			"Password":    "hunter3",
+		}},
+	}
+
+	redacted := cfg.Redacted()
+	assert.Equal(config.RedactedValue, redacted.Share.Token)
+	assert.Equal(config.RedactedValue, redacted.Webhook.URL)
+	assert.Equal(config.RedactedValue, redacted.Profiles["local"].Password)
+	assert.Equal("root", redacted.Profiles["local"].User)
+	// Nothing to hide is left empty, so it's clear it isn't set
+	assert.Empty(redacted.Profiles["peer"].Password)
+
+	// Options holding secrets are hidden, whatever their case, while the rest are kept to help debugging
+	assert.Equal(
+		map[string]string{"sslmode": "verify-full", "sslpassword": config.RedactedValue, "Password": config.RedactedValue},
+		redacted.Profiles["tls"].AdditionalOptions,
+	)
+
+	// The original is left as it was
+	assert.Equal("hunter2", cfg.Profiles["local"].Password)
+	assert.Equal("keypass", cfg.Profiles["tls"].AdditionalOptions["sslpassword"])
+}
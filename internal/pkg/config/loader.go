@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read when loading configuration
+const (
+	ConfigPathEnv = "SQL_CONFIG"
+	ProfileEnv    = "SQL_PROFILE"
+	HostEnv       = "SQL_HOST"
+	PortEnv       = "SQL_PORT"
+	UserEnv       = "SQL_USER"
+	PasswordEnv   = "SQL_PASSWORD"
+	DatabaseEnv   = "SQL_DATABASE"
+	// Passphrase the history is encrypted with, when history.encryption is passphrase
+	HistoryPassphraseEnv = "SQL_HISTORY_PASSPHRASE"
+)
+
+// Commented version of the default config, written by `sql config init`
+//
+//go:embed default_config.yaml
+var DefaultConfigTemplate []byte
+
+// Where config is read from, unless otherwise specified
+// $SQL_CONFIG, falling back to <user config dir>/sql/config.yaml
+func GetDefaultPath() string {
+	if path := os.Getenv(ConfigPathEnv); path != "" {
+		return path
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(".config", "sql", "config.yaml")
+	}
+
+	return filepath.Join(configDir, "sql", "config.yaml")
+}
+
+// Where queries are saved between sessions, unless configured otherwise
+// <user config dir>/sql/history, next to the config file
+func GetDefaultHistoryPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(".config", "sql", "history")
+	}
+
+	return filepath.Join(configDir, "sql", "history")
+}
+
+// Where internal logs are written when enabled with -log-level, <user cache dir>/sql/sql.log
+func GetDefaultLogPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = ".cache"
+	}
+
+	return filepath.Join(cacheDir, "sql", "sql.log")
+}
+
+// Where bug report bundles are written, <user cache dir>/sql/bugreports
+func GetBugReportDirectory() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = ".cache"
+	}
+
+	return filepath.Join(cacheDir, "sql", "bugreports")
+}
+
+// Where the schema for a connection is saved for use while offline, <user cache dir>/sql/schema/<name>.json
+// Characters which aren't safe in a file name are replaced
+func GetSchemaCachePath(name string) string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = ".cache"
+	}
+
+	fileName := strings.Map(func(char rune) rune {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) || strings.ContainsRune("@.-_", char) {
+			return char
+		}
+
+		return '_'
+	}, name)
+
+	return filepath.Join(cacheDir, "sql", "schema", fmt.Sprint(fileName, ".json"))
+}
+
+// Read config from the path, on top of the defaults
+// A missing file is not an error, the defaults are returned as-is
+func Load(path string) (Config, error) {
+	config := Default()
+
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return config, errors.Join(
+			fmt.Errorf("Failed to read config %s", path),
+			err,
+		)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	// Catch typos in keys, rather than silently ignoring them
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(&config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return config, errors.Join(
+			fmt.Errorf("Failed to parse config %s", path),
+			err,
+		)
+	}
+
+	return config, nil
+}
+
+// Write the commented default config to the path, creating parent directories as needed
+func WriteDefault(path string, overwrite bool) error {
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("Config %s already exists", path)
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to create config directory"),
+			err,
+		)
+	}
+
+	// Config may end up containing passwords, keep it private
+	return os.WriteFile(path, DefaultConfigTemplate, 0o600)
+}
+
+// Serialize config in the same format it is loaded from
+func (config *Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(config)
+}
+
+// Get a profile by name, an empty name uses the default profile if any
+func (config *Config) GetProfile(name string) (profile Profile, err error) {
+	if name == "" {
+		name = config.Profile
+	}
+	if name == "" {
+		return profile, nil
+	}
+
+	profile, exists := config.Profiles[name]
+	if !exists {
+		return profile, fmt.Errorf("Profile %s is not defined", name)
+	}
+
+	return profile, nil
+}
+
+// Fill in connection options from SQL_* environment variables, where set
+func ApplyEnv(connOptions *conn.DSNOptions) error {
+	if host := os.Getenv(HostEnv); host != "" {
+		connOptions.Host = host
+	}
+	if rawPort := os.Getenv(PortEnv); rawPort != "" {
+		port, err := strconv.ParseUint(rawPort, 10, 0)
+		if err != nil {
+			return fmt.Errorf("%s must be a port number, got %s", PortEnv, rawPort)
+		}
+		connOptions.Port = uint(port)
+	}
+	if user := os.Getenv(UserEnv); user != "" {
+		connOptions.User = user
+	}
+	if password := os.Getenv(PasswordEnv); password != "" {
+		connOptions.Password = password
+	}
+	if database := os.Getenv(DatabaseEnv); database != "" {
+		connOptions.DatabaseName = database
+	}
+
+	return nil
+}
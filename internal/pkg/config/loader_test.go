@@ -0,0 +1,161 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigLoadDefaultTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	configPath := filepath.Join(t.TempDir(), "nested", "config.yaml")
+	err := config.WriteDefault(configPath, false)
+	assert.NoError(err)
+
+	loadedConfig, err := config.Load(configPath)
+	assert.NoError(err)
+	assert.Equal(config.Default(), loadedConfig, "commented default config should match the defaults")
+
+	err = config.WriteDefault(configPath, false)
+	assert.Error(err, "should not overwrite existing config without asking")
+
+	err = config.WriteDefault(configPath, true)
+	assert.NoError(err)
+}
+
+func TestConfigLoad(t *testing.T) {
+	var tests = []struct {
+		Name           string
+		Contents       string
+		ExpectedConfig func() config.Config
+		ExpectError    bool
+	}{
+		{
+			Name:           "Empty file",
+			Contents:       "",
+			ExpectedConfig: config.Default,
+		},
+		{
+			Name:     "Partial settings",
+			Contents: "scroll:\n  rows: 1\n",
+			ExpectedConfig: func() config.Config {
+				cfg := config.Default()
+				cfg.Scroll.Rows = 1
+				return cfg
+			},
+		},
+		{
+			Name:     "Profiles",
+			Contents: "profile: local\nprofiles:\n  local:\n    flavor: postgres\n    host: localhost\n    port: 5432\n",
+			ExpectedConfig: func() config.Config {
+				cfg := config.Default()
+				cfg.Profile = "local"
+				cfg.Profiles = map[string]config.Profile{
+					"local": {Flavor: "postgres", Host: "localhost", Port: 5432},
+				}
+				return cfg
+			},
+		},
+		{
+			Name:        "Unknown key",
+			Contents:    "scrol:\n  rows: 1\n",
+			ExpectError: true,
+		},
+		{
+			Name:        "Malformed",
+			Contents:    "scroll: [",
+			ExpectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			err := os.WriteFile(configPath, []byte(test.Contents), 0o600)
+			assert.NoError(err)
+
+			loadedConfig, err := config.Load(configPath)
+			if test.ExpectError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.ExpectedConfig(), loadedConfig)
+		})
+	}
+}
+
+func TestConfigLoadMissingFile(t *testing.T) {
+	loadedConfig, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, config.Default(), loadedConfig)
+}
+
+func TestConfigProfiles(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.Default()
+	cfg.Profiles = map[string]config.Profile{
+		"remote": {
+			Flavor:            "psql",
+			Host:              "remote.example.com",
+			User:              "postgres",
+			AdditionalOptions: map[string]string{"sslmode": "require"},
+		},
+		"invalid": {
+			Flavor: "sqlite",
+		},
+	}
+
+	assert.Error(cfg.Validate(), "unknown flavor should be invalid")
+
+	_, err := cfg.GetProfile("missing")
+	assert.Error(err)
+
+	profile, err := cfg.GetProfile("remote")
+	assert.NoError(err)
+
+	connOptions := conn.DSNOptions{Port: 5433}
+	profile.ApplyTo(&connOptions)
+
+	assert.Equal(conn.DSNOptions{
+		Flavor:            conn.PostgreSQL,
+		Host:              "remote.example.com",
+		User:              "postgres",
+		Port:              5433,
+		AdditionalOptions: map[string]string{"sslmode": "require"},
+	}, connOptions)
+}
+
+func TestConfigApplyEnv(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Setenv(config.HostEnv, "env.example.com")
+	t.Setenv(config.PortEnv, "3307")
+
+	connOptions := conn.DSNOptions{Host: "localhost", User: "root"}
+	err := config.ApplyEnv(&connOptions)
+	assert.NoError(err)
+	assert.Equal(conn.DSNOptions{Host: "env.example.com", Port: 3307, User: "root"}, connOptions)
+
+	t.Setenv(config.PortEnv, "not a port")
+	assert.Error(config.ApplyEnv(&connOptions))
+}
+
+func TestGetSchemaCachePath(t *testing.T) {
+	assert := assert.New(t)
+
+	path := config.GetSchemaCachePath("postgres-admin@db.example.com/app db-5432")
+	assert.Equal("postgres-admin@db.example.com_app_db-5432.json", filepath.Base(path))
+	assert.Equal("schema", filepath.Base(filepath.Dir(path)))
+}
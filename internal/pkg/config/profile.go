@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Saved connection details, so they don't need to be passed as flags each time
+type Profile struct {
+	// mysql, postgres or psql
+	Flavor            string            `yaml:"flavor,omitempty"`
+	Host              string            `yaml:"host,omitempty"`
+	Port              uint              `yaml:"port,omitempty"`
+	User              string            `yaml:"user,omitempty"`
+	Password          string            `yaml:"password,omitempty"`
+	Database          string            `yaml:"database,omitempty"`
+	SafeMode          bool              `yaml:"safe,omitempty"`
+	AdditionalOptions map[string]string `yaml:"additional-options,omitempty"`
+}
+
+// Map the names used in config to a DB flavor
+var profileFlavors = map[string]conn.DBFlavor{
+	"mysql":    conn.MySQL,
+	"postgres": conn.PostgreSQL,
+	"psql":     conn.PostgreSQL,
+}
+
+func (profile *Profile) Validate() error {
+	if profile.Flavor == "" {
+		return nil
+	}
+
+	if _, isValid := profileFlavors[profile.Flavor]; !isValid {
+		return fmt.Errorf("Unknown flavor %s, expected one of mysql, postgres, psql", profile.Flavor)
+	}
+
+	return nil
+}
+
+// Fill in connection options from the profile, where set
+func (profile *Profile) ApplyTo(connOptions *conn.DSNOptions) {
+	if flavor, isValid := profileFlavors[profile.Flavor]; isValid {
+		connOptions.Flavor = flavor
+	}
+	if profile.Host != "" {
+		connOptions.Host = profile.Host
+	}
+	if profile.Port != 0 {
+		connOptions.Port = profile.Port
+	}
+	if profile.User != "" {
+		connOptions.User = profile.User
+	}
+	if profile.Password != "" {
+		connOptions.Password = profile.Password
+	}
+	if profile.Database != "" {
+		connOptions.DatabaseName = profile.Database
+	}
+	if profile.SafeMode {
+		connOptions.SafeMode = true
+	}
+	if len(profile.AdditionalOptions) > 0 {
+		if connOptions.AdditionalOptions == nil {
+			connOptions.AdditionalOptions = make(map[string]string, len(profile.AdditionalOptions))
+		}
+		maps.Copy(connOptions.AdditionalOptions, profile.AdditionalOptions)
+	}
+}
+
+// Inverse of ApplyTo, to describe connection options in the same format as config
+func ProfileFromConnOptions(connOptions *conn.DSNOptions) Profile {
+	profile := Profile{
+		Host:              connOptions.Host,
+		Port:              connOptions.Port,
+		User:              connOptions.User,
+		Password:          connOptions.Password,
+		Database:          connOptions.DatabaseName,
+		SafeMode:          connOptions.SafeMode,
+		AdditionalOptions: connOptions.AdditionalOptions,
+	}
+
+	switch connOptions.Flavor {
+	case conn.MySQL:
+		{
+			profile.Flavor = "mysql"
+		}
+	case conn.PostgreSQL:
+		{
+			profile.Flavor = "postgres"
+		}
+	}
+
+	return profile
+}
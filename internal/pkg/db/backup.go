@@ -0,0 +1,230 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// Longer names are truncated by Postgres (63) or rejected by MySQL (64), so backups are kept within both
+const maxBackupNameLength = 63
+
+// Backups are named after the table and the day they were taken, ex: users_backup_20240131
+var backupNameRegExp = regexp.MustCompile(`^(.+)_backup_\d{8}$`)
+
+// \backup <table>
+// Copy a table's rows to <table>_backup_<YYYYMMDD>, as a safety net before modifying it
+// Only the data is copied, the backup has no indexes or constraints
+// Refused inside a transaction on MySQL, as CREATE TABLE would commit it implicitly
+func (db *DBClient) backupCommand(argument string) (*QueryResult, error) {
+	tableName := strings.TrimSpace(strings.TrimSuffix(argument, ";"))
+	if tableName == "" || strings.Contains(tableName, " ") {
+		return nil, errors.New(`Usage: \backup <table>`)
+	}
+
+	if db.connManager.GetFlavor() == conn.MySQL && db.InTransaction() {
+		return nil, errors.New(`\backup can't be taken inside a transaction on MySQL, as creating the backup table would commit it, commit or roll back first`)
+	}
+
+	backupName := fmt.Sprint(tableName, "_backup_", time.Now().Format("20060102"))
+	if len(backupName) > maxBackupNameLength {
+		return nil, fmt.Errorf("Backup name %s is longer than %d characters, back up the table manually", backupName, maxBackupNameLength)
+	}
+
+	backupStatement, err := db.checkStatement(fmt.Sprintf(
+		"CREATE TABLE %s AS SELECT * FROM %s",
+		db.quoteIdentifier(backupName),
+		db.quoteIdentifier(tableName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.assertTableExists(tableName); err != nil {
+		return nil, err
+	}
+	if err := db.assertTableExists(backupName); err == nil {
+		return nil, fmt.Errorf(`%s already exists, drop it or \restore-from it first`, backupName)
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = connection.ExecContext(db.ctx, backupStatement)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to back up %s", tableName),
+			err,
+		)
+	}
+	db.schema = nil
+
+	var rowCount int
+	err = connection.GetContext(db.ctx, &rowCount, fmt.Sprint("SELECT COUNT(*) FROM ", db.quoteIdentifier(backupName)))
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Backed up %s to %s, but failed to count its rows", tableName, backupName),
+			err,
+		)
+	}
+
+	return newTextResult(
+		[]string{"Backup"},
+		[][]string{{fmt.Sprintf("Copied %d rows of %s to %s", rowCount, tableName, backupName)}},
+	), nil
+}
+
+// \restore-from <backup> [table]
+// Replace the rows of a table with those of a backup taken by \backup, in a single transaction
+// The table is worked out from the backup's name when not given
+// Refused in safe mode, as every row of the table is deleted before the backup's are copied back
+func (db *DBClient) restoreFromCommand(argument string) (*QueryResult, error) {
+	usageError := errors.New(`Usage: \restore-from <backup> [table]`)
+
+	arguments := strings.Fields(strings.TrimSuffix(argument, ";"))
+	if len(arguments) == 0 || len(arguments) > 2 {
+		return nil, usageError
+	}
+
+	backupName := arguments[0]
+	var tableName string
+	if len(arguments) == 2 {
+		tableName = arguments[1]
+	} else if match := backupNameRegExp.FindStringSubmatch(backupName); match != nil {
+		tableName = match[1]
+	} else {
+		return nil, fmt.Errorf(`Can't tell which table %s is a backup of, pass it as well: \restore-from %s <table>`, backupName, backupName)
+	}
+
+	// MySQL would refuse the DELETE itself, and on Postgres checkSafeMode would, so refuse up front saying why
+	if db.connManager.IsSafeMode() {
+		return nil, fmt.Errorf(`Safe mode: \restore-from replaces every row of %s, turn safe mode off with \safe off first`, tableName)
+	}
+
+	columns, err := db.getRestorableColumns(backupName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	quotedColumns := make([]string, len(columns))
+	for idx, column := range columns {
+		quotedColumns[idx] = db.quoteIdentifier(column)
+	}
+	columnList := strings.Join(quotedColumns, ", ")
+
+	// Both are checked before either is run, so a refusal never leaves the table half restored
+	clearStatement, err := db.checkStatement(fmt.Sprint("DELETE FROM ", db.quoteIdentifier(tableName)))
+	if err != nil {
+		return nil, err
+	}
+	copyStatement, err := db.checkStatement(fmt.Sprintf(
+		"INSERT INTO %s (%s) SELECT %s FROM %s",
+		db.quoteIdentifier(tableName),
+		columnList,
+		columnList,
+		db.quoteIdentifier(backupName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// Within the sandbox, the sandbox's transaction is used so the restore can be rolled back with everything else
+	var transaction *sqlx.Tx
+	execer := sqlx.ExecerContext(connection)
+	if !db.InTransaction() {
+		transaction, err = connection.BeginTxx(db.ctx, nil)
+		if err != nil {
+			return nil, errors.Join(
+				errors.New("Failed to start transaction"),
+				err,
+			)
+		}
+		defer transaction.Rollback()
+
+		execer = transaction
+	}
+
+	db.resultCache.invalidate()
+
+	_, err = execer.ExecContext(db.ctx, clearStatement)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to clear %s, nothing was changed", tableName),
+			err,
+		)
+	}
+
+	insertResult, err := execer.ExecContext(db.ctx, copyStatement)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to copy rows back from %s, nothing was changed", backupName),
+			err,
+		)
+	}
+
+	if transaction != nil {
+		if err = transaction.Commit(); err != nil {
+			return nil, errors.Join(
+				errors.New("Failed to commit restore"),
+				err,
+			)
+		}
+	}
+
+	restoredRows, err := insertResult.RowsAffected()
+	if err != nil {
+		return newTextResult(
+			[]string{"Restore"},
+			[][]string{{fmt.Sprintf("Restored %s from %s", tableName, backupName)}},
+		), nil
+	}
+
+	return newTextResult(
+		[]string{"Restore"},
+		[][]string{{fmt.Sprintf("Restored %d rows of %s from %s", restoredRows, tableName, backupName)}},
+	), nil
+}
+
+// Columns found in both the backup and the table, so columns added since the backup keep their defaults
+// Generated columns are left out as they can't be inserted into, apart from unique ones such as auto increment keys
+// whose values other tables may reference
+func (db *DBClient) getRestorableColumns(backupName string, tableName string) ([]string, error) {
+	backup, err := db.GetTableSchema(backupName)
+	if err != nil {
+		return nil, err
+	}
+	table, err := db.GetTableSchema(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []string
+	for _, column := range table.Columns {
+		isInBackup := slices.ContainsFunc(backup.Columns, func(backupColumn ColumnSchema) bool {
+			return backupColumn.Name == column.Name
+		})
+
+		if isInBackup && (!column.Generated || isUniqueColumn(column, table.Indexes)) {
+			columns = append(columns, column.Name)
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%s and %s have no columns in common", backupName, tableName)
+	}
+
+	return columns, nil
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupRefusedInMySQLTransaction(t *testing.T) {
+	assert := assert.New(t)
+
+	dbClient, err := CreateDBClient(&fakeConnManager{flavor: conn.MySQL})
+	if !assert.NoError(err) {
+		return
+	}
+
+	// Only needs to look open, nothing is sent
+	dbClient.transactionConn = &sqlx.Conn{}
+
+	_, err = dbClient.Query(`\backup users`)
+	assert.ErrorContains(err, `\backup can't be taken inside a transaction on MySQL`)
+}
+
+func TestRestoreFromRefusedInSafeMode(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, flavor := range []conn.DBFlavor{conn.MySQL, conn.PostgreSQL} {
+		dbClient, err := CreateDBClient(&fakeConnManager{flavor: flavor, safeMode: true})
+		if !assert.NoError(err) {
+			return
+		}
+
+		_, err = dbClient.Query(`\restore-from users_backup_20240131`)
+		assert.EqualError(err, `Safe mode: \restore-from replaces every row of users, turn safe mode off with \safe off first`, flavor)
+	}
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// How many of the latest statements are kept for bug reports
+const recentStatementsKept = 50
+
+// Replaces string and number literals in statements kept for bug reports
+const redactedLiteral = "?"
+
+// A statement run this session, with its values hidden, see redactStatement
+type RecentStatement struct {
+	RanAt     time.Time     `json:"ran_at"`
+	Statement string        `json:"statement"`
+	Duration  time.Duration `json:"duration"`
+	// Empty when the statement succeeded
+	Error string `json:"error,omitempty"`
+}
+
+// The latest statements run, oldest first
+// Guarded by a mutex, as a report may be written while recovering from a crash on another goroutine
+type recentStatements struct {
+	mutex      sync.Mutex
+	statements []RecentStatement
+}
+
+// Writes the bundle \bugreport asks for, see bugreport.Reporter
+type BugReporter interface {
+	// Returns where the bundle was written
+	WriteReport(statements []RecentStatement) (path string, err error)
+}
+
+// Let \bugreport write a bundle for attaching to issues
+func (db *DBClient) SetBugReporter(reporter BugReporter) {
+	db.bugReporter = reporter
+}
+
+func (db *DBClient) recordRecentStatement(statement string, duration time.Duration, err error) {
+	recentStatement := RecentStatement{
+		RanAt:     time.Now().Add(-duration),
+		Statement: db.redactStatement(statement),
+		Duration:  duration,
+	}
+	if err != nil {
+		// Errors often quote the values the statement was given
+		recentStatement.Error = db.redactStatement(err.Error())
+	}
+
+	db.recentStatements.mutex.Lock()
+	defer db.recentStatements.mutex.Unlock()
+
+	db.recentStatements.statements = append(db.recentStatements.statements, recentStatement)
+	if overflow := len(db.recentStatements.statements) - recentStatementsKept; overflow > 0 {
+		db.recentStatements.statements = db.recentStatements.statements[overflow:]
+	}
+}
+
+// The latest statements run this session with their values hidden, oldest first
+func (db *DBClient) RecentStatements() []RecentStatement {
+	db.recentStatements.mutex.Lock()
+	defer db.recentStatements.mutex.Unlock()
+
+	statements := make([]RecentStatement, len(db.recentStatements.statements))
+	copy(statements, db.recentStatements.statements)
+
+	return statements
+}
+
+// Statement with string and number literals replaced by ? and comments left out, keeping its shape
+// Values such as emails or passwords in an INSERT can't end up in logs or bug reports, while table and column names do
+func (db *DBClient) redactStatement(statement string) string {
+	return redactStatement(db.connManager.GetFlavor(), statement)
+}
+
+func redactStatement(flavor conn.DBFlavor, statement string) string {
+	var redacted strings.Builder
+
+	for _, token := range lexer.Tokenize(flavor, statement) {
+		switch token.Kind {
+		case lexer.String, lexer.Number:
+			{
+				redacted.WriteString(redactedLiteral)
+			}
+		case lexer.Comment:
+			{
+			}
+		default:
+			{
+				redacted.WriteString(token.Text)
+			}
+		}
+	}
+
+	return redacted.String()
+}
+
+// \bugreport
+// Write version info, the config with secrets hidden, recent logs and the latest statements to a zip for an issue
+func (db *DBClient) bugReportCommand(string) (*QueryResult, error) {
+	if db.bugReporter == nil {
+		return nil, errors.New("Bug reports are not available")
+	}
+
+	path, err := db.bugReporter.WriteReport(db.RecentStatements())
+	if err != nil {
+		return nil, err
+	}
+
+	return newTextResult(
+		[]string{"Bug Report", "Contents"},
+		[][]string{{path, "Version, config without passwords, recent logs and statements without their values"}},
+	), nil
+}
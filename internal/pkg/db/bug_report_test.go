@@ -0,0 +1,94 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+// Keeps the statements it was asked to report, without writing anything
+type fakeBugReporter struct {
+	statements []RecentStatement
+}
+
+func (fake *fakeBugReporter) WriteReport(statements []RecentStatement) (string, error) {
+	fake.statements = statements
+	return "/tmp/sql-bugreport.zip", nil
+}
+
+func TestRedactStatement(t *testing.T) {
+	var tests = []struct {
+		Flavor            conn.DBFlavor
+		Statement         string
+		ExpectedStatement string
+	}{
+		{
+			conn.MySQL,
+			"INSERT INTO users (email, age) VALUES ('ada@example.com', 36)",
+			"INSERT INTO users (email, age) VALUES (?, ?)",
+		},
+		{
+			conn.PostgreSQL,
+			`SELECT "password" FROM users WHERE id = $1 -- token abc123`,
+			`SELECT "password" FROM users WHERE id = $1 `,
+		},
+		{
+			conn.PostgreSQL,
+			"ALTER USER app WITH PASSWORD $$hunter2$$",
+			"ALTER USER app WITH PASSWORD ?",
+		},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.ExpectedStatement, redactStatement(test.Flavor, test.Statement), test.Statement)
+	}
+}
+
+func TestBugReportCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	dbClient, err := CreateDBClient(&fakeConnManager{database: "test"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	_, err = dbClient.Query(`\bugreport`)
+	assert.ErrorContains(err, "Bug reports are not available")
+
+	reporter := &fakeBugReporter{}
+	dbClient.SetBugReporter(reporter)
+
+	// Fails as nothing is connected, which is kept along with the statement
+	_, err = dbClient.Query("SELECT * FROM users WHERE email = 'ada@example.com';")
+	assert.Error(err)
+
+	result, err := dbClient.Query(`\bugreport`)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal("/tmp/sql-bugreport.zip", result.Rows[0]["Bug Report"].ToString())
+
+	statements := make([]string, len(reporter.statements))
+	for idx, recentStatement := range reporter.statements {
+		statements[idx] = recentStatement.Statement
+	}
+	assert.Equal([]string{`\bugreport`, "SELECT * FROM users WHERE email = ?;"}, statements)
+	assert.NotEmpty(reporter.statements[1].Error)
+}
+
+func TestRecentStatementsKept(t *testing.T) {
+	dbClient, err := CreateDBClient(&fakeConnManager{database: "test"})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	for idx := range recentStatementsKept + 5 {
+		dbClient.recordRecentStatement(fmt.Sprintf("SELECT %d AS query%d", idx, idx), 0, nil)
+	}
+
+	statements := dbClient.RecentStatements()
+	assert.Len(t, statements, recentStatementsKept)
+	assert.Equal(t, "SELECT ? AS query5", statements[0].Statement)
+}
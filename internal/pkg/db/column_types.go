@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"slices"
+)
+
+// What's known about a result column beyond its name
+type ColumnType struct {
+	// As the database names it, ex: VARCHAR or INT4, empty when the driver doesn't report it
+	DatabaseType string
+	// Nil when neither the driver nor the schema says whether the column can be NULL
+	Nullable *bool
+	// Table the column was selected from, empty when it can't be derived from the statement
+	Table string
+}
+
+func getColumnTypes(sqlColumnTypes []*sql.ColumnType) []ColumnType {
+	columnTypes := make([]ColumnType, len(sqlColumnTypes))
+
+	for idx, sqlColumnType := range sqlColumnTypes {
+		columnTypes[idx].DatabaseType = sqlColumnType.DatabaseTypeName()
+
+		if nullable, isReported := sqlColumnType.Nullable(); isReported {
+			columnTypes[idx].Nullable = &nullable
+		}
+	}
+
+	return columnTypes
+}
+
+// Schema to fill in the source table of each column from, and nullability the driver didn't report
+// Only single table SELECTs are considered, as otherwise which table a column came from is ambiguous
+// Loaded before the statement runs, as the connection is busy while its rows are being read
+func (db *DBClient) getColumnSourceSchema(statement string) (schema *Schema, tableName string) {
+	tableName, isSingleTable := getSingleTableSelectTable(statement)
+	if !isSingleTable {
+		return nil, ""
+	}
+
+	schema, err := db.GetSchema()
+	if err != nil {
+		return nil, ""
+	}
+
+	return schema, tableName
+}
+
+func addColumnSources(schema *Schema, tableName string, result *QueryResult) {
+	if schema == nil || len(result.ColumnTypes) != len(result.Columns) {
+		return
+	}
+
+	table, exists := schema.Tables[tableName]
+	if !exists {
+		return
+	}
+
+	for idx, columnName := range result.Columns {
+		columnIdx := slices.IndexFunc(table.Columns, func(column ColumnSchema) bool {
+			return column.Name == columnName
+		})
+		if columnIdx == -1 {
+			// An expression or alias rather than a column of the table
+			continue
+		}
+
+		columnType := &result.ColumnTypes[idx]
+		columnType.Table = tableName
+		if columnType.Nullable == nil {
+			nullable := table.Columns[columnIdx].Nullable
+			columnType.Nullable = &nullable
+		}
+	}
+}
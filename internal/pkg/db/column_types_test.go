@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddColumnSources(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := &Schema{
+		Tables: map[string]*TableSchema{
+			"users": {
+				Columns: []ColumnSchema{
+					{Name: "id", Type: "bigint", Nullable: false},
+					{Name: "email", Type: "varchar(255)", Nullable: true},
+				},
+			},
+		},
+	}
+
+	reportedNullable := false
+	result := &QueryResult{
+		Columns: []string{"id", "email", "email_count"},
+		ColumnTypes: []ColumnType{
+			{DatabaseType: "BIGINT", Nullable: &reportedNullable},
+			{DatabaseType: "VARCHAR"},
+			{DatabaseType: "BIGINT"},
+		},
+	}
+
+	addColumnSources(schema, "users", result)
+
+	assert.Equal("users", result.ColumnTypes[0].Table)
+	assert.Same(&reportedNullable, result.ColumnTypes[0].Nullable, "nullability reported by the driver is kept")
+
+	assert.Equal("users", result.ColumnTypes[1].Table)
+	if assert.NotNil(result.ColumnTypes[1].Nullable) {
+		assert.True(*result.ColumnTypes[1].Nullable, "nullability falls back to the schema")
+	}
+
+	assert.Equal("", result.ColumnTypes[2].Table, "expressions have no source table")
+	assert.Nil(result.ColumnTypes[2].Nullable)
+
+	// Unknown tables leave the result as is
+	otherResult := &QueryResult{Columns: []string{"id"}, ColumnTypes: []ColumnType{{DatabaseType: "INT"}}}
+	addColumnSources(schema, "orders", otherResult)
+	assert.Equal("", otherResult.ColumnTypes[0].Table)
+}
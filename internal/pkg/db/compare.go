@@ -0,0 +1,320 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Rows per chunk, tables are hashed a chunk at a time so differences can be narrowed down to a key range
+const compareChunkSize = 1000
+
+// Hash and row count for one key range of a table
+type compareChunk struct {
+	Chunk    int    `db:"chunk"`
+	RowCount int    `db:"row_count"`
+	Hash     string `db:"chunk_hash"`
+}
+
+const postgresCompareColumnsQuery string = `
+SELECT column_name
+FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema())
+AND table_name = $2
+ORDER BY ordinal_position
+`
+
+const postgresComparePrimaryKeyQuery string = `
+SELECT a.attname
+FROM pg_index ix
+JOIN pg_class c ON c.oid = ix.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = ix.indkey[0]
+WHERE ix.indisprimary
+AND ix.indnkeyatts = 1
+AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+AND c.relname = $2
+`
+
+const mySQLCompareColumnsQuery string = `
+SELECT COLUMN_NAME
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+AND TABLE_NAME = ?
+ORDER BY ORDINAL_POSITION
+`
+
+const mySQLComparePrimaryKeyQuery string = `
+SELECT MAX(COLUMN_NAME)
+FROM information_schema.STATISTICS
+WHERE INDEX_NAME = 'PRIMARY'
+AND TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+AND TABLE_NAME = ?
+HAVING COUNT(*) = 1
+`
+
+// \compare <table a> <table b> [key]
+// Hash both tables in chunks of key ranges, reporting the ranges where their rows differ
+// Key defaults to the primary key of table a, which must be a single column
+func (db *DBClient) compareCommand(argument string) (*QueryResult, error) {
+	arguments := strings.Fields(strings.TrimSuffix(argument, ";"))
+	if len(arguments) < 2 || len(arguments) > 3 {
+		return nil, errors.New(`Usage: \compare <table a> <table b> [key]`)
+	}
+	tableA, tableB := arguments[0], arguments[1]
+
+	var columnsQuery, primaryKeyQuery string
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			columnsQuery, primaryKeyQuery = postgresCompareColumnsQuery, postgresComparePrimaryKeyQuery
+		}
+	case conn.MySQL:
+		{
+			columnsQuery, primaryKeyQuery = mySQLCompareColumnsQuery, mySQLComparePrimaryKeyQuery
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\compare`, db.connManager.GetFlavor())
+		}
+	}
+
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	tableASchema, tableAName := splitQualifiedName(tableA)
+
+	// Rows from both tables are hashed using the columns of table a
+	var columns []string
+	err = connection.SelectContext(db.ctx, &columns, columnsQuery, tableASchema, tableAName)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("Table %s does not exist", tableA)
+	}
+
+	var key string
+	if len(arguments) == 3 {
+		key = arguments[2]
+	} else {
+		var primaryKeys []string
+		err = connection.SelectContext(db.ctx, &primaryKeys, primaryKeyQuery, tableASchema, tableAName)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(primaryKeys) != 1 {
+			return nil, fmt.Errorf("%s has no single column primary key, specify a key to compare by", tableA)
+		}
+		key = primaryKeys[0]
+	}
+
+	boundaries, err := db.getCompareBoundaries(tableA, key)
+	if err != nil {
+		return nil, err
+	}
+
+	chunksA, err := db.getCompareChunks(tableA, tableA, key, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	chunksB, err := db.getCompareChunks(tableA, tableB, key, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := buildCompareRows(key, boundaries, chunksA, chunksB)
+	if len(rows) == 0 {
+		totalRows := 0
+		for _, chunk := range chunksA {
+			totalRows += chunk.RowCount
+		}
+
+		return newTextResult(
+			[]string{"Compare"},
+			[][]string{{fmt.Sprintf("%s and %s match, %d rows compared", tableA, tableB, totalRows)}},
+		), nil
+	}
+
+	return newTextResult(
+		[]string{"Range", fmt.Sprint("Rows in ", tableA), fmt.Sprint("Rows in ", tableB)},
+		rows,
+	), nil
+}
+
+// Split schema.table, schema is empty when not given
+func splitQualifiedName(name string) (schema string, table string) {
+	schema, table, isQualified := strings.Cut(name, ".")
+	if !isQualified {
+		return "", name
+	}
+
+	return schema, table
+}
+
+// Quote each part of a possibly schema qualified name
+func (db *DBClient) quoteQualifiedName(name string) string {
+	schema, table := splitQualifiedName(name)
+	if schema == "" {
+		return db.quoteIdentifier(table)
+	}
+
+	return fmt.Sprint(db.quoteIdentifier(schema), ".", db.quoteIdentifier(table))
+}
+
+// Cast an expression to text, as CHAR means a single character in Postgres
+func (db *DBClient) castToText(expression string) string {
+	if db.connManager.GetFlavor() == conn.MySQL {
+		return fmt.Sprintf("CAST(%s AS CHAR)", expression)
+	}
+
+	return fmt.Sprintf("CAST(%s AS TEXT)", expression)
+}
+
+// Every chunk starts at a key taken from table a, numbered from 1
+// Shared between the boundaries and chunks queries so both agree on where chunks start
+func (db *DBClient) buildCompareBoundariesCTE(boundaryTable string, key string) string {
+	return fmt.Sprintf(`
+WITH boundary_keys AS (
+  SELECT %[1]s AS boundary_key, ROW_NUMBER() OVER (ORDER BY %[1]s) AS rn
+  FROM %[2]s
+),
+boundaries AS (
+  SELECT boundary_key, ROW_NUMBER() OVER (ORDER BY boundary_key) AS chunk
+  FROM boundary_keys
+  WHERE MOD(rn - 1, %[3]d) = 0
+)`,
+		db.quoteIdentifier(key),
+		db.quoteQualifiedName(boundaryTable),
+		compareChunkSize,
+	)
+}
+
+func (db *DBClient) getCompareBoundaries(boundaryTable string, key string) (boundaries []string, err error) {
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(
+		"%s\nSELECT %s FROM boundaries ORDER BY chunk",
+		db.buildCompareBoundariesCTE(boundaryTable, key),
+		db.castToText("boundary_key"),
+	)
+
+	err = connection.SelectContext(db.ctx, &boundaries, query)
+	return boundaries, err
+}
+
+// Row count and hash of each chunk of table, chunks being determined by boundaryTable
+// Rows with a key before the first boundary fall in chunk 0
+func (db *DBClient) getCompareChunks(boundaryTable string, table string, key string, columns []string) (chunks []compareChunk, err error) {
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// Sum the first 60 bits of each row's hash, so the result doesn't depend on row order
+	var rowHash string
+	switch db.connManager.GetFlavor() {
+	case conn.MySQL:
+		{
+			quotedColumns := make([]string, len(columns))
+			for idx, column := range columns {
+				quotedColumns[idx] = fmt.Sprintf(`COALESCE(CAST(t.%s AS CHAR), '\\N')`, db.quoteIdentifier(column))
+			}
+
+			rowHash = fmt.Sprintf(
+				"CAST(CONV(SUBSTRING(MD5(CONCAT_WS('|', %s)), 1, 15), 16, 10) AS UNSIGNED)",
+				strings.Join(quotedColumns, ", "),
+			)
+		}
+	default:
+		{
+			quotedColumns := make([]string, len(columns))
+			for idx, column := range columns {
+				quotedColumns[idx] = fmt.Sprint("t.", db.quoteIdentifier(column))
+			}
+
+			rowHash = fmt.Sprintf(
+				"('x' || substr(md5(ROW(%s)::text), 1, 15))::bit(60)::bigint",
+				strings.Join(quotedColumns, ", "),
+			)
+		}
+	}
+
+	query := fmt.Sprintf(`%s
+SELECT chunk, COUNT(*) AS row_count, %s AS chunk_hash
+FROM (
+  SELECT
+    COALESCE((SELECT MAX(chunk) FROM boundaries WHERE boundary_key <= t.%s), 0) AS chunk,
+    %s AS row_hash
+  FROM %s t
+) hashed
+GROUP BY chunk
+ORDER BY chunk`,
+		db.buildCompareBoundariesCTE(boundaryTable, key),
+		db.castToText("SUM(row_hash)"),
+		db.quoteIdentifier(key),
+		rowHash,
+		db.quoteQualifiedName(table),
+	)
+
+	err = connection.SelectContext(db.ctx, &chunks, query)
+	return chunks, err
+}
+
+// One row per key range where the tables differ
+func buildCompareRows(key string, boundaries []string, chunksA []compareChunk, chunksB []compareChunk) (rows [][]string) {
+	// Table a is empty, so everything in table b is a difference
+	if len(boundaries) == 0 {
+		if len(chunksB) > 0 {
+			rows = append(rows, []string{"all", "0", fmt.Sprint(chunksB[0].RowCount)})
+		}
+
+		return rows
+	}
+
+	chunksAByIdx := make(map[int]compareChunk, len(chunksA))
+	for _, chunk := range chunksA {
+		chunksAByIdx[chunk.Chunk] = chunk
+	}
+
+	chunksBByIdx := make(map[int]compareChunk, len(chunksB))
+	for _, chunk := range chunksB {
+		chunksBByIdx[chunk.Chunk] = chunk
+	}
+
+	for chunkIdx := 0; chunkIdx <= len(boundaries); chunkIdx++ {
+		chunkA, chunkB := chunksAByIdx[chunkIdx], chunksBByIdx[chunkIdx]
+		if chunkA.RowCount == chunkB.RowCount && chunkA.Hash == chunkB.Hash {
+			continue
+		}
+
+		var keyRange string
+		switch {
+		case chunkIdx == 0:
+			{
+				keyRange = fmt.Sprintf("%s < %s", key, boundaries[0])
+			}
+		case chunkIdx == len(boundaries):
+			{
+				keyRange = fmt.Sprintf("%s >= %s", key, boundaries[chunkIdx-1])
+			}
+		default:
+			{
+				keyRange = fmt.Sprintf("%s >= %s AND %s < %s", key, boundaries[chunkIdx-1], key, boundaries[chunkIdx])
+			}
+		}
+
+		rows = append(rows, []string{keyRange, fmt.Sprint(chunkA.RowCount), fmt.Sprint(chunkB.RowCount)})
+	}
+
+	return rows
+}
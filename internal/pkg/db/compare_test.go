@@ -0,0 +1,63 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCompareRows(t *testing.T) {
+	var tests = []struct {
+		Name         string
+		Boundaries   []string
+		ChunksA      []compareChunk
+		ChunksB      []compareChunk
+		ExpectedRows [][]string
+	}{
+		{
+			Name:       "Identical",
+			Boundaries: []string{"1", "1001"},
+			ChunksA:    []compareChunk{{1, 1000, "123"}, {2, 5, "456"}},
+			ChunksB:    []compareChunk{{1, 1000, "123"}, {2, 5, "456"}},
+		},
+		{
+			Name:       "Changed Row",
+			Boundaries: []string{"1", "1001", "2001"},
+			ChunksA:    []compareChunk{{1, 1000, "123"}, {2, 1000, "456"}, {3, 5, "789"}},
+			ChunksB:    []compareChunk{{1, 1000, "123"}, {2, 1000, "457"}, {3, 5, "789"}},
+			ExpectedRows: [][]string{
+				{"id >= 1001 AND id < 2001", "1000", "1000"},
+			},
+		},
+		{
+			Name:       "Extra Rows Outside Boundaries",
+			Boundaries: []string{"10", "1010"},
+			ChunksA:    []compareChunk{{1, 1000, "123"}, {2, 5, "456"}},
+			ChunksB:    []compareChunk{{0, 2, "12"}, {1, 1000, "123"}, {2, 6, "999"}},
+			ExpectedRows: [][]string{
+				{"id < 10", "0", "2"},
+				{"id >= 1010", "5", "6"},
+			},
+		},
+		{
+			Name:         "Empty Table A",
+			ChunksB:      []compareChunk{{0, 3, "12"}},
+			ExpectedRows: [][]string{{"all", "0", "3"}},
+		},
+		{
+			Name: "Both Empty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			assert.Equal(
+				test.ExpectedRows,
+				buildCompareRows("id", test.Boundaries, test.ChunksA, test.ChunksB),
+			)
+		})
+	}
+}
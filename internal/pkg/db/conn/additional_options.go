@@ -0,0 +1,210 @@
+package conn
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Options understood by go-sql-driver/mysql, along with common session variables it sets on connect
+// See https://github.com/go-sql-driver/mysql#parameters
+var mySQLAdditionalOptions = []string{
+	"allowAllFiles", "allowCleartextPasswords", "allowFallbackToPlaintext", "allowNativePasswords",
+	"allowOldPasswords", "charset", "checkConnLiveness", "clientFoundRows", "collation",
+	"columnsWithAlias", "connectionAttributes", "interpolateParams", "loc", "maxAllowedPacket",
+	"multiStatements", "parseTime", "readTimeout", "rejectReadOnly", "serverPubKey", "timeTruncate",
+	"timeout", "tls", "writeTimeout",
+	// Session variables
+	"autocommit", "foreign_key_checks", "max_execution_time", "net_read_timeout", "net_write_timeout",
+	"sql_mode", "sql_safe_updates", "time_zone", "transaction_isolation", "unique_checks", "wait_timeout",
+}
+
+// Connection parameters understood by libpq and pgx, along with common run-time settings pgx sets on connect
+// See https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-PARAMKEYWORDS
+var postgreSQLAdditionalOptions = []string{
+	"application_name", "channel_binding", "client_encoding", "connect_timeout",
+	"fallback_application_name", "gssencmode", "gsslib", "hostaddr", "keepalives", "keepalives_count",
+	"keepalives_idle", "keepalives_interval", "krbsrvname", "load_balance_hosts", "options", "passfile",
+	"requirepeer", "requiressl", "service", "servicefile", "ssl_max_protocol_version",
+	"ssl_min_protocol_version", "sslcert", "sslcompression", "sslcrl", "sslkey", "sslmode",
+	"sslnegotiation", "sslpassword", "sslrootcert", "sslsni", "target_session_attrs", "tcp_user_timeout",
+	// pgx specific
+	"default_query_exec_mode", "description_cache_capacity", "min_read_buffer_size", "statement_cache_capacity",
+	// Run-time settings
+	"datestyle", "idle_in_transaction_session_timeout", "lock_timeout", "role", "search_path",
+	"statement_timeout", "timezone", "work_mem",
+}
+
+func getKnownAdditionalOptions(flavor DBFlavor) []string {
+	switch flavor {
+	case MySQL:
+		{
+			return mySQLAdditionalOptions
+		}
+	case PostgreSQL:
+		{
+			return postgreSQLAdditionalOptions
+		}
+	default:
+		{
+			return nil
+		}
+	}
+}
+
+// Parse options in the format key=value,key2=value2
+// Values containing commas or starting with = can be wrapped in double quotes, ex: sql_mode="ANSI,TRADITIONAL"
+// A key without a value is left with an empty value, to be interpreted as true
+func ParseAdditionalOptions(rawOptions string) (map[string]string, error) {
+	options := map[string]string{}
+
+	for idx, rawOption := range splitAdditionalOptions(rawOptions) {
+		if strings.TrimSpace(rawOption) == "" {
+			return nil, fmt.Errorf("Additional option %d is empty, check for a stray comma", idx+1)
+		}
+
+		key, value, _ := strings.Cut(rawOption, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("Additional option %s is missing a name", rawOption)
+		}
+
+		if strings.HasPrefix(value, "=") {
+			return nil, fmt.Errorf("Additional option %s has a value starting with =, wrap the value in double quotes if intended", rawOption)
+		}
+
+		if unquotedValue, isQuoted := strings.CutPrefix(value, `"`); isQuoted {
+			unquotedValue, isTerminated := strings.CutSuffix(unquotedValue, `"`)
+			if !isTerminated {
+				return nil, fmt.Errorf("Additional option %s has an unterminated quote", key)
+			}
+
+			value = unquotedValue
+		}
+
+		options[key] = value
+	}
+
+	return options, nil
+}
+
+// Split on commas which aren't inside double quotes
+func splitAdditionalOptions(rawOptions string) (rawOptionList []string) {
+	var current strings.Builder
+	inQuotes := false
+
+	for _, char := range rawOptions {
+		switch {
+		case char == '"':
+			{
+				inQuotes = !inQuotes
+				current.WriteRune(char)
+			}
+		case char == ',' && !inQuotes:
+			{
+				rawOptionList = append(rawOptionList, current.String())
+				current.Reset()
+			}
+		default:
+			{
+				current.WriteRune(char)
+			}
+		}
+	}
+
+	return append(rawOptionList, current.String())
+}
+
+// Warnings for additional options the driver for the flavor isn't known to understand, suggesting similar ones
+// Unknown options still work, MySQL sets them as session variables and Postgres as run-time settings, so they're not refused
+func (connOptions *DSNOptions) GetAdditionalOptionNotices() []string {
+	knownOptions := getKnownAdditionalOptions(connOptions.Flavor)
+	if knownOptions == nil {
+		return nil
+	}
+
+	var notices []string
+	for key := range connOptions.AdditionalOptions {
+		isKnown := slices.ContainsFunc(knownOptions, func(knownOption string) bool {
+			return strings.EqualFold(knownOption, key)
+		})
+		if isKnown {
+			continue
+		}
+
+		notice := fmt.Sprintf("Unknown additional option %s for %s", key, connOptions.Flavor.Name())
+		if suggestions := suggestAdditionalOptions(key, knownOptions); len(suggestions) > 0 {
+			notice = fmt.Sprintf("%s, did you mean %s? It's passed to the driver as is", notice, strings.Join(suggestions, " or "))
+		} else {
+			notice = fmt.Sprint(notice, ", it's passed to the driver as is")
+		}
+
+		notices = append(notices, notice)
+	}
+
+	// Sorted so the output is stable, as the options come from a map
+	slices.Sort(notices)
+
+	return notices
+}
+
+// Up to 3 known options closest to the given one, ignoring case
+func suggestAdditionalOptions(option string, knownOptions []string) []string {
+	type suggestion struct {
+		option   string
+		distance int
+	}
+
+	lowerOption := strings.ToLower(option)
+	maxDistance := max(2, len(option)/3)
+
+	var suggestions []suggestion
+	for _, knownOption := range knownOptions {
+		distance := levenshteinDistance(lowerOption, strings.ToLower(knownOption))
+		if distance <= maxDistance {
+			suggestions = append(suggestions, suggestion{knownOption, distance})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a suggestion, b suggestion) int {
+		return a.distance - b.distance
+	})
+
+	suggestedOptions := []string{}
+	for _, suggestion := range suggestions[:min(len(suggestions), 3)] {
+		suggestedOptions = append(suggestedOptions, suggestion.option)
+	}
+
+	return suggestedOptions
+}
+
+// Number of single character insertions, deletions or substitutions to turn a into b
+func levenshteinDistance(a string, b string) int {
+	previousRow := make([]int, len(b)+1)
+	currentRow := make([]int, len(b)+1)
+
+	for idx := range previousRow {
+		previousRow[idx] = idx
+	}
+
+	for aIdx := 1; aIdx <= len(a); aIdx++ {
+		currentRow[0] = aIdx
+
+		for bIdx := 1; bIdx <= len(b); bIdx++ {
+			substitutionCost := 1
+			if a[aIdx-1] == b[bIdx-1] {
+				substitutionCost = 0
+			}
+
+			currentRow[bIdx] = min(
+				previousRow[bIdx]+1,
+				currentRow[bIdx-1]+1,
+				previousRow[bIdx-1]+substitutionCost,
+			)
+		}
+
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	return previousRow[len(b)]
+}
@@ -0,0 +1,127 @@
+package conn_test
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAdditionalOptions(t *testing.T) {
+	var tests = []struct {
+		Name            string
+		RawOptions      string
+		ExpectedOptions map[string]string
+		ExpectedError   string
+	}{
+		{
+			Name:            "Key Value Pairs",
+			RawOptions:      "tls=true,parseTime,timeout=5s",
+			ExpectedOptions: map[string]string{"tls": "true", "parseTime": "", "timeout": "5s"},
+		},
+		{
+			Name:            "Quoted Value With Commas",
+			RawOptions:      `sql_mode="ANSI,TRADITIONAL",tls=true`,
+			ExpectedOptions: map[string]string{"sql_mode": "ANSI,TRADITIONAL", "tls": "true"},
+		},
+		{
+			Name:            "Value Containing Equals",
+			RawOptions:      `options=-csearch_path=app`,
+			ExpectedOptions: map[string]string{"options": "-csearch_path=app"},
+		},
+		{
+			Name:          "Double Equals",
+			RawOptions:    "foo==bar",
+			ExpectedError: "value starting with =",
+		},
+		{
+			Name:          "Stray Comma",
+			RawOptions:    "tls=true,,parseTime",
+			ExpectedError: "Additional option 2 is empty",
+		},
+		{
+			Name:          "Missing Name",
+			RawOptions:    "=true",
+			ExpectedError: "missing a name",
+		},
+		{
+			Name:          "Unterminated Quote",
+			RawOptions:    `sql_mode="ANSI,tls=true`,
+			ExpectedError: "unterminated quote",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			options, err := conn.ParseAdditionalOptions(test.RawOptions)
+			if test.ExpectedError != "" {
+				assert.ErrorContains(err, test.ExpectedError)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.ExpectedOptions, options)
+		})
+	}
+}
+
+func TestGetAdditionalOptionNotices(t *testing.T) {
+	var tests = []struct {
+		Name              string
+		Flavor            conn.DBFlavor
+		AdditionalOptions map[string]string
+		ExpectedNotices   []string
+	}{
+		{
+			Name:              "Known MySQL Options",
+			Flavor:            conn.MySQL,
+			AdditionalOptions: map[string]string{"tls": "preferred", "parseTime": "", "sql_mode": "ANSI"},
+		},
+		{
+			Name:              "Known PostgreSQL Options",
+			Flavor:            conn.PostgreSQL,
+			AdditionalOptions: map[string]string{"sslmode": "disable", "search_path": "app"},
+		},
+		{
+			Name:              "Known Options In Other Case",
+			Flavor:            conn.PostgreSQL,
+			AdditionalOptions: map[string]string{"TimeZone": "UTC", "DateStyle": "ISO"},
+		},
+		{
+			Name:              "Misspelled Option",
+			Flavor:            conn.MySQL,
+			AdditionalOptions: map[string]string{"parseTimes": "true"},
+			ExpectedNotices: []string{
+				"Unknown additional option parseTimes for MySQL, did you mean parseTime? It's passed to the driver as is",
+			},
+		},
+		{
+			Name:              "Session Variable",
+			Flavor:            conn.MySQL,
+			AdditionalOptions: map[string]string{"innodb_lock_wait_timeout": "5"},
+			ExpectedNotices: []string{
+				"Unknown additional option innodb_lock_wait_timeout for MySQL, it's passed to the driver as is",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			connOptions := conn.DSNOptions{
+				Flavor:            test.Flavor,
+				AdditionalOptions: test.AdditionalOptions,
+			}
+
+			assert.NoError(connOptions.Validate(), "unknown options should not be refused")
+			assert.Equal(test.ExpectedNotices, connOptions.GetAdditionalOptionNotices())
+		})
+	}
+}
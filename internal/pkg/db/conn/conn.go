@@ -3,16 +3,51 @@ package conn
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// Returned, joined with the cause, when the database can't be reached
+var ErrConnectionFailed = errors.New("Failed to establish connection to database")
+
+// What DBClient needs from a connection, so it can be given a fake in tests or wrapped by another backend
+// Implemented by ConnectionManager
+type Manager interface {
+	// The single connection statements run on, reconnecting if it was dropped
+	GetConnection() (*sqlx.Conn, error)
+	GetFlavor() DBFlavor
+	GetConnectionInfo() ConnectionInfo
+	// Connect to another database on the same server
+	UseDatabase(databaseName string) error
+	// Change the Postgres search_path, kept when reconnecting
+	SetSearchPath(searchPath string) error
+	// Whether updates and deletes without a WHERE clause are refused
+	IsSafeMode() bool
+	// Turn safe mode on or off for the rest of the session, kept when reconnecting
+	SetSafeMode(enabled bool) error
+	// Rows still being read, closed once the connection is needed for something else
+	SetOpenRows(rows io.Closer)
+	CloseOpenRows()
+	Destroy()
+}
+
+var _ Manager = (*ConnectionManager)(nil)
+
 type ConnectionManager struct {
 	sqlDB      *sqlx.DB
 	conn       *sqlx.Conn
 	dsnManager DSNManager
 	ctx        context.Context
+	// Postgres search_path set for the session, reapplied to new connections so reconnecting keeps it
+	searchPath string
+	// Starts as given when connecting, and can be toggled for the session
+	safeMode bool
+	// Rows still being read, which keep the connection busy until closed
+	openRows io.Closer
 }
 
 func CreateConnectionManager(
@@ -29,10 +64,52 @@ func CreateConnectionManager(
 		conn:       nil,
 		dsnManager: dsnManager,
 		ctx:        ctx,
+		safeMode:   dsnManager.IsSafeMode(),
+	}, nil
+}
+
+// Same as CreateConnectionManager, without checking the database can be reached
+// Statements fail until it can be, which lets the application start while offline
+func OpenConnectionManager(
+	dsnManager DSNManager,
+	ctx context.Context,
+) (*ConnectionManager, error) {
+	sqlDB, err := openDB(dsnManager)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConnectionManager{
+		sqlDB:      sqlDB,
+		conn:       nil,
+		dsnManager: dsnManager,
+		ctx:        ctx,
+		safeMode:   dsnManager.IsSafeMode(),
 	}, nil
 }
 
 func createDB(dsnManager DSNManager) (*sqlx.DB, error) {
+	sqlDB, err := openDB(dsnManager)
+	if err != nil {
+		return nil, err
+	}
+
+	connectionInfo := dsnManager.GetConnectionInfo()
+	logger := slog.With("flavor", connectionInfo.Flavor, "connection", connectionInfo.Label())
+	logger.Debug("Connecting")
+
+	err = sqlDB.Ping()
+	if err != nil {
+		logger.Warn("Failed to connect", "err", err)
+		sqlDB.Close()
+		return nil, errors.Join(ErrConnectionFailed, err)
+	}
+
+	logger.Info("Connected")
+	return sqlDB, nil
+}
+
+func openDB(dsnManager DSNManager) (*sqlx.DB, error) {
 	dataSourceName, err := dsnManager.GetDSN()
 	if err != nil {
 		return nil, errors.Join(
@@ -49,14 +126,6 @@ func createDB(dsnManager DSNManager) (*sqlx.DB, error) {
 		)
 	}
 
-	err = sqlDB.Ping()
-	if err != nil {
-		return nil, errors.Join(
-			errors.New("Failed to establish connection to database"),
-			err,
-		)
-	}
-
 	// Keep connections alive for 5 mins
 	sqlDB.SetConnMaxLifetime(time.Minute * 5)
 
@@ -70,23 +139,40 @@ func createDB(dsnManager DSNManager) (*sqlx.DB, error) {
 func (connManager *ConnectionManager) Destroy() {
 	// Cleanup database resources
 	// Call before this struct drops out of scope
+	connManager.CloseOpenRows()
+
 	// This only returns an error if the connection is already closed, safe to ignore
-	_ = connManager.conn.Close()
+	// Never connected when started offline
+	if connManager.conn != nil {
+		_ = connManager.conn.Close()
+	}
 	_ = connManager.sqlDB.Close()
 
 	connManager.sqlDB = nil
 	connManager.conn = nil
+
+	slog.Debug("Closed connection")
 }
 
 func (connManager *ConnectionManager) GetFlavor() DBFlavor {
 	return connManager.dsnManager.GetFlavor()
 }
 
+func (connManager *ConnectionManager) GetConnectionInfo() ConnectionInfo {
+	return connManager.dsnManager.GetConnectionInfo()
+}
+
+// Connect to another database on the same server, staying connected to the current one if that fails
 func (connManager *ConnectionManager) UseDatabase(databaseName string) error {
+	previousDatabase := connManager.dsnManager.GetConnectionInfo().Database
 	connManager.dsnManager.SetDatabase(databaseName)
 
 	newDB, err := createDB(connManager.dsnManager)
 	if err != nil {
+		slog.Warn("Failed to switch database", "from", previousDatabase, "to", databaseName, "err", err)
+		// Reconnecting should still reach the current database
+		connManager.dsnManager.SetDatabase(previousDatabase)
+
 		return errors.Join(
 			errors.New("Failed to switch database"),
 			err,
@@ -96,38 +182,115 @@ func (connManager *ConnectionManager) UseDatabase(databaseName string) error {
 	// Once we have succesfully connected to new database, cleanup the old instance
 	connManager.Destroy()
 	connManager.sqlDB = newDB
+	// Schemas of the previous database are unlikely to exist in the new one
+	connManager.searchPath = ""
 
+	slog.Info("Switched database", "from", previousDatabase, "to", databaseName)
 	return nil
 }
 
 // We try to use a single connection, instantiated when DBClient is instantiated
 // This will either return that existing connection, or create a new one if that got dropped
 func (connManager *ConnectionManager) GetConnection() (*sqlx.Conn, error) {
+	// Nothing else can run on the connection while rows are being read from it
+	connManager.CloseOpenRows()
+
 	if connManager.conn != nil {
 		// See if our existing connection is still alive
 		err := connManager.conn.PingContext(connManager.ctx)
 		if err == nil {
 			return connManager.conn, nil
 		}
+		slog.Info("Connection dropped, reconnecting", "err", err)
 		connManager.conn.Close()
 	}
 
 	conn, err := connManager.sqlDB.Connx(connManager.ctx)
 
 	if err != nil {
-		return nil, errors.Join(
-			errors.New("Failed to get connection to database"),
-			err,
-		)
+		slog.Warn("Failed to reconnect", "err", err)
+		return nil, errors.Join(ErrConnectionFailed, err)
 	}
+	slog.Debug("Opened connection", "safe_mode", connManager.safeMode, "search_path", connManager.searchPath)
 
-	if connManager.dsnManager.IsSafeMode() {
+	// Postgres has no equivalent, DBClient guards against unbounded updates itself
+	if connManager.safeMode && connManager.GetFlavor() == MySQL {
 		_, err = conn.ExecContext(connManager.ctx, "SET SQL_SAFE_UPDATES = 1")
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if connManager.searchPath != "" {
+		_, err = conn.ExecContext(connManager.ctx, fmt.Sprint("SET search_path TO ", connManager.searchPath))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	connManager.conn = conn
 	return connManager.conn, nil
 }
+
+// Leave rows open for reading after the statement returns, such as for a result read a page at a time
+// They're closed as soon as the connection is needed for something else
+func (connManager *ConnectionManager) SetOpenRows(rows io.Closer) {
+	connManager.CloseOpenRows()
+	connManager.openRows = rows
+}
+
+// Close rows left open with SetOpenRows, if any
+func (connManager *ConnectionManager) CloseOpenRows() {
+	if connManager.openRows != nil {
+		_ = connManager.openRows.Close()
+		connManager.openRows = nil
+	}
+}
+
+// Change the Postgres search_path for the rest of the session
+// searchPath is a list of already quoted schemas, ex: sales, public
+func (connManager *ConnectionManager) SetSearchPath(searchPath string) error {
+	conn, err := connManager.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(connManager.ctx, fmt.Sprint("SET search_path TO ", searchPath))
+	if err != nil {
+		return err
+	}
+
+	connManager.searchPath = searchPath
+	return nil
+}
+
+func (connManager *ConnectionManager) IsSafeMode() bool {
+	return connManager.safeMode
+}
+
+// Turn safe mode on or off for the rest of the session
+// On MySQL this sets SQL_SAFE_UPDATES on the current connection, later connections get it as they're made
+func (connManager *ConnectionManager) SetSafeMode(enabled bool) error {
+	if connManager.GetFlavor() == MySQL {
+		conn, err := connManager.GetConnection()
+		if err != nil {
+			return err
+		}
+
+		safeUpdates := 0
+		if enabled {
+			safeUpdates = 1
+		}
+
+		_, err = conn.ExecContext(connManager.ctx, fmt.Sprint("SET SQL_SAFE_UPDATES = ", safeUpdates))
+		if err != nil {
+			return errors.Join(
+				errors.New("Failed to change safe mode"),
+				err,
+			)
+		}
+	}
+
+	connManager.safeMode = enabled
+	return nil
+}
@@ -25,11 +25,58 @@ func (flavor *DBFlavor) isValid() bool {
 	return true
 }
 
+// Human friendly name of the flavor, ex: PostgreSQL rather than the driver name
+func (flavor *DBFlavor) Name() string {
+	switch *flavor {
+	case MySQL:
+		{
+			return "MySQL"
+		}
+	case PostgreSQL:
+		{
+			return "PostgreSQL"
+		}
+	default:
+		{
+			return string(*flavor)
+		}
+	}
+}
+
 type DSNManager interface {
 	GetDSN() (string, error)
 	IsSafeMode() bool
 	GetFlavor() DBFlavor
 	SetDatabase(databaseName string)
+	GetConnectionInfo() ConnectionInfo
+}
+
+// Details of a connection which are safe to display, without secrets such as the password
+type ConnectionInfo struct {
+	Flavor   DBFlavor
+	User     string
+	Host     string
+	Port     uint
+	Database string
+}
+
+// Short description of the connection, ex: root@localhost/example
+func (info *ConnectionInfo) Label() string {
+	var label strings.Builder
+
+	if info.User != "" {
+		label.WriteString(info.User)
+		label.WriteRune('@')
+	}
+
+	label.WriteString(info.Host)
+
+	if info.Database != "" {
+		label.WriteRune('/')
+		label.WriteString(info.Database)
+	}
+
+	return label.String()
 }
 
 type DSNOptions struct {
@@ -39,7 +86,7 @@ type DSNOptions struct {
 	User         string
 	Password     string
 	Port         uint
-	// Only works in MySQL
+	// SQL_SAFE_UPDATES on MySQL, checked by DBClient on Postgres
 	SafeMode          bool
 	AdditionalOptions map[string]string
 }
@@ -64,6 +111,21 @@ func (connOptions *DSNOptions) SetDatabase(databaseName string) {
 	connOptions.DatabaseName = databaseName
 }
 
+func (connOptions *DSNOptions) GetConnectionInfo() ConnectionInfo {
+	host := connOptions.Host
+	if host == "" {
+		host = "localhost"
+	}
+
+	return ConnectionInfo{
+		Flavor:   connOptions.Flavor,
+		User:     connOptions.User,
+		Host:     host,
+		Port:     connOptions.Port,
+		Database: connOptions.DatabaseName,
+	}
+}
+
 func (connOptions *DSNOptions) GetDSN() (string, error) {
 	switch connOptions.Flavor {
 	case MySQL:
@@ -253,3 +253,39 @@ func TestDBConnOptionsInvalidFlavor(t *testing.T) {
 	assert.Empty(connOptionsString)
 	assert.Error(err)
 }
+
+func TestConnOptionsConnectionInfoLabel(t *testing.T) {
+	var tests = []struct {
+		Name          string
+		ConnOptions   conn.DSNOptions
+		ExpectedLabel string
+	}{
+		{
+			Name:          "Defaults",
+			ConnOptions:   conn.DSNOptions{Flavor: conn.MySQL},
+			ExpectedLabel: "localhost",
+		},
+		{
+			Name: "Everything",
+			ConnOptions: conn.DSNOptions{
+				Flavor:       conn.PostgreSQL,
+				Host:         "db.example.com",
+				User:         "admin",
+				Password:     "secret",
+				DatabaseName: "app",
+				Port:         5433,
+			},
+			ExpectedLabel: "admin@db.example.com/app",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			connectionInfo := test.ConnOptions.GetConnectionInfo()
+			assert.Equal(test.ExpectedLabel, connectionInfo.Label())
+		})
+	}
+}
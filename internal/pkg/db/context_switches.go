@@ -0,0 +1,45 @@
+package db
+
+import (
+	"time"
+)
+
+// What a context switch changed
+const (
+	databaseSetting   = "database"
+	searchPathSetting = "search_path"
+)
+
+// A change of the database or search_path, which every statement after it runs against
+// Kept for \switches, as the change is easy to lose track of once it scrolls out of view
+type contextSwitch struct {
+	at      time.Time
+	setting string
+	from    string
+	to      string
+}
+
+func (db *DBClient) recordContextSwitch(setting string, from string, to string) {
+	db.contextSwitches = append(db.contextSwitches, contextSwitch{
+		at:      time.Now(),
+		setting: setting,
+		from:    from,
+		to:      to,
+	})
+}
+
+// \switches
+// List every database and search_path switch this session, oldest first
+func (db *DBClient) switchesCommand(string) (*QueryResult, error) {
+	rows := make([][]string, len(db.contextSwitches))
+	for idx, contextSwitch := range db.contextSwitches {
+		rows[idx] = []string{
+			contextSwitch.at.Format(time.TimeOnly),
+			contextSwitch.setting,
+			contextSwitch.from,
+			contextSwitch.to,
+		}
+	}
+
+	return newTextResult([]string{"Switched At", "Setting", "From", "To"}, rows), nil
+}
@@ -3,31 +3,100 @@ package db
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/azvaliev/sql/internal/pkg/policy"
+	"github.com/azvaliev/sql/internal/pkg/telemetry"
+	"github.com/azvaliev/sql/internal/pkg/webhook"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
 )
 
 type DBClient struct {
 	ctx         context.Context
-	connManager *conn.ConnectionManager
+	connManager conn.Manager
+	// Whether special statements such as DESCRIBE are rewritten for the current flavor
+	transformsEnabled bool
+	// Metadata about tables in the current schema, loaded on first use
+	schema *Schema
+	// Where the schema is saved for use while offline, empty when disabled
+	schemaCachePath string
+	// Connection the open transaction began on, nil outside of a transaction
+	transactionConn *sqlx.Conn
+	// Aggregates of every query run this session, for \stats and metrics
+	stats *sessionStats
+	// Feature usage counts, nil when not set up
+	telemetry *telemetry.Recorder
+	// Recent results of read-only statements, empty unless enabled
+	resultCache *resultCache
+	// Notified when slow statements finish, nil when not set up
+	webhook *webhook.Notifier
+	// When table and column names are quoted in generated statements
+	identifierQuoting lexer.IdentifierQuoting
+	// Postgres search_path as last read, nil until read or once it may have changed
+	searchPath *string
+	// Rows Query reads up front, 0 for every row
+	rowLimit int
+	// Temporary tables and views created this session, for \temp
+	tempObjects []tempObject
+	// Database and search_path switches this session, for \switches
+	contextSwitches []contextSwitch
+	// Asked about each statement before it's sent, nil when not set up
+	policyHook policy.Hook
+	// Latest statements, with their values hidden, for bug reports
+	recentStatements *recentStatements
+	// Writes bundles for \bugreport, nil when not set up
+	bugReporter BugReporter
 }
 
-// Instantiate a DBClient from a DSN
-func CreateDBClient(connManager *conn.ConnectionManager) (*DBClient, error) {
+// Instantiate a DBClient from a connection manager, usually a *conn.ConnectionManager
+func CreateDBClient(connManager conn.Manager) (*DBClient, error) {
 	if connManager == nil {
 		return nil, errors.New("Cannot instantiate DBClient with nil connection manager")
 	}
 
 	db := DBClient{
-		ctx:         context.Background(),
-		connManager: connManager,
+		ctx:               context.Background(),
+		connManager:       connManager,
+		transformsEnabled: true,
+		stats:             newSessionStats(),
+		recentStatements:  &recentStatements{},
+		resultCache:       newResultCache(),
+		identifierQuoting: lexer.QuoteAlways,
 	}
 
 	return &db, nil
 }
 
+// When disabled, statements are always sent to the database verbatim
+func (db *DBClient) SetTransformsEnabled(enabled bool) {
+	db.transformsEnabled = enabled
+}
+
+// With lexer.QuoteWhenNeeded, generated statements only quote mixed case, reserved or otherwise unusual names
+func (db *DBClient) SetIdentifierQuoting(quoting lexer.IdentifierQuoting) {
+	db.identifierQuoting = quoting
+}
+
+// Cap the rows Query reads up front, so a large result doesn't have to be read all at once. 0 reads every row
+func (db *DBClient) SetRowLimit(limit int) {
+	db.rowLimit = limit
+}
+
+// Rows Query reads up front, and how many more to read at a time
+func (db *DBClient) GetRowLimit() int {
+	return db.rowLimit
+}
+
+// Who and what is connected to
+func (db *DBClient) GetConnectionInfo() conn.ConnectionInfo {
+	return db.connManager.GetConnectionInfo()
+}
+
 // Cleanup database resources
 // Call before this struct drops out of scope
 func (db *DBClient) Destroy() {
@@ -35,20 +104,157 @@ func (db *DBClient) Destroy() {
 }
 
 // Run a query and store the output in a displayable format
+// Only the first rows up to the row limit are read, the rest are left in the result's stream
 // NOTE: results and error may both be nil if a query is succesful yet doesn't return any rows
 func (db *DBClient) Query(statement string) (results *QueryResult, err error) {
+	return db.QueryStream(statement, db.rowLimit)
+}
+
+// Same as Query, reading only the first pageSize rows of a large result, or all of them when 0
+// The rest are read with the result's FetchRows, until another statement runs and closes them
+func (db *DBClient) QueryStream(statement string, pageSize int) (results *QueryResult, err error) {
+	startTime := time.Now()
+	defer func() {
+		duration := time.Since(startTime)
+		if results != nil {
+			results.Duration = duration
+		}
+		db.recordStatement(statement, duration, results, err)
+	}()
+
+	queryStatement, useCache := statement, true
+
+	// \nocache prefix skips the result cache for a single statement
+	if uncachedStatement, isNocache := stripNocachePrefix(statement); isNocache {
+		queryStatement, useCache = uncachedStatement, false
+	}
+
+	err = db.trackSearchPathChange(queryStatement, func() (err error) {
+		results, err = db.query(queryStatement, useCache, pageSize)
+		return err
+	})
+	if err != nil && db.transformsEnabled {
+		if offlineResult := db.getOfflineSchemaResult(queryStatement, err); offlineResult != nil {
+			return offlineResult, nil
+		}
+	}
+
+	return results, err
+}
+
+// Run a query, reusing a recent result of the identical statement when allowed and the cache is enabled
+func (db *DBClient) query(statement string, useCache bool, pageSize int) (results *QueryResult, err error) {
+	statementWithParams := &StatementWithParams{statement, nil}
+
+	// \raw prefix skips transforming a single statement
+	rawStatement, isRaw := stripRawPrefix(statement)
+	commandName, commandArgument, isMetaCommand := ParseMetaCommand(statement)
+
+	if isTransactionStatement, err := db.runTransactionStatement(statement); isTransactionStatement {
+		return nil, err
+	}
+
+	if isRaw {
+		statementWithParams.statement = rawStatement
+	} else if databaseName, isUse := parseUseStatement(db.connManager.GetFlavor(), statement); isUse {
+		return db.useDatabase(databaseName)
+	} else if isMetaCommand {
+		return db.runMetaCommand(commandName, commandArgument)
+	} else if db.transformsEnabled {
+		statementWithParams, err = db.transformStatement(statement)
+		if err != nil {
+			return nil, errors.Join(
+				errors.New("Query Failed"),
+				err,
+			)
+		}
+		if statementWithParams.statement != statement {
+			slog.Debug("Transformed statement", "statement", db.redactStatement(statement), "sent", db.redactStatement(statementWithParams.statement))
+		}
+	}
 
-	conn, err := db.connManager.GetConnection()
+	// What's checked is what's sent, so transforms such as DESCRIBE can't be used to get around the policy
+	statementWithParams.statement, err = db.checkStatement(statementWithParams.statement)
 	if err != nil {
 		return nil, err
 	}
 
-	statementWithParams, err := db.transformStatement(statement)
+	if !db.resultCache.enabled() {
+		return db.runStatementPaged(statementWithParams, pageSize)
+	}
+
+	cacheKey, isCacheable := db.getResultCacheKey(statement)
+	if !isCacheable {
+		// Anything else may have changed the data cached results came from
+		db.resultCache.invalidate()
+		return db.runStatementPaged(statementWithParams, pageSize)
+	}
+
+	if useCache {
+		if cachedResult := db.resultCache.get(cacheKey, time.Now()); cachedResult != nil {
+			return cachedResult, nil
+		}
+	}
+
+	results, err = db.runStatementPaged(statementWithParams, pageSize)
+	// Only complete results are reused
+	if err == nil && results != nil && results.Stream == nil {
+		db.resultCache.set(cacheKey, results, time.Now())
+	}
+
+	return results, err
+}
+
+// Track a statement in the session stats and feature usage
+func (db *DBClient) recordStatement(statement string, duration time.Duration, results *QueryResult, err error) {
+	// Stats are served as metrics, so like the log they only see the statement with its values hidden
+	redactedStatement := db.redactStatement(statement)
+
+	db.stats.record(redactedStatement, duration, results, err)
+	db.recordFeatureUsage(statement)
+	db.recordRecentStatement(statement, duration, err)
+	if err == nil {
+		db.trackTempObjects(statement)
+	}
+
+	var rowCount *int
+	if results != nil {
+		resultRowCount := len(results.Rows)
+		rowCount = &resultRowCount
+	}
+
+	logAttrs := []any{"statement", redactedStatement, "duration", duration}
+	if rowCount != nil {
+		logAttrs = append(logAttrs, "rows", *rowCount)
+	}
 	if err != nil {
-		return nil, errors.Join(
-			errors.New("Query Failed"),
-			err,
-		)
+		logAttrs = append(logAttrs, "err", db.redactStatement(err.Error()))
+	}
+	slog.Debug("Statement finished", logAttrs...)
+
+	if db.webhook != nil {
+		db.webhook.StatementFinished(statement, duration, rowCount, err)
+	}
+}
+
+// Notify a webhook when statements running longer than its threshold finish
+func (db *DBClient) SetWebhook(notifier *webhook.Notifier) {
+	db.webhook = notifier
+}
+
+// Execute a statement exactly as given, and store the output in a displayable format
+func (db *DBClient) runStatement(statementWithParams *StatementWithParams) (results *QueryResult, err error) {
+	return db.runStatementPaged(statementWithParams, 0)
+}
+
+// Same as runStatement, reading only the first pageSize rows, or all of them when 0
+// The rest are left in the result's stream, to be read with FetchRows
+func (db *DBClient) runStatementPaged(statementWithParams *StatementWithParams, pageSize int) (results *QueryResult, err error) {
+	columnSourceSchema, columnSourceTable := db.getColumnSourceSchema(statementWithParams.statement)
+
+	conn, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
 	}
 
 	// Execute the statement and get the raw rows iterator
@@ -65,60 +271,66 @@ func (db *DBClient) Query(statement string) (results *QueryResult, err error) {
 	} else if rows == nil {
 		return nil, nil
 	}
-	defer func() {
-		err := rows.Close()
-		if err != nil {
-			panic("Failed to cleanup rows")
-		}
-	}()
 
 	columnParsingError := errors.New("Could not determine columns")
 
 	columns, err := rows.Columns()
 	if err != nil {
+		_ = rows.Close()
 		return nil, errors.Join(
 			columnParsingError,
 			err,
 		)
 	}
 
-	// Scan all the rows into a string format, since we're just selecting to display
-	rawRows := [][]NullString{}
-	for rows.Next() {
-		rawRow := make([]NullString, len(columns))
-		rawRowPtrs := make([]any, len(columns))
-
-		for i := range rawRow {
-			rawRow[i] = NullString{}
-			rawRowPtrs[i] = &rawRow[i]
-		}
-
-		if err = rows.Scan(rawRowPtrs...); err != nil {
+	// Statements such as SET, or DDL on MySQL, can return rows without columns
+	// There's nothing to show of them, so they're reported like a statement run with Exec
+	if len(columns) == 0 {
+		if err := rows.Close(); err != nil {
 			return nil, errors.Join(
-				errors.New("failed to read rows"),
+				errors.New("Query Failed"),
 				err,
 			)
 		}
 
-		rawRows = append(rawRows, rawRow)
+		return newStatusResult(db.connManager.GetFlavor(), statementWithParams.statement), nil
 	}
 
-	// Transform each row into a map of column -> value
-	mappedRows := make([]map[string]*NullString, len(rawRows))
-	for rowIdx := range rawRows {
-		rawRow := rawRows[rowIdx]
-		mappedRow := make(map[string]*NullString, len(rawRow))
+	// Type information is only shown alongside the result, so it's fine to go without
+	var columnTypes []ColumnType
+	if sqlColumnTypes, err := rows.ColumnTypes(); err == nil {
+		columnTypes = getColumnTypes(sqlColumnTypes)
+	}
 
-		for columnIdx, columnValue := range rawRow {
-			columnName := columns[columnIdx]
-			mappedRow[columnName] = &columnValue
-		}
+	// Scan rows into a string format, since we're just selecting to display
+	stream := newRowStream(rows, columns)
+	mappedRows, err := stream.fetch(pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	results = &QueryResult{
+		Rows:        mappedRows,
+		Columns:     columns,
+		ColumnTypes: columnTypes,
+	}
+	if mappedRows == nil {
+		results.Rows = []map[string]*NullString{}
+	}
+	addColumnSources(columnSourceSchema, columnSourceTable, results)
 
-		mappedRows[rowIdx] = mappedRow
+	if !stream.done {
+		results.Stream = stream
+		db.connManager.SetOpenRows(stream)
 	}
 
+	return results, nil
+}
+
+// Result of a statement which returned no columns, describing what it did
+func newStatusResult(flavor conn.DBFlavor, statement string) *QueryResult {
 	return &QueryResult{
-		Rows:    mappedRows,
-		Columns: columns,
-	}, err
+		Rows:   []map[string]*NullString{},
+		Status: describeExecStatement(flavor, statement, -1),
+	}
 }
@@ -68,6 +68,19 @@ func TestDBMySQLConnOptions(t *testing.T) {
 
 				assert.Equal(fmt.Sprint(1), actualSafeMode)
 			}
+
+			// Statements returning no columns are reported with a status
+			{
+				result, err := dbClient.Query("SET @answer = 42")
+				assert.NoError(err)
+				assert.Empty(result.Columns)
+				assert.Equal("Statement executed", result.Status)
+
+				result, err = dbClient.Query("CREATE TEMPORARY TABLE zero_columns (id int)")
+				assert.NoError(err)
+				assert.Empty(result.Columns)
+				assert.Equal("Table created", result.Status)
+			}
 		})
 	}
 }
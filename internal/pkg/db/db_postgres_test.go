@@ -3,6 +3,7 @@ package db_test
 import (
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/azvaliev/sql/internal/pkg/db/conn"
@@ -113,7 +114,7 @@ func TestDBPostgresDescribe(t *testing.T) {
 
 			// Validate describe output
 			for _, row := range describeResult.Rows {
-				assert.Len(row, 5)
+				assert.Len(row, 6)
 
 				switch row["Field"].ToString() {
 				case "id":
@@ -122,6 +123,7 @@ func TestDBPostgresDescribe(t *testing.T) {
 						assert.Equal("NO", row["Null"].ToString())
 						assert.Equal("PRI", row["Key"].ToString())
 						assert.Equal("nextval('test_id_seq'::regclass)", row["Default"].ToString())
+						assert.Equal("auto_increment", row["Extra"].ToString())
 						break
 					}
 				case "external_id":
@@ -130,6 +132,7 @@ func TestDBPostgresDescribe(t *testing.T) {
 						assert.Equal("YES", row["Null"].ToString())
 						assert.Equal("UNI", row["Key"].ToString())
 						assert.Equal("NULL", row["Default"].ToString())
+						assert.Empty(row["Extra"].ToString())
 						break
 					}
 				case "created_at":
@@ -138,6 +141,7 @@ func TestDBPostgresDescribe(t *testing.T) {
 						assert.Equal("NO", row["Null"].ToString())
 						assert.Equal("MUL", row["Key"].ToString())
 						assert.Equal("now()", row["Default"].ToString())
+						assert.Empty(row["Extra"].ToString())
 						break
 					}
 				default:
@@ -150,3 +154,404 @@ func TestDBPostgresDescribe(t *testing.T) {
 		})
 	}
 }
+
+func TestDBPostgresDescribeMultiConstraint(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - DESCRIBE multi constraint columns", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			setupStatements := []string{
+				`CREATE TABLE multi (
+					a INT NOT NULL,
+					b INT NOT NULL,
+					c INT,
+					d TEXT,
+					e INT,
+					f NUMERIC(10, 2),
+					PRIMARY KEY (a, b),
+					UNIQUE (c),
+					UNIQUE (d, e)
+				)`,
+				// Columns in several indexes should only appear once, with the highest priority key
+				`CREATE INDEX multi_b_idx ON multi (b)`,
+				`CREATE INDEX multi_c_idx ON multi (c)`,
+				// Partial unique indexes don't guarantee uniqueness of the column
+				`CREATE UNIQUE INDEX multi_e_partial_idx ON multi (e) WHERE e > 0`,
+				// Dropped columns should not be listed
+				`ALTER TABLE multi ADD COLUMN dropped INT`,
+				`ALTER TABLE multi DROP COLUMN dropped`,
+			}
+			for _, statement := range setupStatements {
+				_, err := dbClient.Query(statement)
+				assert.NoError(err, statement)
+			}
+
+			describeResult, err := dbClient.Query("DESCRIBE multi")
+			assert.NoError(err)
+
+			expectedRows := []struct{ Field, Type, Null, Key string }{
+				{"a", "integer", "NO", "PRI"},
+				{"b", "integer", "NO", "PRI"},
+				{"c", "integer", "YES", "UNI"},
+				{"d", "text", "YES", "MUL"},
+				{"e", "integer", "YES", "MUL"},
+				{"f", "numeric(10,2)", "YES", ""},
+			}
+
+			assert.Len(describeResult.Rows, len(expectedRows), "each column should appear exactly once")
+			for idx, expectedRow := range expectedRows {
+				if idx >= len(describeResult.Rows) {
+					break
+				}
+				actualRow := describeResult.Rows[idx]
+
+				assert.Equal(expectedRow.Field, actualRow["Field"].ToString(), "columns should be in table order")
+				assert.Equal(expectedRow.Type, actualRow["Type"].ToString(), expectedRow.Field)
+				assert.Equal(expectedRow.Null, actualRow["Null"].ToString(), expectedRow.Field)
+				assert.Equal(expectedRow.Key, actualRow["Key"].ToString(), expectedRow.Field)
+			}
+		})
+	}
+}
+
+func TestDBPostgresDescribeExtra(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - DESCRIBE Extra", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			_, err := dbClient.Query(`
+				CREATE TABLE extra (
+					always_id INT GENERATED ALWAYS AS IDENTITY,
+					default_id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+					serial_id BIGSERIAL,
+					price NUMERIC(10, 2) DEFAULT 0,
+					price_with_tax NUMERIC GENERATED ALWAYS AS (price * 1.2) STORED
+				)
+			`)
+			assert.NoError(err)
+
+			describeResult, err := dbClient.Query("DESCRIBE extra")
+			assert.NoError(err)
+
+			expectedRows := []struct{ Field, Default, Extra string }{
+				{"always_id", "NULL", "auto_increment (GENERATED ALWAYS AS IDENTITY)"},
+				{"default_id", "NULL", "auto_increment (GENERATED BY DEFAULT AS IDENTITY)"},
+				{"serial_id", "nextval('extra_serial_id_seq'::regclass)", "auto_increment"},
+				{"price", "0", ""},
+				{"price_with_tax", "NULL", "STORED GENERATED ((price * 1.2))"},
+			}
+
+			assert.Len(describeResult.Rows, len(expectedRows))
+			for idx, expectedRow := range expectedRows {
+				if idx >= len(describeResult.Rows) {
+					break
+				}
+				actualRow := describeResult.Rows[idx]
+
+				assert.Equal(expectedRow.Field, actualRow["Field"].ToString())
+				assert.Equal(expectedRow.Default, actualRow["Default"].ToString(), expectedRow.Field)
+				assert.Equal(expectedRow.Extra, actualRow["Extra"].ToString(), expectedRow.Field)
+			}
+		})
+	}
+}
+
+func TestDBPostgresDescribeCaseFolding(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - DESCRIBE case folding", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			_, err := dbClient.Query("CREATE TABLE users (id INT)")
+			assert.NoError(err)
+			_, err = dbClient.Query(`CREATE TABLE "Users" (name TEXT)`)
+			assert.NoError(err)
+			_, err = dbClient.Query(`CREATE TABLE "Orders" (total INT)`)
+			assert.NoError(err)
+
+			describedFields := func(statement string) string {
+				describeResult, err := dbClient.Query(statement)
+				if !assert.NoError(err, statement) || !assert.NotEmpty(describeResult.Rows, statement) {
+					return ""
+				}
+				return describeResult.Rows[0]["Field"].ToString()
+			}
+
+			// Unquoted names are folded, as Postgres does in queries
+			assert.Equal("id", describedFields("DESCRIBE Users"))
+			// Quoted names are exact
+			assert.Equal("name", describedFields(`DESCRIBE "Users"`))
+			// With only a mixed case table, it's found either way
+			assert.Equal("total", describedFields("DESCRIBE Orders"))
+		})
+	}
+}
+
+func TestDBPostgresSearchPath(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - search_path", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			setupStatements := []string{
+				"CREATE SCHEMA sales",
+				"CREATE TABLE sales.orders (id INT)",
+				"CREATE TABLE customers (id INT)",
+			}
+			for _, statement := range setupStatements {
+				_, err := dbClient.Query(statement)
+				assert.NoError(err, statement)
+			}
+			assert.Equal(`"$user", public`, dbClient.GetSearchPath())
+
+			result, err := dbClient.Query(`\schemas`)
+			assert.NoError(err)
+			schemas := map[string]string{}
+			for _, row := range result.Rows {
+				schemas[row["Schema"].ToString()] = row["In search_path"].ToString()
+			}
+			assert.Equal(map[string]string{"public": "current", "sales": ""}, schemas)
+
+			_, err = dbClient.Query(`\setschema missing`)
+			assert.ErrorContains(err, "does not exist")
+
+			result, err = dbClient.Query(`\setschema sales, public`)
+			assert.NoError(err)
+			assert.Equal("sales, public", result.Rows[0]["search_path"].ToString())
+			assert.Equal("sales, public", dbClient.GetSearchPath())
+
+			// Tables of every schema in the search_path are listed
+			result, err = dbClient.Query("SHOW TABLES")
+			assert.NoError(err)
+			var tableNames []string
+			for _, row := range result.Rows {
+				tableNames = append(tableNames, row["table_name"].ToString())
+			}
+			assert.Equal([]string{"customers", "orders"}, tableNames)
+
+			_, err = dbClient.Query("DESCRIBE orders")
+			assert.NoError(err)
+
+			// Changed directly, it's read again
+			_, err = dbClient.Query("SET search_path TO public")
+			assert.NoError(err)
+			assert.Equal("public", dbClient.GetSearchPath())
+
+			// SET is sent with Exec when typed, which reads it again too
+			_, err = dbClient.Exec("SET search_path TO sales")
+			assert.NoError(err)
+			assert.Equal("sales", dbClient.GetSearchPath())
+
+			result, err = dbClient.Query("SHOW TABLES")
+			assert.NoError(err)
+			tableNames = nil
+			for _, row := range result.Rows {
+				tableNames = append(tableNames, row["table_name"].ToString())
+			}
+			assert.Equal([]string{"orders"}, tableNames)
+
+			result, err = dbClient.Query(`\switches`)
+			assert.NoError(err)
+			lastSwitch := result.Rows[len(result.Rows)-1]
+			assert.Equal("public", lastSwitch["From"].ToString())
+			assert.Equal("sales", lastSwitch["To"].ToString())
+		})
+	}
+}
+
+func TestDBPostgresTempObjects(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - temp objects", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			setupStatements := []string{
+				"CREATE TABLE totals (id INT)",
+				"CREATE TEMP TABLE totals AS SELECT 1 AS id",
+				"CREATE TEMPORARY VIEW recent_totals AS SELECT * FROM totals",
+			}
+			for _, statement := range setupStatements {
+				_, err := dbClient.Query(statement)
+				assert.NoError(err, statement)
+			}
+
+			result, err := dbClient.Query(`\temp`)
+			assert.NoError(err)
+			assert.Len(result.Rows, 2)
+			assert.Equal("totals", result.Rows[0]["Name"].ToString())
+			assert.Equal("view", result.Rows[1]["Type"].ToString())
+			assert.Len(result.Actions, 1)
+
+			result, err = dbClient.Query(`\temp drop`)
+			assert.NoError(err)
+			assert.Equal("recent_totals, totals", result.Rows[0]["Dropped"].ToString())
+
+			result, err = dbClient.Query(`\temp`)
+			assert.NoError(err)
+			assert.Empty(result.Rows)
+
+			// The permanent table of the same name is left alone
+			_, err = dbClient.Query("SELECT * FROM totals")
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestDBPostgresUseDatabase(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - USE", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			_, err := dbClient.Query("CREATE DATABASE analytics")
+			assert.NoError(err)
+
+			result, err := dbClient.Query("SHOW DATABASES")
+			assert.NoError(err)
+			var databaseNames []string
+			for _, row := range result.Rows {
+				databaseNames = append(databaseNames, row["Database"].ToString())
+			}
+			assert.Contains(databaseNames, "analytics")
+			assert.Contains(databaseNames, "test")
+			assert.NotContains(databaseNames, "template0")
+
+			result, err = dbClient.Query("USE Analytics;")
+			assert.NoError(err)
+			assert.Equal("Switched to analytics from test", result.Rows[0]["Database"].ToString())
+			assert.Equal("analytics", dbClient.GetConnectionInfo().Database)
+
+			result, err = dbClient.Query("SELECT current_database() AS name")
+			assert.NoError(err)
+			assert.Equal("analytics", result.Rows[0]["name"].ToString())
+
+			// Stays connected to the current database when the new one can't be reached
+			_, err = dbClient.Query(`\use missing`)
+			assert.Error(err)
+			assert.Equal("analytics", dbClient.GetConnectionInfo().Database)
+
+			_, err = dbClient.Query("SELECT 1")
+			assert.NoError(err)
+		})
+	}
+}
+
+func TestDBPostgresServerInfo(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - Server info", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			serverInfo, err := dbClient.GetServerInfo()
+			if !assert.NoError(err) {
+				return
+			}
+
+			assert.True(strings.HasPrefix(serverInfo.Version, fmt.Sprint("PostgreSQL ", postgresVersion)), serverInfo.Version)
+			assert.Equal("UTF8", serverInfo.CharacterSet)
+			assert.NotEmpty(serverInfo.TimeZone)
+			assert.False(serverInfo.ReadOnly)
+			assert.GreaterOrEqual(serverInfo.Connections, 1)
+		})
+	}
+}
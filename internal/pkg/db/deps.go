@@ -0,0 +1,151 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Everything which would break, or be dropped along with, the target table or view
+const postgresDepsQuery string = `
+WITH target AS (
+  SELECT c.oid
+  FROM pg_class c
+  WHERE c.relname = $1
+  AND c.relnamespace = (SELECT oid FROM pg_namespace WHERE nspname = current_schema())
+)
+SELECT "Type", "Name", "Definition"
+FROM (
+  SELECT DISTINCT
+    CASE dependent.relkind WHEN 'm' THEN 'materialized view' ELSE 'view' END AS "Type",
+    dependent.oid::regclass::text AS "Name",
+    '' AS "Definition"
+  FROM pg_depend d
+  JOIN pg_rewrite r ON r.oid = d.objid
+  JOIN pg_class dependent ON dependent.oid = r.ev_class
+  WHERE d.classid = 'pg_rewrite'::regclass
+  AND d.refclassid = 'pg_class'::regclass
+  AND d.refobjid = (SELECT oid FROM target)
+  AND dependent.oid <> d.refobjid
+
+  UNION ALL
+
+  SELECT
+    'foreign key',
+    con.conrelid::regclass::text || '.' || con.conname,
+    pg_get_constraintdef(con.oid)
+  FROM pg_constraint con
+  WHERE con.contype = 'f'
+  AND con.confrelid = (SELECT oid FROM target)
+
+  UNION ALL
+
+  SELECT DISTINCT
+    'function',
+    p.oid::regprocedure::text,
+    ''
+  FROM pg_depend d
+  JOIN pg_proc p ON p.oid = d.objid
+  WHERE d.classid = 'pg_proc'::regclass
+  AND d.refclassid = 'pg_class'::regclass
+  AND d.refobjid = (SELECT oid FROM target)
+
+  UNION ALL
+
+  SELECT
+    'trigger',
+    t.tgname,
+    pg_get_triggerdef(t.oid)
+  FROM pg_trigger t
+  WHERE NOT t.tgisinternal
+  AND t.tgrelid = (SELECT oid FROM target)
+) dependents
+ORDER BY "Type", "Name"
+`
+
+// Views and routines are matched by name, as MySQL doesn't track routine dependencies
+const mySQLDepsQuery string = `
+SELECT Type, Name, Definition
+FROM (
+  SELECT
+    CAST('view' AS CHAR) AS Type,
+    CAST(VIEW_NAME AS CHAR) AS Name,
+    CAST('' AS CHAR) AS Definition
+  FROM information_schema.VIEW_TABLE_USAGE
+  WHERE TABLE_SCHEMA = DATABASE()
+  AND TABLE_NAME = ?
+
+  UNION ALL
+
+  SELECT
+    'foreign key',
+    CONCAT(TABLE_NAME, '.', CONSTRAINT_NAME),
+    CONCAT(
+      'FOREIGN KEY (', GROUP_CONCAT(COLUMN_NAME ORDER BY ORDINAL_POSITION), ') ',
+      'REFERENCES ', REFERENCED_TABLE_NAME,
+      '(', GROUP_CONCAT(REFERENCED_COLUMN_NAME ORDER BY ORDINAL_POSITION), ')'
+    )
+  FROM information_schema.KEY_COLUMN_USAGE
+  WHERE REFERENCED_TABLE_SCHEMA = DATABASE()
+  AND REFERENCED_TABLE_NAME = ?
+  GROUP BY TABLE_NAME, CONSTRAINT_NAME, REFERENCED_TABLE_NAME
+
+  UNION ALL
+
+  SELECT
+    LOWER(ROUTINE_TYPE),
+    ROUTINE_NAME,
+    ''
+  FROM information_schema.ROUTINES
+  WHERE ROUTINE_SCHEMA = DATABASE()
+  AND ROUTINE_DEFINITION REGEXP CONCAT('\\b', ?, '\\b')
+
+  UNION ALL
+
+  SELECT
+    'trigger',
+    TRIGGER_NAME,
+    CONCAT(ACTION_TIMING, ' ', EVENT_MANIPULATION)
+  FROM information_schema.TRIGGERS
+  WHERE EVENT_OBJECT_SCHEMA = DATABASE()
+  AND EVENT_OBJECT_TABLE = ?
+) dependents
+ORDER BY Type, Name
+`
+
+// \deps <table or view>
+// List the views, foreign keys, functions and triggers that depend on an object, before dropping or altering it
+func (db *DBClient) depsCommand(objectName string) (*QueryResult, error) {
+	objectName = strings.TrimSuffix(objectName, ";")
+	if objectName == "" {
+		return nil, errors.New(`Usage: \deps <table or view>`)
+	}
+
+	var statementWithParams *StatementWithParams
+
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			statementWithParams = &StatementWithParams{postgresDepsQuery, []interface{}{objectName}}
+		}
+	case conn.MySQL:
+		{
+			statementWithParams = &StatementWithParams{
+				mySQLDepsQuery,
+				[]interface{}{objectName, objectName, objectName, objectName},
+			}
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\deps`, db.connManager.GetFlavor())
+		}
+	}
+
+	err := db.assertTableExists(objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.runStatement(statementWithParams)
+}
@@ -0,0 +1,555 @@
+package db
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/jmoiron/sqlx"
+)
+
+// Rows per INSERT in a dump, small enough to stay well under statement size limits
+const dumpInsertBatchSize = 100
+
+const mySQLDumpTablesQuery string = `
+SELECT TABLE_NAME AS table_name
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = DATABASE()
+AND TABLE_TYPE = 'BASE TABLE'
+ORDER BY TABLE_NAME
+`
+
+const postgresDumpTablesQuery string = `
+SELECT tablename AS table_name
+FROM pg_tables
+WHERE schemaname = current_schema()
+ORDER BY tablename
+`
+
+// Generated columns can't be inserted into, the database fills them in again on restore
+const mySQLDumpColumnsQuery string = `
+SELECT COLUMN_NAME AS column_name
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = DATABASE()
+AND TABLE_NAME = ?
+AND EXTRA NOT IN ('VIRTUAL GENERATED', 'STORED GENERATED')
+ORDER BY ORDINAL_POSITION
+`
+
+const postgresDumpColumnsQuery string = `
+SELECT
+  a.attname AS column_name,
+  format_type(a.atttypid, a.atttypmod) AS column_type,
+  a.attnotnull AS not_null,
+  COALESCE(pg_get_expr(d.adbin, d.adrelid), '') AS column_default,
+  a.attidentity <> '' OR COALESCE(pg_get_expr(d.adbin, d.adrelid), '') LIKE 'nextval(%' AS is_identity,
+  a.attgenerated <> '' AS is_generated
+FROM pg_attribute a
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = $1::regclass
+AND a.attnum > 0
+AND NOT a.attisdropped
+ORDER BY a.attnum
+`
+
+const postgresDumpConstraintsQuery string = `
+SELECT
+  conname AS constraint_name,
+  pg_get_constraintdef(oid) AS definition,
+  contype = 'f' AS is_foreign_key
+FROM pg_constraint
+WHERE conrelid = $1::regclass
+AND contype IN ('p', 'u', 'c', 'f', 'x')
+ORDER BY contype = 'p' DESC, conname
+`
+
+// Indexes backing a constraint are created along with it
+const postgresDumpIndexesQuery string = `
+SELECT pg_get_indexdef(i.indexrelid) AS definition
+FROM pg_index i
+WHERE i.indrelid = $1::regclass
+AND NOT EXISTS (
+  SELECT 1 FROM pg_constraint c
+  WHERE c.conindid = i.indexrelid
+  AND c.contype IN ('p', 'u', 'x')
+)
+ORDER BY i.indexrelid
+`
+
+type postgresDumpColumn struct {
+	Name        string `db:"column_name"`
+	Type        string `db:"column_type"`
+	NotNull     bool   `db:"not_null"`
+	Default     string `db:"column_default"`
+	IsIdentity  bool   `db:"is_identity"`
+	IsGenerated bool   `db:"is_generated"`
+}
+
+type postgresDumpConstraint struct {
+	Name         string `db:"constraint_name"`
+	Definition   string `db:"definition"`
+	IsForeignKey bool   `db:"is_foreign_key"`
+}
+
+// Schema and data of a table, ready to write to a dump
+type tableDump struct {
+	name string
+	// CREATE TABLE and anything else needed before the data, such as dropping the existing table
+	createStatements []string
+	// Columns to insert, leaving out generated columns
+	columns []string
+	// Run after the data of every table is in, such as foreign keys and indexes
+	finalStatements []string
+}
+
+// \dump [table|all] <file>
+// Write the schema and data of a table, or every table, to a SQL file which \restore can run
+// Meant for small databases and quick backups, rows are written as plain INSERTs
+func (db *DBClient) dumpCommand(argument string) (*QueryResult, error) {
+	usageError := errors.New(`Usage: \dump [table|all] <file>`)
+
+	arguments := strings.Fields(strings.TrimSuffix(argument, ";"))
+	var tableName, path string
+	switch len(arguments) {
+	case 1:
+		{
+			path = arguments[0]
+		}
+	case 2:
+		{
+			tableName, path = arguments[0], arguments[1]
+		}
+	default:
+		{
+			return nil, usageError
+		}
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var tableNames []string
+	if tableName == "" || strings.EqualFold(tableName, "all") {
+		tableNames, err = db.getDumpTableNames(connection)
+	} else {
+		err = db.assertTableExists(tableName)
+		tableNames = []string{tableName}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to create dump file %s", path),
+			err,
+		)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	dumpedRows, err := db.writeDump(connection, writer, tableNames)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to dump to %s", path),
+			err,
+		)
+	}
+
+	return newTextResult(
+		[]string{"Dump"},
+		[][]string{{fmt.Sprintf("Dumped %d tables and %d rows to %s", len(tableNames), dumpedRows, path)}},
+	), nil
+}
+
+func (db *DBClient) getDumpTableNames(connection *sqlx.Conn) (tableNames []string, err error) {
+	tablesQuery := mySQLDumpTablesQuery
+	if db.connManager.GetFlavor() == conn.PostgreSQL {
+		tablesQuery = postgresDumpTablesQuery
+	}
+
+	err = connection.SelectContext(db.ctx, &tableNames, tablesQuery)
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to list tables"),
+			err,
+		)
+	}
+
+	return tableNames, nil
+}
+
+func (db *DBClient) writeDump(connection *sqlx.Conn, writer io.Writer, tableNames []string) (dumpedRows int, err error) {
+	flavor := db.connManager.GetFlavor()
+	connectionInfo := db.connManager.GetConnectionInfo()
+
+	writeStatement := func(statement string) {
+		if err == nil {
+			_, err = fmt.Fprint(writer, statement, ";\n")
+		}
+	}
+
+	_, err = fmt.Fprintf(
+		writer,
+		"-- Dump of %s, taken %s\n\n",
+		connectionInfo.Label(),
+		time.Now().UTC().Format(time.RFC3339),
+	)
+
+	// Tables may reference each other in any order, so foreign keys are only checked once everything is in
+	if flavor == conn.MySQL {
+		writeStatement("SET FOREIGN_KEY_CHECKS = 0")
+	}
+
+	var finalStatements []string
+	for _, tableName := range tableNames {
+		if err != nil {
+			return dumpedRows, err
+		}
+
+		var table *tableDump
+		table, err = db.getTableDump(connection, tableName)
+		if err != nil {
+			return dumpedRows, err
+		}
+
+		fmt.Fprintf(writer, "\n-- %s\n", tableName)
+		for _, statement := range table.createStatements {
+			writeStatement(statement)
+		}
+
+		var tableRows int
+		tableRows, err = db.writeTableData(connection, writer, table)
+		dumpedRows += tableRows
+
+		finalStatements = append(finalStatements, table.finalStatements...)
+	}
+
+	if len(finalStatements) > 0 {
+		fmt.Fprintln(writer)
+	}
+	for _, statement := range finalStatements {
+		writeStatement(statement)
+	}
+
+	if flavor == conn.MySQL {
+		writeStatement("SET FOREIGN_KEY_CHECKS = 1")
+	}
+
+	return dumpedRows, err
+}
+
+func (db *DBClient) getTableDump(connection *sqlx.Conn, tableName string) (*tableDump, error) {
+	var table *tableDump
+	var err error
+
+	switch db.connManager.GetFlavor() {
+	case conn.MySQL:
+		{
+			table, err = db.getMySQLTableDump(connection, tableName)
+		}
+	case conn.PostgreSQL:
+		{
+			table, err = db.getPostgresTableDump(connection, tableName)
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\dump`, db.connManager.GetFlavor())
+		}
+	}
+
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to get the definition of %s", tableName),
+			err,
+		)
+	}
+
+	return table, nil
+}
+
+func (db *DBClient) getMySQLTableDump(connection *sqlx.Conn, tableName string) (*tableDump, error) {
+	quotedTableName := db.quoteIdentifier(tableName)
+
+	var createTable struct {
+		Table       string `db:"Table"`
+		CreateTable string `db:"Create Table"`
+	}
+	err := connection.QueryRowxContext(db.ctx, fmt.Sprint("SHOW CREATE TABLE ", quotedTableName)).StructScan(&createTable)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &tableDump{
+		name: tableName,
+		createStatements: []string{
+			fmt.Sprint("DROP TABLE IF EXISTS ", quotedTableName),
+			createTable.CreateTable,
+		},
+	}
+
+	err = connection.SelectContext(db.ctx, &table.columns, mySQLDumpColumnsQuery, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+// Postgres has no SHOW CREATE TABLE, so the definition is put together from the catalog
+// Sequences behind serial columns become identity columns, continuing from the highest value dumped
+func (db *DBClient) getPostgresTableDump(connection *sqlx.Conn, tableName string) (*tableDump, error) {
+	quotedTableName := db.quoteIdentifier(tableName)
+
+	var columns []postgresDumpColumn
+	err := connection.SelectContext(db.ctx, &columns, postgresDumpColumnsQuery, quotedTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var constraints []postgresDumpConstraint
+	err = connection.SelectContext(db.ctx, &constraints, postgresDumpConstraintsQuery, quotedTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []string
+	err = connection.SelectContext(db.ctx, &indexes, postgresDumpIndexesQuery, quotedTableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &tableDump{name: tableName}
+
+	var definitions []string
+	for _, column := range columns {
+		definition := fmt.Sprint(db.quoteIdentifier(column.Name), " ", column.Type)
+
+		switch {
+		case column.IsGenerated:
+			{
+				definition = fmt.Sprintf("%s GENERATED ALWAYS AS (%s) STORED", definition, column.Default)
+			}
+		case column.IsIdentity:
+			{
+				definition = fmt.Sprint(definition, " GENERATED BY DEFAULT AS IDENTITY")
+
+				sequenceArguments := []*NullString{
+					{sql.NullString{String: quotedTableName, Valid: true}},
+					{sql.NullString{String: column.Name, Valid: true}},
+				}
+				table.finalStatements = append(table.finalStatements, fmt.Sprintf(
+					"SELECT setval(pg_get_serial_sequence(%s, %s), COALESCE(MAX(%s), 0) + 1, false) FROM %s",
+					quoteLiteralForFlavor(conn.PostgreSQL, sequenceArguments[0]),
+					quoteLiteralForFlavor(conn.PostgreSQL, sequenceArguments[1]),
+					db.quoteIdentifier(column.Name),
+					quotedTableName,
+				))
+			}
+		case column.Default != "":
+			{
+				definition = fmt.Sprint(definition, " DEFAULT ", column.Default)
+			}
+		}
+
+		if column.NotNull {
+			definition = fmt.Sprint(definition, " NOT NULL")
+		}
+		if !column.IsGenerated {
+			table.columns = append(table.columns, column.Name)
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	for _, constraint := range constraints {
+		definition := fmt.Sprintf("CONSTRAINT %s %s", db.quoteIdentifier(constraint.Name), constraint.Definition)
+
+		// Added at the end, once the referenced table exists
+		if constraint.IsForeignKey {
+			table.finalStatements = append(
+				table.finalStatements,
+				fmt.Sprintf("ALTER TABLE %s ADD %s", quotedTableName, definition),
+			)
+			continue
+		}
+
+		definitions = append(definitions, definition)
+	}
+
+	table.createStatements = []string{
+		fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", quotedTableName),
+		fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", quotedTableName, strings.Join(definitions, ",\n  ")),
+	}
+	table.finalStatements = append(table.finalStatements, indexes...)
+
+	return table, nil
+}
+
+// Write the rows of a table as INSERTs, reading them a row at a time so large tables aren't held in memory
+func (db *DBClient) writeTableData(connection *sqlx.Conn, writer io.Writer, table *tableDump) (dumpedRows int, err error) {
+	if len(table.columns) == 0 {
+		return 0, nil
+	}
+
+	flavor := db.connManager.GetFlavor()
+
+	quotedColumns := make([]string, len(table.columns))
+	for idx, column := range table.columns {
+		quotedColumns[idx] = db.quoteIdentifier(column)
+	}
+	columnList := strings.Join(quotedColumns, ", ")
+	quotedTableName := db.quoteIdentifier(table.name)
+
+	rows, err := connection.QueryContext(
+		db.ctx,
+		fmt.Sprintf("SELECT %s FROM %s", columnList, quotedTableName),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	row := make([]NullString, len(table.columns))
+	rowPtrs := make([]any, len(table.columns))
+	for idx := range row {
+		rowPtrs[idx] = &row[idx]
+	}
+
+	values := make([]string, len(table.columns))
+	for rows.Next() {
+		if err = rows.Scan(rowPtrs...); err != nil {
+			return dumpedRows, err
+		}
+
+		for idx := range row {
+			values[idx] = formatDumpValue(flavor, &row[idx])
+		}
+
+		// Each batch of rows starts a new INSERT
+		separator := ",\n  "
+		if dumpedRows%dumpInsertBatchSize == 0 {
+			separator = fmt.Sprintf("INSERT INTO %s (%s) VALUES\n  ", quotedTableName, columnList)
+			if dumpedRows > 0 {
+				separator = fmt.Sprint(";\n", separator)
+			}
+		}
+
+		_, err = fmt.Fprint(writer, separator, "(", strings.Join(values, ", "), ")")
+		if err != nil {
+			return dumpedRows, err
+		}
+
+		dumpedRows++
+	}
+	if dumpedRows > 0 {
+		_, err = fmt.Fprint(writer, ";\n")
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+
+	return dumpedRows, err
+}
+
+// Quote a value for a dump, binary data which isn't valid text is written as hex so the file stays readable
+func formatDumpValue(flavor conn.DBFlavor, value *NullString) string {
+	if value.Valid && !utf8.ValidString(value.String) {
+		if flavor == conn.MySQL {
+			return fmt.Sprintf("X'%s'", hex.EncodeToString([]byte(value.String)))
+		}
+
+		return fmt.Sprintf(`'\x%s'::bytea`, hex.EncodeToString([]byte(value.String)))
+	}
+
+	return quoteLiteralForFlavor(flavor, value)
+}
+
+// Called after each statement of a restore, with how many of the statements have run so far
+type RestoreProgress func(executedStatements int, totalStatements int)
+
+// \restore <file>
+// Run every statement in a SQL file, such as one written by \dump
+func (db *DBClient) restoreCommand(argument string) (*QueryResult, error) {
+	return db.Restore(argument, nil)
+}
+
+// Run every statement in the SQL file given by \restore's argument in order, reporting progress to onProgress after each
+// Stops at the first statement which fails, leaving those before it applied
+func (db *DBClient) Restore(argument string, onProgress RestoreProgress) (*QueryResult, error) {
+	path := strings.TrimSpace(strings.TrimSuffix(argument, ";"))
+	if path == "" || strings.ContainsFunc(path, unicode.IsSpace) {
+		return nil, errors.New(`Usage: \restore <file>`)
+	}
+
+	script, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to read %s", path),
+			err,
+		)
+	}
+
+	statements := lexer.SplitStatements(db.connManager.GetFlavor(), string(script))
+
+	// Whatever was cached may have been replaced
+	defer func() {
+		db.resultCache.invalidate()
+		db.schema = nil
+	}()
+
+	for idx, statement := range statements {
+		isTransactionStatement, err := db.runTransactionStatement(statement)
+		if !isTransactionStatement {
+			err = db.restoreStatement(statement)
+		}
+		if err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("Restore stopped at statement %d of %d, the statements before it were applied", idx+1, len(statements)),
+				err,
+			)
+		}
+
+		if onProgress != nil {
+			onProgress(idx+1, len(statements))
+		}
+	}
+
+	return newTextResult(
+		[]string{"Restore"},
+		[][]string{{fmt.Sprintf("Ran %d statements from %s", len(statements), path)}},
+	), nil
+}
+
+// Run a statement of a restore, held to safe mode and the policy hook like any other statement sent
+// A rewrite from the policy hook is run in place of the statement
+func (db *DBClient) restoreStatement(statement string) error {
+	statement, err := db.checkStatement(statement)
+	if err != nil {
+		return err
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = connection.ExecContext(db.ctx, statement)
+	return err
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDumpValue(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Flavor   conn.DBFlavor
+		Value    NullString
+		Expected string
+	}{
+		{
+			Name:     "NULL",
+			Flavor:   conn.MySQL,
+			Value:    NullString{},
+			Expected: "NULL",
+		},
+		{
+			Name:     "MySQL text",
+			Flavor:   conn.MySQL,
+			Value:    NullString{sql.NullString{String: `it's C:\temp`, Valid: true}},
+			Expected: `'it''s C:\\temp'`,
+		},
+		{
+			Name:     "Postgres text",
+			Flavor:   conn.PostgreSQL,
+			Value:    NullString{sql.NullString{String: `it's C:\temp`, Valid: true}},
+			Expected: `'it''s C:\temp'`,
+		},
+		{
+			Name:     "MySQL binary",
+			Flavor:   conn.MySQL,
+			Value:    NullString{sql.NullString{String: "\xff\x00\x01", Valid: true}},
+			Expected: "X'ff0001'",
+		},
+		{
+			Name:     "Postgres binary",
+			Flavor:   conn.PostgreSQL,
+			Value:    NullString{sql.NullString{String: "\xff\x00\x01", Valid: true}},
+			Expected: `'\xff0001'::bytea`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.Expected, formatDumpValue(test.Flavor, &test.Value))
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// A foreign key, with columns on either side joined by ", "
+type foreignKey struct {
+	Table             string `db:"table_name"`
+	Columns           string `db:"columns"`
+	ReferencedTable   string `db:"referenced_table"`
+	ReferencedColumns string `db:"referenced_columns"`
+}
+
+// Schema defaults to the current one when empty
+const postgresERDTablesQuery string = `
+SELECT c.relname
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p')
+AND NOT c.relispartition
+AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+ORDER BY c.relname
+`
+
+const postgresERDForeignKeysQuery string = `
+SELECT
+  src.relname AS table_name,
+  (
+    SELECT string_agg(a.attname, ', ' ORDER BY k.ord)
+    FROM unnest(con.conkey) WITH ORDINALITY k(attnum, ord)
+    JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+  ) AS columns,
+  dst.relname AS referenced_table,
+  (
+    SELECT string_agg(a.attname, ', ' ORDER BY k.ord)
+    FROM unnest(con.confkey) WITH ORDINALITY k(attnum, ord)
+    JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+  ) AS referenced_columns
+FROM pg_constraint con
+JOIN pg_class src ON src.oid = con.conrelid
+JOIN pg_class dst ON dst.oid = con.confrelid
+JOIN pg_namespace n ON n.oid = src.relnamespace
+WHERE con.contype = 'f'
+AND n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+ORDER BY src.relname, con.conname
+`
+
+const mySQLERDTablesQuery string = `
+SELECT TABLE_NAME
+FROM information_schema.TABLES
+WHERE TABLE_TYPE = 'BASE TABLE'
+AND TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+ORDER BY TABLE_NAME
+`
+
+const mySQLERDForeignKeysQuery string = `
+SELECT
+  TABLE_NAME AS table_name,
+  GROUP_CONCAT(COLUMN_NAME ORDER BY ORDINAL_POSITION SEPARATOR ', ') AS columns,
+  REFERENCED_TABLE_NAME AS referenced_table,
+  GROUP_CONCAT(REFERENCED_COLUMN_NAME ORDER BY ORDINAL_POSITION SEPARATOR ', ') AS referenced_columns
+FROM information_schema.KEY_COLUMN_USAGE
+WHERE REFERENCED_TABLE_NAME IS NOT NULL
+AND TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE())
+GROUP BY TABLE_NAME, CONSTRAINT_NAME, REFERENCED_TABLE_NAME
+ORDER BY TABLE_NAME, CONSTRAINT_NAME
+`
+
+var erdColumns = []string{"Table", "References", "Referenced By"}
+
+// \erd [schema]
+// Map out how tables relate through foreign keys, also offering the graph as DOT or mermaid to copy
+func (db *DBClient) erdCommand(schema string) (*QueryResult, error) {
+	schema = strings.TrimSuffix(schema, ";")
+
+	var tablesQuery, foreignKeysQuery string
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			tablesQuery, foreignKeysQuery = postgresERDTablesQuery, postgresERDForeignKeysQuery
+		}
+	case conn.MySQL:
+		{
+			tablesQuery, foreignKeysQuery = mySQLERDTablesQuery, mySQLERDForeignKeysQuery
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\erd`, db.connManager.GetFlavor())
+		}
+	}
+
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []string
+	err = connection.SelectContext(db.ctx, &tables, tablesQuery, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("No tables found in schema %s", schema)
+	}
+
+	var foreignKeys []foreignKey
+	err = connection.SelectContext(db.ctx, &foreignKeys, foreignKeysQuery, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newTextResult(erdColumns, buildERDRows(tables, foreignKeys))
+	result.CopyFormats = []ResultCopyFormat{
+		{"DOT", []byte(buildERDDot(tables, foreignKeys))},
+		{"Mermaid", []byte(buildERDMermaid(tables, foreignKeys))},
+	}
+
+	return result, nil
+}
+
+// One row per table, listing foreign keys in both directions
+func buildERDRows(tables []string, foreignKeys []foreignKey) (rows [][]string) {
+	references := make(map[string][]string, len(tables))
+	referencedBy := make(map[string][]string, len(tables))
+
+	for _, foreignKey := range foreignKeys {
+		references[foreignKey.Table] = append(
+			references[foreignKey.Table],
+			fmt.Sprintf("%s (%s → %s)", foreignKey.ReferencedTable, foreignKey.Columns, foreignKey.ReferencedColumns),
+		)
+		referencedBy[foreignKey.ReferencedTable] = append(
+			referencedBy[foreignKey.ReferencedTable],
+			fmt.Sprintf("%s (%s → %s)", foreignKey.Table, foreignKey.Columns, foreignKey.ReferencedColumns),
+		)
+	}
+
+	for _, table := range tables {
+		rows = append(rows, []string{
+			table,
+			strings.Join(references[table], "; "),
+			strings.Join(referencedBy[table], "; "),
+		})
+	}
+
+	return rows
+}
+
+// Graphviz digraph, with an edge from each table to the tables it references
+func buildERDDot(tables []string, foreignKeys []foreignKey) string {
+	var dot strings.Builder
+
+	dot.WriteString("digraph erd {\n")
+	for _, table := range tables {
+		fmt.Fprintf(&dot, "  %q;\n", table)
+	}
+	for _, foreignKey := range foreignKeys {
+		fmt.Fprintf(
+			&dot,
+			"  %q -> %q [label=%q];\n",
+			foreignKey.Table,
+			foreignKey.ReferencedTable,
+			fmt.Sprintf("%s → %s", foreignKey.Columns, foreignKey.ReferencedColumns),
+		)
+	}
+	dot.WriteString("}\n")
+
+	return dot.String()
+}
+
+// Mermaid erDiagram, each foreign key being many-to-one
+func buildERDMermaid(tables []string, foreignKeys []foreignKey) string {
+	var mermaid strings.Builder
+
+	mermaid.WriteString("erDiagram\n")
+	for _, table := range tables {
+		fmt.Fprintf(&mermaid, "  %s\n", table)
+	}
+	for _, foreignKey := range foreignKeys {
+		fmt.Fprintf(
+			&mermaid,
+			"  %s }o--|| %s : %q\n",
+			foreignKey.Table,
+			foreignKey.ReferencedTable,
+			foreignKey.Columns,
+		)
+	}
+
+	return mermaid.String()
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var erdTestTables = []string{"comments", "posts", "tags", "users"}
+
+var erdTestForeignKeys = []foreignKey{
+	{"comments", "post_id", "posts", "id"},
+	{"comments", "author_id", "users", "id"},
+	{"posts", "user_id", "users", "id"},
+}
+
+func TestBuildERDRows(t *testing.T) {
+	assert := assert.New(t)
+
+	rows := buildERDRows(erdTestTables, erdTestForeignKeys)
+
+	assert.Equal(
+		[][]string{
+			{"comments", "posts (post_id → id); users (author_id → id)", ""},
+			{"posts", "users (user_id → id)", "comments (post_id → id)"},
+			{"tags", "", ""},
+			{"users", "", "comments (author_id → id); posts (user_id → id)"},
+		},
+		rows,
+	)
+}
+
+func TestBuildERDDot(t *testing.T) {
+	assert := assert.New(t)
+
+	dot := buildERDDot(erdTestTables, erdTestForeignKeys)
+
+	assert.Equal(
+		`digraph erd {
+  "comments";
+  "posts";
+  "tags";
+  "users";
+  "comments" -> "posts" [label="post_id → id"];
+  "comments" -> "users" [label="author_id → id"];
+  "posts" -> "users" [label="user_id → id"];
+}
+`,
+		dot,
+	)
+}
+
+func TestBuildERDMermaid(t *testing.T) {
+	assert := assert.New(t)
+
+	mermaid := buildERDMermaid(erdTestTables, erdTestForeignKeys)
+
+	assert.Equal(
+		`erDiagram
+  comments
+  posts
+  tags
+  users
+  comments }o--|| posts : "post_id"
+  comments }o--|| users : "author_id"
+  posts }o--|| users : "user_id"
+`,
+		mermaid,
+	)
+}
@@ -0,0 +1,217 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Outcome of a statement which doesn't return rows, such as DDL or DML
+type ExecResult struct {
+	// Describes what happened, ex: Table created
+	Status string
+	// -1 when the database doesn't report it
+	RowsAffected int64
+	// ID generated for an AUTO_INCREMENT column by an INSERT on MySQL, 0 otherwise
+	LastInsertID int64
+	Duration     time.Duration
+}
+
+// Status with how long the statement took, ex: Table created (0.02s) or Query OK, 1 row affected, last insert ID 7 (0.01s)
+func (execResult *ExecResult) Summary() string {
+	status := execResult.Status
+	if execResult.LastInsertID > 0 {
+		status = fmt.Sprint(status, ", last insert ID ", execResult.LastInsertID)
+	}
+
+	return fmt.Sprintf("%s (%.2fs)", status, execResult.Duration.Seconds())
+}
+
+// Statements which never return rows, by their first keyword
+var execStatementKeywords = map[string]bool{
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true, "COMMENT": true,
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "MERGE": true,
+	"GRANT": true, "REVOKE": true, "SET": true, "USE": true, "LOCK": true, "UNLOCK": true,
+}
+
+// What a statement did, by its first keyword, when it doesn't say how many rows it affected
+var execStatementVerbs = map[string]string{
+	"CREATE": "created", "ALTER": "altered", "DROP": "dropped", "TRUNCATE": "truncated", "RENAME": "renamed",
+}
+
+// What statements which don't affect a number of rows or create objects did, by their first keyword
+var statementStatuses = map[string]string{
+	"CALL": "Procedure called", "DO": "Code block run", "VACUUM": "Vacuum done", "ANALYZE": "Statistics updated",
+	"REINDEX": "Indexes rebuilt", "REFRESH": "Materialized view refreshed", "RESET": "Setting reset",
+	"DISCARD": "Session state discarded", "CHECKPOINT": "Checkpoint done", "FLUSH": "Flushed",
+}
+
+// Whether a statement is DDL or DML which doesn't return rows, and so should be run with Exec rather than Query
+// Anything which isn't certain to be, such as meta commands or INSERT ... RETURNING, is left for Query
+func (db *DBClient) IsExecStatement(statement string) bool {
+	return isExecStatement(db.connManager.GetFlavor(), statement)
+}
+
+func isExecStatement(flavor conn.DBFlavor, statement string) bool {
+	if rawStatement, isRaw := stripRawPrefix(statement); isRaw {
+		statement = rawStatement
+	} else if _, _, isMetaCommand := ParseMetaCommand(statement); isMetaCommand {
+		return false
+	} else if _, isUse := parseUseStatement(flavor, statement); isUse {
+		// Switches the connection's database, which Query handles
+		return false
+	}
+
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 || !execStatementKeywords[strings.ToUpper(tokens[0].Text)] {
+		return false
+	}
+
+	for _, token := range tokens {
+		if token.IsKeyword("RETURNING") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Run a statement which doesn't return rows, such as DDL or DML, reporting what it did
+func (db *DBClient) Exec(statement string) (result *ExecResult, err error) {
+	startTime := time.Now()
+	defer func() {
+		db.recordStatement(statement, time.Since(startTime), nil, err)
+	}()
+
+	if rawStatement, isRaw := stripRawPrefix(statement); isRaw {
+		statement = rawStatement
+	}
+
+	statement, err = db.checkStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var execResult sql.Result
+	err = db.trackSearchPathChange(statement, func() (err error) {
+		execResult, err = connection.ExecContext(db.ctx, statement)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Query Failed"),
+			err,
+		)
+	}
+
+	rowsAffected, err := execResult.RowsAffected()
+	if err != nil {
+		rowsAffected = -1
+	}
+
+	// Postgres has no equivalent, RETURNING is used instead
+	var lastInsertID int64
+	if db.connManager.GetFlavor() == conn.MySQL {
+		if insertID, err := execResult.LastInsertId(); err == nil {
+			lastInsertID = insertID
+		}
+	}
+
+	db.resultCache.invalidate()
+
+	status := describeExecStatement(db.connManager.GetFlavor(), statement, rowsAffected)
+
+	// DDL may change the tables metadata is cached for
+	if isSchemaChange(db.connManager.GetFlavor(), statement) {
+		db.schema = nil
+	}
+
+	return &ExecResult{
+		Status:       status,
+		RowsAffected: rowsAffected,
+		LastInsertID: lastInsertID,
+		Duration:     time.Since(startTime),
+	}, nil
+}
+
+func isSchemaChange(flavor conn.DBFlavor, statement string) bool {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	return len(tokens) > 0 && execStatementVerbs[strings.ToUpper(tokens[0].Text)] != ""
+}
+
+// Describe what a statement did, ex: Table created or Query OK, 3 rows affected
+func describeExecStatement(flavor conn.DBFlavor, statement string, rowsAffected int64) string {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 {
+		return "Statement executed"
+	}
+
+	keyword := strings.ToUpper(tokens[0].Text)
+
+	switch keyword {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE", "MERGE":
+		{
+			if rowsAffected < 0 {
+				return "Query OK"
+			}
+
+			rowLabel := "rows"
+			if rowsAffected == 1 {
+				rowLabel = "row"
+			}
+
+			return fmt.Sprintf("Query OK, %d %s affected", rowsAffected, rowLabel)
+		}
+	case "GRANT":
+		{
+			return "Privileges granted"
+		}
+	case "REVOKE":
+		{
+			return "Privileges revoked"
+		}
+	case "TRUNCATE":
+		{
+			return "Table truncated"
+		}
+	}
+
+	if status, hasStatus := statementStatuses[keyword]; hasStatus {
+		return status
+	}
+
+	verb, hasVerb := execStatementVerbs[keyword]
+	if !hasVerb {
+		return "Statement executed"
+	}
+
+	// Name the kind of object, skipping modifiers such as OR REPLACE, UNIQUE or TEMPORARY
+	for _, token := range tokens[1:] {
+		if token.Kind != lexer.Word {
+			break
+		}
+
+		objectKind := strings.ToUpper(token.Text)
+		if schemaObjectKinds[objectKind] {
+			return fmt.Sprint(strings.ToUpper(objectKind[:1]), strings.ToLower(objectKind[1:]), " ", verb)
+		}
+	}
+
+	return fmt.Sprint("Statement ", verb)
+}
+
+var schemaObjectKinds = map[string]bool{
+	"TABLE": true, "VIEW": true, "INDEX": true, "SCHEMA": true, "DATABASE": true, "SEQUENCE": true,
+	"FUNCTION": true, "PROCEDURE": true, "TRIGGER": true, "TYPE": true, "EXTENSION": true, "USER": true,
+	"ROLE": true, "EVENT": true, "DOMAIN": true, "POLICY": true,
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsExecStatement(t *testing.T) {
+	var tests = []struct {
+		Statement      string
+		ExpectedIsExec bool
+	}{
+		{"CREATE TABLE foo (id int)", true},
+		{"  -- add a row\n insert into foo (id) values (1)", true},
+		{"UPDATE foo SET id = 2", true},
+		{`\raw DROP TABLE foo`, true},
+		{"INSERT INTO foo (id) VALUES (1) RETURNING id", false},
+		{"SELECT * FROM foo", false},
+		{"WITH ids AS (SELECT 1) SELECT * FROM ids", false},
+		{"DESCRIBE foo", false},
+		{"BEGIN", false},
+		{"USE analytics", false},
+		{`\seed foo 10`, false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.ExpectedIsExec, isExecStatement(conn.PostgreSQL, test.Statement))
+		})
+	}
+}
+
+func TestDescribeExecStatement(t *testing.T) {
+	var tests = []struct {
+		Statement      string
+		RowsAffected   int64
+		ExpectedStatus string
+	}{
+		{"CREATE TABLE foo (id int)", 0, "Table created"},
+		{"create or replace view bar as select 1", 0, "View created"},
+		{"CREATE UNIQUE INDEX foo_id ON foo (id)", 0, "Index created"},
+		{"DROP TABLE IF EXISTS foo", 0, "Table dropped"},
+		{"ALTER TABLE foo ADD COLUMN name text", 0, "Table altered"},
+		{"TRUNCATE foo", 0, "Table truncated"},
+		{"INSERT INTO foo (id) VALUES (1)", 1, "Query OK, 1 row affected"},
+		{"UPDATE foo SET id = 2", 3, "Query OK, 3 rows affected"},
+		{"DELETE FROM foo", 0, "Query OK, 0 rows affected"},
+		{"UPDATE foo SET id = 2", -1, "Query OK"},
+		{"GRANT SELECT ON foo TO someone", 0, "Privileges granted"},
+		{"SET search_path TO app", 0, "Statement executed"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.ExpectedStatus, describeExecStatement(conn.PostgreSQL, test.Statement, test.RowsAffected))
+		})
+	}
+}
+
+func TestNewStatusResult(t *testing.T) {
+	var tests = []struct {
+		Flavor         conn.DBFlavor
+		Statement      string
+		ExpectedStatus string
+	}{
+		{conn.MySQL, "SET @answer = 42", "Statement executed"},
+		{conn.PostgreSQL, "SET search_path TO app", "Statement executed"},
+		{conn.MySQL, "CREATE TABLE foo (id int)", "Table created"},
+		{conn.MySQL, "DROP INDEX foo_id ON foo", "Index dropped"},
+		{conn.PostgreSQL, "INSERT INTO foo (id) VALUES (1)", "Query OK"},
+		{conn.MySQL, "CALL refresh_totals()", "Procedure called"},
+		{conn.PostgreSQL, "VACUUM foo", "Vacuum done"},
+		{conn.PostgreSQL, "REFRESH MATERIALIZED VIEW totals", "Materialized view refreshed"},
+		{conn.PostgreSQL, "LISTEN jobs", "Statement executed"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+
+			result := newStatusResult(test.Flavor, test.Statement)
+			assert.Equal(test.ExpectedStatus, result.Status)
+			assert.Empty(result.Columns)
+			assert.NotNil(result.Rows)
+		})
+	}
+}
+
+func TestExecResultSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	result := &ExecResult{Status: "Query OK, 1 row affected", RowsAffected: 1, Duration: 10 * time.Millisecond}
+	assert.Equal("Query OK, 1 row affected (0.01s)", result.Summary())
+
+	result.LastInsertID = 7
+	assert.Equal("Query OK, 1 row affected, last insert ID 7 (0.01s)", result.Summary())
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Insert a single row, with values passed as parameters rather than inlined into the statement
+// Returns the statement that was run, for display
+func (db *DBClient) InsertRow(tableName string, columns []string, values []*NullString) (statement string, result *QueryResult, err error) {
+	if len(columns) != len(values) {
+		return "", nil, errors.New("Each column being inserted needs a value")
+	}
+
+	db.resultCache.invalidate()
+
+	statementWithParams := db.buildInsertRow(tableName, columns, values)
+	statementWithParams.statement, err = db.checkStatement(statementWithParams.statement)
+	if err != nil {
+		return statementWithParams.statement, nil, err
+	}
+
+	result, err = db.runStatement(statementWithParams)
+	if err != nil {
+		return statementWithParams.statement, nil, errors.Join(
+			errors.New("Insert Failed"),
+			err,
+		)
+	}
+
+	return statementWithParams.statement, result, nil
+}
+
+func (db *DBClient) buildInsertRow(tableName string, columns []string, values []*NullString) *StatementWithParams {
+	// Without any values, every column gets its default
+	if len(columns) == 0 {
+		if db.connManager.GetFlavor() == conn.MySQL {
+			return &StatementWithParams{fmt.Sprintf("INSERT INTO %s () VALUES ()", db.quoteIdentifier(tableName)), nil}
+		}
+
+		return &StatementWithParams{fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", db.quoteIdentifier(tableName)), nil}
+	}
+
+	quotedColumns := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	params := make([]interface{}, len(columns))
+	for idx, column := range columns {
+		quotedColumns[idx] = db.quoteIdentifier(column)
+		placeholders[idx] = db.getParamPlaceholder(idx)
+
+		if values[idx] != nil && values[idx].Valid {
+			params[idx] = values[idx].String
+		}
+	}
+
+	return &StatementWithParams{
+		fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES (%s)",
+			db.quoteIdentifier(tableName),
+			strings.Join(quotedColumns, ", "),
+			strings.Join(placeholders, ", "),
+		),
+		params,
+	}
+}
+
+// Most databases cap the number of parameters in a single statement at 65535
+const maxInsertParams = 65535
+
+// Rows per INSERT when inserting many rows at once
+const insertBatchSize = 500
+
+// Called after each batch of a bulk insert, with how many of the rows have been inserted so far
+type BulkInsertProgress func(insertedRows int, totalRows int)
+
+// Insert many rows using multi-row INSERTs, each of at most insertBatchSize rows
+// A nil value inserts NULL. On failure, the number of rows inserted by earlier batches is returned
+func (db *DBClient) BulkInsert(tableName string, columns []string, rows [][]any) (insertedRows int, err error) {
+	return db.BulkInsertWithProgress(tableName, columns, rows, nil)
+}
+
+// Same as BulkInsert, reporting progress to onProgress after each batch
+func (db *DBClient) BulkInsertWithProgress(
+	tableName string,
+	columns []string,
+	rows [][]any,
+	onProgress BulkInsertProgress,
+) (insertedRows int, err error) {
+	if len(columns) == 0 {
+		return 0, errors.New("No columns to insert")
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return 0, err
+	}
+
+	db.resultCache.invalidate()
+
+	batchSize := min(insertBatchSize, maxInsertParams/len(columns))
+
+	quotedColumns := make([]string, len(columns))
+	for idx, column := range columns {
+		quotedColumns[idx] = db.quoteIdentifier(column)
+	}
+
+	for batchStart := 0; batchStart < len(rows); batchStart += batchSize {
+		batch := rows[batchStart:min(batchStart+batchSize, len(rows))]
+
+		rowPlaceholders := make([]string, len(batch))
+		params := make([]any, 0, len(batch)*len(columns))
+		for rowIdx, row := range batch {
+			if len(row) != len(columns) {
+				return insertedRows, fmt.Errorf("Row %d has %d values for %d columns", batchStart+rowIdx+1, len(row), len(columns))
+			}
+
+			placeholders := make([]string, len(columns))
+			for columnIdx := range columns {
+				placeholders[columnIdx] = db.getParamPlaceholder(len(params))
+				params = append(params, row[columnIdx])
+			}
+
+			rowPlaceholders[rowIdx] = fmt.Sprintf("(%s)", strings.Join(placeholders, ", "))
+		}
+
+		insertStatement, err := db.checkStatement(fmt.Sprintf(
+			"INSERT INTO %s (%s) VALUES %s",
+			db.quoteIdentifier(tableName),
+			strings.Join(quotedColumns, ", "),
+			strings.Join(rowPlaceholders, ", "),
+		))
+		if err != nil {
+			return insertedRows, err
+		}
+
+		_, err = connection.ExecContext(db.ctx, insertStatement, params...)
+		if err != nil {
+			return insertedRows, err
+		}
+
+		insertedRows += len(batch)
+		if onProgress != nil {
+			onProgress(insertedRows, len(rows))
+		}
+	}
+
+	return insertedRows, nil
+}
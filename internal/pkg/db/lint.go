@@ -0,0 +1,145 @@
+package db
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Checks a statement for syntax from another flavor, at the token at idx
+// Returns a warning if the token starts such syntax
+type lintRule func(tokens []lexer.Token, idx int) (warning string)
+
+var postgresLintRules = []lintRule{
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("LIMIT") &&
+			tokenKindAt(tokens, idx+1) == lexer.Number &&
+			tokenAt(tokens, idx+2).IsOperator(",") {
+			return "LIMIT offset, count is MySQL syntax, use LIMIT count OFFSET offset"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].Kind == lexer.QuotedIdentifier && strings.HasPrefix(tokens[idx].Text, "`") {
+			return "Backticks are MySQL syntax, quote identifiers with double quotes"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("AUTO_INCREMENT") {
+			return "AUTO_INCREMENT is MySQL syntax, use GENERATED ALWAYS AS IDENTITY"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("IFNULL") && tokenAt(tokens, idx+1).IsOperator("(") {
+			return "IFNULL is MySQL syntax, use COALESCE"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("UNSIGNED") {
+			return "PostgreSQL has no UNSIGNED types"
+		}
+		return ""
+	},
+}
+
+var mySQLLintRules = []lintRule{
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("ILIKE") {
+			return "ILIKE is PostgreSQL syntax, use LIKE which is case insensitive for most collations"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsOperator("::") {
+			return "Casting with :: is PostgreSQL syntax, use CAST(x AS type)"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("RETURNING") {
+			return "MySQL does not support RETURNING"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("DISTINCT") && tokenAt(tokens, idx+1).IsKeyword("ON") {
+			return "DISTINCT ON is PostgreSQL syntax, MySQL does not support it"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("FULL") &&
+			(tokenAt(tokens, idx+1).IsKeyword("JOIN") || tokenAt(tokens, idx+1).IsKeyword("OUTER")) {
+			return "MySQL does not support FULL OUTER JOIN, combine a LEFT JOIN and RIGHT JOIN with UNION"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsOperator("||") {
+			return "|| is logical OR in MySQL, use CONCAT() to join strings"
+		}
+		return ""
+	},
+	func(tokens []lexer.Token, idx int) string {
+		if tokens[idx].IsKeyword("NULLS") &&
+			(tokenAt(tokens, idx+1).IsKeyword("FIRST") || tokenAt(tokens, idx+1).IsKeyword("LAST")) {
+			return "MySQL does not support NULLS FIRST / NULLS LAST"
+		}
+		return ""
+	},
+}
+
+// Warn about syntax in a statement which isn't valid for the current flavor, before it's sent
+// Warnings are hints only, the statement may still be run
+func (db *DBClient) Lint(statement string) (warnings []string) {
+	return lintStatement(db.connManager.GetFlavor(), statement)
+}
+
+func lintStatement(flavor conn.DBFlavor, statement string) (warnings []string) {
+	// Commands are handled by the client, not the database
+	if _, _, isMetaCommand := ParseMetaCommand(statement); isMetaCommand {
+		return nil
+	}
+
+	var rules []lintRule
+	switch flavor {
+	case conn.PostgreSQL:
+		{
+			rules = postgresLintRules
+		}
+	case conn.MySQL:
+		{
+			rules = mySQLLintRules
+		}
+	}
+
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	for idx := range tokens {
+		for _, rule := range rules {
+			warning := rule(tokens, idx)
+			if warning != "" && !slices.Contains(warnings, warning) {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	return warnings
+}
+
+// Token at idx, or an empty token past the end
+func tokenAt(tokens []lexer.Token, idx int) lexer.Token {
+	if idx >= len(tokens) {
+		return lexer.Token{}
+	}
+
+	return tokens[idx]
+}
+
+func tokenKindAt(tokens []lexer.Token, idx int) lexer.TokenKind {
+	return tokenAt(tokens, idx).Kind
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintStatement(t *testing.T) {
+	var tests = []struct {
+		Flavor           conn.DBFlavor
+		Statement        string
+		ExpectedWarnings []string
+	}{
+		{conn.PostgreSQL, "SELECT * FROM users LIMIT 10, 20;", []string{"LIMIT offset, count is MySQL syntax, use LIMIT count OFFSET offset"}},
+		{conn.PostgreSQL, "SELECT * FROM users LIMIT 10 OFFSET 20;", nil},
+		{conn.PostgreSQL, "SELECT `id` FROM `users`;", []string{"Backticks are MySQL syntax, quote identifiers with double quotes"}},
+		{conn.PostgreSQL, "SELECT IFNULL(name, '') FROM users WHERE name ILIKE 'a%';", []string{"IFNULL is MySQL syntax, use COALESCE"}},
+		{conn.PostgreSQL, "SELECT 'LIMIT 1, 2', '`quoted`';", nil},
+		{conn.MySQL, "SELECT * FROM users WHERE name ILIKE 'a%';", []string{"ILIKE is PostgreSQL syntax, use LIKE which is case insensitive for most collations"}},
+		{conn.MySQL, "SELECT id::text FROM users;", []string{"Casting with :: is PostgreSQL syntax, use CAST(x AS type)"}},
+		{conn.MySQL, "SELECT first || ' ' || last FROM users ORDER BY id NULLS LAST;", []string{
+			"|| is logical OR in MySQL, use CONCAT() to join strings",
+			"MySQL does not support NULLS FIRST / NULLS LAST",
+		}},
+		{conn.MySQL, "SELECT * FROM a FULL OUTER JOIN b ON a.id = b.id;", []string{"MySQL does not support FULL OUTER JOIN, combine a LEFT JOIN and RIGHT JOIN with UNION"}},
+		{conn.MySQL, "SELECT * FROM users LIMIT 10, 20;", nil},
+		{conn.MySQL, `\translate SELECT id::text FROM users;`, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Statement, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			assert.Equal(test.ExpectedWarnings, lintStatement(test.Flavor, test.Statement))
+		})
+	}
+}
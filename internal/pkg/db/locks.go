@@ -0,0 +1,276 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// A database session which is either waiting on, or holding, a lock another session wants
+type lockSession struct {
+	ID         string `db:"id"`
+	User       string `db:"user"`
+	State      string `db:"state"`
+	Seconds    string `db:"seconds"`
+	WaitingFor string `db:"waiting_for"`
+	Query      string `db:"query"`
+	// IDs of the sessions holding locks this session is waiting on
+	BlockedBy []string
+}
+
+var locksColumns = []string{"Session", "Blocking", "User", "State", "Seconds", "Waiting For", "Query"}
+
+// \locks
+// Show which sessions are blocked waiting on locks, as a tree under the session blocking them
+func (db *DBClient) locksCommand(argument string) (*QueryResult, error) {
+	var sessions []*lockSession
+	var err error
+
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			sessions, err = db.getPostgresLockSessions()
+		}
+	case conn.MySQL:
+		{
+			sessions, err = db.getMySQLLockSessions()
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\locks`, db.connManager.GetFlavor())
+		}
+	}
+
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to get lock information"),
+			err,
+		)
+	}
+
+	return newTextResult(locksColumns, buildLockTreeRows(sessions)), nil
+}
+
+const postgresLockSessionsQuery string = `
+WITH sessions AS (
+  SELECT
+    a.pid,
+    COALESCE(a.usename, '') AS "user",
+    COALESCE(a.state, '') AS state,
+    COALESCE(EXTRACT(EPOCH FROM now() - a.query_start)::bigint::text, '') AS seconds,
+    COALESCE((
+      SELECT string_agg(DISTINCT l.mode || ' on ' || COALESCE(l.relation::regclass::text, l.locktype), ', ')
+      FROM pg_locks l
+      WHERE l.pid = a.pid AND NOT l.granted
+    ), '') AS waiting_for,
+    COALESCE(a.query, '') AS query,
+    pg_blocking_pids(a.pid) AS blocked_by
+  FROM pg_stat_activity a
+  WHERE a.pid <> pg_backend_pid()
+)
+SELECT
+  pid::text AS id,
+  "user",
+  state,
+  seconds,
+  waiting_for,
+  query,
+  array_to_string(blocked_by, ',') AS blocked_by
+FROM sessions
+WHERE cardinality(blocked_by) > 0
+OR pid IN (SELECT unnest(blocked_by) FROM sessions)
+`
+
+func (db *DBClient) getPostgresLockSessions() ([]*lockSession, error) {
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		lockSession
+		RawBlockedBy string `db:"blocked_by"`
+	}
+	err = connection.SelectContext(db.ctx, &rows, postgresLockSessionsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*lockSession, len(rows))
+	for idx, row := range rows {
+		session := row.lockSession
+		if row.RawBlockedBy != "" {
+			session.BlockedBy = strings.Split(row.RawBlockedBy, ",")
+		}
+
+		sessions[idx] = &session
+	}
+
+	return sessions, nil
+}
+
+const mySQLLockWaitsQuery string = `
+SELECT
+  CAST(rt.PROCESSLIST_ID AS CHAR) AS waiting_id,
+  CAST(bt.PROCESSLIST_ID AS CHAR) AS blocking_id,
+  CONCAT(rl.LOCK_MODE, ' on ', rl.OBJECT_SCHEMA, '.', rl.OBJECT_NAME) AS waiting_for
+FROM performance_schema.data_lock_waits w
+JOIN performance_schema.threads rt ON rt.THREAD_ID = w.REQUESTING_THREAD_ID
+JOIN performance_schema.threads bt ON bt.THREAD_ID = w.BLOCKING_THREAD_ID
+JOIN performance_schema.data_locks rl ON rl.ENGINE_LOCK_ID = w.REQUESTING_ENGINE_LOCK_ID
+`
+
+const mySQLLockSessionsQuery string = `
+SELECT
+  CAST(ID AS CHAR) AS id,
+  COALESCE(USER, '') AS user,
+  COALESCE(STATE, COMMAND, '') AS state,
+  CAST(TIME AS CHAR) AS seconds,
+  '' AS waiting_for,
+  COALESCE(INFO, '') AS query
+FROM information_schema.PROCESSLIST
+WHERE ID <> CONNECTION_ID()
+`
+
+func (db *DBClient) getMySQLLockSessions() ([]*lockSession, error) {
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var lockWaits []struct {
+		WaitingID  string `db:"waiting_id"`
+		BlockingID string `db:"blocking_id"`
+		WaitingFor string `db:"waiting_for"`
+	}
+	err = connection.SelectContext(db.ctx, &lockWaits, mySQLLockWaitsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lockWaits) == 0 {
+		return nil, nil
+	}
+
+	var allSessions []*lockSession
+	err = connection.SelectContext(db.ctx, &allSessions, mySQLLockSessionsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionsByID := make(map[string]*lockSession, len(allSessions))
+	for _, session := range allSessions {
+		sessionsByID[session.ID] = session
+	}
+
+	// Only sessions involved in a lock wait are of interest
+	var involvedSessions []*lockSession
+	getInvolvedSession := func(id string) *lockSession {
+		session, exists := sessionsByID[id]
+		if !exists {
+			// Session may have ended since the lock waits were read
+			session = &lockSession{ID: id}
+			sessionsByID[id] = session
+		}
+
+		if !slices.Contains(involvedSessions, session) {
+			involvedSessions = append(involvedSessions, session)
+		}
+
+		return session
+	}
+
+	for _, lockWait := range lockWaits {
+		waitingSession := getInvolvedSession(lockWait.WaitingID)
+		getInvolvedSession(lockWait.BlockingID)
+
+		if !slices.Contains(waitingSession.BlockedBy, lockWait.BlockingID) {
+			waitingSession.BlockedBy = append(waitingSession.BlockedBy, lockWait.BlockingID)
+		}
+		if waitingSession.WaitingFor == "" {
+			waitingSession.WaitingFor = lockWait.WaitingFor
+		}
+	}
+
+	return involvedSessions, nil
+}
+
+// Lay out sessions as a tree, each blocked session indented under the session(s) blocking it
+// Sessions at the root of the tree are not waiting on anything, and are the ones to look at first
+func buildLockTreeRows(sessions []*lockSession) (rows [][]string) {
+	sessionsByID := make(map[string]*lockSession, len(sessions))
+	blockedSessionIDs := make(map[string][]string, len(sessions))
+	for _, session := range sessions {
+		sessionsByID[session.ID] = session
+		for _, blockingID := range session.BlockedBy {
+			blockedSessionIDs[blockingID] = append(blockedSessionIDs[blockingID], session.ID)
+		}
+	}
+
+	// Count every session waiting, directly or indirectly, on a given session
+	var countBlocked func(id string, visited map[string]bool) int
+	countBlocked = func(id string, visited map[string]bool) (count int) {
+		for _, blockedID := range blockedSessionIDs[id] {
+			if visited[blockedID] {
+				continue
+			}
+			visited[blockedID] = true
+			count += 1 + countBlocked(blockedID, visited)
+		}
+
+		return count
+	}
+
+	var addSession func(id string, depth int, path map[string]bool)
+	addSession = func(id string, depth int, path map[string]bool) {
+		session, exists := sessionsByID[id]
+		if !exists {
+			session = &lockSession{ID: id}
+		}
+
+		label := session.ID
+		if depth > 0 {
+			label = fmt.Sprint(strings.Repeat("   ", depth-1), "└─ ", session.ID)
+		}
+
+		rows = append(rows, []string{
+			label,
+			fmt.Sprint(countBlocked(id, map[string]bool{id: true})),
+			session.User,
+			session.State,
+			session.Seconds,
+			session.WaitingFor,
+			strings.Join(strings.Fields(session.Query), " "),
+		})
+
+		// Guard against cycles, i.e. a deadlock that hasn't been resolved yet
+		path[id] = true
+		defer delete(path, id)
+
+		for _, blockedID := range blockedSessionIDs[id] {
+			if !path[blockedID] {
+				addSession(blockedID, depth+1, path)
+			}
+		}
+	}
+
+	var rootIDs []string
+	for _, session := range sessions {
+		if len(session.BlockedBy) == 0 {
+			rootIDs = append(rootIDs, session.ID)
+		}
+	}
+	// Every session is waiting on another, start from an arbitrary one in the cycle
+	if len(rootIDs) == 0 && len(sessions) > 0 {
+		rootIDs = append(rootIDs, sessions[0].ID)
+	}
+
+	for _, rootID := range rootIDs {
+		addSession(rootID, 0, map[string]bool{})
+	}
+
+	return rows
+}
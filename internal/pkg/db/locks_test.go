@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLockTreeRows(t *testing.T) {
+	var tests = []struct {
+		Name             string
+		Sessions         []*lockSession
+		ExpectedSessions []string
+		ExpectedBlocking []string
+	}{
+		{
+			Name:     "No Lock Waits",
+			Sessions: nil,
+		},
+		{
+			Name: "Chain",
+			Sessions: []*lockSession{
+				{ID: "3", BlockedBy: []string{"2"}},
+				{ID: "2", BlockedBy: []string{"1"}},
+				{ID: "1"},
+			},
+			ExpectedSessions: []string{"1", "└─ 2", "   └─ 3"},
+			ExpectedBlocking: []string{"2", "1", "0"},
+		},
+		{
+			Name: "Multiple Blocked",
+			Sessions: []*lockSession{
+				{ID: "1"},
+				{ID: "2", BlockedBy: []string{"1"}},
+				{ID: "3", BlockedBy: []string{"1"}},
+				{ID: "4"},
+				{ID: "5", BlockedBy: []string{"4"}},
+			},
+			ExpectedSessions: []string{"1", "└─ 2", "└─ 3", "4", "└─ 5"},
+			ExpectedBlocking: []string{"2", "0", "0", "1", "0"},
+		},
+		{
+			Name: "Deadlock",
+			Sessions: []*lockSession{
+				{ID: "1", BlockedBy: []string{"2"}},
+				{ID: "2", BlockedBy: []string{"1"}},
+			},
+			ExpectedSessions: []string{"1", "└─ 2"},
+			ExpectedBlocking: []string{"1", "1"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			rows := buildLockTreeRows(test.Sessions)
+
+			var actualSessions, actualBlocking []string
+			for _, row := range rows {
+				assert.Len(row, len(locksColumns))
+				actualSessions = append(actualSessions, row[0])
+				actualBlocking = append(actualBlocking, row[1])
+			}
+
+			assert.Equal(test.ExpectedSessions, actualSessions)
+			assert.Equal(test.ExpectedBlocking, actualBlocking)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// One row of vacuum/analyze history and dead tuple counts
+const postgresMaintenanceQuery string = `
+SELECT
+  relname AS "Table",
+  n_live_tup AS "Live Tuples",
+  n_dead_tup AS "Dead Tuples",
+  CASE
+    WHEN n_live_tup + n_dead_tup = 0 THEN 0
+    ELSE round(100.0 * n_dead_tup / (n_live_tup + n_dead_tup), 2)
+  END AS "Dead %",
+  n_mod_since_analyze AS "Modified Since Analyze",
+  last_vacuum AS "Last Vacuum",
+  last_autovacuum AS "Last Autovacuum",
+  last_analyze AS "Last Analyze",
+  last_autoanalyze AS "Last Autoanalyze"
+FROM pg_stat_user_tables
+WHERE schemaname = current_schema()
+AND relname = $1
+`
+
+// One row of size and fragmentation stats
+// Fragmentation is the share of allocated space which is free, and reclaimable by OPTIMIZE TABLE
+const mySQLMaintenanceQuery string = `
+SELECT
+  TABLE_NAME AS "Table",
+  ENGINE AS "Engine",
+  TABLE_ROWS AS "Rows (Estimate)",
+  DATA_LENGTH AS "Data Bytes",
+  INDEX_LENGTH AS "Index Bytes",
+  DATA_FREE AS "Free Bytes",
+  CASE
+    WHEN DATA_LENGTH + INDEX_LENGTH + DATA_FREE = 0 THEN 0
+    ELSE ROUND(100 * DATA_FREE / (DATA_LENGTH + INDEX_LENGTH + DATA_FREE), 2)
+  END AS "Fragmentation %",
+  UPDATE_TIME AS "Last Update",
+  CHECK_TIME AS "Last Check"
+FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = DATABASE()
+AND TABLE_NAME = ?
+`
+
+// \maintenance <table>
+// Show when a table was last vacuumed/analyzed or how fragmented it is, offering to run the fix
+func (db *DBClient) maintenanceCommand(tableName string) (*QueryResult, error) {
+	tableName = strings.TrimSuffix(tableName, ";")
+	if tableName == "" {
+		return nil, errors.New(`Usage: \maintenance <table>`)
+	}
+
+	var statementWithParams *StatementWithParams
+	var actions []ResultAction
+	quotedTableName := db.quoteIdentifier(tableName)
+
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			err := db.assertPostgresTableExists(tableName)
+			if err != nil {
+				return nil, err
+			}
+
+			statementWithParams = &StatementWithParams{postgresMaintenanceQuery, []interface{}{tableName}}
+			actions = []ResultAction{
+				{"Analyze", fmt.Sprintf("ANALYZE %s;", quotedTableName)},
+				{"Vacuum", fmt.Sprintf("VACUUM (ANALYZE) %s;", quotedTableName)},
+			}
+		}
+	case conn.MySQL:
+		{
+			statementWithParams = &StatementWithParams{mySQLMaintenanceQuery, []interface{}{tableName}}
+			actions = []ResultAction{
+				{"Analyze", fmt.Sprintf("ANALYZE TABLE %s;", quotedTableName)},
+				{"Optimize", fmt.Sprintf("OPTIMIZE TABLE %s;", quotedTableName)},
+			}
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\maintenance`, db.connManager.GetFlavor())
+		}
+	}
+
+	result, err := db.runStatement(statementWithParams)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil || len(result.Rows) == 0 {
+		return nil, fmt.Errorf("Table %s does not exist", tableName)
+	}
+
+	result.Actions = actions
+	return result, nil
+}
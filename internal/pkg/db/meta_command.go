@@ -0,0 +1,118 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Commands starting with a backslash, handled by the client rather than sent to the database as-is
+// Argument is everything following the command name
+type metaCommand func(db *DBClient, argument string) (*QueryResult, error)
+
+var metaCommands = map[string]metaCommand{
+	"translate":    (*DBClient).translateCommand,
+	"locks":        (*DBClient).locksCommand,
+	"maintenance":  (*DBClient).maintenanceCommand,
+	"deps":         (*DBClient).depsCommand,
+	"erd":          (*DBClient).erdCommand,
+	"schema":       (*DBClient).schemaCommand,
+	"compare":      (*DBClient).compareCommand,
+	"seed":         (*DBClient).seedCommand,
+	"timing":       (*DBClient).timingCommand,
+	"stats":        (*DBClient).statsCommand,
+	"telemetry":    (*DBClient).telemetryCommand,
+	"dump":         (*DBClient).dumpCommand,
+	"restore":      (*DBClient).restoreCommand,
+	"backup":       (*DBClient).backupCommand,
+	"restore-from": (*DBClient).restoreFromCommand,
+	"schemas":      (*DBClient).schemasCommand,
+	"setschema":    (*DBClient).setSchemaCommand,
+	"temp":         (*DBClient).tempCommand,
+	"use":          (*DBClient).useCommand,
+	"switches":     (*DBClient).switchesCommand,
+	"safe":         (*DBClient).safeCommand,
+	"bugreport":    (*DBClient).bugReportCommand,
+}
+
+// Split a meta command such as `\translate SHOW TABLES;` into its name and argument
+func ParseMetaCommand(statement string) (name string, argument string, isMetaCommand bool) {
+	trimmedStatement := strings.TrimSpace(statement)
+	if !strings.HasPrefix(trimmedStatement, `\`) {
+		return "", "", false
+	}
+
+	commandWithArgument := strings.TrimPrefix(trimmedStatement, `\`)
+	nameEnd := strings.IndexFunc(commandWithArgument, unicode.IsSpace)
+	if nameEnd == -1 {
+		nameEnd = len(commandWithArgument)
+	}
+
+	name = strings.TrimSuffix(commandWithArgument[:nameEnd], ";")
+	argument = strings.TrimSpace(commandWithArgument[nameEnd:])
+	if name == "" {
+		return "", "", false
+	}
+
+	return name, argument, true
+}
+
+func (db *DBClient) runMetaCommand(name string, argument string) (*QueryResult, error) {
+	command, exists := metaCommands[name]
+	if !exists {
+		return nil, fmt.Errorf(`Unknown command \%s`, name)
+	}
+
+	return command(db, argument)
+}
+
+// Build a result from plain values, for commands which produce output without a query
+func newTextResult(columns []string, rows [][]string) *QueryResult {
+	mappedRows := make([]map[string]*NullString, len(rows))
+
+	for rowIdx, row := range rows {
+		mappedRow := make(map[string]*NullString, len(columns))
+		for columnIdx, columnName := range columns {
+			value := NullString{}
+			if columnIdx < len(row) {
+				value.String = row[columnIdx]
+				value.Valid = true
+			}
+
+			mappedRow[columnName] = &value
+		}
+
+		mappedRows[rowIdx] = mappedRow
+	}
+
+	return &QueryResult{
+		Rows:    mappedRows,
+		Columns: columns,
+	}
+}
+
+// \translate <statement>
+// Show what a statement would be transformed into for the current flavor, without running it
+func (db *DBClient) translateCommand(statement string) (*QueryResult, error) {
+	if statement == "" {
+		return nil, fmt.Errorf(`Usage: \translate <statement>`)
+	}
+
+	statementWithParams, err := db.transformStatement(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	formattedParams := make([]string, len(statementWithParams.params))
+	for idx, param := range statementWithParams.params {
+		formattedParams[idx] = fmt.Sprintf("%s = %v", db.getParamPlaceholder(idx), param)
+	}
+
+	return newTextResult(
+		[]string{"Statement", "Parameters"},
+		[][]string{{
+			strings.TrimSpace(statementWithParams.statement),
+			strings.Join(formattedParams, ", "),
+		}},
+	), nil
+}
@@ -0,0 +1,36 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetaCommand(t *testing.T) {
+	var tests = []struct {
+		Statement             string
+		ExpectedName          string
+		ExpectedArgument      string
+		ExpectedIsMetaCommand bool
+	}{
+		{`\translate SHOW INDEXES FROM foo;`, "translate", "SHOW INDEXES FROM foo;", true},
+		{"  \\locks;  ", "locks", "", true},
+		{"\\translate\n  DESCRIBE foo", "translate", "DESCRIBE foo", true},
+		{`SELECT '\foo'`, "", "", false},
+		{`\`, "", "", false},
+		{"", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Statement, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			name, argument, isMetaCommand := db.ParseMetaCommand(test.Statement)
+			assert.Equal(test.ExpectedIsMetaCommand, isMetaCommand)
+			assert.Equal(test.ExpectedName, name)
+			assert.Equal(test.ExpectedArgument, argument)
+		})
+	}
+}
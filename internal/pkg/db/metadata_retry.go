@@ -0,0 +1,53 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// Run queries the client makes for itself, such as loading the schema or checking a table exists
+// When the connection drops during them, they're run once more on a new connection, as getting the connection
+// checks it's alive and reconnects. Otherwise a connection lost while idle fails the user's DESCRIBE for them
+func (db *DBClient) runMetadataQuery(query func(connection *sqlx.Conn) error) error {
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	err = query(connection)
+	if !isTransientConnectionError(err) {
+		return err
+	}
+
+	slog.Info("Connection dropped during metadata query, retrying", "err", err)
+	connection, err = db.connManager.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	return query(connection)
+}
+
+// Whether an error is from the connection dropping, rather than from the query itself
+func isTransientConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// Hands out connections without connecting, counting how many were asked for
+type reconnectingConnManager struct {
+	fakeConnManager
+	connections int
+}
+
+func (fake *reconnectingConnManager) GetConnection() (*sqlx.Conn, error) {
+	fake.connections++
+	return nil, nil
+}
+
+func TestIsTransientConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"No error", nil, false},
+		{"Bad connection", driver.ErrBadConn, true},
+		{"Closed mid-query", fmt.Errorf("reading response: %w", io.ErrUnexpectedEOF), true},
+		{"Reset by the server", fmt.Errorf("write: %w", syscall.ECONNRESET), true},
+		{"Query error", errors.New(`relation "users" does not exist`), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isTransientConnectionError(test.err))
+		})
+	}
+}
+
+func TestRunMetadataQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	connManager := &reconnectingConnManager{}
+	dbClient, err := CreateDBClient(connManager)
+	if !assert.NoError(err) {
+		return
+	}
+
+	// Dropped once, then succeeds on the new connection
+	attempts := 0
+	err = dbClient.runMetadataQuery(func(*sqlx.Conn) error {
+		attempts++
+		if attempts == 1 {
+			return driver.ErrBadConn
+		}
+
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(2, attempts)
+	assert.Equal(2, connManager.connections)
+
+	// Only retried once
+	attempts = 0
+	err = dbClient.runMetadataQuery(func(*sqlx.Conn) error {
+		attempts++
+		return driver.ErrBadConn
+	})
+	assert.ErrorIs(err, driver.ErrBadConn)
+	assert.Equal(2, attempts)
+
+	// Errors from the query itself aren't retried
+	attempts = 0
+	queryErr := errors.New("permission denied")
+	err = dbClient.runMetadataQuery(func(*sqlx.Conn) error {
+		attempts++
+		return queryErr
+	})
+	assert.ErrorIs(err, queryErr)
+	assert.Equal(1, attempts)
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/azvaliev/sql/internal/pkg/policy"
+)
+
+// Ask a hook about every statement sent on the user's behalf, such as a script enforcing an organization's guardrails
+// Queries the client makes for itself, such as loading the schema, aren't checked
+func (db *DBClient) SetPolicyHook(hook policy.Hook) {
+	db.policyHook = hook
+}
+
+// Statement to send in place of the one given, once safe mode and the policy hook allow it
+// Statements meta commands such as \backup build go through this too, not only those typed
+func (db *DBClient) checkStatement(statement string) (string, error) {
+	if err := db.checkSafeMode(statement); err != nil {
+		return "", err
+	}
+
+	return db.checkPolicy(statement)
+}
+
+// Statement to send in place of the one given, as rewritten by the policy hook
+// Refused when the hook denies it, or can't decide, so a broken hook never lets statements through
+func (db *DBClient) checkPolicy(statement string) (string, error) {
+	if db.policyHook == nil {
+		return statement, nil
+	}
+
+	connectionInfo := db.connManager.GetConnectionInfo()
+	decision, err := db.policyHook.Check(policy.Request{
+		Statement: statement,
+		Flavor:    connectionInfo.Flavor.Name(),
+		Database:  connectionInfo.Database,
+		User:      connectionInfo.User,
+	})
+	if err != nil {
+		return "", errors.Join(
+			errors.New("Refused as the policy hook failed"),
+			err,
+		)
+	}
+
+	switch decision.Verdict {
+	case policy.Deny:
+		{
+			slog.Info("Policy hook refused statement", "statement", db.redactStatement(statement), "reason", decision.Reason)
+			if decision.Reason == "" {
+				return "", errors.New("Refused by policy")
+			}
+
+			return "", fmt.Errorf("Refused by policy: %s", decision.Reason)
+		}
+	case policy.Rewrite:
+		{
+			slog.Info("Policy hook rewrote statement", "statement", db.redactStatement(statement), "sent", db.redactStatement(decision.Statement))
+			return decision.Statement, nil
+		}
+	default:
+		{
+			return statement, nil
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/azvaliev/sql/internal/pkg/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+// Decides by statement, recording every request
+type fakePolicyHook struct {
+	decisions map[string]policy.Decision
+	err       error
+	requests  []policy.Request
+}
+
+func (fake *fakePolicyHook) Check(request policy.Request) (policy.Decision, error) {
+	fake.requests = append(fake.requests, request)
+	if fake.err != nil {
+		return policy.Decision{}, fake.err
+	}
+
+	decision, exists := fake.decisions[request.Statement]
+	if !exists {
+		return policy.Decision{Verdict: policy.Allow}, nil
+	}
+
+	return decision, nil
+}
+
+func TestCheckPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	dbClient, err := CreateDBClient(&fakeConnManager{database: "test"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	statement, err := dbClient.checkPolicy("SELECT * FROM users")
+	assert.NoError(err)
+	assert.Equal("SELECT * FROM users", statement)
+
+	hook := &fakePolicyHook{decisions: map[string]policy.Decision{
+		"SELECT * FROM users":   {Verdict: policy.Rewrite, Statement: "SELECT id FROM users"},
+		"SELECT * FROM secrets": {Verdict: policy.Deny, Reason: "secrets are off limits"},
+		"DROP TABLE users":      {Verdict: policy.Deny},
+	}}
+	dbClient.SetPolicyHook(hook)
+
+	statement, err = dbClient.checkPolicy("SELECT * FROM users")
+	assert.NoError(err)
+	assert.Equal("SELECT id FROM users", statement)
+	assert.Equal(
+		policy.Request{Statement: "SELECT * FROM users", Flavor: "PostgreSQL", Database: "test"},
+		hook.requests[0],
+	)
+
+	_, err = dbClient.Query("SELECT * FROM secrets")
+	assert.EqualError(err, "Refused by policy: secrets are off limits")
+	_, err = dbClient.Exec("DROP TABLE users")
+	assert.EqualError(err, "Refused by policy")
+
+	// Allowed statements are sent, to the database the fake can't reach
+	_, err = dbClient.Exec("UPDATE users SET active = false WHERE id = 1")
+	assert.ErrorIs(err, conn.ErrConnectionFailed)
+
+	// A hook which can't decide refuses everything
+	hook.err = errors.New("hook crashed")
+	_, err = dbClient.Query("SELECT 1")
+	assert.ErrorContains(err, "Refused as the policy hook failed")
+	assert.ErrorContains(err, "hook crashed")
+}
+
+func TestRestoreChecksPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	connManager := &fakeConnManager{database: "test"}
+	dbClient, err := CreateDBClient(connManager)
+	if !assert.NoError(err) {
+		return
+	}
+
+	restorePath := filepath.Join(t.TempDir(), "restore.sql")
+	err = os.WriteFile(restorePath, []byte("DROP TABLE users;\nCREATE TABLE users (id INT);\n"), 0o600)
+	if !assert.NoError(err) {
+		return
+	}
+
+	hook := &fakePolicyHook{decisions: map[string]policy.Decision{}}
+	dbClient.SetPolicyHook(hook)
+	statements := lexer.SplitStatements(conn.PostgreSQL, "DROP TABLE users;")
+	hook.decisions[statements[0]] = policy.Decision{Verdict: policy.Deny, Reason: "no dropping tables"}
+
+	// Stops at the refused statement, without running the rest
+	_, err = dbClient.Restore(restorePath, nil)
+	assert.ErrorContains(err, "Restore stopped at statement 1 of 2")
+	assert.ErrorContains(err, "Refused by policy: no dropping tables")
+	assert.Len(hook.requests, 1)
+
+	// Safe mode is checked too
+	connManager.safeMode = true
+	err = os.WriteFile(restorePath, []byte("DELETE FROM users;\n"), 0o600)
+	if !assert.NoError(err) {
+		return
+	}
+
+	_, err = dbClient.Restore(restorePath, nil)
+	assert.ErrorContains(err, "Restore stopped at statement 1 of 1")
+	assert.NotErrorIs(err, conn.ErrConnectionFailed)
+}
+
+func TestMetaCommandsCheckPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	dbClient, err := CreateDBClient(&fakeConnManager{database: "test"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	backupName := fmt.Sprint("users_backup_", time.Now().Format("20060102"))
+	hook := &fakePolicyHook{decisions: map[string]policy.Decision{
+		"SELECT * FROM pii": {Verdict: policy.Deny, Reason: "no SELECT * on personal data"},
+		fmt.Sprintf(`CREATE TABLE "%s" AS SELECT * FROM "users"`, backupName): {Verdict: policy.Deny, Reason: "no copies of users"},
+		`INSERT INTO "users" ("name") VALUES ($1)`:                            {Verdict: policy.Deny, Reason: "no inserts"},
+		`EXPLAIN (FORMAT JSON) SELECT * FROM pii`:                             {Verdict: policy.Deny},
+	}}
+	dbClient.SetPolicyHook(hook)
+
+	_, err = dbClient.Query(`\timing SELECT * FROM pii`)
+	assert.EqualError(err, "Refused by policy: no SELECT * on personal data")
+
+	_, err = dbClient.Query(`\backup users`)
+	assert.EqualError(err, "Refused by policy: no copies of users")
+
+	_, _, err = dbClient.InsertRow("users", []string{"name"}, []*NullString{{}})
+	assert.EqualError(err, "Refused by policy: no inserts")
+
+	_, _, err = dbClient.EstimateRows("SELECT * FROM pii")
+	assert.EqualError(err, "Refused by policy")
+}
@@ -1,19 +1,34 @@
 package db
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
 )
 
 type NullString struct {
 	sql.NullString
 }
 
+// How ToString writes NULL, as in CSV or markdown
+const DefaultNullToken = "NULL"
+
 func (nullString *NullString) ToString() string {
+	return nullString.ToStringWithNull(DefaultNullToken)
+}
+
+// The value, or nullToken when it's NULL, such as to show NULL as ∅
+func (nullString *NullString) ToStringWithNull(nullToken string) string {
 	if !nullString.Valid {
-		return "NULL"
+		return nullToken
 	}
 
 	return nullString.String
@@ -36,6 +51,80 @@ type QueryResult struct {
 	Rows []map[string]*NullString
 	// Column names, order preserved with how they were selected
 	Columns []string
+	// Type information for each of Columns, in the same order, nil when the result didn't come from the database
+	ColumnTypes []ColumnType
+	// Follow-up statements which can be run on the result, such as table maintenance
+	Actions []ResultAction
+	// Other representations of the result which can be copied, in addition to CSV and JSON
+	CopyFormats []ResultCopyFormat
+	// Reused from an identical statement run moments before, rather than fetched again
+	Cached bool
+	// How long the statement took, up to reading the first page of rows when they're read a page at a time
+	Duration time.Duration
+	// Describes what a statement which returned no columns did, such as SET or DDL on MySQL, ex: Table created
+	// Empty for results with columns
+	Status string
+	// Set when answered from the offline schema cache as the database was unreachable, to when it was saved
+	SchemaCachedAt *time.Time
+	// Rows not yet read, nil once every row has been. See FetchRows
+	Stream *RowStream `json:"-"`
+}
+
+// Status with how long the statement took, ex: Table created (0.02s)
+func (queryResult *QueryResult) Summary() string {
+	return fmt.Sprintf("%s (%.2fs)", queryResult.Status, queryResult.Duration.Seconds())
+}
+
+// Read up to count more rows from the stream into Rows, or every remaining row when count is 0
+// Returns how many were added
+func (queryResult *QueryResult) FetchRows(count int) (int, error) {
+	if queryResult.Stream == nil {
+		return 0, nil
+	}
+
+	rows, err := queryResult.Stream.fetch(count)
+	queryResult.Rows = append(queryResult.Rows, rows...)
+	if queryResult.Stream.done {
+		queryResult.Stream = nil
+	}
+
+	return len(rows), err
+}
+
+// Pass the rows not yet read to handle a page of up to pageSize at a time, without keeping them in Rows
+// So a result too large to hold in memory can still be written out in full
+func (queryResult *QueryResult) ReadRemainingRows(pageSize int, handle func(rows []map[string]*NullString) error) error {
+	for queryResult.HasMoreRows() {
+		rows, err := queryResult.Stream.fetch(pageSize)
+		if queryResult.Stream.done {
+			queryResult.Stream = nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := handle(rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Whether more rows may be read with FetchRows
+func (queryResult *QueryResult) HasMoreRows() bool {
+	return queryResult.Stream != nil && !queryResult.Stream.Interrupted()
+}
+
+// A statement offered alongside a result, run only once the user confirms
+type ResultAction struct {
+	Label     string
+	Statement string
+}
+
+type ResultCopyFormat struct {
+	Label   string
+	Content []byte
 }
 
 func (queryResult *QueryResult) ToJSON() (res []byte) {
@@ -52,25 +141,117 @@ func (queryResult *QueryResult) ToJSON() (res []byte) {
 	return res
 }
 
+// How a result is written as CSV
+type CSVOptions struct {
+	// Separates values, a comma when unset
+	Delimiter rune
+	// Leave out the line of column names
+	NoHeader bool
+}
+
+// Check the delimiter can be told apart from the values and quoting around it
+func (options CSVOptions) Validate() error {
+	switch options.Delimiter {
+	case '"', '\r', '\n', utf8.RuneError:
+		{
+			return fmt.Errorf("CSV delimiter can't be %q", options.Delimiter)
+		}
+	}
+
+	return nil
+}
+
+// Comma separated with a header line, quoting values which hold a comma, quote or newline
 func (queryResult *QueryResult) ToCSV() (res []byte) {
-	var resString strings.Builder
+	return queryResult.ToCSVWithOptions(CSVOptions{})
+}
+
+// Options are expected to have been validated, see CSVOptions.Validate
+func (queryResult *QueryResult) ToCSVWithOptions(options CSVOptions) (res []byte) {
+	var resBuffer bytes.Buffer
 
-	// Add column header
-	resString.WriteString(
-		strings.Join(queryResult.Columns, ","),
-	)
+	csvWriter := csv.NewWriter(&resBuffer)
+	if options.Delimiter != 0 {
+		csvWriter.Comma = options.Delimiter
+	}
+
+	if !options.NoHeader {
+		csvWriter.Write(queryResult.Columns)
+	}
 
-	// Add each row
 	for _, row := range queryResult.Rows {
-		resString.WriteRune('\n')
 		rowValues := make([]string, len(queryResult.Columns))
 
 		for columnIdx, columnName := range queryResult.Columns {
 			cellValue := row[columnName]
 			rowValues[columnIdx] = cellValue.ToString()
 		}
-		resString.WriteString(strings.Join(rowValues, ","))
+		csvWriter.Write(rowValues)
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		// Writing to memory only fails for an invalid delimiter, which options are validated against
+		panic(errors.Join(
+			errors.New("Failed to write query results as CSV"),
+			err,
+		))
+	}
+
+	return resBuffer.Bytes()
+}
+
+// Render as a GitHub flavored markdown table, with the pipes lined up so it reads well before it's rendered too
+// Pipes are escaped and newlines flattened, so each row stays on one line
+func (queryResult *QueryResult) ToMarkdown() []byte {
+	header := make([]string, len(queryResult.Columns))
+	// The separator needs at least three dashes
+	widths := make([]int, len(queryResult.Columns))
+	for columnIdx, columnName := range queryResult.Columns {
+		header[columnIdx] = escapeMarkdownCell(columnName)
+		widths[columnIdx] = max(uniseg.StringWidth(header[columnIdx]), 3)
+	}
+
+	rows := make([][]string, len(queryResult.Rows))
+	for rowIdx, row := range queryResult.Rows {
+		rows[rowIdx] = make([]string, len(queryResult.Columns))
+		for columnIdx, columnName := range queryResult.Columns {
+			value := escapeMarkdownCell(row[columnName].ToString())
+			rows[rowIdx][columnIdx] = value
+			widths[columnIdx] = max(widths[columnIdx], uniseg.StringWidth(value))
+		}
+	}
+
+	var resString strings.Builder
+
+	writeRow := func(values []string) {
+		resString.WriteString("|")
+		for columnIdx, value := range values {
+			resString.WriteString(" ")
+			resString.WriteString(value)
+			resString.WriteString(strings.Repeat(" ", widths[columnIdx]-uniseg.StringWidth(value)))
+			resString.WriteString(" |")
+		}
+		resString.WriteRune('\n')
+	}
+
+	writeRow(header)
+
+	separators := make([]string, len(widths))
+	for idx, width := range widths {
+		separators[idx] = strings.Repeat("-", width)
+	}
+	writeRow(separators)
+
+	for _, row := range rows {
+		writeRow(row)
 	}
 
 	return []byte(resString.String())
 }
+
+var markdownCellReplacer = strings.NewReplacer("|", "\\|", "\r\n", " ", "\n", " ")
+
+func escapeMarkdownCell(value string) string {
+	return markdownCellReplacer.Replace(value)
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNullStringToStringWithNull(t *testing.T) {
+	assert := assert.New(t)
+
+	null := &NullString{}
+	assert.Equal("NULL", null.ToString())
+	assert.Equal("∅", null.ToStringWithNull("∅"))
+	assert.Equal("", null.ToStringWithNull(""))
+
+	text := &NullString{sql.NullString{String: "NULL", Valid: true}}
+	assert.Equal("NULL", text.ToStringWithNull("∅"))
+}
+
+func TestQueryResultToMarkdown(t *testing.T) {
+	assert := assert.New(t)
+
+	result := &QueryResult{
+		Columns: []string{"id", "note"},
+		Rows: []map[string]*NullString{
+			{
+				"id":   {sql.NullString{String: "1", Valid: true}},
+				"note": {sql.NullString{String: "a | b\nc", Valid: true}},
+			},
+			{
+				"id":   {sql.NullString{String: "2", Valid: true}},
+				"note": {},
+			},
+		},
+	}
+
+	assert.Equal(
+		"| id  | note     |\n"+
+			"| --- | -------- |\n"+
+			"| 1   | a \\| b c |\n"+
+			"| 2   | NULL     |\n",
+		string(result.ToMarkdown()),
+	)
+}
+
+func TestQueryResultToCSV(t *testing.T) {
+	result := &QueryResult{
+		Columns: []string{"id", "note"},
+		Rows: []map[string]*NullString{
+			{
+				"id":   {sql.NullString{String: "1", Valid: true}},
+				"note": {sql.NullString{String: `{"tags": ["a", "b"]}`, Valid: true}},
+			},
+			{
+				"id":   {sql.NullString{String: "2", Valid: true}},
+				"note": {sql.NullString{String: "first line\nsecond; line", Valid: true}},
+			},
+			{
+				"id":   {sql.NullString{String: "3", Valid: true}},
+				"note": {},
+			},
+		},
+	}
+
+	var tests = []struct {
+		Name     string
+		Options  CSVOptions
+		Expected string
+	}{
+		{
+			Name:    "Default",
+			Options: CSVOptions{},
+			Expected: "id,note\n" +
+				"1,\"{\"\"tags\"\": [\"\"a\"\", \"\"b\"\"]}\"\n" +
+				"2,\"first line\nsecond; line\"\n" +
+				"3,NULL\n",
+		},
+		{
+			Name:    "Custom delimiter without header",
+			Options: CSVOptions{Delimiter: ';', NoHeader: true},
+			Expected: "1;\"{\"\"tags\"\": [\"\"a\"\", \"\"b\"\"]}\"\n" +
+				"2;\"first line\nsecond; line\"\n" +
+				"3;NULL\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.Expected, string(result.ToCSVWithOptions(test.Options)))
+		})
+	}
+}
+
+func TestCSVOptionsValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(CSVOptions{}.Validate())
+	assert.NoError(CSVOptions{Delimiter: '\t'}.Validate())
+	assert.ErrorContains(CSVOptions{Delimiter: '"'}.Validate(), "CSV delimiter can't be")
+	assert.ErrorContains(CSVOptions{Delimiter: '\n'}.Validate(), "CSV delimiter can't be")
+}
+
+func TestQueryResultSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	result := &QueryResult{Status: "Table created", Duration: 20 * time.Millisecond}
+	assert.Equal("Table created (0.02s)", result.Summary())
+}
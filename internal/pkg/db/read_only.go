@@ -0,0 +1,55 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Statements which change data or schema, by their first keyword
+var writeStatementKeywords = map[string]bool{
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true, "COMMENT": true,
+	"INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true, "MERGE": true,
+	"GRANT": true, "REVOKE": true,
+}
+
+// Keywords after a WITH which make it a data-modifying statement
+var writeCTEKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true,
+}
+
+// Whether a statement writes, and so would be refused by a read-only server such as a replica
+// Meta commands aren't counted, even those which write, as they say what they do
+func (db *DBClient) IsWriteStatement(statement string) bool {
+	return isWriteStatement(db.connManager.GetFlavor(), statement)
+}
+
+func isWriteStatement(flavor conn.DBFlavor, statement string) bool {
+	if rawStatement, isRaw := stripRawPrefix(statement); isRaw {
+		statement = rawStatement
+	} else if _, _, isMetaCommand := ParseMetaCommand(statement); isMetaCommand {
+		return false
+	}
+
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 {
+		return false
+	}
+
+	firstKeyword := strings.ToUpper(tokens[0].Text)
+	if writeStatementKeywords[firstKeyword] {
+		return true
+	}
+	if firstKeyword != "WITH" {
+		return false
+	}
+
+	for _, token := range tokens[1:] {
+		if token.Kind == lexer.Word && writeCTEKeywords[strings.ToUpper(token.Text)] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsWriteStatement(t *testing.T) {
+	var tests = []struct {
+		Statement string
+		Expected  bool
+	}{
+		{"SELECT * FROM users", false},
+		{"SHOW TABLES;", false},
+		{"UPDATE users SET active = false WHERE id = 1", true},
+		{"insert into users (name) values ('ada') returning id;", true},
+		{"DELETE FROM users", true},
+		{"CREATE TABLE teams (id int)", true},
+		{"-- tidy up\nDROP TABLE teams", true},
+		{"WITH inactive AS (DELETE FROM users WHERE active = false RETURNING id) SELECT count(*) FROM inactive", true},
+		{"WITH recent AS (SELECT * FROM users) SELECT * FROM recent", false},
+		{"SELECT 'update' AS action", false},
+		{`\raw INSERT INTO users (name) VALUES ('ada')`, true},
+		{`\seed users 10`, false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.Expected, isWriteStatement(conn.PostgreSQL, test.Statement))
+		})
+	}
+}
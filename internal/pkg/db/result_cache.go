@@ -0,0 +1,162 @@
+package db
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Most results kept at once, the oldest is dropped to make room
+const resultCacheCapacity = 32
+
+// Keywords anywhere in a statement which mean it may change something, so its result can't be reused
+var nonCacheableKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "INTO": true, "SHARE": true, "LOCK": true,
+}
+
+// Statements whose results are safe to reuse, by their first keyword
+var cacheableStatementKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "SHOW": true, "DESCRIBE": true, "DESC": true, "TABLE": true, "VALUES": true,
+}
+
+// Recent results of read-only statements, so resubmitting the same one doesn't hit the database again
+// Disabled while the time to live is 0
+type resultCache struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	entries map[resultCacheKey]*resultCacheEntry
+}
+
+type resultCacheKey struct {
+	database  string
+	statement string
+}
+
+type resultCacheEntry struct {
+	result   *QueryResult
+	storedAt time.Time
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{
+		entries: map[resultCacheKey]*resultCacheEntry{},
+	}
+}
+
+func (cache *resultCache) setTTL(ttl time.Duration) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.ttl = ttl
+	if ttl <= 0 {
+		clear(cache.entries)
+	}
+}
+
+func (cache *resultCache) enabled() bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	return cache.ttl > 0
+}
+
+// Cached result for the key, marked as cached, or nil when there's none younger than the time to live
+func (cache *resultCache) get(key resultCacheKey, now time.Time) *QueryResult {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	entry, exists := cache.entries[key]
+	if !exists {
+		return nil
+	}
+
+	if now.Sub(entry.storedAt) >= cache.ttl {
+		delete(cache.entries, key)
+		return nil
+	}
+
+	cachedResult := *entry.result
+	cachedResult.Cached = true
+
+	return &cachedResult
+}
+
+func (cache *resultCache) set(key resultCacheKey, result *QueryResult, now time.Time) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.ttl <= 0 {
+		return
+	}
+
+	if _, exists := cache.entries[key]; !exists && len(cache.entries) >= resultCacheCapacity {
+		var oldestKey resultCacheKey
+		var oldestStoredAt time.Time
+		for entryKey, entry := range cache.entries {
+			if oldestStoredAt.IsZero() || entry.storedAt.Before(oldestStoredAt) {
+				oldestKey = entryKey
+				oldestStoredAt = entry.storedAt
+			}
+		}
+
+		delete(cache.entries, oldestKey)
+	}
+
+	cache.entries[key] = &resultCacheEntry{result, now}
+}
+
+// Drop every cached result, as something may have changed the data they came from
+func (cache *resultCache) invalidate() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	clear(cache.entries)
+}
+
+// Reuse results of identical read-only statements run within this long of each other, 0 to disable
+func (db *DBClient) SetResultCacheTTL(ttl time.Duration) {
+	db.resultCache.setTTL(ttl)
+}
+
+// Key a statement by the database it runs against and its significant tokens
+// So differences in whitespace, comments or a trailing semicolon still hit the cache
+// Returns false when the statement's result shouldn't be cached
+func (db *DBClient) getResultCacheKey(statement string) (resultCacheKey, bool) {
+	if db.InTransaction() {
+		// Other statements in the transaction may have changed what it would return
+		return resultCacheKey{}, false
+	}
+
+	normalizedStatement, isCacheable := normalizeCacheableStatement(db.connManager.GetFlavor(), statement)
+	if !isCacheable {
+		return resultCacheKey{}, false
+	}
+
+	return resultCacheKey{db.GetConnectionInfo().Database, normalizedStatement}, true
+}
+
+func normalizeCacheableStatement(flavor conn.DBFlavor, statement string) (string, bool) {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 || !cacheableStatementKeywords[strings.ToUpper(tokens[0].Text)] {
+		return "", false
+	}
+
+	tokenTexts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		// Locking reads, SELECT ... INTO and data-modifying CTEs are run for their side effects
+		if token.Kind == lexer.Word && nonCacheableKeywords[strings.ToUpper(token.Text)] {
+			return "", false
+		}
+
+		tokenTexts = append(tokenTexts, token.Text)
+	}
+
+	if tokens[len(tokens)-1].IsOperator(";") {
+		tokenTexts = tokenTexts[:len(tokenTexts)-1]
+	}
+
+	return strings.Join(tokenTexts, " "), true
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCacheableStatement(t *testing.T) {
+	var tests = []struct {
+		Statement           string
+		ExpectedStatement   string
+		ExpectedIsCacheable bool
+	}{
+		{"SELECT * FROM users;", "SELECT * FROM users", true},
+		{"  select *\n  from users -- again\n", "select * from users", true},
+		{"WITH ids AS (SELECT 1) SELECT * FROM ids", "WITH ids AS ( SELECT 1 ) SELECT * FROM ids", true},
+		{"SELECT 'a  b'", "SELECT 'a  b'", true},
+		{"SHOW TABLES", "SHOW TABLES", true},
+		{"SELECT * FROM users FOR UPDATE", "", false},
+		{"SELECT * FROM users FOR SHARE", "", false},
+		{"SELECT id INTO ids FROM users", "", false},
+		{"WITH deleted AS (DELETE FROM users RETURNING id) SELECT * FROM deleted", "", false},
+		{"UPDATE users SET name = 'a'", "", false},
+		{`\stats`, "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+
+			statement, isCacheable := normalizeCacheableStatement(conn.PostgreSQL, test.Statement)
+			assert.Equal(test.ExpectedIsCacheable, isCacheable)
+			assert.Equal(test.ExpectedStatement, statement)
+		})
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := newResultCache()
+	key := resultCacheKey{"example", "SELECT 1"}
+	result := &QueryResult{Columns: []string{"1"}}
+	now := time.Now()
+
+	cache.set(key, result, now)
+	assert.Nil(cache.get(key, now), "Nothing is cached while disabled")
+
+	cache.setTTL(10 * time.Second)
+	cache.set(key, result, now)
+
+	cachedResult := cache.get(key, now.Add(5*time.Second))
+	if assert.NotNil(cachedResult) {
+		assert.True(cachedResult.Cached)
+		assert.Equal(result.Columns, cachedResult.Columns)
+	}
+	assert.False(result.Cached, "Stored result is left unmarked")
+
+	assert.Nil(cache.get(resultCacheKey{"other", "SELECT 1"}, now), "Results are per database")
+	assert.Nil(cache.get(key, now.Add(10*time.Second)), "Expired results aren't reused")
+
+	cache.set(key, result, now)
+	cache.invalidate()
+	assert.Nil(cache.get(key, now))
+}
+
+func TestResultCacheCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := newResultCache()
+	cache.setTTL(time.Minute)
+	now := time.Now()
+
+	for idx := range resultCacheCapacity + 1 {
+		key := resultCacheKey{"example", fmt.Sprint("SELECT ", idx)}
+		cache.set(key, &QueryResult{}, now.Add(time.Duration(idx)*time.Millisecond))
+	}
+
+	assert.Len(cache.entries, resultCacheCapacity)
+	assert.Nil(cache.get(resultCacheKey{"example", "SELECT 0"}, now), "Oldest result is dropped")
+	assert.NotNil(cache.get(resultCacheKey{"example", fmt.Sprint("SELECT ", resultCacheCapacity)}, now))
+}
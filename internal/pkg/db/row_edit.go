@@ -0,0 +1,154 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// The table a result row came from, and how to find that row again
+type RowEditTarget struct {
+	Table      string
+	PrimaryKey []string
+	// Result columns which are columns of the table, in result order
+	Columns []string
+}
+
+// SELECT ... FROM table [alias] followed by nothing but filtering, ordering or limits
+var singleTableSelectRegExp = regexp.MustCompile(
+	"(?is)^\\s*SELECT\\s+.+?\\s+FROM\\s+[\"`]?(\\w+)[\"`]?(\\s+(AS\\s+)?\\w+)?\\s*(WHERE\\b.*|ORDER\\s+BY\\b.*|LIMIT\\b.*)?;?\\s*$",
+)
+
+// Anything making result rows something other than rows of the table
+var aggregatingSelectRegExp = regexp.MustCompile(`(?i)\b(JOIN|GROUP\s+BY|HAVING|DISTINCT|UNION)\b`)
+
+// Get the table of a single table query, if the result has enough columns to identify and update a row
+func (db *DBClient) GetRowEditTarget(query string, resultColumns []string) (*RowEditTarget, error) {
+	tableName, isSingleTable := getSingleTableSelectTable(query)
+	if !isSingleTable {
+		return nil, errors.New("Only rows from a SELECT on a single table can be edited")
+	}
+
+	schema, err := db.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	// Table may have been created since the schema was loaded
+	if _, exists := schema.Tables[tableName]; !exists {
+		schema, err = db.RefreshSchema()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return getRowEditTarget(schema, tableName, resultColumns)
+}
+
+func getSingleTableSelectTable(query string) (tableName string, isSingleTable bool) {
+	matches := singleTableSelectRegExp.FindStringSubmatch(query)
+	if matches == nil || aggregatingSelectRegExp.MatchString(query) {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
+func getRowEditTarget(schema *Schema, tableName string, resultColumns []string) (*RowEditTarget, error) {
+	table, exists := schema.Tables[tableName]
+	if !exists {
+		return nil, fmt.Errorf("Table %s does not exist", tableName)
+	}
+
+	target := &RowEditTarget{Table: tableName}
+	for _, index := range table.Indexes {
+		if index.Primary {
+			target.PrimaryKey = index.Columns
+			break
+		}
+	}
+
+	if len(target.PrimaryKey) == 0 {
+		return nil, fmt.Errorf("Rows of %s can't be edited, it has no primary key", tableName)
+	}
+
+	for _, resultColumn := range resultColumns {
+		isTableColumn := slices.ContainsFunc(table.Columns, func(column ColumnSchema) bool {
+			return column.Name == resultColumn
+		})
+
+		if isTableColumn && !slices.Contains(target.Columns, resultColumn) {
+			target.Columns = append(target.Columns, resultColumn)
+		}
+	}
+
+	for _, primaryKeyColumn := range target.PrimaryKey {
+		if !slices.Contains(target.Columns, primaryKeyColumn) {
+			return nil, fmt.Errorf("Select the primary key column %s to edit rows of %s", primaryKeyColumn, tableName)
+		}
+	}
+
+	return target, nil
+}
+
+// Build an UPDATE setting the changed columns of a row, found by its primary key values in row
+func (db *DBClient) BuildRowUpdate(
+	target *RowEditTarget,
+	row map[string]*NullString,
+	changes map[string]*NullString,
+) (string, error) {
+	return buildRowUpdate(db.connManager.GetFlavor(), db.identifierQuoting, target, row, changes)
+}
+
+func buildRowUpdate(
+	flavor conn.DBFlavor,
+	quoting lexer.IdentifierQuoting,
+	target *RowEditTarget,
+	row map[string]*NullString,
+	changes map[string]*NullString,
+) (string, error) {
+	if len(changes) == 0 {
+		return "", errors.New("No changes to save")
+	}
+
+	// Keep assignments in column order so the statement is predictable
+	var assignments []string
+	for _, column := range target.Columns {
+		value, changed := changes[column]
+		if !changed {
+			continue
+		}
+
+		assignments = append(assignments, fmt.Sprintf(
+			"%s = %s",
+			lexer.QuoteIdentifier(flavor, column, quoting),
+			quoteLiteralForFlavor(flavor, value),
+		))
+	}
+
+	conditions := make([]string, len(target.PrimaryKey))
+	for idx, primaryKeyColumn := range target.PrimaryKey {
+		value := row[primaryKeyColumn]
+		if value == nil || !value.Valid {
+			return "", fmt.Errorf("Row has no value for primary key column %s", primaryKeyColumn)
+		}
+
+		conditions[idx] = fmt.Sprintf(
+			"%s = %s",
+			lexer.QuoteIdentifier(flavor, primaryKeyColumn, quoting),
+			quoteLiteralForFlavor(flavor, value),
+		)
+	}
+
+	return fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s;",
+		lexer.QuoteIdentifier(flavor, target.Table, quoting),
+		strings.Join(assignments, ", "),
+		strings.Join(conditions, " AND "),
+	), nil
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSingleTableSelectTable(t *testing.T) {
+	var tests = []struct {
+		Query                 string
+		ExpectedTableName     string
+		ExpectedIsSingleTable bool
+	}{
+		{"SELECT * FROM users;", "users", true},
+		{"select id, name from users u where id > 5 order by id limit 10;", "users", true},
+		{"SELECT *\nFROM `users` AS u\nLIMIT 5", "users", true},
+		{`SELECT * FROM "users" WHERE name = 'foo'`, "users", true},
+		{"SELECT * FROM users JOIN posts ON posts.user_id = users.id;", "", false},
+		{"SELECT * FROM users, posts;", "", false},
+		{"SELECT DISTINCT name FROM users;", "", false},
+		{"SELECT name, COUNT(*) FROM users GROUP BY name;", "", false},
+		{"UPDATE users SET name = 'foo';", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Query, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			tableName, isSingleTable := getSingleTableSelectTable(test.Query)
+			assert.Equal(test.ExpectedTableName, tableName)
+			assert.Equal(test.ExpectedIsSingleTable, isSingleTable)
+		})
+	}
+}
+
+var rowEditTestSchema = &Schema{
+	Tables: map[string]*TableSchema{
+		"users": {
+			Columns: []ColumnSchema{{Name: "id"}, {Name: "name"}, {Name: "email"}},
+			Indexes: []IndexSchema{{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true}},
+		},
+		"logs": {
+			Columns: []ColumnSchema{{Name: "message"}},
+		},
+	},
+}
+
+func TestGetRowEditTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	target, err := getRowEditTarget(rowEditTestSchema, "users", []string{"name", "id", "upper_name"})
+	assert.NoError(err)
+	assert.Equal(
+		&RowEditTarget{Table: "users", PrimaryKey: []string{"id"}, Columns: []string{"name", "id"}},
+		target,
+	)
+
+	_, err = getRowEditTarget(rowEditTestSchema, "users", []string{"name"})
+	assert.ErrorContains(err, "primary key column id")
+
+	_, err = getRowEditTarget(rowEditTestSchema, "logs", []string{"message"})
+	assert.ErrorContains(err, "no primary key")
+
+	_, err = getRowEditTarget(rowEditTestSchema, "missing", []string{"id"})
+	assert.Error(err)
+}
+
+func TestBuildRowUpdate(t *testing.T) {
+	target := &RowEditTarget{Table: "users", PrimaryKey: []string{"id"}, Columns: []string{"id", "name", "email"}}
+	row := map[string]*NullString{
+		"id":    {sql.NullString{String: "5", Valid: true}},
+		"name":  {sql.NullString{String: "foo", Valid: true}},
+		"email": {sql.NullString{String: "foo@example.com", Valid: true}},
+	}
+	changes := map[string]*NullString{
+		"email": {},
+		"name":  {sql.NullString{String: `O'Brien \ Co`, Valid: true}},
+	}
+
+	var tests = []struct {
+		Flavor            conn.DBFlavor
+		ExpectedStatement string
+	}{
+		{conn.PostgreSQL, `UPDATE "users" SET "name" = 'O''Brien \ Co', "email" = NULL WHERE "id" = '5';`},
+		{conn.MySQL, "UPDATE `users` SET `name` = 'O''Brien \\\\ Co', `email` = NULL WHERE `id` = '5';"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.Flavor), func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			statement, err := buildRowUpdate(test.Flavor, lexer.QuoteAlways, target, row, changes)
+			assert.NoError(err)
+			assert.Equal(test.ExpectedStatement, statement)
+
+			_, err = buildRowUpdate(test.Flavor, lexer.QuoteAlways, target, row, map[string]*NullString{})
+			assert.Error(err)
+		})
+	}
+}
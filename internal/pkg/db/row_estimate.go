@@ -0,0 +1,159 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Statements EXPLAIN can plan, by their first keyword
+var explainableStatementKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "INSERT": true, "UPDATE": true, "DELETE": true, "REPLACE": true,
+}
+
+// Savepoint a failed EXPLAIN is rolled back to, so it doesn't abort an open Postgres transaction
+const estimateSavepoint string = "sql_estimate"
+
+// Most rows any step of a statement's plan is expected to read, from EXPLAIN without running the statement
+// Statements EXPLAIN can't plan, such as DDL, meta commands or SHOW, aren't estimated
+func (db *DBClient) EstimateRows(statement string) (estimate int64, isEstimated bool, err error) {
+	if rawStatement, isRaw := stripRawPrefix(statement); isRaw {
+		statement = rawStatement
+	} else if _, _, isMetaCommand := ParseMetaCommand(statement); isMetaCommand {
+		return 0, false, nil
+	}
+
+	flavor := db.connManager.GetFlavor()
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 || !explainableStatementKeywords[strings.ToUpper(tokens[0].Text)] {
+		return 0, false, nil
+	}
+
+	switch flavor {
+	case conn.PostgreSQL:
+		{
+			estimate, err = db.estimatePostgresRows(statement)
+		}
+	case conn.MySQL:
+		{
+			estimate, err = db.estimateMySQLRows(statement)
+		}
+	default:
+		{
+			return 0, false, nil
+		}
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return estimate, true, nil
+}
+
+func (db *DBClient) estimatePostgresRows(statement string) (estimate int64, err error) {
+	explainStatement, err := db.checkStatement(fmt.Sprint("EXPLAIN (FORMAT JSON) ", statement))
+	if err != nil {
+		return 0, err
+	}
+
+	if db.InTransaction() {
+		connection, connErr := db.getTransactionConnection()
+		if connErr != nil {
+			return 0, connErr
+		}
+
+		if _, savepointErr := connection.ExecContext(db.ctx, fmt.Sprint("SAVEPOINT ", estimateSavepoint)); savepointErr != nil {
+			return 0, savepointErr
+		}
+		defer func() {
+			if _, rollbackErr := connection.ExecContext(db.ctx, fmt.Sprint("ROLLBACK TO SAVEPOINT ", estimateSavepoint)); rollbackErr != nil {
+				err = errors.Join(err, rollbackErr)
+			}
+		}()
+	}
+
+	explainResult, err := db.runStatement(&StatementWithParams{explainStatement, nil})
+	if err != nil {
+		return 0, err
+	}
+	if explainResult == nil || len(explainResult.Rows) == 0 || len(explainResult.Columns) == 0 {
+		return 0, errors.New("EXPLAIN returned no plan")
+	}
+
+	return parsePostgresPlanRows(explainResult.Rows[0][explainResult.Columns[0]].ToString())
+}
+
+// A step of a Postgres plan, and the steps it reads from
+type postgresPlanNode struct {
+	PlanRows float64            `json:"Plan Rows"`
+	Plans    []postgresPlanNode `json:"Plans"`
+}
+
+// Most rows any step is expected to produce, from the output of EXPLAIN (FORMAT JSON)
+// The top step only counts what's returned, so a full scan under an aggregate would be missed by it alone
+func parsePostgresPlanRows(plan string) (int64, error) {
+	var explainOutput []struct {
+		Plan postgresPlanNode `json:"Plan"`
+	}
+
+	if err := json.Unmarshal([]byte(plan), &explainOutput); err != nil {
+		return 0, errors.Join(
+			errors.New("Failed to parse EXPLAIN output"),
+			err,
+		)
+	}
+	if len(explainOutput) == 0 {
+		return 0, errors.New("EXPLAIN returned no plan")
+	}
+
+	var maxPlanRows func(node postgresPlanNode) float64
+	maxPlanRows = func(node postgresPlanNode) float64 {
+		rows := node.PlanRows
+		for _, child := range node.Plans {
+			rows = max(rows, maxPlanRows(child))
+		}
+
+		return rows
+	}
+
+	return int64(maxPlanRows(explainOutput[0].Plan)), nil
+}
+
+func (db *DBClient) estimateMySQLRows(statement string) (int64, error) {
+	explainStatement, err := db.checkStatement(fmt.Sprint("EXPLAIN ", statement))
+	if err != nil {
+		return 0, err
+	}
+
+	explainResult, err := db.runStatement(&StatementWithParams{explainStatement, nil})
+	if err != nil {
+		return 0, err
+	}
+	if explainResult == nil {
+		return 0, errors.New("EXPLAIN returned no plan")
+	}
+
+	return getMySQLExplainRows(explainResult), nil
+}
+
+// Most rows any table of the plan is expected to read, from the rows column of EXPLAIN
+func getMySQLExplainRows(explainResult *QueryResult) int64 {
+	var estimate int64
+	for _, row := range explainResult.Rows {
+		rows, exists := row["rows"]
+		if !exists || !rows.Valid {
+			continue
+		}
+
+		if tableRows, err := strconv.ParseInt(rows.String, 10, 64); err == nil {
+			estimate = max(estimate, tableRows)
+		}
+	}
+
+	return estimate
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePostgresPlanRows(t *testing.T) {
+	var tests = []struct {
+		Name          string
+		Plan          string
+		ExpectedRows  int64
+		ExpectedError bool
+	}{
+		{
+			Name:         "Single scan",
+			Plan:         `[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 1200}}]`,
+			ExpectedRows: 1200,
+		},
+		{
+			Name: "Full scan under an aggregate",
+			Plan: `[{"Plan": {"Node Type": "Aggregate", "Plan Rows": 1, "Plans": [
+				{"Node Type": "Gather", "Plan Rows": 2, "Plans": [
+					{"Node Type": "Seq Scan", "Plan Rows": 1500000000}
+				]}
+			]}}]`,
+			ExpectedRows: 1500000000,
+		},
+		{
+			Name:          "Empty Plan",
+			Plan:          `[]`,
+			ExpectedError: true,
+		},
+		{
+			Name:          "Not JSON",
+			Plan:          `Seq Scan on foo`,
+			ExpectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rows, err := parsePostgresPlanRows(test.Plan)
+			if test.ExpectedError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.ExpectedRows, rows)
+		})
+	}
+}
+
+func TestGetMySQLExplainRows(t *testing.T) {
+	assert := assert.New(t)
+
+	explainResult := &QueryResult{
+		Columns: []string{"table", "rows"},
+		Rows: []map[string]*NullString{
+			{"table": {sql.NullString{String: "users", Valid: true}}, "rows": {sql.NullString{String: "250", Valid: true}}},
+			{"table": {sql.NullString{String: "events", Valid: true}}, "rows": {sql.NullString{String: "98000000", Valid: true}}},
+			{"table": {sql.NullString{String: "<derived2>", Valid: true}}, "rows": {}},
+		},
+	}
+
+	assert.Equal(int64(98000000), getMySQLExplainRows(explainResult))
+}
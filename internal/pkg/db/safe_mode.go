@@ -0,0 +1,104 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// \safe [on|off]
+// Show or change whether updates and deletes without a WHERE clause are refused
+// MySQL enforces this itself with SQL_SAFE_UPDATES, on Postgres statements are checked before they're sent
+func (db *DBClient) safeCommand(argument string) (*QueryResult, error) {
+	switch strings.ToLower(argument) {
+	case "":
+		{
+		}
+	case "on":
+		{
+			if err := db.connManager.SetSafeMode(true); err != nil {
+				return nil, err
+			}
+		}
+	case "off":
+		{
+			if err := db.connManager.SetSafeMode(false); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		{
+			return nil, errors.New(`Usage: \safe [on|off]`)
+		}
+	}
+
+	state := "off"
+	if db.connManager.IsSafeMode() {
+		state = "on"
+	}
+
+	return newTextResult([]string{"Safe mode"}, [][]string{{state}}), nil
+}
+
+// Whether updates and deletes without a WHERE clause are refused
+func (db *DBClient) IsSafeMode() bool {
+	return db.connManager.IsSafeMode()
+}
+
+// Refuse an update or delete without a WHERE clause while in safe mode on Postgres
+// MySQL refuses them itself, with a more specific error
+func (db *DBClient) checkSafeMode(statement string) error {
+	flavor := db.connManager.GetFlavor()
+	if flavor != conn.PostgreSQL || !db.connManager.IsSafeMode() {
+		return nil
+	}
+
+	return checkUnboundedUpdate(flavor, statement)
+}
+
+// Error for an UPDATE or DELETE which would change every row of its table, as it has no WHERE clause
+// Clauses of subqueries don't count, only the statement's own, including after a WITH
+func checkUnboundedUpdate(flavor conn.DBFlavor, statement string) error {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 {
+		return nil
+	}
+	if !tokens[0].IsKeyword("UPDATE") && !tokens[0].IsKeyword("DELETE") && !tokens[0].IsKeyword("WITH") {
+		return nil
+	}
+
+	verb := ""
+	depth := 0
+	for _, token := range tokens {
+		switch {
+		case token.IsOperator("("):
+			{
+				depth++
+			}
+		case token.IsOperator(")"):
+			{
+				depth--
+			}
+		case depth != 0:
+			{
+			}
+		case verb == "" && (token.IsKeyword("UPDATE") || token.IsKeyword("DELETE")):
+			{
+				verb = strings.ToUpper(token.Text)
+			}
+		case verb != "" && token.IsKeyword("WHERE"):
+			{
+				return nil
+			}
+		}
+	}
+
+	if verb == "" {
+		return nil
+	}
+
+	return fmt.Errorf(`Safe mode: %s without a WHERE clause would change every row, add one or turn safe mode off with \safe off`, verb)
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckUnboundedUpdate(t *testing.T) {
+	var tests = []struct {
+		Statement     string
+		ExpectedError string
+	}{
+		{"SELECT * FROM users", ""},
+		{"UPDATE users SET active = false WHERE id = 1", ""},
+		{"DELETE FROM users WHERE id = 1", ""},
+		{"UPDATE users SET active = false", "UPDATE without a WHERE clause"},
+		{"delete from users;", "DELETE without a WHERE clause"},
+		{"UPDATE users SET team_id = (SELECT id FROM teams WHERE name = 'core')", "UPDATE without a WHERE clause"},
+		{"DELETE FROM users -- WHERE id = 1", "DELETE without a WHERE clause"},
+		{"WITH inactive AS (SELECT id FROM users WHERE active = false) DELETE FROM users", "DELETE without a WHERE clause"},
+		{"WITH inactive AS (SELECT id FROM users) DELETE FROM users WHERE id IN (SELECT id FROM inactive)", ""},
+		{"WITH recent AS (SELECT * FROM users) SELECT * FROM recent", ""},
+		{"INSERT INTO users (name) VALUES ('update')", ""},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+
+			err := checkUnboundedUpdate(conn.PostgreSQL, test.Statement)
+			if test.ExpectedError == "" {
+				assert.NoError(err)
+			} else {
+				assert.ErrorContains(err, test.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestSafeCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	connManager := &fakeConnManager{database: "test"}
+	dbClient, err := CreateDBClient(connManager)
+	if !assert.NoError(err) {
+		return
+	}
+
+	result, err := dbClient.Query(`\safe on`)
+	assert.NoError(err)
+	assert.Equal("on", result.Rows[0]["Safe mode"].ToString())
+	assert.True(dbClient.IsSafeMode())
+
+	_, err = dbClient.Query("DELETE FROM users")
+	assert.ErrorContains(err, "Safe mode")
+	_, err = dbClient.Exec("UPDATE users SET active = false;")
+	assert.ErrorContains(err, "Safe mode")
+
+	result, err = dbClient.Query(`\safe off`)
+	assert.NoError(err)
+	assert.Equal("off", result.Rows[0]["Safe mode"].ToString())
+
+	// Sent to the database, which the fake can't reach
+	_, err = dbClient.Exec("UPDATE users SET active = false;")
+	assert.ErrorIs(err, conn.ErrConnectionFailed)
+
+	_, err = dbClient.Query(`\safe maybe`)
+	assert.ErrorContains(err, "Usage")
+}
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/jmoiron/sqlx"
+)
+
+// Metadata about the tables in the current schema
+type Schema struct {
+	Flavor conn.DBFlavor           `json:"flavor"`
+	Tables map[string]*TableSchema `json:"tables"`
+	// Set when loaded from the offline schema cache as the database was unreachable, to when it was saved
+	CachedAt *time.Time `json:"-"`
+}
+
+type TableSchema struct {
+	Columns []ColumnSchema `json:"columns"`
+	Indexes []IndexSchema  `json:"indexes"`
+}
+
+type ColumnSchema struct {
+	Name     string  `json:"name"`
+	Type     string  `json:"type"`
+	Nullable bool    `json:"nullable"`
+	Default  *string `json:"default"`
+	// Value comes from the database, such as identity, auto_increment or generated columns
+	Generated bool `json:"generated"`
+}
+
+type IndexSchema struct {
+	Name string `json:"name"`
+	// Column names, or expressions for expression indexes
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	Primary bool     `json:"primary"`
+}
+
+// Index columns are aggregated with a separator which won't appear in an expression
+const schemaIndexColumnSeparator = "\x1f"
+
+const postgresSchemaColumnsQuery string = `
+SELECT
+  c.relname AS table_name,
+  a.attname AS column_name,
+  format_type(a.atttypid, a.atttypmod) AS column_type,
+  NOT a.attnotnull AS nullable,
+  pg_get_expr(d.adbin, d.adrelid) AS column_default,
+  a.attidentity <> '' OR a.attgenerated <> '' AS generated
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE n.nspname = current_schema()
+AND c.relkind IN ('r', 'p', 'v', 'm')
+AND a.attnum > 0
+AND NOT a.attisdropped
+ORDER BY c.relname, a.attnum
+`
+
+const postgresSchemaIndexesQuery string = `
+SELECT
+  t.relname AS table_name,
+  i.relname AS index_name,
+  (
+    SELECT string_agg(pg_get_indexdef(ix.indexrelid, k, true), chr(31) ORDER BY k)
+    FROM generate_series(1, ix.indnkeyatts) k
+  ) AS columns,
+  ix.indisunique AS is_unique,
+  ix.indisprimary AS is_primary
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+WHERE n.nspname = current_schema()
+ORDER BY t.relname, i.relname
+`
+
+const mySQLSchemaColumnsQuery string = `
+SELECT
+  TABLE_NAME AS table_name,
+  COLUMN_NAME AS column_name,
+  COLUMN_TYPE AS column_type,
+  IS_NULLABLE = 'YES' AS nullable,
+  COLUMN_DEFAULT AS column_default,
+  EXTRA LIKE '%auto_increment%' OR EXTRA IN ('VIRTUAL GENERATED', 'STORED GENERATED') AS generated
+FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = DATABASE()
+ORDER BY TABLE_NAME, ORDINAL_POSITION
+`
+
+var mySQLSchemaIndexesQuery string = fmt.Sprintf(`
+SELECT
+  TABLE_NAME AS table_name,
+  INDEX_NAME AS index_name,
+  GROUP_CONCAT(COALESCE(COLUMN_NAME, EXPRESSION) ORDER BY SEQ_IN_INDEX SEPARATOR '%s') AS columns,
+  NON_UNIQUE = 0 AS is_unique,
+  INDEX_NAME = 'PRIMARY' AS is_primary
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = DATABASE()
+GROUP BY TABLE_NAME, INDEX_NAME, NON_UNIQUE
+ORDER BY TABLE_NAME, INDEX_NAME
+`, schemaIndexColumnSeparator)
+
+// Get metadata about the current schema, loading it on first use
+// While the database is unreachable, the schema saved to the offline cache is returned, with CachedAt set
+func (db *DBClient) GetSchema() (*Schema, error) {
+	if db.schema != nil {
+		return db.schema, nil
+	}
+
+	schema, err := db.RefreshSchema()
+	if offlineSchema := db.getOfflineSchema(err); offlineSchema != nil {
+		return offlineSchema, nil
+	}
+
+	return schema, err
+}
+
+// Schema as last loaded, without querying the database
+// Nil until it's loaded, and again once it may have changed
+func (db *DBClient) GetCachedSchema() *Schema {
+	return db.schema
+}
+
+// Reload metadata about the current schema, such as after running migrations
+func (db *DBClient) RefreshSchema() (*Schema, error) {
+	var columnsQuery, indexesQuery string
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			columnsQuery, indexesQuery = postgresSchemaColumnsQuery, postgresSchemaIndexesQuery
+		}
+	case conn.MySQL:
+		{
+			columnsQuery, indexesQuery = mySQLSchemaColumnsQuery, mySQLSchemaIndexesQuery
+		}
+	default:
+		{
+			return nil, commandNotSupportedError("Loading schema", db.connManager.GetFlavor())
+		}
+	}
+
+	schemaLoadError := errors.New("Failed to load schema")
+
+	var columns []struct {
+		TableName string  `db:"table_name"`
+		Name      string  `db:"column_name"`
+		Type      string  `db:"column_type"`
+		Nullable  bool    `db:"nullable"`
+		Default   *string `db:"column_default"`
+		Generated bool    `db:"generated"`
+	}
+	err := db.runMetadataQuery(func(connection *sqlx.Conn) error {
+		return connection.SelectContext(db.ctx, &columns, columnsQuery)
+	})
+	if err != nil {
+		return nil, errors.Join(schemaLoadError, err)
+	}
+
+	var indexes []struct {
+		TableName string `db:"table_name"`
+		Name      string `db:"index_name"`
+		Columns   string `db:"columns"`
+		Unique    bool   `db:"is_unique"`
+		Primary   bool   `db:"is_primary"`
+	}
+	err = db.runMetadataQuery(func(connection *sqlx.Conn) error {
+		return connection.SelectContext(db.ctx, &indexes, indexesQuery)
+	})
+	if err != nil {
+		return nil, errors.Join(schemaLoadError, err)
+	}
+
+	schema := &Schema{
+		Flavor: db.connManager.GetFlavor(),
+		Tables: make(map[string]*TableSchema),
+	}
+	getTable := func(tableName string) *TableSchema {
+		table, exists := schema.Tables[tableName]
+		if !exists {
+			table = &TableSchema{}
+			schema.Tables[tableName] = table
+		}
+
+		return table
+	}
+
+	for _, column := range columns {
+		table := getTable(column.TableName)
+		table.Columns = append(table.Columns, ColumnSchema{
+			Name:      column.Name,
+			Type:      column.Type,
+			Nullable:  column.Nullable,
+			Default:   column.Default,
+			Generated: column.Generated,
+		})
+	}
+
+	for _, index := range indexes {
+		table := getTable(index.TableName)
+		table.Indexes = append(table.Indexes, IndexSchema{
+			Name:    index.Name,
+			Columns: strings.Split(index.Columns, schemaIndexColumnSeparator),
+			Unique:  index.Unique,
+			Primary: index.Primary,
+		})
+	}
+
+	db.schema = schema
+
+	// Not being able to work offline later shouldn't stop anything working now
+	_ = db.saveSchemaCache(schema)
+
+	return schema, nil
+}
+
+// Get metadata about a table in the current schema
+// Reloads the schema if the table isn't known, as it may have been created since the schema was loaded
+func (db *DBClient) GetTableSchema(tableName string) (*TableSchema, error) {
+	schema, err := db.GetSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	if table, exists := schema.Tables[tableName]; exists {
+		return table, nil
+	}
+
+	schema, err = db.RefreshSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	if table, exists := schema.Tables[tableName]; exists {
+		return table, nil
+	}
+
+	return nil, fmt.Errorf("Table %s does not exist", tableName)
+}
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Last schema loaded from the database, saved to disk so schema statements can be answered while offline
+type schemaCacheFile struct {
+	SavedAt time.Time `json:"saved_at"`
+	Schema  *Schema   `json:"schema"`
+}
+
+// Save the schema to this file whenever it's loaded, and fall back to it while the database is unreachable
+// Empty to disable
+func (db *DBClient) SetSchemaCachePath(path string) {
+	db.schemaCachePath = path
+}
+
+// Whether there's a saved schema to fall back to at the path
+func HasSchemaCache(path string) bool {
+	_, err := os.Stat(path)
+	return path != "" && err == nil
+}
+
+func (db *DBClient) saveSchemaCache(schema *Schema) error {
+	if db.schemaCachePath == "" {
+		return nil
+	}
+
+	contents, err := json.Marshal(schemaCacheFile{time.Now(), schema})
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to serialize schema cache"),
+			err,
+		)
+	}
+
+	// Table and column names may be sensitive, so only the current user can read them
+	err = os.MkdirAll(filepath.Dir(db.schemaCachePath), 0o700)
+	if err == nil {
+		err = os.WriteFile(db.schemaCachePath, contents, 0o600)
+	}
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to write schema cache to %s", db.schemaCachePath),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Load the saved schema, with CachedAt set to when it was saved
+func (db *DBClient) loadSchemaCache() (*Schema, error) {
+	contents, err := os.ReadFile(db.schemaCachePath)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to read schema cache from %s", db.schemaCachePath),
+			err,
+		)
+	}
+
+	var cacheFile schemaCacheFile
+	err = json.Unmarshal(contents, &cacheFile)
+	if err != nil || cacheFile.Schema == nil {
+		return nil, errors.Join(
+			fmt.Errorf("%s is not a valid schema cache", db.schemaCachePath),
+			err,
+		)
+	}
+
+	if cacheFile.Schema.Flavor != db.connManager.GetFlavor() {
+		return nil, fmt.Errorf("Schema cache %s is for %s", db.schemaCachePath, cacheFile.Schema.Flavor)
+	}
+
+	cacheFile.Schema.CachedAt = &cacheFile.SavedAt
+	return cacheFile.Schema, nil
+}
+
+// Fall back to the saved schema when loading failed because the database is unreachable
+// Returns nil when the error is for another reason, or there's no usable saved schema
+func (db *DBClient) getOfflineSchema(err error) *Schema {
+	if db.schemaCachePath == "" || !errors.Is(err, conn.ErrConnectionFailed) {
+		return nil
+	}
+
+	schema, err := db.loadSchemaCache()
+	if err != nil {
+		return nil
+	}
+
+	return schema
+}
+
+// Answer DESCRIBE, SHOW COLUMNS and SHOW TABLES from the saved schema, when the statement failed as the database is unreachable
+// Returns nil when it can't be answered offline
+func (db *DBClient) getOfflineSchemaResult(statement string, err error) *QueryResult {
+	tableName, isDescribe := statementIsDescribe(statement)
+	if !isDescribe {
+		var pattern *string
+		tableName, pattern, isDescribe = statementIsShowColumns(statement)
+		isDescribe = isDescribe && pattern == nil
+	}
+
+	isShowTables := statementIsShowTables(statement)
+	if !isDescribe && !isShowTables {
+		return nil
+	}
+
+	schema := db.getOfflineSchema(err)
+	if schema == nil {
+		return nil
+	}
+
+	var result *QueryResult
+	if isShowTables {
+		result = buildOfflineShowTablesResult(schema)
+	} else if table, exists := schema.Tables[tableName]; exists {
+		result = buildOfflineDescribeResult(table)
+	} else {
+		return nil
+	}
+
+	result.SchemaCachedAt = schema.CachedAt
+	return result
+}
+
+func buildOfflineShowTablesResult(schema *Schema) *QueryResult {
+	tableNames := make([]string, 0, len(schema.Tables))
+	for tableName := range schema.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	slices.Sort(tableNames)
+
+	rows := make([][]string, len(tableNames))
+	for idx, tableName := range tableNames {
+		rows[idx] = []string{tableName}
+	}
+
+	return newTextResult([]string{"table_name"}, rows)
+}
+
+// Mirrors the shape of MySQL's DESCRIBE, as far as the saved schema allows
+func buildOfflineDescribeResult(table *TableSchema) *QueryResult {
+	rows := make([][]string, len(table.Columns))
+
+	for idx, column := range table.Columns {
+		null := "NO"
+		if column.Nullable {
+			null = "YES"
+		}
+
+		columnDefault := "NULL"
+		if column.Default != nil {
+			columnDefault = *column.Default
+		}
+
+		extra := ""
+		if column.Generated {
+			extra = "generated"
+		}
+
+		rows[idx] = []string{column.Name, column.Type, null, getOfflineColumnKey(table, column.Name), columnDefault, extra}
+	}
+
+	return newTextResult([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}, rows)
+}
+
+// PRI, UNI or MUL, the same as DESCRIBE
+func getOfflineColumnKey(table *TableSchema, columnName string) string {
+	key := ""
+
+	for _, index := range table.Indexes {
+		if index.Primary && slices.Contains(index.Columns, columnName) {
+			return "PRI"
+		}
+
+		if len(index.Columns) == 0 || index.Columns[0] != columnName {
+			continue
+		}
+
+		if index.Unique && len(index.Columns) == 1 {
+			key = "UNI"
+		} else if key == "" {
+			key = "MUL"
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOfflineColumnKey(t *testing.T) {
+	table := &TableSchema{
+		Indexes: []IndexSchema{
+			{Name: "pk", Columns: []string{"tenant_id", "id"}, Unique: true, Primary: true},
+			{Name: "email_key", Columns: []string{"email"}, Unique: true},
+			{Name: "name_idx", Columns: []string{"last_name", "first_name"}},
+			{Name: "email_name_idx", Columns: []string{"email", "last_name"}},
+		},
+	}
+
+	var tests = []struct {
+		Column      string
+		ExpectedKey string
+	}{
+		{"tenant_id", "PRI"},
+		{"id", "PRI"},
+		{"email", "UNI"},
+		{"last_name", "MUL"},
+		{"first_name", ""},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Column, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.ExpectedKey, getOfflineColumnKey(table, test.Column))
+		})
+	}
+}
+
+func TestOfflineSchemaResult(t *testing.T) {
+	assert := assert.New(t)
+
+	// Nothing listens on port 1, so every statement fails to connect
+	connManager, err := conn.OpenConnectionManager(&conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "127.0.0.1",
+		Port:         1,
+		User:         "offline",
+		DatabaseName: "offline",
+	}, context.Background())
+	if !assert.NoError(err) {
+		return
+	}
+
+	dbClient, err := CreateDBClient(connManager)
+	if !assert.NoError(err) {
+		return
+	}
+	defer dbClient.Destroy()
+
+	_, err = dbClient.Query("DESCRIBE users")
+	assert.ErrorIs(err, conn.ErrConnectionFailed, "Nothing to fall back to without a schema cache")
+
+	dbClient.SetSchemaCachePath(filepath.Join(t.TempDir(), "schema", "offline.json"))
+	columnDefault := "now()"
+	err = dbClient.saveSchemaCache(&Schema{
+		Flavor: conn.PostgreSQL,
+		Tables: map[string]*TableSchema{
+			"users": {
+				Columns: []ColumnSchema{
+					{Name: "id", Type: "integer", Generated: true},
+					{Name: "created_at", Type: "timestamp", Nullable: true, Default: &columnDefault},
+				},
+				Indexes: []IndexSchema{{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true}},
+			},
+			"accounts": {},
+		},
+	})
+	if !assert.NoError(err) {
+		return
+	}
+
+	result, err := dbClient.Query("DESCRIBE users;")
+	if assert.NoError(err) {
+		assert.NotNil(result.SchemaCachedAt, "Offline results are marked stale")
+		assert.Equal([]string{"Field", "Type", "Null", "Key", "Default", "Extra"}, result.Columns)
+		assert.Equal("PRI", result.Rows[0]["Key"].String)
+		assert.Equal("generated", result.Rows[0]["Extra"].String)
+		assert.Equal("now()", result.Rows[1]["Default"].String)
+	}
+
+	result, err = dbClient.Query("SHOW TABLES")
+	if assert.NoError(err) {
+		assert.Len(result.Rows, 2)
+		assert.Equal("accounts", result.Rows[0]["table_name"].String)
+	}
+
+	schema, err := dbClient.GetSchema()
+	if assert.NoError(err) {
+		assert.NotNil(schema.CachedAt)
+	}
+
+	_, err = dbClient.Query("DESCRIBE missing")
+	assert.Error(err)
+
+	_, err = dbClient.Query("SELECT * FROM users")
+	assert.Error(err, "Only schema statements are answered offline")
+}
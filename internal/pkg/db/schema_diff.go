@@ -0,0 +1,253 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+type SchemaChangeKind string
+
+const (
+	SchemaAdded   SchemaChangeKind = "added"
+	SchemaRemoved SchemaChangeKind = "removed"
+	SchemaChanged SchemaChangeKind = "changed"
+)
+
+// A single difference between two schemas
+type SchemaChange struct {
+	Kind SchemaChangeKind
+	// table, column or index
+	Object string
+	// Columns and indexes are prefixed with their table, i.e users.email
+	Name   string
+	Detail string
+}
+
+// Everything added, removed or changed going from before to after
+func DiffSchemas(before *Schema, after *Schema) (changes []SchemaChange) {
+	tableNames := make([]string, 0, len(before.Tables))
+	for tableName := range before.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	for tableName := range after.Tables {
+		if _, exists := before.Tables[tableName]; !exists {
+			tableNames = append(tableNames, tableName)
+		}
+	}
+	slices.Sort(tableNames)
+
+	for _, tableName := range tableNames {
+		beforeTable, existedBefore := before.Tables[tableName]
+		afterTable, existsAfter := after.Tables[tableName]
+
+		switch {
+		case !existedBefore:
+			{
+				changes = append(changes, SchemaChange{SchemaAdded, "table", tableName, ""})
+			}
+		case !existsAfter:
+			{
+				changes = append(changes, SchemaChange{SchemaRemoved, "table", tableName, ""})
+			}
+		default:
+			{
+				changes = append(changes, diffColumns(tableName, beforeTable.Columns, afterTable.Columns)...)
+				changes = append(changes, diffIndexes(tableName, beforeTable.Indexes, afterTable.Indexes)...)
+			}
+		}
+	}
+
+	return changes
+}
+
+func diffColumns(tableName string, before []ColumnSchema, after []ColumnSchema) (changes []SchemaChange) {
+	diffNamed(
+		before,
+		after,
+		func(column ColumnSchema) string { return column.Name },
+		func(column ColumnSchema) string { return column.Describe() },
+		func(kind SchemaChangeKind, name string, detail string) {
+			changes = append(changes, SchemaChange{kind, "column", fmt.Sprint(tableName, ".", name), detail})
+		},
+	)
+
+	return changes
+}
+
+func diffIndexes(tableName string, before []IndexSchema, after []IndexSchema) (changes []SchemaChange) {
+	diffNamed(
+		before,
+		after,
+		func(index IndexSchema) string { return index.Name },
+		func(index IndexSchema) string { return describeIndexSchema(index) },
+		func(kind SchemaChangeKind, name string, detail string) {
+			changes = append(changes, SchemaChange{kind, "index", fmt.Sprint(tableName, ".", name), detail})
+		},
+	)
+
+	return changes
+}
+
+// Match up items by name, reporting those only on one side or described differently on each
+func diffNamed[T any](
+	before []T,
+	after []T,
+	getName func(T) string,
+	describe func(T) string,
+	report func(kind SchemaChangeKind, name string, detail string),
+) {
+	afterByName := make(map[string]T, len(after))
+	for _, item := range after {
+		afterByName[getName(item)] = item
+	}
+
+	beforeByName := make(map[string]T, len(before))
+	for _, beforeItem := range before {
+		name := getName(beforeItem)
+		beforeByName[name] = beforeItem
+
+		afterItem, existsAfter := afterByName[name]
+		if !existsAfter {
+			report(SchemaRemoved, name, describe(beforeItem))
+			continue
+		}
+
+		beforeDescription, afterDescription := describe(beforeItem), describe(afterItem)
+		if beforeDescription != afterDescription {
+			report(SchemaChanged, name, fmt.Sprint(beforeDescription, " → ", afterDescription))
+		}
+	}
+
+	for _, afterItem := range after {
+		name := getName(afterItem)
+		if _, existedBefore := beforeByName[name]; !existedBefore {
+			report(SchemaAdded, name, describe(afterItem))
+		}
+	}
+}
+
+// Type, nullability and default of a column, i.e "integer NOT NULL DEFAULT 0"
+func (column ColumnSchema) Describe() string {
+	description := []string{column.Type}
+
+	if column.Nullable {
+		description = append(description, "NULL")
+	} else {
+		description = append(description, "NOT NULL")
+	}
+
+	if column.Default != nil {
+		description = append(description, "DEFAULT", *column.Default)
+	}
+
+	if column.Generated {
+		description = append(description, "GENERATED")
+	}
+
+	return strings.Join(description, " ")
+}
+
+func describeIndexSchema(index IndexSchema) string {
+	description := fmt.Sprintf("(%s)", strings.Join(index.Columns, ", "))
+	if index.Primary {
+		description = fmt.Sprint("PRIMARY KEY ", description)
+	} else if index.Unique {
+		description = fmt.Sprint("UNIQUE ", description)
+	}
+
+	return description
+}
+
+// \schema snapshot <file> or \schema diff <file>
+// Save the current schema to a file, or compare the current schema against one saved earlier
+func (db *DBClient) schemaCommand(argument string) (*QueryResult, error) {
+	usageError := errors.New(`Usage: \schema snapshot <file> | \schema diff <file>`)
+
+	arguments := strings.Fields(strings.TrimSuffix(argument, ";"))
+	if len(arguments) != 2 {
+		return nil, usageError
+	}
+	subcommand, path := arguments[0], arguments[1]
+
+	// Migrations may have run since the schema was last loaded
+	schema, err := db.RefreshSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	switch subcommand {
+	case "snapshot":
+		{
+			return db.snapshotSchema(schema, path)
+		}
+	case "diff":
+		{
+			return db.diffSchemaSnapshot(schema, path)
+		}
+	default:
+		{
+			return nil, usageError
+		}
+	}
+}
+
+func (db *DBClient) snapshotSchema(schema *Schema, path string) (*QueryResult, error) {
+	snapshot, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to serialize schema"),
+			err,
+		)
+	}
+
+	err = os.WriteFile(path, snapshot, 0644)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to write schema snapshot to %s", path),
+			err,
+		)
+	}
+
+	return newTextResult(
+		[]string{"Snapshot"},
+		[][]string{{fmt.Sprintf("Saved %d tables to %s", len(schema.Tables), path)}},
+	), nil
+}
+
+func (db *DBClient) diffSchemaSnapshot(schema *Schema, path string) (*QueryResult, error) {
+	rawSnapshot, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to read schema snapshot from %s", path),
+			err,
+		)
+	}
+
+	var snapshot Schema
+	err = json.Unmarshal(rawSnapshot, &snapshot)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("%s is not a valid schema snapshot", path),
+			err,
+		)
+	}
+
+	changes := DiffSchemas(&snapshot, schema)
+	if len(changes) == 0 {
+		return newTextResult(
+			[]string{"Diff"},
+			[][]string{{fmt.Sprintf("No differences from %s", path)}},
+		), nil
+	}
+
+	rows := make([][]string, len(changes))
+	for idx, change := range changes {
+		rows[idx] = []string{string(change.Kind), change.Object, change.Name, change.Detail}
+	}
+
+	return newTextResult([]string{"Change", "Object", "Name", "Detail"}, rows), nil
+}
@@ -0,0 +1,64 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	assert := assert.New(t)
+
+	defaultNow := "now()"
+	before := &db.Schema{
+		Tables: map[string]*db.TableSchema{
+			"users": {
+				Columns: []db.ColumnSchema{
+					{Name: "id", Type: "integer"},
+					{Name: "email", Type: "varchar(255)", Nullable: true},
+					{Name: "nickname", Type: "text", Nullable: true},
+				},
+				Indexes: []db.IndexSchema{
+					{Name: "users_pkey", Columns: []string{"id"}, Unique: true},
+					{Name: "users_email_idx", Columns: []string{"email"}},
+				},
+			},
+			"sessions": {
+				Columns: []db.ColumnSchema{{Name: "id", Type: "integer"}},
+			},
+		},
+	}
+	after := &db.Schema{
+		Tables: map[string]*db.TableSchema{
+			"users": {
+				Columns: []db.ColumnSchema{
+					{Name: "id", Type: "integer"},
+					{Name: "email", Type: "text"},
+					{Name: "created_at", Type: "timestamp", Default: &defaultNow},
+				},
+				Indexes: []db.IndexSchema{
+					{Name: "users_pkey", Columns: []string{"id"}, Unique: true},
+					{Name: "users_email_idx", Columns: []string{"email"}, Unique: true},
+				},
+			},
+			"posts": {
+				Columns: []db.ColumnSchema{{Name: "id", Type: "integer"}},
+			},
+		},
+	}
+
+	assert.Equal(
+		[]db.SchemaChange{
+			{Kind: db.SchemaAdded, Object: "table", Name: "posts"},
+			{Kind: db.SchemaRemoved, Object: "table", Name: "sessions"},
+			{Kind: db.SchemaChanged, Object: "column", Name: "users.email", Detail: "varchar(255) NULL → text NOT NULL"},
+			{Kind: db.SchemaRemoved, Object: "column", Name: "users.nickname", Detail: "text NULL"},
+			{Kind: db.SchemaAdded, Object: "column", Name: "users.created_at", Detail: "timestamp NOT NULL DEFAULT now()"},
+			{Kind: db.SchemaChanged, Object: "index", Name: "users.users_email_idx", Detail: "(email) → UNIQUE (email)"},
+		},
+		db.DiffSchemas(before, after),
+	)
+
+	assert.Empty(db.DiffSchemas(after, after))
+}
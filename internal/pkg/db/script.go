@@ -0,0 +1,23 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Split text holding several statements, such as a pasted migration script, into each statement with its semicolon
+// Semicolons within strings, quoted identifiers and comments don't end a statement
+func (db *DBClient) SplitStatements(script string) []string {
+	return splitStatements(db.connManager.GetFlavor(), script)
+}
+
+func splitStatements(flavor conn.DBFlavor, script string) []string {
+	statements := lexer.SplitStatements(flavor, script)
+	for idx, statement := range statements {
+		statements[idx] = fmt.Sprint(statement, ";")
+	}
+
+	return statements
+}
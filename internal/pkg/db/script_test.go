@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	var tests = []struct {
+		Name       string
+		Script     string
+		Statements []string
+	}{
+		{"Single statement", "SELECT 1;", []string{"SELECT 1;"}},
+		{
+			"Migration script",
+			"CREATE TABLE foo (id int);\n-- seed it\nINSERT INTO foo VALUES (1);\n",
+			[]string{"CREATE TABLE foo (id int);", "-- seed it\nINSERT INTO foo VALUES (1);"},
+		},
+		{"Semicolon in string", "SELECT 'a;b'; SELECT 2;", []string{"SELECT 'a;b';", "SELECT 2;"}},
+		{"Meta command", `\temp; SELECT 1;`, []string{`\temp;`, "SELECT 1;"}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.Statements, splitStatements(conn.PostgreSQL, test.Script))
+		})
+	}
+}
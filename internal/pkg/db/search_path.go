@@ -0,0 +1,144 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Statements which may change the search_path, after which it's read again
+var searchPathChangeRegExp = regexp.MustCompile(`(?is)^\s*((SET|RESET)\b.*\b(search_path|schema|all)\b|DISCARD\b)`)
+
+const postgresSchemasQuery string = `
+SELECT
+  n.nspname AS "Schema",
+  pg_get_userbyid(n.nspowner) AS "Owner",
+  CASE
+    WHEN n.nspname = current_schema() THEN 'current'
+    WHEN n.nspname = ANY(current_schemas(false)) THEN 'yes'
+    ELSE ''
+  END AS "In search_path"
+FROM pg_namespace n
+WHERE n.nspname NOT LIKE 'pg\_%'
+AND n.nspname <> 'information_schema'
+ORDER BY n.nspname ASC
+`
+
+// Postgres search_path for the session, ex: "$user", public
+// Read once and kept until a statement may have changed it. Empty for MySQL, or when it can't be read
+func (db *DBClient) GetSearchPath() string {
+	if db.connManager.GetFlavor() != conn.PostgreSQL {
+		return ""
+	}
+
+	if db.searchPath == nil {
+		// Failures are kept too, so an unreachable database isn't retried every time the status bar is drawn
+		var searchPath string
+		if connection, err := db.getTransactionConnection(); err == nil {
+			_ = connection.GetContext(db.ctx, &searchPath, "SHOW search_path")
+		}
+		db.searchPath = &searchPath
+	}
+
+	return *db.searchPath
+}
+
+// Run a statement, reading the search_path again afterwards when the statement may have changed it
+// A change is recorded for \switches, whether the statement was sent by Query or Exec
+func (db *DBClient) trackSearchPathChange(statement string, run func() error) error {
+	if !searchPathChangeRegExp.MatchString(statement) {
+		return run()
+	}
+
+	previousSearchPath := db.GetSearchPath()
+	err := run()
+	db.invalidateSearchPath()
+
+	if searchPath := db.GetSearchPath(); err == nil && searchPath != previousSearchPath {
+		db.recordContextSwitch(searchPathSetting, previousSearchPath, searchPath)
+	}
+
+	return err
+}
+
+// Forget what was loaded for the previous search_path, as unqualified names may now refer to other tables
+func (db *DBClient) invalidateSearchPath() {
+	db.searchPath = nil
+	db.schema = nil
+}
+
+// \schemas
+// List the schemas of the database, and which are in the search_path
+func (db *DBClient) schemasCommand(argument string) (*QueryResult, error) {
+	if db.connManager.GetFlavor() != conn.PostgreSQL {
+		return nil, commandNotSupportedError(`\schemas`, db.connManager.GetFlavor())
+	}
+
+	return db.runStatement(&StatementWithParams{postgresSchemasQuery, nil})
+}
+
+// \setschema <schema>[, <schema>...]
+// Set the search_path for the rest of the session, kept when reconnecting
+func (db *DBClient) setSchemaCommand(argument string) (*QueryResult, error) {
+	if db.connManager.GetFlavor() != conn.PostgreSQL {
+		return nil, commandNotSupportedError(`\setschema`, db.connManager.GetFlavor())
+	}
+
+	var schemas []string
+	for _, schema := range strings.Split(strings.TrimSuffix(argument, ";"), ",") {
+		if schema = strings.TrimSpace(schema); schema != "" {
+			schemas = append(schemas, schema)
+		}
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New(`Usage: \setschema <schema>[, <schema>...]`)
+	}
+
+	// Rolling back the sandbox would quietly undo the change
+	if db.InTransaction() {
+		return nil, errors.New(`Commit or roll back the sandbox before using \setschema`)
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// Postgres accepts schemas which don't exist, leaving tables mysteriously missing
+	quotedSchemas := make([]string, len(schemas))
+	for idx, schema := range schemas {
+		var exists bool
+		err = connection.GetContext(db.ctx, &exists, "SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)", schema)
+		if err != nil {
+			return nil, errors.Join(
+				errors.New("Unable to validate that the schema exists"),
+				err,
+			)
+		}
+		if !exists {
+			return nil, fmt.Errorf(`Schema %s does not exist, see \schemas`, schema)
+		}
+
+		quotedSchemas[idx] = db.quoteIdentifier(schema)
+	}
+
+	previousSearchPath := db.GetSearchPath()
+	err = db.connManager.SetSearchPath(strings.Join(quotedSchemas, ", "))
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to set search_path"),
+			err,
+		)
+	}
+	db.invalidateSearchPath()
+	db.resultCache.invalidate()
+	db.recordContextSwitch(searchPathSetting, previousSearchPath, db.GetSearchPath())
+
+	return newTextResult(
+		[]string{"search_path"},
+		[][]string{{db.GetSearchPath()}},
+	), nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+var setSearchPathRegExp = regexp.MustCompile(`(?i)^SET search_path TO (.+)$`)
+
+// Postgres stand-in which only knows about the search_path, enough to follow it being changed
+type searchPathDriver struct {
+	searchPath string
+}
+
+func (fake *searchPathDriver) Connect(context.Context) (driver.Conn, error) { return fake, nil }
+func (fake *searchPathDriver) Driver() driver.Driver                        { return nil }
+
+func (fake *searchPathDriver) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("Statements can't be prepared")
+}
+func (fake *searchPathDriver) Close() error { return nil }
+func (fake *searchPathDriver) Begin() (driver.Tx, error) {
+	return nil, errors.New("Transactions aren't supported")
+}
+
+func (fake *searchPathDriver) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if match := setSearchPathRegExp.FindStringSubmatch(query); match != nil {
+		fake.searchPath = match[1]
+	}
+
+	return driver.RowsAffected(0), nil
+}
+
+func (fake *searchPathDriver) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if query != "SHOW search_path" {
+		return nil, errors.New("Unexpected query")
+	}
+
+	return &searchPathRows{value: fake.searchPath}, nil
+}
+
+type searchPathRows struct {
+	value string
+	read  bool
+}
+
+func (rows *searchPathRows) Columns() []string { return []string{"search_path"} }
+func (rows *searchPathRows) Close() error      { return nil }
+
+func (rows *searchPathRows) Next(dest []driver.Value) error {
+	if rows.read {
+		return io.EOF
+	}
+
+	rows.read = true
+	dest[0] = rows.value
+	return nil
+}
+
+// Hands out a connection to searchPathDriver
+type searchPathConnManager struct {
+	fakeConnManager
+	connection *sqlx.Conn
+}
+
+func (fake *searchPathConnManager) GetConnection() (*sqlx.Conn, error) {
+	return fake.connection, nil
+}
+
+func TestExecSearchPathChange(t *testing.T) {
+	assert := assert.New(t)
+
+	fakeDriver := &searchPathDriver{searchPath: "public"}
+	connection, err := sqlx.NewDb(sql.OpenDB(fakeDriver), "postgres").Connx(context.Background())
+	if !assert.NoError(err) {
+		return
+	}
+	defer connection.Close()
+
+	dbClient, err := CreateDBClient(&searchPathConnManager{connection: connection})
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal("public", dbClient.GetSearchPath())
+	dbClient.schema = &Schema{}
+
+	_, err = dbClient.Exec("SET search_path TO sales")
+	assert.NoError(err)
+	assert.Equal("sales", dbClient.GetSearchPath())
+	// Tables were loaded for the previous search_path
+	assert.Nil(dbClient.schema)
+
+	result, err := dbClient.Query(`\switches`)
+	if !assert.NoError(err) || !assert.Len(result.Rows, 1) {
+		return
+	}
+	assert.Equal("search_path", result.Rows[0]["Setting"].ToString())
+	assert.Equal("public", result.Rows[0]["From"].ToString())
+	assert.Equal("sales", result.Rows[0]["To"].ToString())
+}
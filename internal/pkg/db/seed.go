@@ -0,0 +1,376 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// Upper bound on rows generated by a single \seed, to avoid an accidental extra zero filling the disk
+const maxSeedRows = 1_000_000
+
+var (
+	seedFirstNames = []string{"James", "Mary", "Robert", "Patricia", "John", "Jennifer", "Michael", "Linda", "David", "Elizabeth", "Aisha", "Wei", "Carlos", "Priya", "Yuki", "Olga"}
+	seedLastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez", "Kim", "Nguyen", "Patel", "Ivanova", "Sato", "Okafor"}
+	seedCities     = []string{"London", "Paris", "Tokyo", "New York", "Lagos", "Mumbai", "São Paulo", "Sydney", "Toronto", "Berlin", "Seoul", "Mexico City"}
+	seedCountries  = []string{"United Kingdom", "France", "Japan", "United States", "Nigeria", "India", "Brazil", "Australia", "Canada", "Germany", "South Korea", "Mexico"}
+	seedWords      = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna", "aliqua"}
+)
+
+// Type name and the arguments in parentheses, i.e varchar(32) or numeric(10,2)
+var columnTypeRegExp = regexp.MustCompile(`^([^(]+)(?:\((.*)\))?`)
+
+// \seed <table> <rows>
+// Fill a table with plausible fake data, for populating development databases
+// Columns with defaults or generated values are left for the database to fill in
+func (db *DBClient) seedCommand(argument string) (*QueryResult, error) {
+	usageError := errors.New(`Usage: \seed <table> <rows>`)
+
+	arguments := strings.Fields(strings.TrimSuffix(argument, ";"))
+	if len(arguments) != 2 {
+		return nil, usageError
+	}
+
+	tableName := arguments[0]
+	rowCount, err := strconv.Atoi(arguments[1])
+	if err != nil || rowCount < 1 {
+		return nil, usageError
+	}
+	if rowCount > maxSeedRows {
+		return nil, fmt.Errorf("Can't seed more than %d rows at once", maxSeedRows)
+	}
+
+	table, err := db.GetTableSchema(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var columns []ColumnSchema
+	var columnNames []string
+	for _, column := range table.Columns {
+		if column.Generated || column.Default != nil {
+			continue
+		}
+
+		columns = append(columns, column)
+		columnNames = append(columnNames, column.Name)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("Every column of %s has a default, nothing to seed", tableName)
+	}
+
+	// Unique integer columns count up from the current maximum instead of being random
+	sequenceStarts := make(map[string]int64)
+	for _, column := range columns {
+		if isSeedSequenceColumn(column, table.Indexes) {
+			sequenceStarts[column.Name], err = db.getSeedSequenceStart(tableName, column.Name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	random := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), rand.Uint64()))
+
+	rows := make([][]interface{}, rowCount)
+	for rowIdx := range rows {
+		row := make([]interface{}, len(columns))
+		for columnIdx, column := range columns {
+			sequenceStart, isSequence := sequenceStarts[column.Name]
+
+			var value interface{}
+			if isSequence {
+				value = fmt.Sprint(sequenceStart + int64(rowIdx))
+			} else {
+				value, err = generateSeedValue(db.connManager.GetFlavor(), column, isUniqueColumn(column, table.Indexes), rowIdx, random)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			row[columnIdx] = value
+		}
+
+		rows[rowIdx] = row
+	}
+
+	insertedRows, err := db.BulkInsert(tableName, columnNames, rows)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Seeding failed after inserting %d rows", insertedRows),
+			err,
+		)
+	}
+
+	return newTextResult(
+		[]string{"Seed"},
+		[][]string{{fmt.Sprintf("Inserted %d rows into %s", insertedRows, tableName)}},
+	), nil
+}
+
+func isUniqueColumn(column ColumnSchema, indexes []IndexSchema) bool {
+	for _, index := range indexes {
+		if index.Unique && len(index.Columns) == 1 && index.Columns[0] == column.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSeedSequenceColumn(column ColumnSchema, indexes []IndexSchema) bool {
+	typeName, _ := parseColumnType(column.Type)
+	return strings.Contains(typeName, "int") && !strings.Contains(typeName, "interval") && isUniqueColumn(column, indexes)
+}
+
+func (db *DBClient) getSeedSequenceStart(tableName string, columnName string) (int64, error) {
+	connection, err := db.connManager.GetConnection()
+	if err != nil {
+		return 0, err
+	}
+
+	var maxValue string
+	err = connection.GetContext(
+		db.ctx,
+		&maxValue,
+		fmt.Sprintf(
+			"SELECT %s FROM %s",
+			db.castToText(fmt.Sprintf("COALESCE(MAX(%s), 0)", db.quoteIdentifier(columnName))),
+			db.quoteIdentifier(tableName),
+		),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	maxValueInt, err := strconv.ParseInt(maxValue, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return maxValueInt + 1, nil
+}
+
+// Lowercase type name and its arguments, i.e "varchar", ["32"]
+func parseColumnType(columnType string) (typeName string, typeArgs []string) {
+	matches := columnTypeRegExp.FindStringSubmatch(strings.ToLower(strings.TrimSpace(columnType)))
+	if matches == nil {
+		return strings.ToLower(columnType), nil
+	}
+
+	typeName = strings.TrimSpace(matches[1])
+	if matches[2] != "" {
+		for _, typeArg := range strings.Split(matches[2], ",") {
+			typeArgs = append(typeArgs, strings.TrimSpace(typeArg))
+		}
+	}
+
+	return typeName, typeArgs
+}
+
+// A plausible value for a column, based on its type and name
+// Values are strings for the database to convert, or nil for NULL
+func generateSeedValue(flavor conn.DBFlavor, column ColumnSchema, unique bool, rowIdx int, random *rand.Rand) (interface{}, error) {
+	typeName, typeArgs := parseColumnType(column.Type)
+
+	switch {
+	case typeName == "enum" || typeName == "set":
+		{
+			if len(typeArgs) == 0 {
+				break
+			}
+
+			return strings.Trim(typeArgs[random.IntN(len(typeArgs))], "'"), nil
+		}
+	case typeName == "bool" || typeName == "boolean" || (typeName == "tinyint" && len(typeArgs) == 1 && typeArgs[0] == "1"):
+		{
+			isTrue := random.IntN(2) == 1
+			if flavor == conn.MySQL {
+				if isTrue {
+					return "1", nil
+				}
+				return "0", nil
+			}
+
+			return strconv.FormatBool(isTrue), nil
+		}
+	case strings.Contains(typeName, "point") || strings.Contains(typeName, "geometry") || strings.Contains(typeName, "polygon"):
+		{
+			// Spatial types need constructing with functions, rather than from a string
+			break
+		}
+	case strings.Contains(typeName, "interval"):
+		{
+			return fmt.Sprintf("%d days", random.IntN(365)), nil
+		}
+	case strings.Contains(typeName, "int") || strings.Contains(typeName, "serial"):
+		{
+			maxValue := 1_000_000
+			switch {
+			case strings.HasPrefix(typeName, "tinyint"):
+				{
+					maxValue = 127
+				}
+			case strings.HasPrefix(typeName, "smallint"):
+				{
+					maxValue = 32767
+				}
+			}
+
+			return fmt.Sprint(random.IntN(maxValue)), nil
+		}
+	case strings.Contains(typeName, "numeric") || strings.Contains(typeName, "decimal"):
+		{
+			precision, scale := 10, 2
+			if len(typeArgs) > 0 {
+				precision, _ = strconv.Atoi(typeArgs[0])
+			}
+			if len(typeArgs) > 1 {
+				scale, _ = strconv.Atoi(typeArgs[1])
+			}
+
+			maxValue := math.Min(math.Pow10(precision-scale)-1, 100_000)
+			return strconv.FormatFloat(random.Float64()*maxValue, 'f', scale, 64), nil
+		}
+	case strings.Contains(typeName, "float") || strings.Contains(typeName, "double") || strings.Contains(typeName, "real"):
+		{
+			return strconv.FormatFloat(random.Float64()*10_000, 'f', 2, 64), nil
+		}
+	case strings.HasPrefix(typeName, "timestamp") || strings.HasPrefix(typeName, "datetime"):
+		{
+			return generateSeedTime(random).Format(time.DateTime), nil
+		}
+	case strings.HasPrefix(typeName, "date"):
+		{
+			return generateSeedTime(random).Format(time.DateOnly), nil
+		}
+	case strings.HasPrefix(typeName, "time"):
+		{
+			return generateSeedTime(random).Format(time.TimeOnly), nil
+		}
+	case typeName == "year":
+		{
+			return fmt.Sprint(generateSeedTime(random).Year()), nil
+		}
+	case typeName == "uuid":
+		{
+			uuid := make([]byte, 16)
+			for idx := range uuid {
+				uuid[idx] = byte(random.IntN(256))
+			}
+			// Version 4, variant 1
+			uuid[6] = (uuid[6] & 0x0f) | 0x40
+			uuid[8] = (uuid[8] & 0x3f) | 0x80
+
+			return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:]), nil
+		}
+	case strings.HasPrefix(typeName, "json"):
+		{
+			return fmt.Sprintf(`{"seed": %d, "word": %q}`, rowIdx, pickSeedValue(seedWords, random)), nil
+		}
+	case strings.Contains(typeName, "char") || strings.Contains(typeName, "text") || strings.Contains(typeName, "blob") || typeName == "bytea":
+		{
+			maxLength := 0
+			if len(typeArgs) > 0 {
+				maxLength, _ = strconv.Atoi(typeArgs[0])
+			}
+
+			return generateSeedText(column.Name, unique, rowIdx, maxLength, random), nil
+		}
+	}
+
+	if column.Nullable {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("Don't know how to generate values for %s, a %s column", column.Name, column.Type)
+}
+
+// Sometime in the last two years
+func generateSeedTime(random *rand.Rand) time.Time {
+	const twoYears = 2 * 365 * 24 * time.Hour
+	return time.Now().UTC().Add(-time.Duration(random.Int64N(int64(twoYears)))).Truncate(time.Second)
+}
+
+func pickSeedValue(values []string, random *rand.Rand) string {
+	return values[random.IntN(len(values))]
+}
+
+// Text resembling what the column name suggests it holds, such as an email or a city
+func generateSeedText(columnName string, unique bool, rowIdx int, maxLength int, random *rand.Rand) string {
+	name := strings.ToLower(columnName)
+	firstName, lastName := pickSeedValue(seedFirstNames, random), pickSeedValue(seedLastNames, random)
+
+	var text string
+	switch {
+	case strings.Contains(name, "email"):
+		{
+			// Always numbered, as emails are frequently unique
+			text = fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(firstName), strings.ToLower(lastName), rowIdx+random.IntN(1_000_000)*1_000_000)
+			unique = false
+		}
+	case strings.Contains(name, "first") && strings.Contains(name, "name"):
+		{
+			text = firstName
+		}
+	case (strings.Contains(name, "last") && strings.Contains(name, "name")) || strings.Contains(name, "surname"):
+		{
+			text = lastName
+		}
+	case strings.Contains(name, "username") || strings.Contains(name, "login"):
+		{
+			text = strings.ToLower(fmt.Sprint(firstName, lastName[:1]))
+		}
+	case strings.Contains(name, "name"):
+		{
+			text = fmt.Sprint(firstName, " ", lastName)
+		}
+	case strings.Contains(name, "city"):
+		{
+			text = pickSeedValue(seedCities, random)
+		}
+	case strings.Contains(name, "country"):
+		{
+			text = pickSeedValue(seedCountries, random)
+		}
+	case strings.Contains(name, "phone"):
+		{
+			text = fmt.Sprintf("+1-555-%03d-%04d", random.IntN(1000), random.IntN(10_000))
+		}
+	case strings.Contains(name, "url") || strings.Contains(name, "website"):
+		{
+			text = fmt.Sprintf("https://example.com/%s", pickSeedValue(seedWords, random))
+		}
+	default:
+		{
+			words := make([]string, 3+random.IntN(6))
+			for idx := range words {
+				words[idx] = pickSeedValue(seedWords, random)
+			}
+			text = strings.Join(words, " ")
+		}
+	}
+
+	suffix := ""
+	if unique {
+		suffix = fmt.Sprintf("-%d-%d", rowIdx, random.IntN(1_000_000))
+	}
+
+	if maxLength > 0 {
+		runes := []rune(text)
+		if len(runes)+len(suffix) > maxLength {
+			runes = runes[:max(0, maxLength-len(suffix))]
+		}
+		text = string(runes)
+	}
+
+	return text + suffix
+}
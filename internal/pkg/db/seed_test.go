@@ -0,0 +1,91 @@
+package db
+
+import (
+	"math/rand/v2"
+	"regexp"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColumnType(t *testing.T) {
+	var tests = []struct {
+		ColumnType       string
+		ExpectedTypeName string
+		ExpectedTypeArgs []string
+	}{
+		{"integer", "integer", nil},
+		{"character varying(32)", "character varying", []string{"32"}},
+		{"NUMERIC(10, 2)", "numeric", []string{"10", "2"}},
+		{"int unsigned", "int unsigned", nil},
+		{"enum('a','b')", "enum", []string{"'a'", "'b'"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.ColumnType, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			typeName, typeArgs := parseColumnType(test.ColumnType)
+			assert.Equal(test.ExpectedTypeName, typeName)
+			assert.Equal(test.ExpectedTypeArgs, typeArgs)
+		})
+	}
+}
+
+func TestGenerateSeedValue(t *testing.T) {
+	var tests = []struct {
+		Flavor        conn.DBFlavor
+		Column        ColumnSchema
+		ExpectedValue *regexp.Regexp
+	}{
+		{conn.PostgreSQL, ColumnSchema{Name: "id", Type: "integer"}, regexp.MustCompile(`^\d+$`)},
+		{conn.MySQL, ColumnSchema{Name: "age", Type: "tinyint unsigned"}, regexp.MustCompile(`^\d{1,3}$`)},
+		{conn.PostgreSQL, ColumnSchema{Name: "active", Type: "boolean"}, regexp.MustCompile(`^(true|false)$`)},
+		{conn.MySQL, ColumnSchema{Name: "active", Type: "tinyint(1)"}, regexp.MustCompile(`^(0|1)$`)},
+		{conn.PostgreSQL, ColumnSchema{Name: "price", Type: "numeric(5,2)"}, regexp.MustCompile(`^\d{1,3}\.\d{2}$`)},
+		{conn.PostgreSQL, ColumnSchema{Name: "created_at", Type: "timestamp without time zone"}, regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`)},
+		{conn.MySQL, ColumnSchema{Name: "born_on", Type: "date"}, regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)},
+		{conn.PostgreSQL, ColumnSchema{Name: "external_id", Type: "uuid"}, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{conn.PostgreSQL, ColumnSchema{Name: "contact_email", Type: "character varying(255)"}, regexp.MustCompile(`^[a-z]+\.[a-z]+\d+@example\.com$`)},
+		{conn.MySQL, ColumnSchema{Name: "status", Type: "enum('new','done')"}, regexp.MustCompile(`^(new|done)$`)},
+	}
+
+	random := rand.New(rand.NewPCG(1, 2))
+
+	for _, test := range tests {
+		t.Run(test.Column.Type, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			for rowIdx := 0; rowIdx < 20; rowIdx++ {
+				value, err := generateSeedValue(test.Flavor, test.Column, false, rowIdx, random)
+				assert.NoError(err)
+				assert.Regexp(test.ExpectedValue, value)
+			}
+		})
+	}
+
+	t.Run("Unknown Types", func(t *testing.T) {
+		assert := assert.New(t)
+
+		value, err := generateSeedValue(conn.MySQL, ColumnSchema{Name: "location", Type: "point", Nullable: true}, false, 0, random)
+		assert.NoError(err)
+		assert.Nil(value)
+
+		_, err = generateSeedValue(conn.MySQL, ColumnSchema{Name: "location", Type: "point"}, false, 0, random)
+		assert.Error(err)
+	})
+}
+
+func TestGenerateSeedTextMaxLength(t *testing.T) {
+	assert := assert.New(t)
+	random := rand.New(rand.NewPCG(1, 2))
+
+	for rowIdx := 0; rowIdx < 100; rowIdx++ {
+		text := generateSeedText("description", true, rowIdx, 12, random)
+		assert.LessOrEqual(utf8.RuneCountInString(text), 12, text)
+	}
+}
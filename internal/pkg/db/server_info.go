@@ -0,0 +1,113 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// What the connected server is and how it's set up, shown when starting
+type ServerInfo struct {
+	// Product and version, ex: PostgreSQL 16.2
+	Version string
+	// Character set of the current database, ex: utf8mb4
+	CharacterSet string
+	TimeZone     string
+	// Writes would be refused, such as on a replica
+	ReadOnly bool
+	// Zero when it can't be read
+	Uptime time.Duration
+	// Sessions connected to the server, including this one. -1 when it can't be read
+	Connections int
+}
+
+type serverInfoRow struct {
+	Version       string `db:"version"`
+	CharacterSet  string `db:"character_set"`
+	TimeZone      string `db:"time_zone"`
+	ReadOnly      bool   `db:"read_only"`
+	UptimeSeconds int64  `db:"uptime_seconds"`
+	Connections   int    `db:"connections"`
+}
+
+const postgresServerInfoQuery string = `
+SELECT
+  current_setting('server_version') AS version,
+  pg_encoding_to_char(d.encoding) AS character_set,
+  current_setting('TimeZone') AS time_zone,
+  pg_is_in_recovery() OR current_setting('default_transaction_read_only') = 'on' AS read_only,
+  EXTRACT(EPOCH FROM now() - pg_postmaster_start_time())::bigint AS uptime_seconds,
+  (SELECT count(*) FROM pg_stat_activity WHERE backend_type = 'client backend') AS connections
+FROM pg_database d
+WHERE d.datname = current_database()
+`
+
+const mySQLServerInfoQuery string = `
+SELECT
+  VERSION() AS version,
+  @@character_set_database AS character_set,
+  IF(@@session.time_zone = 'SYSTEM', @@system_time_zone, @@session.time_zone) AS time_zone,
+  @@global.read_only AS read_only
+`
+
+// Uptime and sessions connected, which MySQL only reports as status variables
+const mySQLServerStatusQuery string = "SHOW GLOBAL STATUS WHERE Variable_name IN ('Uptime', 'Threads_connected')"
+
+// Read what the connected server is and how it's set up
+func (db *DBClient) GetServerInfo() (*ServerInfo, error) {
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	flavor := db.connManager.GetFlavor()
+
+	var row serverInfoRow
+	if flavor == conn.PostgreSQL {
+		err = connection.GetContext(db.ctx, &row, postgresServerInfoQuery)
+	} else {
+		row.Connections = -1
+		err = connection.GetContext(db.ctx, &row, mySQLServerInfoQuery)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Status variables may be hidden without the privileges to read them, the rest is still worth showing
+	if flavor == conn.MySQL {
+		var statusRows []struct {
+			Name  string `db:"Variable_name"`
+			Value string `db:"Value"`
+		}
+		if err := connection.SelectContext(db.ctx, &statusRows, mySQLServerStatusQuery); err == nil {
+			for _, statusRow := range statusRows {
+				value, err := strconv.ParseInt(statusRow.Value, 10, 64)
+				if err != nil {
+					continue
+				}
+
+				switch statusRow.Name {
+				case "Uptime":
+					{
+						row.UptimeSeconds = value
+					}
+				case "Threads_connected":
+					{
+						row.Connections = int(value)
+					}
+				}
+			}
+		}
+	}
+
+	return &ServerInfo{
+		Version:      fmt.Sprint(flavor.Name(), " ", row.Version),
+		CharacterSet: row.CharacterSet,
+		TimeZone:     row.TimeZone,
+		ReadOnly:     row.ReadOnly,
+		Uptime:       time.Duration(row.UptimeSeconds) * time.Second,
+		Connections:  row.Connections,
+	}, nil
+}
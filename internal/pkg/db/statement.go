@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/jmoiron/sqlx"
 )
 
 type StatementWithParams struct {
@@ -25,6 +28,10 @@ func (db *DBClient) transformStatement(statement string) (
 		return db.buildDescribeQuery(tableName, statement)
 	}
 
+	if tableName, pattern, isShowColumns := statementIsShowColumns(statement); isShowColumns {
+		return db.buildShowColumnsQuery(tableName, pattern, statement)
+	}
+
 	if tableName, isShowIndexes := statementIsShowIndexes(statement); isShowIndexes {
 		return db.buildShowIndexesQuery(tableName, statement)
 	}
@@ -33,9 +40,47 @@ func (db *DBClient) transformStatement(statement string) (
 		return db.buildShowTablesQuery(statement)
 	}
 
+	if tableName, isShowCreateTable := statementIsShowCreateTable(statement); isShowCreateTable {
+		return db.buildShowCreateTableQuery(tableName, statement)
+	}
+
+	if statementIsShowDatabases(statement) {
+		return db.buildShowDatabasesQuery(statement)
+	}
+
 	return &StatementWithParams{statement, nil}, nil
 }
 
+const rawPrefix = `\raw`
+
+// Statements prefixed with \nocache always run against the database, refreshing any cached result
+const nocachePrefix = `\nocache`
+
+// Statements prefixed with \raw are sent verbatim, without being transformed
+func stripRawPrefix(statement string) (rawStatement string, isRaw bool) {
+	return stripStatementPrefix(statement, rawPrefix)
+}
+
+func stripNocachePrefix(statement string) (uncachedStatement string, isNocache bool) {
+	return stripStatementPrefix(statement, nocachePrefix)
+}
+
+func stripStatementPrefix(statement string, prefix string) (strippedStatement string, hasPrefix bool) {
+	trimmedStatement := strings.TrimSpace(statement)
+	if !strings.HasPrefix(trimmedStatement, prefix) {
+		return statement, false
+	}
+
+	strippedStatement = strings.TrimPrefix(trimmedStatement, prefix)
+
+	// Avoid matching something like \rawfoo
+	if strippedStatement != "" && !unicode.IsSpace(rune(strippedStatement[0])) {
+		return statement, false
+	}
+
+	return strings.TrimSpace(strippedStatement), true
+}
+
 var describeRegExp = regexp.MustCompile(`(?i)^DESCRIBE "?(\w+)"?;?$`)
 
 func statementIsDescribe(statement string) (tableName string, isDescribe bool) {
@@ -48,6 +93,27 @@ func statementIsDescribe(statement string) (tableName string, isDescribe bool) {
 	return tableName, true
 }
 
+var showColumnsRegExp = regexp.MustCompile(`(?i)^SHOW (?:COLUMNS|FIELDS) FROM "?(\w+)"?(?:\s+LIKE\s+'((?:[^']|'')*)')?;?$`)
+
+// SHOW COLUMNS FROM X [LIKE 'pattern']
+// Pattern is nil when not specified
+func statementIsShowColumns(statement string) (tableName string, pattern *string, isShowColumns bool) {
+	matches := showColumnsRegExp.FindStringSubmatch(strings.TrimSpace(statement))
+	if len(matches) != 3 {
+		return "", nil, false
+	}
+
+	tableName = matches[1]
+
+	// Distinguish between no LIKE clause, and LIKE ''
+	if strings.Contains(strings.ToUpper(matches[0]), "LIKE") {
+		unescapedPattern := strings.ReplaceAll(matches[2], "''", "'")
+		pattern = &unescapedPattern
+	}
+
+	return tableName, pattern, true
+}
+
 func statementIsShowTables(statement string) bool {
 	normalizedStatement := strings.ReplaceAll(
 		strings.ToUpper(strings.TrimSpace(statement)),
@@ -58,6 +124,28 @@ func statementIsShowTables(statement string) bool {
 	return normalizedStatement == "SHOW TABLES"
 }
 
+var showCreateTableRegExp = regexp.MustCompile(`(?i)^SHOW CREATE TABLE "?(\w+)"?;?$`)
+
+func statementIsShowCreateTable(statement string) (tableName string, isShowCreateTable bool) {
+	matches := showCreateTableRegExp.FindStringSubmatch(strings.TrimSpace(statement))
+	if len(matches) != 2 {
+		return "", false
+	}
+
+	tableName = matches[1]
+	return tableName, true
+}
+
+func statementIsShowDatabases(statement string) bool {
+	normalizedStatement := strings.ReplaceAll(
+		strings.ToUpper(strings.TrimSpace(statement)),
+		";",
+		"",
+	)
+
+	return normalizedStatement == "SHOW DATABASES"
+}
+
 var showIndexesRegExp = regexp.MustCompile(`(?i)^SHOW INDEXES FROM "?(\w+)"?;?$`)
 
 func statementIsShowIndexes(statement string) (tableName string, isShowIndexes bool) {
@@ -70,6 +158,15 @@ func statementIsShowIndexes(statement string) (tableName string, isShowIndexes b
 	return tableName, true
 }
 
+// How the parameter at idx is referred to in a statement for the current flavor
+func (db *DBClient) getParamPlaceholder(idx int) string {
+	if db.connManager.GetFlavor() == conn.PostgreSQL {
+		return fmt.Sprint("$", idx+1)
+	}
+
+	return "?"
+}
+
 func commandNotSupportedError(command string, flavor conn.DBFlavor) error {
 	return fmt.Errorf("%s not supported for %s", command, flavor)
 }
@@ -91,6 +188,45 @@ func (db *DBClient) buildShowTablesQuery(originalStatement string) (showTablesQu
 	}
 }
 
+func (db *DBClient) buildShowCreateTableQuery(tableName string, originalStatement string) (showCreateTableQuery *StatementWithParams, err error) {
+	switch db.connManager.GetFlavor() {
+	case conn.MySQL:
+		{
+			return &StatementWithParams{originalStatement, nil}, nil
+		}
+	case conn.PostgreSQL:
+		{
+			tableName, err := db.resolvePostgresTableName(tableName, originalStatement)
+			if err != nil {
+				return nil, err
+			}
+
+			return &StatementWithParams{postgresShowCreateTableQuery, []interface{}{tableName}}, nil
+		}
+	default:
+		{
+			return nil, commandNotSupportedError("SHOW CREATE TABLE", db.connManager.GetFlavor())
+		}
+	}
+}
+
+func (db *DBClient) buildShowDatabasesQuery(originalStatement string) (showDatabasesQuery *StatementWithParams, err error) {
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			return &StatementWithParams{postgresShowDatabasesQuery, nil}, nil
+		}
+	case conn.MySQL:
+		{
+			return &StatementWithParams{originalStatement, nil}, nil
+		}
+	default:
+		{
+			return nil, commandNotSupportedError("SHOW DATABASES", db.connManager.GetFlavor())
+		}
+	}
+}
+
 func (db *DBClient) buildShowIndexesQuery(tableName string, originalStatement string) (showIndexesQuery *StatementWithParams, err error) {
 	switch db.connManager.GetFlavor() {
 	case conn.MySQL:
@@ -99,7 +235,7 @@ func (db *DBClient) buildShowIndexesQuery(tableName string, originalStatement st
 		}
 	case conn.PostgreSQL:
 		{
-			err := db.assertPostgresTableExists(tableName)
+			tableName, err := db.resolvePostgresTableName(tableName, originalStatement)
 			if err != nil {
 				return nil, err
 			}
@@ -121,7 +257,7 @@ func (db *DBClient) buildDescribeQuery(tableName string, originalStatement strin
 		}
 	case conn.PostgreSQL:
 		{
-			err := db.assertPostgresTableExists(tableName)
+			tableName, err := db.resolvePostgresTableName(tableName, originalStatement)
 			if err != nil {
 				return nil, err
 			}
@@ -135,25 +271,94 @@ func (db *DBClient) buildDescribeQuery(tableName string, originalStatement strin
 	}
 }
 
+func (db *DBClient) buildShowColumnsQuery(tableName string, pattern *string, originalStatement string) (showColumnsQuery *StatementWithParams, err error) {
+	switch db.connManager.GetFlavor() {
+	case conn.MySQL:
+		{
+			return &StatementWithParams{originalStatement, nil}, nil
+		}
+	case conn.PostgreSQL:
+		{
+			tableName, err := db.resolvePostgresTableName(tableName, originalStatement)
+			if err != nil {
+				return nil, err
+			}
+
+			if pattern == nil {
+				return &StatementWithParams{postgresDescribeQuery, []interface{}{tableName}}, nil
+			}
+
+			return &StatementWithParams{postgresShowColumnsLikeQuery, []interface{}{tableName, *pattern}}, nil
+		}
+	default:
+		{
+			return nil, commandNotSupportedError("SHOW COLUMNS", db.connManager.GetFlavor())
+		}
+	}
+}
+
+// Any table or view reachable by unqualified name through the search_path
 const postgresTableExistQuery string = `
+   SELECT EXISTS (
+       SELECT 1
+       FROM   pg_class
+       WHERE  relname = $1
+       AND    relkind IN ('r', 'p', 'v', 'm', 'f')
+       AND    pg_table_is_visible(oid)
+   );`
+
+const mySQLTableExistQuery string = `
    SELECT EXISTS (
        SELECT 1
        FROM   information_schema.tables
-       WHERE  table_schema = current_schema()
-       AND    table_name = $1
+       WHERE  table_schema = DATABASE()
+       AND    table_name = ?
    );`
 
+// Check a table or view exists in the current schema, for commands which would otherwise silently return nothing
+func (db *DBClient) assertTableExists(tableName string) (err error) {
+	switch db.connManager.GetFlavor() {
+	case conn.MySQL:
+		{
+			return db.assertTableExistsWithQuery(tableName, mySQLTableExistQuery)
+		}
+	default:
+		{
+			return db.assertPostgresTableExists(tableName)
+		}
+	}
+}
+
+// Postgres folds unquoted names to lowercase, so DESCRIBE Users describes users, as SELECT * FROM Users would read it
+// Tables created with a quoted mixed case name are still found when no lowercase table exists, and quoted names are exact
+func (db *DBClient) resolvePostgresTableName(tableName string, originalStatement string) (string, error) {
+	isQuoted := strings.Contains(originalStatement, fmt.Sprint(`"`, tableName, `"`))
+	foldedName := strings.ToLower(tableName)
+
+	if !isQuoted && foldedName != tableName {
+		if err := db.assertPostgresTableExists(foldedName); err == nil {
+			return foldedName, nil
+		}
+	}
+
+	return tableName, db.assertPostgresTableExists(tableName)
+}
+
 func (db *DBClient) assertPostgresTableExists(tableName string) (err error) {
-	conn, err := db.connManager.GetConnection()
-	if err != nil {
+	return db.assertTableExistsWithQuery(tableName, postgresTableExistQuery)
+}
+
+func (db *DBClient) assertTableExistsWithQuery(tableName string, tableExistQuery string) (err error) {
+	var exists bool
+	err = db.runMetadataQuery(func(connection *sqlx.Conn) error {
+		return connection.GetContext(db.ctx, &exists, tableExistQuery, tableName)
+	})
+	if errors.Is(err, conn.ErrConnectionFailed) {
 		return errors.Join(
 			errors.New("Failed to get connection"),
 			err,
 		)
 	}
-
-	var exists bool
-	err = conn.GetContext(db.ctx, &exists, postgresTableExistQuery, tableName)
 	if err != nil && err != sql.ErrNoRows {
 		return errors.Join(
 			errors.New("Unable to validate that the table exists"),
@@ -168,88 +373,202 @@ func (db *DBClient) assertPostgresTableExists(tableName string) (err error) {
 	return nil
 }
 
+// Tables reachable by unqualified name through the search_path, as MySQL lists those of the current database
+// Where several schemas have a table of the same name, only the one found first is visible
 const postgresShowTablesQuery string = `
-SELECT table_name
-FROM information_schema.tables
-WHERE table_schema = current_schema()
-ORDER BY table_name ASC
+SELECT c.relname AS table_name
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind IN ('r', 'p', 'v', 'm', 'f')
+AND pg_table_is_visible(c.oid)
+AND n.nspname NOT IN ('pg_catalog', 'information_schema')
+ORDER BY c.relname ASC
 `
 
+// Mirrors MySQL's SHOW CREATE TABLE, putting the definition back together from pg_catalog
+// Columns come first, then constraints, then any index not backing a constraint as its own CREATE INDEX
+const postgresShowCreateTableQuery string = `
+SELECT
+  c.relname AS "Table",
+  'CREATE TABLE ' || quote_ident(c.relname) || E' (\n  ' ||
+  array_to_string(
+    ARRAY(
+      SELECT
+        quote_ident(a.attname) || ' ' || format_type(a.atttypid, a.atttypmod) ||
+        CASE
+          WHEN a.attidentity = 'a' THEN ' GENERATED ALWAYS AS IDENTITY'
+          WHEN a.attidentity = 'd' THEN ' GENERATED BY DEFAULT AS IDENTITY'
+          WHEN a.attgenerated = 's' THEN ' GENERATED ALWAYS AS (' || pg_get_expr(d.adbin, d.adrelid) || ') STORED'
+          WHEN d.adbin IS NOT NULL THEN ' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid)
+          ELSE ''
+        END ||
+        CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END
+      FROM pg_attribute a
+      LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+      WHERE a.attrelid = c.oid
+      AND a.attnum > 0
+      AND NOT a.attisdropped
+      ORDER BY a.attnum
+    ) ||
+    ARRAY(
+      SELECT 'CONSTRAINT ' || quote_ident(con.conname) || ' ' || pg_get_constraintdef(con.oid)
+      FROM pg_constraint con
+      WHERE con.conrelid = c.oid
+      AND con.contype IN ('p', 'u', 'c', 'f', 'x')
+      ORDER BY con.contype = 'p' DESC, con.conname
+    ),
+    E',\n  '
+  ) ||
+  E'\n)' ||
+  CASE WHEN c.relkind = 'p' THEN ' PARTITION BY ' || pg_get_partkeydef(c.oid) ELSE '' END ||
+  ';' ||
+  COALESCE(
+    (
+      SELECT E'\n' || string_agg(pg_get_indexdef(i.indexrelid) || ';', E'\n' ORDER BY i.indexrelid)
+      FROM pg_index i
+      WHERE i.indrelid = c.oid
+      -- Indexes backing a constraint are created along with it
+      AND NOT EXISTS (
+        SELECT 1 FROM pg_constraint con
+        WHERE con.conindid = i.indexrelid
+        AND con.contype IN ('p', 'u', 'x')
+      )
+    ),
+    ''
+  ) AS "Create Table"
+FROM pg_class c
+WHERE c.relname = $1
+AND c.relkind IN ('r', 'p')
+AND pg_table_is_visible(c.oid)
+`
+
+// Databases which can be connected to with USE, named as in MySQL's SHOW DATABASES
+const postgresShowDatabasesQuery string = `
+SELECT datname AS "Database"
+FROM pg_database
+WHERE NOT datistemplate
+AND datallowconn
+ORDER BY datname ASC
+`
+
+// Mirrors the shape of MySQL's SHOW INDEXES, one row per indexed column
+// Expression holds the expression for columns of an expression index, and Predicate the WHERE clause of a partial index
 const postgresShowIndexesQuery string = `
-SELECT indexname, indexdef
-FROM pg_indexes
-WHERE tablename = $1
-ORDER BY indexname ASC
+SELECT
+  t.relname AS "Table",
+  CASE WHEN ix.indisunique THEN 0 ELSE 1 END AS "Non_unique",
+  i.relname AS "Key_name",
+  k.ordinality AS "Seq_in_index",
+  a.attname AS "Column_name",
+  CASE
+    WHEN (ix.indoption[(k.ordinality - 1)::int]::int & 1) = 1 THEN 'D'
+    ELSE 'A'
+  END AS "Collation",
+  CASE
+    WHEN a.attname IS NULL OR a.attnotnull THEN ''
+    ELSE 'YES'
+  END AS "Null",
+  upper(am.amname) AS "Index_type",
+  CASE
+    WHEN k.attnum = 0 THEN pg_get_indexdef(ix.indexrelid, k.ordinality::int, true)
+  END AS "Expression",
+  pg_get_expr(ix.indpred, ix.indrelid) AS "Predicate",
+  pg_get_indexdef(ix.indexrelid) AS "Definition"
+FROM pg_index ix
+JOIN pg_class t ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_am am ON am.oid = i.relam
+CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ordinality)
+LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum AND k.attnum <> 0
+WHERE t.relname = $1
+AND pg_table_is_visible(t.oid)
+-- Skip INCLUDE columns, which are not part of the key
+AND k.ordinality <= ix.indnkeyatts
+ORDER BY i.relname ASC, k.ordinality ASC
 `
 
+// Mirrors MySQL's DESCRIBE output, built from pg_catalog so each column appears exactly once
+// Extra notes serial/identity columns as auto_increment, and the expression of generated columns
+// When a column participates in several indexes, Key takes the highest priority of PRI > UNI > MUL
+//   - PRI: part of the primary key
+//   - UNI: the only key column of a unique, non-partial index
+//   - MUL: first column of any other index, where a given value may occur multiple times
 const postgresDescribeQuery string = `
-WITH columns AS (
-  SELECT
-    c.column_name AS "Field",
-    -- Include character length and numeric precision/scale for relevant data types
-    CASE
-        WHEN c.data_type = 'character' AND c.character_maximum_length IS NOT NULL THEN c.data_type || '(' || c.character_maximum_length || ')'
-        WHEN c.data_type = 'character varying' AND c.character_maximum_length IS NOT NULL THEN c.data_type || '(' || c.character_maximum_length || ')'
-        WHEN c.data_type = 'character' THEN c.data_type
-        WHEN c.data_type = 'character varying' THEN c.data_type
-        WHEN c.data_type = 'numeric' THEN c.data_type || '(' || c.numeric_precision || ', ' || c.numeric_scale || ')'
-        ELSE c.data_type
-    END AS "Type",
-    CASE
-        WHEN c.is_nullable = 'YES' THEN 'YES'
-        ELSE 'NO'
-    END AS "Null",
-    CASE
-        WHEN kcu.column_name IS NOT NULL AND tc.constraint_type = 'PRIMARY KEY' THEN 'PRI'
-        WHEN kcu.column_name IS NOT NULL AND tc.constraint_type = 'UNIQUE' THEN 'UNI'
-        WHEN i.indexname IS NOT NULL AND i.indisunique THEN 'UNI'
-        WHEN i.indexname IS NOT NULL THEN 'MUL'
-        ELSE ''
-    END AS "Key",
-    COALESCE(c.column_default, 'NULL') AS "Default"
-  FROM
-    information_schema.columns c
-  LEFT JOIN
-    information_schema.key_column_usage kcu
-    ON c.table_name = kcu.table_name AND c.column_name = kcu.column_name
-  LEFT JOIN
-    information_schema.table_constraints tc
-    ON kcu.table_name = tc.table_name AND kcu.constraint_name = tc.constraint_name
-  LEFT JOIN
-    (
-        SELECT
-            ic.relname as indexname,
-            a.attname as column_name,
-            i.indrelid::regclass::text as table_name,
-            a.attnum,
-            i.indkey as indkey,
-            i.indkey[0] as first_column,
-            i.indisunique
-        FROM
-            pg_class t,
-            pg_class ic,
-            pg_index i,
-            pg_attribute a
-        WHERE
-            t.oid = i.indrelid
-            AND ic.oid = i.indexrelid
-            AND a.attrelid = t.oid
-            AND a.attnum = ANY(i.indkey)
-            AND t.relkind = 'r'
-            AND ic.relkind = 'i'
-            AND i.indisprimary = false
-    ) i
-    ON c.table_name = i.table_name AND c.column_name = i.column_name
-    AND (i.column_name = c.column_name AND (i.attnum = i.first_column OR array_length(i.indkey, 1) = 1))
-  WHERE
-    c.table_name = $1
-)
 SELECT
-  "Field",
-  "Type",
-  "Null",
-  "Key",
-  "Default"
-FROM
-  columns;
+  a.attname AS "Field",
+  format_type(a.atttypid, a.atttypmod) AS "Type",
+  CASE
+    WHEN a.attnotnull THEN 'NO'
+    ELSE 'YES'
+  END AS "Null",
+  CASE
+    WHEN EXISTS (
+      SELECT 1 FROM pg_index i
+      WHERE i.indrelid = a.attrelid
+      AND i.indisprimary
+      AND a.attnum = ANY(i.indkey::int2[])
+    ) THEN 'PRI'
+    WHEN EXISTS (
+      SELECT 1 FROM pg_index i
+      WHERE i.indrelid = a.attrelid
+      AND i.indisunique
+      AND i.indnkeyatts = 1
+      AND i.indpred IS NULL
+      AND i.indkey[0] = a.attnum
+    ) THEN 'UNI'
+    WHEN EXISTS (
+      SELECT 1 FROM pg_index i
+      WHERE i.indrelid = a.attrelid
+      AND i.indkey[0] = a.attnum
+    ) THEN 'MUL'
+    ELSE ''
+  END AS "Key",
+  CASE
+    -- Generated columns store their expression as the default, but can't actually be given a default
+    WHEN a.attgenerated <> '' THEN 'NULL'
+    ELSE COALESCE(pg_get_expr(d.adbin, d.adrelid), 'NULL')
+  END AS "Default",
+  CASE
+    WHEN a.attidentity = 'a' THEN 'auto_increment (GENERATED ALWAYS AS IDENTITY)'
+    WHEN a.attidentity = 'd' THEN 'auto_increment (GENERATED BY DEFAULT AS IDENTITY)'
+    WHEN a.attgenerated = 's' THEN 'STORED GENERATED (' || pg_get_expr(d.adbin, d.adrelid) || ')'
+    -- serial columns, defaulting to the next value of a sequence
+    WHEN pg_get_expr(d.adbin, d.adrelid) LIKE 'nextval(%' THEN 'auto_increment'
+    ELSE ''
+  END AS "Extra"
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE c.relname = $1
+AND pg_table_is_visible(c.oid)
+AND a.attnum > 0
+AND NOT a.attisdropped
+ORDER BY a.attnum;
 `
+
+// DESCRIBE, filtered to column names matching a LIKE pattern
+var postgresShowColumnsLikeQuery string = fmt.Sprintf(`
+SELECT *
+FROM (%s) AS describe_columns
+WHERE "Field" LIKE $2
+`, strings.TrimSuffix(strings.TrimSpace(postgresDescribeQuery), ";"))
+
+// Quote a table or column name for safe use in a generated statement, as configured with SetIdentifierQuoting
+func (db *DBClient) quoteIdentifier(identifier string) string {
+	return lexer.QuoteIdentifier(db.connManager.GetFlavor(), identifier, db.identifierQuoting)
+}
+
+// Quote a value as a string literal, NULL if it isn't valid
+// MySQL treats backslashes as escapes within literals by default, while Postgres does not
+func quoteLiteralForFlavor(flavor conn.DBFlavor, value *NullString) string {
+	if value == nil || !value.Valid {
+		return "NULL"
+	}
+
+	escapedValue := strings.ReplaceAll(value.String, "'", "''")
+	if flavor == conn.MySQL {
+		escapedValue = strings.ReplaceAll(escapedValue, `\`, `\\`)
+	}
+
+	return fmt.Sprint("'", escapedValue, "'")
+}
@@ -2,10 +2,13 @@ package db_test
 
 import (
 	"fmt"
+	"path/filepath"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/azvaliev/sql/internal/pkg/db"
 	"github.com/azvaliev/sql/internal/pkg/db/conn"
 	"github.com/stretchr/testify/assert"
 )
@@ -147,41 +150,100 @@ func TestDBShowIndexesPostgres(t *testing.T) {
 			showIndexesResult, err := dbClient.Query(showIndexesQuery)
 			assert.NoError(err, "expected to show indexes succesfully", showIndexesQuery)
 
-			assert.Len(showIndexesResult.Rows, 3, "show have 2 created indexes, 1 pkey", showIndexesResult)
+			assert.Equal(
+				[]string{"Table", "Non_unique", "Key_name", "Seq_in_index", "Column_name", "Collation", "Null", "Index_type", "Expression", "Predicate", "Definition"},
+				showIndexesResult.Columns,
+				"should have columns comparable to MySQL SHOW INDEXES",
+			)
+			assert.Len(showIndexesResult.Rows, 4, "show have 2 created indexes with 3 columns, 1 pkey", showIndexesResult)
 
-			expectedResults := []struct{ indexname, indexdef string }{
+			expectedResults := []struct {
+				keyName, nonUnique, seqInIndex, columnName, null, definition string
+			}{
+				{
+					keyName:    index2Name,
+					nonUnique:  "1",
+					seqInIndex: "1",
+					columnName: "d",
+					null:       "YES",
+					definition: createIndex2Statement,
+				},
 				{
-					indexname: index2Name,
-					indexdef:  createIndex2Statement,
+					keyName:    index1Name,
+					nonUnique:  "0",
+					seqInIndex: "1",
+					columnName: "b",
+					null:       "",
+					definition: createIndex1Statement,
 				},
 				{
-					indexname: index1Name,
-					indexdef:  createIndex1Statement,
+					keyName:    index1Name,
+					nonUnique:  "0",
+					seqInIndex: "2",
+					columnName: "c",
+					null:       "",
+					definition: createIndex1Statement,
 				},
 				{
-					indexname: pkeyIndexName,
-					indexdef:  createPkeyStatement,
+					keyName:    pkeyIndexName,
+					nonUnique:  "0",
+					seqInIndex: "1",
+					columnName: "a",
+					null:       "",
+					definition: createPkeyStatement,
 				},
 			}
 
 			for idx, expectedResult := range expectedResults {
+				if idx >= len(showIndexesResult.Rows) {
+					break
+				}
 				actualResult := showIndexesResult.Rows[idx]
 
+				assert.Equal(tableName, actualResult["Table"].ToString())
+				assert.Equal(expectedResult.keyName, actualResult["Key_name"].ToString(), "index names should match", actualResult)
+				assert.Equal(expectedResult.nonUnique, actualResult["Non_unique"].ToString(), actualResult)
+				assert.Equal(expectedResult.seqInIndex, actualResult["Seq_in_index"].ToString(), actualResult)
+				assert.Equal(expectedResult.columnName, actualResult["Column_name"].ToString(), actualResult)
+				assert.Equal(expectedResult.null, actualResult["Null"].ToString(), actualResult)
+				assert.Equal("A", actualResult["Collation"].ToString(), actualResult)
+				assert.Equal("BTREE", actualResult["Index_type"].ToString(), actualResult)
+				assert.Equal("NULL", actualResult["Expression"].ToString(), actualResult)
+				assert.Equal("NULL", actualResult["Predicate"].ToString(), actualResult)
 				assert.Equal(
-					expectedResult.indexname,
-					actualResult["indexname"].ToString(),
-					"index names should match",
-					expectedResult,
-					actualResult,
-				)
-				assert.Equal(
-					expectedResult.indexdef,
-					strings.ReplaceAll(actualResult["indexdef"].ToString(), "USING btree ", ""),
+					expectedResult.definition,
+					strings.ReplaceAll(actualResult["Definition"].ToString(), "USING btree ", ""),
 					"index definitions should match",
-					expectedResult,
 					actualResult,
 				)
 			}
+
+			// Expression and partial indexes
+			{
+				for _, stmnt := range []string{
+					fmt.Sprintf(`CREATE INDEX %s_idx_lower_c ON %s (lower(c) DESC) WHERE b > 0`, tableName, tableName),
+				} {
+					_, err := dbClient.Query(stmnt)
+					assert.NoError(err, stmnt)
+				}
+
+				showIndexesResult, err := dbClient.Query(showIndexesQuery)
+				assert.NoError(err)
+
+				var expressionIndexRow map[string]*db.NullString
+				for _, row := range showIndexesResult.Rows {
+					if row["Key_name"].ToString() == fmt.Sprintf("%s_idx_lower_c", tableName) {
+						expressionIndexRow = row
+					}
+				}
+
+				if assert.NotNil(expressionIndexRow, "expression index should be listed") {
+					assert.Equal("NULL", expressionIndexRow["Column_name"].ToString())
+					assert.Equal("lower(c::text)", expressionIndexRow["Expression"].ToString())
+					assert.Equal("D", expressionIndexRow["Collation"].ToString())
+					assert.Equal("(b > 0)", expressionIndexRow["Predicate"].ToString())
+				}
+			}
 		})
 	}
 }
@@ -243,3 +305,865 @@ func TestDBShowIndexesMySQL(t *testing.T) {
 		})
 	}
 }
+
+func TestDBRawStatementsPostgres(t *testing.T) {
+	connOptions := conn.DSNOptions{
+		Flavor:       conn.PostgreSQL,
+		Host:         "localhost",
+		DatabaseName: "test",
+		User:         "user",
+		Password:     "password",
+		Port:         5432,
+	}
+
+	for _, postgresVersion := range TESTED_POSTGRES_VERSIONS {
+		t.Run(fmt.Sprintf("Postgres %s - raw statements", postgresVersion), func(t *testing.T) {
+			assert := assert.New(t)
+
+			dbClient, cleanup := mustInitTestDBWithClient(
+				&InitTestDBOptions{postgresVersion, &connOptions},
+				assert,
+			)
+			defer cleanup()
+
+			_, err := dbClient.Query("CREATE TABLE foo (id int)")
+			assert.NoError(err)
+
+			// DESCRIBE is not valid Postgres syntax, so only works when transformed
+			_, err = dbClient.Query("DESCRIBE foo")
+			assert.NoError(err, "DESCRIBE should be transformed by default")
+
+			_, err = dbClient.Query(`\raw DESCRIBE foo`)
+			assert.Error(err, "\\raw prefix should skip transforming DESCRIBE")
+
+			result, err := dbClient.Query(`\raw SELECT 1 AS one`)
+			assert.NoError(err, "\\raw prefix should be removed before sending the statement")
+			assert.Equal("1", result.Rows[0]["one"].ToString())
+
+			dbClient.SetTransformsEnabled(false)
+			_, err = dbClient.Query("DESCRIBE foo")
+			assert.Error(err, "DESCRIBE should not be transformed when transforms are disabled")
+		})
+	}
+}
+
+func TestDBTranslateCommand(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\translate", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int)")
+				assert.NoError(err)
+
+				result, err := dbClient.Query(`\translate SHOW INDEXES FROM foo`)
+				assert.NoError(err)
+				assert.Equal([]string{"Statement", "Parameters"}, result.Columns)
+				assert.Len(result.Rows, 1)
+
+				translatedStatement := result.Rows[0]["Statement"].ToString()
+				translatedParams := result.Rows[0]["Parameters"].ToString()
+
+				switch testSuite.ConnOptions.Flavor {
+				case conn.PostgreSQL:
+					{
+						assert.Contains(translatedStatement, "pg_index")
+						assert.Equal("$1 = foo", translatedParams)
+					}
+				case conn.MySQL:
+					{
+						assert.Equal("SHOW INDEXES FROM foo", translatedStatement)
+						assert.Empty(translatedParams)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestDBShowColumns(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - SHOW COLUMNS", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int, user_id int, user_name varchar(255), created_at date)")
+				assert.NoError(err)
+
+				getFields := func(statement string) []string {
+					result, err := dbClient.Query(statement)
+					assert.NoError(err, statement)
+
+					fields := []string{}
+					for _, row := range result.Rows {
+						fields = append(fields, row["Field"].ToString())
+					}
+					slices.Sort(fields)
+
+					return fields
+				}
+
+				assert.Equal(
+					[]string{"created_at", "id", "user_id", "user_name"},
+					getFields("SHOW COLUMNS FROM foo"),
+				)
+				assert.Equal(
+					[]string{"user_id", "user_name"},
+					getFields("SHOW COLUMNS FROM foo LIKE 'user%'"),
+				)
+				assert.Equal(
+					[]string{"id"},
+					getFields("show fields from foo like 'id';"),
+				)
+				assert.Empty(getFields("SHOW COLUMNS FROM foo LIKE 'missing%'"))
+			})
+		}
+	}
+}
+
+func TestDBShowCreateTable(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - SHOW CREATE TABLE", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				setupStatements := []string{
+					"CREATE TABLE accounts (id int PRIMARY KEY)",
+					`CREATE TABLE orders (
+						id int NOT NULL PRIMARY KEY,
+						account_id int REFERENCES accounts (id),
+						total decimal(10, 2) DEFAULT 0 CHECK (total >= 0),
+						note varchar(255)
+					)`,
+					"CREATE INDEX orders_note_idx ON orders (note)",
+				}
+				for _, statement := range setupStatements {
+					_, err := dbClient.Query(statement)
+					assert.NoError(err, statement)
+				}
+
+				result, err := dbClient.Query("SHOW CREATE TABLE orders")
+				if !assert.NoError(err) || !assert.Len(result.Rows, 1) {
+					return
+				}
+				assert.Equal([]string{"Table", "Create Table"}, result.Columns)
+				assert.Equal("orders", result.Rows[0]["Table"].ToString())
+
+				createTable := result.Rows[0]["Create Table"].ToString()
+				assert.Contains(createTable, "CREATE TABLE")
+				assert.Contains(createTable, "orders_note_idx")
+				assert.Contains(strings.ToUpper(createTable), "REFERENCES")
+
+				// The definition is complete enough to create the table again
+				_, err = dbClient.Query("DROP TABLE orders")
+				assert.NoError(err)
+				for _, statement := range dbClient.SplitStatements(createTable) {
+					_, err = dbClient.Query(statement)
+					assert.NoError(err, statement)
+				}
+
+				result, err = dbClient.Query("SHOW CREATE TABLE orders")
+				if assert.NoError(err) && assert.Len(result.Rows, 1) {
+					assert.Equal(createTable, result.Rows[0]["Create Table"].ToString())
+				}
+			})
+		}
+	}
+}
+
+func TestDBMaintenanceCommand(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\maintenance", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int)")
+				assert.NoError(err)
+
+				result, err := dbClient.Query(`\maintenance foo;`)
+				assert.NoError(err)
+				assert.Len(result.Rows, 1)
+				assert.Equal("foo", result.Rows[0]["Table"].ToString())
+
+				actionLabels := []string{}
+				for _, action := range result.Actions {
+					actionLabels = append(actionLabels, action.Label)
+
+					_, err := dbClient.Query(action.Statement)
+					assert.NoError(err, action.Statement)
+				}
+
+				switch testSuite.ConnOptions.Flavor {
+				case conn.PostgreSQL:
+					{
+						assert.Equal([]string{"Analyze", "Vacuum"}, actionLabels)
+					}
+				case conn.MySQL:
+					{
+						assert.Equal([]string{"Analyze", "Optimize"}, actionLabels)
+					}
+				}
+
+				_, err = dbClient.Query(`\maintenance bar`)
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestDBDepsCommand(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\deps", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				for _, statement := range []string{
+					"CREATE TABLE users (id int PRIMARY KEY)",
+					"CREATE TABLE posts (id int PRIMARY KEY, user_id int REFERENCES users(id), CONSTRAINT posts_user_fk FOREIGN KEY (user_id) REFERENCES users(id))",
+					"CREATE VIEW user_ids AS SELECT id FROM users",
+				} {
+					_, err := dbClient.Query(statement)
+					assert.NoError(err, statement)
+				}
+
+				result, err := dbClient.Query(`\deps users;`)
+				assert.NoError(err)
+				assert.Equal([]string{"Type", "Name", "Definition"}, result.Columns)
+
+				dependents := []string{}
+				for _, row := range result.Rows {
+					dependents = append(dependents, fmt.Sprint(row["Type"].ToString(), " ", row["Name"].ToString()))
+				}
+
+				assert.Contains(dependents, "foreign key posts.posts_user_fk")
+				assert.Contains(dependents, "view user_ids")
+
+				_, err = dbClient.Query(`\deps missing`)
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestDBSchemaSnapshotDiff(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\schema", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				snapshotPath := filepath.Join(t.TempDir(), "schema.json")
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int PRIMARY KEY, name varchar(32))")
+				assert.NoError(err)
+
+				_, err = dbClient.Query(fmt.Sprintf(`\schema snapshot %s;`, snapshotPath))
+				assert.NoError(err)
+
+				result, err := dbClient.Query(fmt.Sprintf(`\schema diff %s;`, snapshotPath))
+				assert.NoError(err)
+				assert.Equal([]string{"Diff"}, result.Columns)
+
+				for _, statement := range []string{
+					"CREATE TABLE bar (id int)",
+					"ALTER TABLE foo ADD COLUMN created_at date",
+					"CREATE INDEX foo_name_idx ON foo (name)",
+				} {
+					_, err := dbClient.Query(statement)
+					assert.NoError(err, statement)
+				}
+
+				result, err = dbClient.Query(fmt.Sprintf(`\schema diff %s;`, snapshotPath))
+				assert.NoError(err)
+
+				changes := []string{}
+				for _, row := range result.Rows {
+					changes = append(changes, fmt.Sprint(row["Change"].ToString(), " ", row["Name"].ToString()))
+				}
+
+				assert.ElementsMatch(
+					[]string{"added bar", "added foo.created_at", "added foo.foo_name_idx"},
+					changes,
+				)
+			})
+		}
+	}
+}
+
+func TestDBCompareCommand(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\compare", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				values := []string{}
+				for id := 1; id <= 2500; id++ {
+					values = append(values, fmt.Sprintf("(%d, 'row %d')", id, id))
+				}
+
+				for _, statement := range []string{
+					"CREATE TABLE foo (id int PRIMARY KEY, name varchar(32))",
+					"CREATE TABLE bar (id int PRIMARY KEY, name varchar(32))",
+					fmt.Sprintf("INSERT INTO foo VALUES %s", strings.Join(values, ", ")),
+					fmt.Sprintf("INSERT INTO bar VALUES %s", strings.Join(values, ", ")),
+				} {
+					_, err := dbClient.Query(statement)
+					assert.NoError(err, statement)
+				}
+
+				result, err := dbClient.Query(`\compare foo bar;`)
+				assert.NoError(err)
+				assert.Equal([]string{"Compare"}, result.Columns)
+
+				_, err = dbClient.Query("UPDATE bar SET name = 'changed' WHERE id = 1500")
+				assert.NoError(err)
+
+				result, err = dbClient.Query(`\compare foo bar id;`)
+				assert.NoError(err)
+				assert.Len(result.Rows, 1)
+				assert.Equal("id >= 1001 AND id < 2001", result.Rows[0]["Range"].ToString())
+			})
+		}
+	}
+}
+
+func TestDBInsertRow(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - InsertRow", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int NOT NULL, name varchar(32), status varchar(32) DEFAULT 'new')")
+				assert.NoError(err)
+
+				table, err := dbClient.GetTableSchema("foo")
+				assert.NoError(err)
+				assert.Len(table.Columns, 3)
+
+				name := db.NullString{}
+				name.String, name.Valid = "it's", true
+				id := db.NullString{}
+				id.String, id.Valid = "1", true
+
+				_, _, err = dbClient.InsertRow("foo", []string{"id", "name"}, []*db.NullString{&id, &name})
+				assert.NoError(err)
+
+				_, _, err = dbClient.InsertRow("foo", []string{"id", "name"}, []*db.NullString{&id, {}})
+				assert.NoError(err)
+
+				result, err := dbClient.Query("SELECT name, status FROM foo ORDER BY name")
+				assert.NoError(err)
+				assert.Len(result.Rows, 2)
+
+				names := []string{}
+				for _, row := range result.Rows {
+					names = append(names, row["name"].ToString())
+					assert.Equal("new", row["status"].ToString())
+				}
+				assert.ElementsMatch([]string{"it's", "NULL"}, names)
+
+				_, _, err = dbClient.InsertRow("foo", []string{"name"}, []*db.NullString{&name})
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestDBSeedCommand(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\seed", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query(`
+					CREATE TABLE people (
+						id int PRIMARY KEY,
+						email varchar(255) NOT NULL UNIQUE,
+						name varchar(64) NOT NULL,
+						born_on date,
+						balance decimal(8,2) NOT NULL,
+						created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+					)
+				`)
+				assert.NoError(err)
+
+				for range 2 {
+					_, err = dbClient.Query(`\seed people 1200;`)
+					assert.NoError(err)
+				}
+
+				result, err := dbClient.Query("SELECT COUNT(*) AS total, COUNT(DISTINCT email) AS emails FROM people")
+				assert.NoError(err)
+				assert.Equal("2400", result.Rows[0]["total"].ToString())
+				assert.Equal("2400", result.Rows[0]["emails"].ToString())
+			})
+		}
+	}
+}
+
+func TestDBTimingCommand(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - \\timing", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int)")
+				assert.NoError(err)
+
+				result, err := dbClient.Query(`\timing INSERT INTO foo (id) VALUES (1);`)
+				assert.NoError(err)
+				assert.Equal([]string{"Total", "Server", "Network & Client"}, result.Columns)
+				assert.Len(result.Rows, 1)
+
+				// Timed statements are rolled back
+				result, err = dbClient.Query("SELECT COUNT(*) AS total FROM foo")
+				assert.NoError(err)
+				assert.Equal("0", result.Rows[0]["total"].ToString())
+
+				_, err = dbClient.Query(`\timing SELECT * FROM bar`)
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestDBExec(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Exec", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				result, err := dbClient.Exec("CREATE TABLE foo (id int)")
+				assert.NoError(err)
+				assert.Equal("Table created", result.Status)
+
+				result, err = dbClient.Exec("INSERT INTO foo (id) VALUES (1), (2)")
+				assert.NoError(err)
+				assert.Equal("Query OK, 2 rows affected", result.Status)
+				assert.Equal(int64(2), result.RowsAffected)
+				assert.Equal(int64(0), result.LastInsertID)
+
+				result, err = dbClient.Exec("UPDATE foo SET id = 3 WHERE id = 1")
+				assert.NoError(err)
+				assert.Equal("Query OK, 1 row affected", result.Status)
+
+				if testSuite.ConnOptions.Flavor == conn.MySQL {
+					_, err = dbClient.Exec("CREATE TABLE bar (id int AUTO_INCREMENT PRIMARY KEY, name varchar(20))")
+					assert.NoError(err)
+
+					result, err = dbClient.Exec("INSERT INTO bar (name) VALUES ('baz')")
+					assert.NoError(err)
+					assert.Equal(int64(1), result.LastInsertID)
+					assert.Contains(result.Summary(), "last insert ID 1")
+				}
+
+				_, err = dbClient.Exec("DROP TABLE missing")
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestDBBulkInsert(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - BulkInsert", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int, name varchar(32))")
+				assert.NoError(err)
+
+				rows := make([][]any, 1200)
+				for idx := range rows {
+					rows[idx] = []any{idx, fmt.Sprint("name ", idx)}
+				}
+				rows[0][1] = nil
+
+				progress := []int{}
+				insertedRows, err := dbClient.BulkInsertWithProgress("foo", []string{"id", "name"}, rows, func(insertedRows int, totalRows int) {
+					assert.Equal(1200, totalRows)
+					progress = append(progress, insertedRows)
+				})
+				assert.NoError(err)
+				assert.Equal(1200, insertedRows)
+				assert.Equal([]int{500, 1000, 1200}, progress)
+
+				result, err := dbClient.Query("SELECT COUNT(*) AS total, COUNT(name) AS named FROM foo")
+				assert.NoError(err)
+				assert.Equal("1200", result.Rows[0]["total"].ToString())
+				assert.Equal("1199", result.Rows[0]["named"].ToString())
+
+				_, err = dbClient.BulkInsert("foo", []string{"id", "name"}, [][]any{{1}})
+				assert.Error(err)
+			})
+		}
+	}
+}
+
+func TestDBResultCache(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Result Cache", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				dbClient.SetResultCacheTTL(time.Minute)
+
+				_, err := dbClient.Exec("CREATE TABLE foo (id int)")
+				assert.NoError(err)
+
+				result, err := dbClient.Query("SELECT * FROM foo")
+				assert.NoError(err)
+				assert.False(result.Cached)
+
+				result, err = dbClient.Query("SELECT *\n  FROM foo;")
+				assert.NoError(err)
+				assert.True(result.Cached, "Identical statement reuses the result")
+
+				result, err = dbClient.Query(`\nocache SELECT * FROM foo`)
+				assert.NoError(err)
+				assert.False(result.Cached, "\\nocache bypasses the cache")
+
+				_, err = dbClient.Exec("INSERT INTO foo (id) VALUES (1)")
+				assert.NoError(err)
+
+				result, err = dbClient.Query("SELECT * FROM foo")
+				assert.NoError(err)
+				assert.False(result.Cached, "Changing data invalidates the cache")
+				assert.Len(result.Rows, 1)
+			})
+		}
+	}
+}
+
+func TestDBColumnTypes(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Column Types", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Exec("CREATE TABLE foo (id int NOT NULL, name varchar(20))")
+				assert.NoError(err)
+
+				result, err := dbClient.Query("SELECT id, name, 1 AS one FROM foo")
+				assert.NoError(err)
+				if !assert.Len(result.ColumnTypes, 3) {
+					return
+				}
+
+				assert.NotEmpty(result.ColumnTypes[0].DatabaseType)
+				assert.Equal("foo", result.ColumnTypes[0].Table)
+				if assert.NotNil(result.ColumnTypes[0].Nullable) {
+					assert.False(*result.ColumnTypes[0].Nullable)
+				}
+				assert.Equal("foo", result.ColumnTypes[1].Table)
+				assert.Equal("", result.ColumnTypes[2].Table, "Expressions have no source table")
+			})
+		}
+	}
+}
+
+func TestDBDumpRestore(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Dump and Restore", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				idColumn := "id SERIAL PRIMARY KEY"
+				if testSuite.ConnOptions.Flavor == conn.MySQL {
+					idColumn = "id INT AUTO_INCREMENT PRIMARY KEY"
+				}
+
+				_, err := dbClient.Exec(fmt.Sprintf("CREATE TABLE authors (%s, name varchar(20) NOT NULL)", idColumn))
+				assert.NoError(err)
+				_, err = dbClient.Exec(fmt.Sprintf(
+					"CREATE TABLE books (%s, author_id int REFERENCES authors (id), title varchar(50))",
+					idColumn,
+				))
+				assert.NoError(err)
+				_, err = dbClient.Exec("INSERT INTO authors (name) VALUES ('O''Brien'), ('Semi; colon')")
+				assert.NoError(err)
+				_, err = dbClient.Exec("INSERT INTO books (author_id, title) VALUES (1, NULL), (2, 'Two')")
+				assert.NoError(err)
+
+				dumpPath := filepath.Join(t.TempDir(), "dump.sql")
+				result, err := dbClient.Query(fmt.Sprint(`\dump all `, dumpPath))
+				assert.NoError(err)
+				assert.Equal(fmt.Sprint("Dumped 2 tables and 4 rows to ", dumpPath), result.Rows[0]["Dump"].ToString())
+
+				_, err = dbClient.Exec("DELETE FROM books")
+				assert.NoError(err)
+
+				executedStatements := 0
+				_, err = dbClient.Restore(dumpPath, func(executed int, total int) {
+					executedStatements = executed
+				})
+				assert.NoError(err)
+				assert.Greater(executedStatements, 0)
+
+				result, err = dbClient.Query("SELECT a.name, b.title FROM books b JOIN authors a ON a.id = b.author_id ORDER BY b.id")
+				assert.NoError(err)
+				if assert.Len(result.Rows, 2) {
+					assert.Equal("O'Brien", result.Rows[0]["name"].ToString())
+					assert.False(result.Rows[0]["title"].Valid)
+					assert.Equal("Semi; colon", result.Rows[1]["name"].ToString())
+				}
+
+				// New rows continue numbering after the restored ones
+				_, err = dbClient.Exec("INSERT INTO authors (name) VALUES ('Third')")
+				assert.NoError(err)
+				result, err = dbClient.Query("SELECT id FROM authors WHERE name = 'Third'")
+				assert.NoError(err)
+				assert.Equal("3", result.Rows[0]["id"].ToString())
+			})
+		}
+	}
+}
+
+func TestDBBackupRestoreFrom(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Backup and Restore From", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Exec("CREATE TABLE foo (id int PRIMARY KEY, name varchar(20))")
+				assert.NoError(err)
+				_, err = dbClient.Exec("INSERT INTO foo VALUES (1, 'a'), (2, 'b')")
+				assert.NoError(err)
+
+				backupName := fmt.Sprint("foo_backup_", time.Now().Format("20060102"))
+				result, err := dbClient.Query(`\backup foo`)
+				assert.NoError(err)
+				assert.Equal(fmt.Sprint("Copied 2 rows of foo to ", backupName), result.Rows[0]["Backup"].ToString())
+
+				// Only one backup a day, rather than overwriting the last
+				_, err = dbClient.Query(`\backup foo`)
+				assert.ErrorContains(err, "already exists")
+
+				_, err = dbClient.Exec("UPDATE foo SET name = 'oops'")
+				assert.NoError(err)
+
+				result, err = dbClient.Query(fmt.Sprint(`\restore-from `, backupName))
+				assert.NoError(err)
+				assert.Equal(
+					fmt.Sprintf("Restored 2 rows of foo from %s", backupName),
+					result.Rows[0]["Restore"].ToString(),
+				)
+
+				result, err = dbClient.Query("SELECT name FROM foo ORDER BY id")
+				assert.NoError(err)
+				if assert.Len(result.Rows, 2) {
+					assert.Equal("a", result.Rows[0]["name"].ToString())
+					assert.Equal("b", result.Rows[1]["name"].ToString())
+				}
+			})
+		}
+	}
+}
+
+func TestDBQueryStream(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Query Stream", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				_, err := dbClient.Exec("CREATE TABLE foo (id int PRIMARY KEY)")
+				assert.NoError(err)
+				_, err = dbClient.Exec("INSERT INTO foo VALUES (1), (2), (3), (4), (5)")
+				assert.NoError(err)
+
+				result, err := dbClient.QueryStream("SELECT id FROM foo ORDER BY id", 2)
+				assert.NoError(err)
+				assert.Len(result.Rows, 2)
+				assert.True(result.HasMoreRows())
+
+				added, err := result.FetchRows(2)
+				assert.NoError(err)
+				assert.Equal(2, added)
+				assert.Equal("4", result.Rows[3]["id"].ToString())
+
+				// Reading past the end completes the result
+				added, err = result.FetchRows(2)
+				assert.NoError(err)
+				assert.Equal(1, added)
+				assert.False(result.HasMoreRows())
+				assert.Nil(result.Stream)
+
+				// Another statement closes a result still being read, rather than failing
+				result, err = dbClient.QueryStream("SELECT id FROM foo ORDER BY id", 2)
+				assert.NoError(err)
+				countResult, err := dbClient.Query("SELECT COUNT(*) AS total FROM foo")
+				assert.NoError(err)
+				assert.Equal("5", countResult.Rows[0]["total"].ToString())
+
+				assert.True(result.Stream.Interrupted())
+				assert.False(result.HasMoreRows())
+				added, err = result.FetchRows(2)
+				assert.NoError(err)
+				assert.Equal(0, added)
+				assert.Len(result.Rows, 2)
+
+				// The row limit applies to Query
+				dbClient.SetRowLimit(3)
+				result, err = dbClient.Query("SELECT id FROM foo ORDER BY id")
+				assert.NoError(err)
+				assert.Len(result.Rows, 3)
+				assert.True(result.HasMoreRows())
+			})
+		}
+	}
+}
+
+func TestDBTransactions(t *testing.T) {
+	for _, testSuite := range showTablesTestSuite {
+		for _, dbVersion := range testSuite.DBVersions {
+			t.Run(fmt.Sprintf("%s %s - Transactions", testSuite.ConnOptions.Flavor, dbVersion), func(t *testing.T) {
+				assert := assert.New(t)
+
+				dbClient, cleanup := mustInitTestDBWithClient(
+					&InitTestDBOptions{dbVersion, &testSuite.ConnOptions},
+					assert,
+				)
+				defer cleanup()
+
+				getCount := func() string {
+					result, err := dbClient.Query("SELECT COUNT(*) AS total FROM foo")
+					assert.NoError(err)
+
+					return result.Rows[0]["total"].ToString()
+				}
+
+				_, err := dbClient.Query("CREATE TABLE foo (id int)")
+				assert.NoError(err)
+
+				// Rolled back changes are discarded
+				assert.NoError(dbClient.BeginTransaction())
+				assert.True(dbClient.InTransaction())
+				assert.Error(dbClient.BeginTransaction())
+
+				_, err = dbClient.Query("INSERT INTO foo VALUES (1)")
+				assert.NoError(err)
+				assert.Equal("1", getCount())
+
+				assert.NoError(dbClient.RollbackTransaction())
+				assert.False(dbClient.InTransaction())
+				assert.Equal("0", getCount())
+
+				// Typed transaction statements are tracked too
+				_, err = dbClient.Query("BEGIN;")
+				assert.NoError(err)
+				assert.True(dbClient.InTransaction())
+
+				_, err = dbClient.Query("INSERT INTO foo VALUES (1)")
+				assert.NoError(err)
+
+				_, err = dbClient.Query("commit;")
+				assert.NoError(err)
+				assert.False(dbClient.InTransaction())
+				assert.Equal("1", getCount())
+
+				assert.Error(dbClient.CommitTransaction())
+			})
+		}
+	}
+}
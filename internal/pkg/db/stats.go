@@ -0,0 +1,131 @@
+package db
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How many of the slowest queries are kept for \stats
+const slowestQueriesTracked = 5
+
+// Aggregates of every query run during the session
+// Guarded by a mutex, as metrics may be read from an HTTP handler while queries run
+type sessionStats struct {
+	mutex sync.Mutex
+
+	startedAt      time.Time
+	queries        int
+	errors         int
+	rowsFetched    int
+	totalQueryTime time.Duration
+	// Sorted from slowest to fastest
+	slowestQueries []SlowQuery
+}
+
+type SlowQuery struct {
+	Statement string        `json:"statement"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// Point in time copy of the session aggregates, safe to read without locking
+type SessionStats struct {
+	StartedAt      time.Time     `json:"started_at"`
+	Queries        int           `json:"queries"`
+	Errors         int           `json:"errors"`
+	RowsFetched    int           `json:"rows_fetched"`
+	TotalQueryTime time.Duration `json:"total_query_time"`
+	SlowestQueries []SlowQuery   `json:"slowest_queries"`
+}
+
+func newSessionStats() *sessionStats {
+	return &sessionStats{
+		startedAt: time.Now(),
+	}
+}
+
+func (stats *sessionStats) record(statement string, duration time.Duration, result *QueryResult, err error) {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	stats.queries++
+	stats.totalQueryTime += duration
+
+	if err != nil {
+		stats.errors++
+	} else if result != nil {
+		stats.rowsFetched += len(result.Rows)
+	}
+
+	insertIdx, _ := slices.BinarySearchFunc(stats.slowestQueries, duration, func(slowQuery SlowQuery, duration time.Duration) int {
+		// Reversed, as the slowest query comes first
+		return cmp.Compare(duration, slowQuery.Duration)
+	})
+	if insertIdx < slowestQueriesTracked {
+		stats.slowestQueries = slices.Insert(
+			stats.slowestQueries,
+			insertIdx,
+			SlowQuery{strings.TrimSpace(statement), duration},
+		)
+		stats.slowestQueries = stats.slowestQueries[:min(len(stats.slowestQueries), slowestQueriesTracked)]
+	}
+}
+
+func (stats *sessionStats) snapshot() SessionStats {
+	stats.mutex.Lock()
+	defer stats.mutex.Unlock()
+
+	return SessionStats{
+		StartedAt:      stats.startedAt,
+		Queries:        stats.queries,
+		Errors:         stats.errors,
+		RowsFetched:    stats.rowsFetched,
+		TotalQueryTime: stats.totalQueryTime,
+		SlowestQueries: slices.Clone(stats.slowestQueries),
+	}
+}
+
+// Aggregates of every query run so far this session
+func (db *DBClient) GetSessionStats() SessionStats {
+	return db.stats.snapshot()
+}
+
+// Share of queries which failed, from 0 to 1
+func (stats *SessionStats) ErrorRate() float64 {
+	if stats.Queries == 0 {
+		return 0
+	}
+
+	return float64(stats.Errors) / float64(stats.Queries)
+}
+
+// \stats
+// Show aggregates of the queries run this session
+func (db *DBClient) statsCommand(string) (*QueryResult, error) {
+	stats := db.GetSessionStats()
+	return newTextResult([]string{"Metric", "Value"}, buildStatsRows(&stats, time.Now())), nil
+}
+
+func buildStatsRows(stats *SessionStats, now time.Time) [][]string {
+	rows := [][]string{
+		{"Session Duration", now.Sub(stats.StartedAt).Round(time.Second).String()},
+		{"Queries", strconv.Itoa(stats.Queries)},
+		{"Errors", strconv.Itoa(stats.Errors)},
+		{"Error Rate", fmt.Sprintf("%.1f%%", stats.ErrorRate()*100)},
+		{"Rows Fetched", strconv.Itoa(stats.RowsFetched)},
+		{"Total Query Time", formatTimingDuration(stats.TotalQueryTime)},
+	}
+
+	for idx, slowQuery := range stats.SlowestQueries {
+		rows = append(rows, []string{
+			fmt.Sprintf("Slowest #%d", idx+1),
+			fmt.Sprintf("%s  %s", formatTimingDuration(slowQuery.Duration), slowQuery.Statement),
+		})
+	}
+
+	return rows
+}
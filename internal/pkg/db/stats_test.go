@@ -0,0 +1,80 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionStatsRecord(t *testing.T) {
+	assert := assert.New(t)
+	stats := newSessionStats()
+
+	result := newTextResult([]string{"id"}, [][]string{{"1"}, {"2"}})
+	for idx := range slowestQueriesTracked + 2 {
+		stats.record(fmt.Sprintf("SELECT %d", idx), time.Duration(idx)*time.Millisecond, result, nil)
+	}
+	stats.record("SELECT * FROM missing", 3*time.Millisecond+500*time.Microsecond, nil, errors.New("Query Failed"))
+
+	snapshot := stats.snapshot()
+	assert.Equal(slowestQueriesTracked+3, snapshot.Queries)
+	assert.Equal(1, snapshot.Errors)
+	assert.Equal(2*(slowestQueriesTracked+2), snapshot.RowsFetched)
+	assert.InDelta(1.0/8.0, snapshot.ErrorRate(), 0.0001)
+
+	slowestStatements := make([]string, len(snapshot.SlowestQueries))
+	for idx, slowQuery := range snapshot.SlowestQueries {
+		slowestStatements[idx] = slowQuery.Statement
+	}
+	assert.Equal(
+		[]string{"SELECT 6", "SELECT 5", "SELECT 4", "SELECT * FROM missing", "SELECT 3"},
+		slowestStatements,
+	)
+}
+
+func TestBuildStatsRows(t *testing.T) {
+	assert := assert.New(t)
+
+	startedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	stats := SessionStats{
+		StartedAt:      startedAt,
+		Queries:        4,
+		Errors:         1,
+		RowsFetched:    120,
+		TotalQueryTime: 30 * time.Millisecond,
+		SlowestQueries: []SlowQuery{{"SELECT * FROM foo", 25 * time.Millisecond}},
+	}
+
+	assert.Equal(
+		[][]string{
+			{"Session Duration", "1m30s"},
+			{"Queries", "4"},
+			{"Errors", "1"},
+			{"Error Rate", "25.0%"},
+			{"Rows Fetched", "120"},
+			{"Total Query Time", "30ms"},
+			{"Slowest #1", "25ms  SELECT * FROM foo"},
+		},
+		buildStatsRows(&stats, startedAt.Add(90*time.Second)),
+	)
+}
+
+func TestSessionStatsRedacted(t *testing.T) {
+	assert := assert.New(t)
+
+	dbClient, err := CreateDBClient(&fakeConnManager{})
+	if !assert.NoError(err) {
+		return
+	}
+
+	// Fails against the fake, but is recorded all the same
+	_, _ = dbClient.Exec("UPDATE users SET password = 'hunter2' WHERE id = 42")
+
+	stats := dbClient.GetSessionStats()
+	if assert.Len(stats.SlowestQueries, 1) {
+		assert.Equal("UPDATE users SET password = ? WHERE id = ?", stats.SlowestQueries[0].Statement)
+	}
+}
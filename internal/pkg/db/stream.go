@@ -0,0 +1,97 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Rows of a result yet to be read, fetched a page at a time so a large result isn't held in memory all at once
+// Holds the connection until every row is read, or it's closed before the connection is next used
+type RowStream struct {
+	rows    *sqlx.Rows
+	columns []string
+	// Every row has been read
+	done bool
+	// Closed before every row was read, such as by another statement running
+	interrupted bool
+}
+
+func newRowStream(rows *sqlx.Rows, columns []string) *RowStream {
+	return &RowStream{rows: rows, columns: columns}
+}
+
+// Read up to count rows, or every remaining row when count is 0
+// Returns fewer once the result runs out, or nothing once the stream is closed
+func (stream *RowStream) fetch(count int) ([]map[string]*NullString, error) {
+	var mappedRows []map[string]*NullString
+	if stream.done || stream.interrupted {
+		return mappedRows, nil
+	}
+
+	for count == 0 || len(mappedRows) < count {
+		if !stream.rows.Next() {
+			err := stream.rows.Err()
+			stream.finish()
+			if err != nil {
+				return mappedRows, errors.Join(
+					errors.New("failed to read rows"),
+					err,
+				)
+			}
+
+			return mappedRows, nil
+		}
+
+		rawRow := make([]NullString, len(stream.columns))
+		rawRowPtrs := make([]any, len(stream.columns))
+		for idx := range rawRow {
+			rawRowPtrs[idx] = &rawRow[idx]
+		}
+
+		if err := stream.rows.Scan(rawRowPtrs...); err != nil {
+			stream.finish()
+			return mappedRows, errors.Join(
+				errors.New("failed to read rows"),
+				err,
+			)
+		}
+
+		// Map each row of column -> value
+		mappedRow := make(map[string]*NullString, len(rawRow))
+		for columnIdx := range rawRow {
+			mappedRow[stream.columns[columnIdx]] = &rawRow[columnIdx]
+		}
+		mappedRows = append(mappedRows, mappedRow)
+	}
+
+	return mappedRows, nil
+}
+
+func (stream *RowStream) finish() {
+	stream.done = true
+	if err := stream.rows.Close(); err != nil {
+		panic("Failed to cleanup rows")
+	}
+}
+
+// Whether the stream was closed before every row was read, leaving the result incomplete
+func (stream *RowStream) Interrupted() bool {
+	return stream.interrupted
+}
+
+// Stop reading, freeing the connection for other statements
+// Drivers read through whatever is left of the result to do so, which takes a moment for very large results
+func (stream *RowStream) Close() error {
+	if stream.done || stream.interrupted {
+		return nil
+	}
+
+	stream.interrupted = true
+	return stream.rows.Close()
+}
+
+// Discard the rest of any result being read a page at a time, such as before using the connection from another goroutine
+func (db *DBClient) CloseRowStreams() {
+	db.connManager.CloseOpenRows()
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/azvaliev/sql/internal/pkg/telemetry"
+)
+
+// Statement types counted as features
+// Anything else is counted as "other", so a typo or identifier at the start of a statement is never recorded
+var telemetryStatementKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "INSERT": true, "UPDATE": true, "DELETE": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true,
+	"DESCRIBE": true, "SHOW": true, "EXPLAIN": true, "USE": true, "SET": true,
+	"BEGIN": true, "START": true, "COMMIT": true, "END": true, "ROLLBACK": true,
+	"GRANT": true, "REVOKE": true, "ANALYZE": true, "VACUUM": true, "OPTIMIZE": true, "CALL": true,
+}
+
+// Count feature usage of each statement run with recorder
+func (db *DBClient) SetTelemetry(recorder *telemetry.Recorder) {
+	db.telemetry = recorder
+}
+
+func (db *DBClient) recordFeatureUsage(statement string) {
+	if db.telemetry == nil {
+		return
+	}
+
+	db.telemetry.Record(getStatementFeature(db.connManager.GetFlavor(), statement))
+}
+
+// Name of the feature a statement uses, without anything specific to the statement such as identifiers
+func getStatementFeature(flavor conn.DBFlavor, statement string) string {
+	if _, isRaw := stripRawPrefix(statement); isRaw {
+		return `\raw`
+	}
+
+	if _, isNocache := stripNocachePrefix(statement); isNocache {
+		return `\nocache`
+	}
+
+	if commandName, _, isMetaCommand := ParseMetaCommand(statement); isMetaCommand {
+		if _, exists := metaCommands[commandName]; exists {
+			return `\` + commandName
+		}
+
+		return "other"
+	}
+
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 || tokens[0].Kind != lexer.Word {
+		return "other"
+	}
+
+	keyword := strings.ToUpper(tokens[0].Text)
+	if !telemetryStatementKeywords[keyword] {
+		return "other"
+	}
+
+	return keyword
+}
+
+// \telemetry
+// Show whether telemetry is on, and exactly what would be sent
+func (db *DBClient) telemetryCommand(string) (*QueryResult, error) {
+	if db.telemetry == nil {
+		return nil, errors.New("Telemetry is not available")
+	}
+
+	payload, err := json.Marshal(db.telemetry.Payload())
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to encode telemetry"),
+			err,
+		)
+	}
+
+	status := "off, nothing is sent"
+	if db.telemetry.Enabled() {
+		status = "on, sent when exiting"
+	}
+
+	return newTextResult(
+		[]string{"Telemetry", "Endpoint", "Payload"},
+		[][]string{{status, db.telemetry.Endpoint(), string(payload)}},
+	), nil
+}
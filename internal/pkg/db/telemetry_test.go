@@ -0,0 +1,34 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStatementFeature(t *testing.T) {
+	var tests = []struct {
+		Statement       string
+		ExpectedFeature string
+	}{
+		{"SELECT * FROM users WHERE email = 'someone@example.com';", "SELECT"},
+		{"  -- comment\n  describe users", "DESCRIBE"},
+		{`\erd analytics`, `\erd`},
+		{`\raw DESCRIBE users`, `\raw`},
+		{`\nocache SELECT * FROM users`, `\nocache`},
+		{`\secret_command`, "other"},
+		{"users_password_hunter2", "other"},
+		{"(SELECT 1)", "other"},
+		{"", "other"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.ExpectedFeature, getStatementFeature(conn.PostgreSQL, test.Statement))
+		})
+	}
+}
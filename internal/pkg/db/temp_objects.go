@@ -0,0 +1,235 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+const tempDropArgument = "drop"
+
+// A temporary table or view created this session
+type tempObject struct {
+	name string
+	// TABLE or VIEW
+	kind      string
+	createdAt time.Time
+}
+
+// Remember temporary tables and views a statement created, so they can be listed and dropped with \temp
+func (db *DBClient) trackTempObjects(statement string) {
+	if uncachedStatement, isNocache := stripNocachePrefix(statement); isNocache {
+		statement = uncachedStatement
+	}
+	if rawStatement, isRaw := stripRawPrefix(statement); isRaw {
+		statement = rawStatement
+	}
+
+	name, kind, isTemp := parseTempObject(db.connManager.GetFlavor(), statement)
+	if !isTemp {
+		return
+	}
+
+	// Created again, ex: after being dropped
+	db.tempObjects = slices.DeleteFunc(db.tempObjects, func(object tempObject) bool {
+		return object.name == name
+	})
+	db.tempObjects = append(db.tempObjects, tempObject{name: name, kind: kind, createdAt: time.Now()})
+}
+
+// Name and kind of the object a CREATE TEMPORARY TABLE or VIEW statement creates, ex: (totals, TABLE)
+func parseTempObject(flavor conn.DBFlavor, statement string) (name string, kind string, isTemp bool) {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) == 0 || !tokens[0].IsKeyword("CREATE") {
+		return "", "", false
+	}
+
+	idx := 1
+	// Modifiers before the kind, ex: CREATE OR REPLACE GLOBAL TEMPORARY VIEW
+	for idx < len(tokens) && tokens[idx].Kind == lexer.Word {
+		word := strings.ToUpper(tokens[idx].Text)
+		if word == "TEMP" || word == "TEMPORARY" {
+			isTemp = true
+		} else if word == "TABLE" || word == "VIEW" {
+			kind = word
+			idx++
+			break
+		} else if word != "OR" && word != "REPLACE" && word != "GLOBAL" && word != "LOCAL" && word != "UNLOGGED" {
+			return "", "", false
+		}
+		idx++
+	}
+	if !isTemp || kind == "" {
+		return "", "", false
+	}
+
+	if idx+2 < len(tokens) && tokens[idx].IsKeyword("IF") && tokens[idx+1].IsKeyword("NOT") && tokens[idx+2].IsKeyword("EXISTS") {
+		idx += 3
+	}
+
+	// Only the last part of a qualified name, ex: pg_temp.totals
+	for ; idx < len(tokens); idx++ {
+		switch tokens[idx].Kind {
+		case lexer.Word:
+			{
+				name = tokens[idx].Text
+				if flavor == conn.PostgreSQL {
+					name = strings.ToLower(name)
+				}
+			}
+		case lexer.QuotedIdentifier:
+			{
+				quote := tokens[idx].Text[:1]
+				name = strings.ReplaceAll(tokens[idx].Text[1:len(tokens[idx].Text)-1], quote+quote, quote)
+			}
+		default:
+			{
+				return "", "", false
+			}
+		}
+
+		if idx+1 >= len(tokens) || !tokens[idx+1].IsOperator(".") {
+			break
+		}
+		idx++
+	}
+
+	return name, kind, name != ""
+}
+
+// \temp [drop]
+// List the temporary tables and views created this session which still exist, or drop them all
+func (db *DBClient) tempCommand(argument string) (*QueryResult, error) {
+	argument = strings.TrimSpace(strings.TrimSuffix(argument, ";"))
+	if argument != "" && argument != tempDropArgument {
+		return nil, errors.New(`Usage: \temp [drop]`)
+	}
+
+	objects, err := db.getExistingTempObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	if argument == tempDropArgument {
+		return db.dropTempObjects(objects)
+	}
+
+	rows := make([][]string, len(objects))
+	for idx, object := range objects {
+		rows[idx] = []string{object.name, strings.ToLower(object.kind), object.createdAt.Format(time.TimeOnly)}
+	}
+
+	result := newTextResult([]string{"Name", "Type", "Created"}, rows)
+	if len(objects) > 0 {
+		result.Actions = []ResultAction{{"Drop All", fmt.Sprint(`\temp `, tempDropArgument)}}
+	}
+
+	return result, nil
+}
+
+// Tracked objects which still exist, forgetting those which don't
+// Temporary objects go away when dropped, when the connection is reestablished, or in Postgres when rolled back
+func (db *DBClient) getExistingTempObjects() ([]tempObject, error) {
+	if len(db.tempObjects) == 0 {
+		return nil, nil
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	var existingObjects []tempObject
+	for _, object := range db.tempObjects {
+		var exists bool
+
+		switch db.connManager.GetFlavor() {
+		case conn.PostgreSQL:
+			{
+				err = connection.GetContext(
+					db.ctx,
+					&exists,
+					"SELECT EXISTS (SELECT 1 FROM pg_class WHERE relnamespace = pg_my_temp_schema() AND relname = $1)",
+					object.name,
+				)
+			}
+		case conn.MySQL:
+			{
+				// MySQL doesn't list temporary tables anywhere, so instead see whether the name can be selected from
+				_, selectErr := connection.ExecContext(db.ctx, fmt.Sprintf("SELECT 1 FROM %s LIMIT 0", db.quoteIdentifier(object.name)))
+				exists = selectErr == nil
+			}
+		default:
+			{
+				return nil, commandNotSupportedError(`\temp`, db.connManager.GetFlavor())
+			}
+		}
+		if err != nil {
+			return nil, errors.Join(
+				errors.New("Unable to check which temporary objects still exist"),
+				err,
+			)
+		}
+
+		if exists {
+			existingObjects = append(existingObjects, object)
+		}
+	}
+
+	db.tempObjects = existingObjects
+	return existingObjects, nil
+}
+
+func (db *DBClient) dropTempObjects(objects []tempObject) (*QueryResult, error) {
+	if len(objects) == 0 {
+		return nil, errors.New("No temporary tables or views to drop")
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	// Views are dropped first, as they may depend on the tables
+	objects = slices.Clone(objects)
+	slices.SortStableFunc(objects, func(a, b tempObject) int {
+		return strings.Compare(b.kind, a.kind)
+	})
+
+	var droppedNames []string
+	for _, object := range objects {
+		// Limited to temporary objects, so a permanent one of the same name is never dropped
+		var dropStatement string
+		if db.connManager.GetFlavor() == conn.PostgreSQL {
+			dropStatement = fmt.Sprintf("DROP %s IF EXISTS pg_temp.%s", object.kind, db.quoteIdentifier(object.name))
+		} else {
+			dropStatement = fmt.Sprint("DROP TEMPORARY TABLE IF EXISTS ", db.quoteIdentifier(object.name))
+		}
+
+		dropStatement, err = db.checkStatement(dropStatement)
+		if err == nil {
+			_, err = connection.ExecContext(db.ctx, dropStatement)
+		}
+		if err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("Failed to drop %s, dropped %d others first", object.name, len(droppedNames)),
+				err,
+			)
+		}
+		droppedNames = append(droppedNames, object.name)
+	}
+
+	// Left tracked, as rolling back the sandbox brings them back in Postgres
+	db.schema = nil
+	db.resultCache.invalidate()
+
+	return newTextResult(
+		[]string{"Dropped"},
+		[][]string{{strings.Join(droppedNames, ", ")}},
+	), nil
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTempObject(t *testing.T) {
+	var tests = []struct {
+		Flavor         conn.DBFlavor
+		Statement      string
+		ExpectedName   string
+		ExpectedKind   string
+		ExpectedIsTemp bool
+	}{
+		{conn.PostgreSQL, "CREATE TEMP TABLE Totals AS SELECT 1", "totals", "TABLE", true},
+		{conn.PostgreSQL, `create temporary table if not exists "Totals" (id int)`, "Totals", "TABLE", true},
+		{conn.PostgreSQL, "CREATE OR REPLACE TEMP VIEW pg_temp.recent AS SELECT 1", "recent", "VIEW", true},
+		{conn.PostgreSQL, "CREATE GLOBAL TEMPORARY TABLE totals (id int)", "totals", "TABLE", true},
+		{conn.MySQL, "CREATE TEMPORARY TABLE `Totals` (id int)", "Totals", "TABLE", true},
+		{conn.MySQL, "CREATE TABLE totals (id int)", "", "", false},
+		{conn.PostgreSQL, "CREATE TEMP SEQUENCE ids", "", "", false},
+		{conn.PostgreSQL, "SELECT 1", "", "", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+
+			name, kind, isTemp := parseTempObject(test.Flavor, test.Statement)
+			assert.Equal(test.ExpectedName, name)
+			assert.Equal(test.ExpectedKind, kind)
+			assert.Equal(test.ExpectedIsTemp, isTemp)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Server time of the most recently finished statement on this connection, in picoseconds
+const mySQLLastStatementTimerQuery string = `
+SELECT TIMER_WAIT
+FROM performance_schema.events_statements_history
+WHERE THREAD_ID = PS_CURRENT_THREAD_ID()
+ORDER BY EVENT_ID DESC
+LIMIT 1
+`
+
+// Savepoint used to discard a timed statement while already inside a transaction
+const timingSavepoint string = "sql_timing"
+
+// Statements MySQL commits implicitly, by their first keyword, which \timing couldn't roll back
+// See https://dev.mysql.com/doc/refman/8.4/en/implicit-commit.html
+var mySQLImplicitCommitKeywords = map[string]bool{
+	"ALTER": true, "CREATE": true, "DROP": true, "RENAME": true, "TRUNCATE": true,
+	"GRANT": true, "REVOKE": true, "SET": true, "LOCK": true, "UNLOCK": true,
+	"BEGIN": true, "START": true, "COMMIT": true,
+	"ANALYZE": true, "OPTIMIZE": true, "REPAIR": true, "CACHE": true, "FLUSH": true, "RESET": true,
+	"INSTALL": true, "UNINSTALL": true,
+}
+
+// \timing <statement>
+// Run a statement and split the time it took between the server and everything else, such as the network
+// The statement runs inside a transaction which is rolled back afterwards, so changes it makes aren't kept
+// Statements MySQL would commit implicitly, such as DDL, are refused, as they'd be kept and commit the open transaction too
+func (db *DBClient) timingCommand(statement string) (result *QueryResult, err error) {
+	if statement == "" {
+		return nil, errors.New(`Usage: \timing <statement>`)
+	}
+
+	statementWithParams := &StatementWithParams{statement, nil}
+	if db.transformsEnabled {
+		statementWithParams, err = db.transformStatement(statement)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if db.connManager.GetFlavor() == conn.MySQL && mySQLCommitsImplicitly(statementWithParams.statement) {
+		return nil, errors.New(`\timing can't roll back this statement, as MySQL commits it implicitly, run it on its own instead`)
+	}
+
+	// The statement itself is checked rather than the EXPLAIN ANALYZE it's wrapped in, as it's run in full either way
+	statementWithParams.statement, err = db.checkStatement(statementWithParams.statement)
+	if err != nil {
+		return nil, err
+	}
+
+	connection, err := db.getTransactionConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	beginStatement, rollbackStatement := "BEGIN", "ROLLBACK"
+	if db.InTransaction() {
+		beginStatement = fmt.Sprint("SAVEPOINT ", timingSavepoint)
+		rollbackStatement = fmt.Sprint("ROLLBACK TO SAVEPOINT ", timingSavepoint)
+	}
+
+	if _, err = connection.ExecContext(db.ctx, beginStatement); err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to start transaction for timing"),
+			err,
+		)
+	}
+	defer func() {
+		if _, rollbackErr := connection.ExecContext(db.ctx, rollbackStatement); rollbackErr != nil {
+			result = nil
+			err = errors.Join(
+				err,
+				errors.New("Failed to roll back timed statement"),
+				rollbackErr,
+			)
+		}
+	}()
+
+	var totalTime, serverTime time.Duration
+
+	switch db.connManager.GetFlavor() {
+	case conn.PostgreSQL:
+		{
+			// EXPLAIN ANALYZE runs the statement and reports how long the server spent planning and executing it
+			explainStatement := &StatementWithParams{
+				fmt.Sprint("EXPLAIN (ANALYZE, FORMAT JSON) ", statementWithParams.statement),
+				statementWithParams.params,
+			}
+
+			startTime := time.Now()
+			explainResult, err := db.runStatement(explainStatement)
+			totalTime = time.Since(startTime)
+			if err != nil {
+				return nil, err
+			}
+
+			if explainResult == nil || len(explainResult.Rows) == 0 || len(explainResult.Columns) == 0 {
+				return nil, errors.New("EXPLAIN ANALYZE returned no plan")
+			}
+
+			serverTime, err = parsePostgresExplainTime(
+				explainResult.Rows[0][explainResult.Columns[0]].ToString(),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case conn.MySQL:
+		{
+			startTime := time.Now()
+			_, err := db.runStatement(statementWithParams)
+			totalTime = time.Since(startTime)
+			if err != nil {
+				return nil, err
+			}
+
+			var timerWait uint64
+			err = connection.QueryRowxContext(db.ctx, mySQLLastStatementTimerQuery).Scan(&timerWait)
+			if err != nil {
+				return nil, errors.Join(
+					errors.New("Failed to read statement time from performance_schema"),
+					err,
+				)
+			}
+
+			serverTime = time.Duration(timerWait/1000) * time.Nanosecond
+		}
+	default:
+		{
+			return nil, commandNotSupportedError(`\timing`, db.connManager.GetFlavor())
+		}
+	}
+
+	return newTextResult(
+		[]string{"Total", "Server", "Network & Client"},
+		[][]string{buildTimingRow(totalTime, serverTime)},
+	), nil
+}
+
+// Whether MySQL commits the statement, and the transaction it's in, as soon as it runs
+func mySQLCommitsImplicitly(statement string) bool {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(conn.MySQL, statement))
+	return len(tokens) > 0 && mySQLImplicitCommitKeywords[strings.ToUpper(tokens[0].Text)]
+}
+
+// Total planning and execution time from the output of EXPLAIN (ANALYZE, FORMAT JSON)
+func parsePostgresExplainTime(plan string) (time.Duration, error) {
+	var explainOutput []struct {
+		PlanningTime  float64 `json:"Planning Time"`
+		ExecutionTime float64 `json:"Execution Time"`
+	}
+
+	err := json.Unmarshal([]byte(plan), &explainOutput)
+	if err != nil {
+		return 0, errors.Join(
+			errors.New("Failed to parse EXPLAIN ANALYZE output"),
+			err,
+		)
+	}
+	if len(explainOutput) == 0 {
+		return 0, errors.New("EXPLAIN ANALYZE returned no plan")
+	}
+
+	// Reported in milliseconds
+	milliseconds := explainOutput[0].PlanningTime + explainOutput[0].ExecutionTime
+	return time.Duration(milliseconds * float64(time.Millisecond)), nil
+}
+
+// Whatever part of the total the server doesn't account for was spent on the network and in the client
+func buildTimingRow(totalTime time.Duration, serverTime time.Duration) []string {
+	otherTime := max(totalTime-serverTime, 0)
+
+	return []string{
+		formatTimingDuration(totalTime),
+		formatTimingDuration(serverTime),
+		formatTimingDuration(otherTime),
+	}
+}
+
+func formatTimingDuration(duration time.Duration) string {
+	return duration.Round(time.Microsecond).String()
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePostgresExplainTime(t *testing.T) {
+	var tests = []struct {
+		Name          string
+		Plan          string
+		ExpectedTime  time.Duration
+		ExpectedError bool
+	}{
+		{
+			Name:         "Planning and Execution",
+			Plan:         `[{"Plan": {"Node Type": "Result"}, "Planning Time": 0.25, "Triggers": [], "Execution Time": 1.5}]`,
+			ExpectedTime: 1750 * time.Microsecond,
+		},
+		{
+			Name:          "Empty Plan",
+			Plan:          `[]`,
+			ExpectedError: true,
+		},
+		{
+			Name:          "Not JSON",
+			Plan:          `Seq Scan on foo`,
+			ExpectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			serverTime, err := parsePostgresExplainTime(test.Plan)
+			if test.ExpectedError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.ExpectedTime, serverTime)
+		})
+	}
+}
+
+func TestBuildTimingRow(t *testing.T) {
+	var tests = []struct {
+		Name        string
+		TotalTime   time.Duration
+		ServerTime  time.Duration
+		ExpectedRow []string
+	}{
+		{
+			Name:        "Network Latency",
+			TotalTime:   25 * time.Millisecond,
+			ServerTime:  1500 * time.Microsecond,
+			ExpectedRow: []string{"25ms", "1.5ms", "23.5ms"},
+		},
+		{
+			Name:        "Server Clock Ahead",
+			TotalTime:   time.Millisecond,
+			ServerTime:  2 * time.Millisecond,
+			ExpectedRow: []string{"1ms", "2ms", "0s"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.ExpectedRow, buildTimingRow(test.TotalTime, test.ServerTime))
+		})
+	}
+}
+
+func TestMySQLCommitsImplicitly(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(mySQLCommitsImplicitly("DROP TABLE users"))
+	assert.True(mySQLCommitsImplicitly("  alter table users add column age int"))
+	assert.True(mySQLCommitsImplicitly("/* cleanup */ TRUNCATE users"))
+	assert.False(mySQLCommitsImplicitly("DELETE FROM users WHERE id = 1"))
+	assert.False(mySQLCommitsImplicitly("SELECT * FROM users"))
+	assert.False(mySQLCommitsImplicitly(""))
+}
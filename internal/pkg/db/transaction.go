@@ -0,0 +1,122 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Statements starting or ending a transaction, tracked so the client knows when one is open
+var (
+	beginTransactionRegExp    = regexp.MustCompile(`(?i)^\s*(BEGIN|START\s+TRANSACTION)\s*;?\s*$`)
+	commitTransactionRegExp   = regexp.MustCompile(`(?i)^\s*(COMMIT|END)\s*;?\s*$`)
+	rollbackTransactionRegExp = regexp.MustCompile(`(?i)^\s*ROLLBACK\s*;?\s*$`)
+)
+
+var errConnectionLostInTransaction = errors.New("Connection to the database was lost, the transaction was rolled back")
+
+// Whether statements are currently running inside a transaction
+func (db *DBClient) InTransaction() bool {
+	return db.transactionConn != nil
+}
+
+// Start a transaction, which all following statements run inside of until it's committed or rolled back
+func (db *DBClient) BeginTransaction() error {
+	if db.InTransaction() {
+		return errors.New("Already in a transaction")
+	}
+
+	conn, err := db.connManager.GetConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(db.ctx, "BEGIN")
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to start transaction"),
+			err,
+		)
+	}
+
+	db.transactionConn = conn
+	return nil
+}
+
+func (db *DBClient) CommitTransaction() error {
+	return db.endTransaction("COMMIT")
+}
+
+func (db *DBClient) RollbackTransaction() error {
+	return db.endTransaction("ROLLBACK")
+}
+
+func (db *DBClient) endTransaction(statement string) error {
+	if !db.InTransaction() {
+		return errors.New("Not in a transaction")
+	}
+
+	conn, err := db.getTransactionConnection()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(db.ctx, statement)
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to %s transaction", strings.ToLower(statement)),
+			err,
+		)
+	}
+
+	db.transactionConn = nil
+	// A rollback undoes SET search_path, and a commit ends SET LOCAL
+	db.invalidateSearchPath()
+	return nil
+}
+
+// Get the connection to run a statement on
+// While in a transaction it must be the same connection the transaction began on, as the transaction is lost when reconnecting
+func (db *DBClient) getTransactionConnection() (*sqlx.Conn, error) {
+	conn, err := db.connManager.GetConnection()
+	if err != nil {
+		if db.InTransaction() {
+			db.transactionConn = nil
+			return nil, errors.Join(errConnectionLostInTransaction, err)
+		}
+
+		return nil, err
+	}
+
+	if db.InTransaction() && conn != db.transactionConn {
+		db.transactionConn = nil
+		return nil, errConnectionLostInTransaction
+	}
+
+	return conn, nil
+}
+
+// Handle statements starting or ending a transaction, so it's tracked the same as when using the methods above
+func (db *DBClient) runTransactionStatement(statement string) (isTransactionStatement bool, err error) {
+	switch {
+	case beginTransactionRegExp.MatchString(statement):
+		{
+			return true, db.BeginTransaction()
+		}
+	case commitTransactionRegExp.MatchString(statement):
+		{
+			return true, db.CommitTransaction()
+		}
+	case rollbackTransactionRegExp.MatchString(statement):
+		{
+			return true, db.RollbackTransaction()
+		}
+	default:
+		{
+			return false, nil
+		}
+	}
+}
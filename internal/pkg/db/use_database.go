@@ -0,0 +1,69 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// USE name, USE "name" or USE `name`
+var useDatabaseRegExp = regexp.MustCompile("(?i)^USE\\s+(?:\"([^\"]+)\"|`([^`]+)`|([\\w$-]+))\\s*;?$")
+
+// Database a USE statement switches to, ex: USE analytics
+// Postgres folds unquoted names to lowercase, as it would in a connection string
+func parseUseStatement(flavor conn.DBFlavor, statement string) (databaseName string, isUse bool) {
+	matches := useDatabaseRegExp.FindStringSubmatch(strings.TrimSpace(statement))
+	if matches == nil {
+		return "", false
+	}
+
+	if matches[3] == "" {
+		return matches[1] + matches[2], true
+	}
+
+	if flavor == conn.PostgreSQL {
+		return strings.ToLower(matches[3]), true
+	}
+
+	return matches[3], true
+}
+
+// \use <database>, or USE <database>
+// Connect to another database on the same server, which is kept when reconnecting
+// Postgres has no USE statement, and in MySQL it would only switch the current connection
+func (db *DBClient) useCommand(argument string) (*QueryResult, error) {
+	databaseName, isUse := parseUseStatement(db.connManager.GetFlavor(), fmt.Sprint("USE ", argument))
+	if !isUse {
+		return nil, errors.New(`Usage: \use <database>`)
+	}
+
+	return db.useDatabase(databaseName)
+}
+
+func (db *DBClient) useDatabase(databaseName string) (*QueryResult, error) {
+	// The transaction would be lost with the connection
+	if db.InTransaction() {
+		return nil, errors.New("Commit or roll back the sandbox before switching database")
+	}
+
+	previousDatabase := db.connManager.GetConnectionInfo().Database
+	if err := db.connManager.UseDatabase(databaseName); err != nil {
+		return nil, err
+	}
+
+	// Everything loaded so far was for the previous database
+	db.invalidateSearchPath()
+	db.resultCache.invalidate()
+	db.tempObjects = nil
+	db.recordContextSwitch(databaseSetting, previousDatabase, databaseName)
+
+	message := fmt.Sprint("Switched to ", databaseName)
+	if previousDatabase != "" {
+		message = fmt.Sprint(message, " from ", previousDatabase)
+	}
+
+	return newTextResult([]string{"Database"}, [][]string{{message}}), nil
+}
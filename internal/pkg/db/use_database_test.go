@@ -0,0 +1,147 @@
+package db
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+// Stands in for a connection manager, switching database without connecting to anything
+type fakeConnManager struct {
+	// PostgreSQL when empty
+	flavor   conn.DBFlavor
+	database string
+	// Returned by UseDatabase, when set
+	useDatabaseErr error
+	safeMode       bool
+}
+
+func (fake *fakeConnManager) GetConnection() (*sqlx.Conn, error) {
+	return nil, conn.ErrConnectionFailed
+}
+
+func (fake *fakeConnManager) GetFlavor() conn.DBFlavor {
+	if fake.flavor == "" {
+		return conn.PostgreSQL
+	}
+
+	return fake.flavor
+}
+
+func (fake *fakeConnManager) GetConnectionInfo() conn.ConnectionInfo {
+	return conn.ConnectionInfo{Flavor: fake.GetFlavor(), Database: fake.database}
+}
+
+func (fake *fakeConnManager) UseDatabase(databaseName string) error {
+	if fake.useDatabaseErr != nil {
+		return fake.useDatabaseErr
+	}
+
+	fake.database = databaseName
+	return nil
+}
+
+func (fake *fakeConnManager) SetSearchPath(string) error { return nil }
+func (fake *fakeConnManager) IsSafeMode() bool           { return fake.safeMode }
+func (fake *fakeConnManager) SetOpenRows(io.Closer)      {}
+func (fake *fakeConnManager) CloseOpenRows()             {}
+func (fake *fakeConnManager) Destroy()                   {}
+
+func (fake *fakeConnManager) SetSafeMode(enabled bool) error {
+	fake.safeMode = enabled
+	return nil
+}
+
+func TestParseUseStatement(t *testing.T) {
+	var tests = []struct {
+		Flavor               conn.DBFlavor
+		Statement            string
+		ExpectedDatabaseName string
+		ExpectedIsUse        bool
+	}{
+		{conn.MySQL, "USE Analytics;", "Analytics", true},
+		{conn.MySQL, "use `sales-2024`", "sales-2024", true},
+		{conn.PostgreSQL, "USE Analytics", "analytics", true},
+		{conn.PostgreSQL, `use "Analytics" ;`, "Analytics", true},
+		{conn.PostgreSQL, "USE", "", false},
+		{conn.PostgreSQL, "USE analytics, sales", "", false},
+		{conn.PostgreSQL, "SELECT 1", "", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Statement, func(t *testing.T) {
+			assert := assert.New(t)
+
+			databaseName, isUse := parseUseStatement(test.Flavor, test.Statement)
+			assert.Equal(test.ExpectedDatabaseName, databaseName)
+			assert.Equal(test.ExpectedIsUse, isUse)
+		})
+	}
+}
+
+func TestUseDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	connManager := &fakeConnManager{database: "test"}
+	dbClient, err := CreateDBClient(connManager)
+	if !assert.NoError(err) {
+		return
+	}
+
+	dbClient.tempObjects = []tempObject{{name: "totals", kind: "TABLE"}}
+
+	result, err := dbClient.Query("USE Analytics;")
+	assert.NoError(err)
+	assert.Equal("Switched to analytics from test", result.Rows[0]["Database"].ToString())
+	assert.Equal("analytics", connManager.database)
+	assert.Empty(dbClient.tempObjects, "Temporary objects go away with the connection")
+
+	connManager.useDatabaseErr = errors.New("Failed to switch database")
+	_, err = dbClient.Query(`\use sales`)
+	assert.ErrorIs(err, connManager.useDatabaseErr)
+	assert.Equal("analytics", connManager.database)
+
+	_, err = dbClient.Query(`\use`)
+	assert.ErrorContains(err, "Usage")
+}
+
+func TestSwitchesCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	connManager := &fakeConnManager{database: "test"}
+	dbClient, err := CreateDBClient(connManager)
+	if !assert.NoError(err) {
+		return
+	}
+
+	_, err = dbClient.Query("USE analytics;")
+	assert.NoError(err)
+	_, err = dbClient.Query(`\use sales`)
+	assert.NoError(err)
+
+	// Failed switches leave the database as it was
+	connManager.useDatabaseErr = errors.New("Failed to switch database")
+	_, err = dbClient.Query(`\use billing`)
+	assert.Error(err)
+
+	result, err := dbClient.Query(`\switches`)
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.Equal([]string{"Switched At", "Setting", "From", "To"}, result.Columns)
+	if !assert.Len(result.Rows, 2) {
+		return
+	}
+	assert.Equal("database", result.Rows[0]["Setting"].ToString())
+	assert.Equal("test", result.Rows[0]["From"].ToString())
+	assert.Equal("analytics", result.Rows[0]["To"].ToString())
+	assert.Equal("analytics", result.Rows[1]["From"].ToString())
+	assert.Equal("sales", result.Rows[1]["To"].ToString())
+}
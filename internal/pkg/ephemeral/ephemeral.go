@@ -0,0 +1,152 @@
+// Disposable databases in a local container, for experimenting without a real database
+package ephemeral
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mysql"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Credentials don't need to be secret, the database is thrown away on exit
+const (
+	databaseName = "scratch"
+	password     = "scratch"
+	// Images can take a while to pull the first time
+	startupTimeout = 3 * time.Minute
+)
+
+// A database running in a container, which only lasts until terminated
+type Database struct {
+	ConnOptions conn.DSNOptions
+	container   testcontainers.Container
+}
+
+// Work out the flavor of an image, ex: mysql:8.4 or postgres:16
+func GetImageFlavor(image string) (conn.DBFlavor, error) {
+	name, _, _ := strings.Cut(path.Base(image), ":")
+
+	switch strings.ToLower(name) {
+	case "mysql":
+		{
+			return conn.MySQL, nil
+		}
+	case "postgres":
+		{
+			return conn.PostgreSQL, nil
+		}
+	default:
+		{
+			return "", fmt.Errorf("Unsupported image %s, expected a mysql or postgres image, ex: mysql:8.4", image)
+		}
+	}
+}
+
+// Start a container from the image, waiting until it accepts connections
+func Start(ctx context.Context, image string) (*Database, error) {
+	flavor, err := GetImageFlavor(image)
+	if err != nil {
+		return nil, err
+	}
+
+	database := &Database{
+		ConnOptions: conn.DSNOptions{
+			Flavor:       flavor,
+			DatabaseName: databaseName,
+			Password:     password,
+		},
+	}
+
+	var containerPort nat.Port
+	switch flavor {
+	case conn.MySQL:
+		{
+			containerPort = "3306/tcp"
+			// Root, so databases and users can be created freely
+			database.ConnOptions.User = "root"
+			database.container, err = mysql.RunContainer(
+				ctx,
+				testcontainers.WithImage(image),
+				mysql.WithDatabase(databaseName),
+				mysql.WithUsername(database.ConnOptions.User),
+				mysql.WithPassword(password),
+				database.waitForConnections(containerPort),
+			)
+		}
+	case conn.PostgreSQL:
+		{
+			containerPort = "5432/tcp"
+			database.ConnOptions.User = "postgres"
+			database.container, err = postgres.RunContainer(
+				ctx,
+				testcontainers.WithImage(image),
+				postgres.WithDatabase(databaseName),
+				postgres.WithUsername(database.ConnOptions.User),
+				postgres.WithPassword(password),
+				database.waitForConnections(containerPort),
+			)
+		}
+	}
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Failed to start %s, is Docker running?", image),
+			err,
+		)
+	}
+
+	host, err := database.container.Host(ctx)
+	if err == nil {
+		database.ConnOptions.Host = host
+
+		var mappedPort nat.Port
+		mappedPort, err = database.container.MappedPort(ctx, containerPort)
+		database.ConnOptions.Port = uint(mappedPort.Int())
+	}
+	if err != nil {
+		database.Terminate(ctx)
+		return nil, errors.Join(
+			fmt.Errorf("Failed to find where %s is listening", image),
+			err,
+		)
+	}
+
+	return database, nil
+}
+
+// Remove the container, along with everything in the database
+func (database *Database) Terminate(ctx context.Context) error {
+	if err := database.container.Terminate(ctx); err != nil {
+		return errors.Join(
+			errors.New("Failed to remove ephemeral database container"),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// The container logs being ready before it accepts connections, so wait until a query succeeds
+func (database *Database) waitForConnections(port nat.Port) testcontainers.CustomizeRequestOption {
+	return testcontainers.WithWaitStrategy(
+		wait.
+			ForSQL(port, string(database.ConnOptions.Flavor), func(host string, port nat.Port) string {
+				connOptions := database.ConnOptions
+				connOptions.Host = host
+				connOptions.Port = uint(port.Int())
+
+				dsn, _ := connOptions.GetDSN()
+				return dsn
+			}).
+			WithStartupTimeout(startupTimeout).
+			WithQuery("SELECT 1"),
+	)
+}
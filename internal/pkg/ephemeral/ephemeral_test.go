@@ -0,0 +1,40 @@
+package ephemeral
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetImageFlavor(t *testing.T) {
+	var tests = []struct {
+		Image          string
+		ExpectedFlavor conn.DBFlavor
+		ExpectError    bool
+	}{
+		{Image: "mysql:8.4", ExpectedFlavor: conn.MySQL},
+		{Image: "mysql", ExpectedFlavor: conn.MySQL},
+		{Image: "postgres:16", ExpectedFlavor: conn.PostgreSQL},
+		{Image: "docker.io/library/postgres:16-alpine", ExpectedFlavor: conn.PostgreSQL},
+		{Image: "registry.example.com:5000/mysql:8.0", ExpectedFlavor: conn.MySQL},
+		{Image: "redis:7", ExpectError: true},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Image, func(t *testing.T) {
+			assert := assert.New(t)
+
+			flavor, err := GetImageFlavor(test.Image)
+			if test.ExpectError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.ExpectedFlavor, flavor)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package format
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// CSV or TSV, quoting values which hold the delimiter, a quote or a newline
+type delimitedWriter struct {
+	writer  *csv.Writer
+	columns []string
+}
+
+func newDelimitedWriter(writer io.Writer, delimiter rune, columns []string) (*delimitedWriter, error) {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Comma = delimiter
+
+	if err := csvWriter.Write(columns); err != nil {
+		return nil, err
+	}
+
+	return &delimitedWriter{writer: csvWriter, columns: columns}, nil
+}
+
+func (delimitedWriter *delimitedWriter) WriteRow(row map[string]*db.NullString) error {
+	return delimitedWriter.writer.Write(rowValues(delimitedWriter.columns, row))
+}
+
+func (delimitedWriter *delimitedWriter) Close() error {
+	delimitedWriter.writer.Flush()
+	return delimitedWriter.writer.Error()
+}
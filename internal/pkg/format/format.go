@@ -0,0 +1,107 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// How a query result is rendered, such as when printed to stdout or saved to a file
+type Format string
+
+const (
+	// Bordered text table, as the mysql client prints
+	Table Format = "table"
+	CSV   Format = "csv"
+	// Tab separated, quoted as CSV is when a value holds a tab, quote or newline
+	TSV  Format = "tsv"
+	JSON Format = "json"
+	// A JSON object per row, a line each
+	JSONL Format = "jsonl"
+	// GitHub flavored table
+	Markdown Format = "markdown"
+)
+
+var Formats = []Format{Table, CSV, TSV, JSON, JSONL, Markdown}
+
+// Every format results can be rendered in for messages, ex: table, csv, tsv
+func List() string {
+	formats := RendererFormats()
+
+	names := make([]string, len(formats))
+	for idx, format := range formats {
+		names[idx] = string(format)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// Writes a result a row at a time, so it needn't be held in memory to be written
+// Formats which need every row up front, such as Table, hold them until closed
+type RowWriter interface {
+	WriteRow(row map[string]*db.NullString) error
+	// Finish the output once every row has been written
+	Close() error
+}
+
+// Start writing a result with the given columns, the header is written straight away when the format has one
+func NewRowWriter(writer io.Writer, format Format, columns []string) (RowWriter, error) {
+	switch format {
+	case Table:
+		{
+			return newTableWriter(writer, columns), nil
+		}
+	case CSV:
+		{
+			return newDelimitedWriter(writer, ',', columns)
+		}
+	case TSV:
+		{
+			return newDelimitedWriter(writer, '\t', columns)
+		}
+	case JSON:
+		{
+			return newJSONWriter(writer, columns, false), nil
+		}
+	case JSONL:
+		{
+			return newJSONWriter(writer, columns, true), nil
+		}
+	case Markdown:
+		{
+			return newMarkdownWriter(writer, columns), nil
+		}
+	default:
+		{
+			return nil, fmt.Errorf("Unknown format %s", format)
+		}
+	}
+}
+
+// Write every row of a result read so far
+func Write(writer io.Writer, format Format, result *db.QueryResult) error {
+	rowWriter, err := NewRowWriter(writer, format, result.Columns)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range result.Rows {
+		if err := rowWriter.WriteRow(row); err != nil {
+			return err
+		}
+	}
+
+	return rowWriter.Close()
+}
+
+// Values of a row in column order, NULL shown as such
+func rowValues(columns []string, row map[string]*db.NullString) []string {
+	values := make([]string, len(columns))
+	for columnIdx, columnName := range columns {
+		values[columnIdx] = row[columnName].ToString()
+	}
+
+	return values
+}
@@ -0,0 +1,100 @@
+package format
+
+import (
+	"bytes"
+	"database/sql"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func newValue(value string) *db.NullString {
+	return &db.NullString{NullString: sql.NullString{String: value, Valid: true}}
+}
+
+var testResult = &db.QueryResult{
+	Columns: []string{"name", "note"},
+	Rows: []map[string]*db.NullString{
+		{"name": newValue("Ada"), "note": newValue(`said "hi", then left`)},
+		{"name": newValue("Ünal"), "note": newValue("line one\nline | two")},
+		{"name": newValue("Bo"), "note": {}},
+	},
+}
+
+func TestWrite(t *testing.T) {
+	var tests = []struct {
+		Format   Format
+		Expected string
+	}{
+		{
+			Format: Table,
+			Expected: "+------+----------------------+\n" +
+				"| name | note                 |\n" +
+				"+------+----------------------+\n" +
+				"| Ada  | said \"hi\", then left |\n" +
+				"| Ünal | line one\\nline | two |\n" +
+				"| Bo   | NULL                 |\n" +
+				"+------+----------------------+\n",
+		},
+		{
+			Format:   CSV,
+			Expected: "name,note\nAda,\"said \"\"hi\"\", then left\"\nÜnal,\"line one\nline | two\"\nBo,NULL\n",
+		},
+		{
+			Format:   TSV,
+			Expected: "name\tnote\nAda\t\"said \"\"hi\"\", then left\"\nÜnal\t\"line one\nline | two\"\nBo\tNULL\n",
+		},
+		{
+			Format: JSON,
+			Expected: `[{"name":"Ada","note":"said \"hi\", then left"},` +
+				`{"name":"Ünal","note":"line one\nline | two"},` +
+				`{"name":"Bo","note":null}]` + "\n",
+		},
+		{
+			Format: JSONL,
+			Expected: `{"name":"Ada","note":"said \"hi\", then left"}` + "\n" +
+				`{"name":"Ünal","note":"line one\nline | two"}` + "\n" +
+				`{"name":"Bo","note":null}` + "\n",
+		},
+		{
+			Format: Markdown,
+			Expected: "| name | note                 |\n" +
+				"| ---- | -------------------- |\n" +
+				"| Ada  | said \"hi\", then left |\n" +
+				"| Ünal | line one line \\| two |\n" +
+				"| Bo   | NULL                 |\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(string(test.Format), func(t *testing.T) {
+			assert := assert.New(t)
+
+			var output bytes.Buffer
+			assert.NoError(Write(&output, test.Format, testResult))
+			assert.Equal(test.Expected, output.String())
+		})
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	emptyResult := &db.QueryResult{Columns: []string{"id"}}
+
+	var output bytes.Buffer
+	assert.NoError(Write(&output, JSON, emptyResult))
+	assert.Equal("[]\n", output.String())
+
+	output.Reset()
+	assert.NoError(Write(&output, JSONL, emptyResult))
+	assert.Equal("", output.String())
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewRowWriter(&bytes.Buffer{}, "xml", []string{"id"})
+	assert.ErrorContains(t, err, "Unknown format xml")
+}
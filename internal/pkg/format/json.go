@@ -0,0 +1,84 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// An object per row with keys in column order, as an array or a line each
+type jsonWriter struct {
+	writer  io.Writer
+	columns []string
+	// A line per row rather than an array
+	lines    bool
+	rowCount int
+}
+
+func newJSONWriter(writer io.Writer, columns []string, lines bool) *jsonWriter {
+	return &jsonWriter{writer: writer, columns: columns, lines: lines}
+}
+
+func (jsonWriter *jsonWriter) WriteRow(row map[string]*db.NullString) error {
+	var encoded bytes.Buffer
+
+	switch {
+	case jsonWriter.lines:
+		{
+		}
+	case jsonWriter.rowCount == 0:
+		{
+			encoded.WriteRune('[')
+		}
+	default:
+		{
+			encoded.WriteRune(',')
+		}
+	}
+
+	// Marshalling the row map would sort keys, rather than keep them in column order
+	encoded.WriteRune('{')
+	for columnIdx, columnName := range jsonWriter.columns {
+		if columnIdx > 0 {
+			encoded.WriteRune(',')
+		}
+
+		key, err := json.Marshal(columnName)
+		if err != nil {
+			return err
+		}
+		value, err := row[columnName].MarshalJSON()
+		if err != nil {
+			return err
+		}
+
+		encoded.Write(key)
+		encoded.WriteRune(':')
+		encoded.Write(value)
+	}
+	encoded.WriteRune('}')
+
+	if jsonWriter.lines {
+		encoded.WriteRune('\n')
+	}
+	jsonWriter.rowCount++
+
+	_, err := jsonWriter.writer.Write(encoded.Bytes())
+	return err
+}
+
+func (jsonWriter *jsonWriter) Close() error {
+	if jsonWriter.lines {
+		return nil
+	}
+
+	closing := "]\n"
+	if jsonWriter.rowCount == 0 {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(jsonWriter.writer, closing)
+	return err
+}
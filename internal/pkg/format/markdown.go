@@ -0,0 +1,28 @@
+package format
+
+import (
+	"io"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// GitHub flavored table, see db.QueryResult.ToMarkdown
+// Rows are held until closed, as every value is needed to line up the pipes
+type markdownWriter struct {
+	writer io.Writer
+	result db.QueryResult
+}
+
+func newMarkdownWriter(writer io.Writer, columns []string) *markdownWriter {
+	return &markdownWriter{writer: writer, result: db.QueryResult{Columns: columns}}
+}
+
+func (markdownWriter *markdownWriter) WriteRow(row map[string]*db.NullString) error {
+	markdownWriter.result.Rows = append(markdownWriter.result.Rows, row)
+	return nil
+}
+
+func (markdownWriter *markdownWriter) Close() error {
+	_, err := markdownWriter.writer.Write(markdownWriter.result.ToMarkdown())
+	return err
+}
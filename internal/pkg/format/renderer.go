@@ -0,0 +1,88 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// Renders whole results one after another, such as the result of each statement run with -e or from stdin
+type Renderer interface {
+	Write(result *db.QueryResult) error
+	// Finish the output once every result has been written
+	Flush() error
+}
+
+// Creates a renderer writing to the given output
+type NewRendererFunc func(writer io.Writer) Renderer
+
+var (
+	renderersMutex sync.RWMutex
+	// By format, the built in formats along with any registered
+	renderers = make(map[Format]NewRendererFunc)
+	// Formats registered outside this package, in the order they were
+	registeredFormats []Format
+)
+
+func init() {
+	for _, format := range Formats {
+		renderers[format] = func(writer io.Writer) Renderer {
+			return &rowRenderer{writer: writer, format: format}
+		}
+	}
+}
+
+// Add a format results can be rendered in, such as one built outside this package
+// Panics when the format is taken, as database/sql does for drivers, since it's a mistake in the program
+func RegisterRenderer(format Format, newRenderer NewRendererFunc) {
+	renderersMutex.Lock()
+	defer renderersMutex.Unlock()
+
+	if newRenderer == nil {
+		panic(fmt.Sprintf("format: RegisterRenderer for %s is nil", format))
+	}
+	if _, exists := renderers[format]; exists {
+		panic(fmt.Sprintf("format: RegisterRenderer called twice for %s", format))
+	}
+
+	renderers[format] = newRenderer
+	registeredFormats = append(registeredFormats, format)
+}
+
+// Start rendering results in the given format
+func NewRenderer(writer io.Writer, format Format) (Renderer, error) {
+	renderersMutex.RLock()
+	defer renderersMutex.RUnlock()
+
+	newRenderer, exists := renderers[format]
+	if !exists {
+		return nil, fmt.Errorf("Unknown format %s", format)
+	}
+
+	return newRenderer(writer), nil
+}
+
+// Every format results can be rendered in, the built in formats first
+func RendererFormats() []Format {
+	renderersMutex.RLock()
+	defer renderersMutex.RUnlock()
+
+	return slices.Concat(Formats, registeredFormats)
+}
+
+// Renders a built in format, each result as it's written, so there's nothing to flush
+type rowRenderer struct {
+	writer io.Writer
+	format Format
+}
+
+func (renderer *rowRenderer) Write(result *db.QueryResult) error {
+	return Write(renderer.writer, renderer.format, result)
+}
+
+func (renderer *rowRenderer) Flush() error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+// Writes how many rows each result had, and how many results there were once flushed
+type countingRenderer struct {
+	writer  io.Writer
+	results int
+}
+
+func (renderer *countingRenderer) Write(result *db.QueryResult) error {
+	renderer.results++
+	_, err := fmt.Fprintf(renderer.writer, "%d rows\n", len(result.Rows))
+	return err
+}
+
+func (renderer *countingRenderer) Flush() error {
+	_, err := fmt.Fprintf(renderer.writer, "%d results\n", renderer.results)
+	return err
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	assert := assert.New(t)
+
+	countFormat := Format("count")
+	RegisterRenderer(countFormat, func(writer io.Writer) Renderer {
+		return &countingRenderer{writer: writer}
+	})
+
+	assert.Equal(append(slices.Clone(Formats), countFormat), RendererFormats())
+	assert.Contains(List(), "markdown, count")
+
+	var output bytes.Buffer
+	renderer, err := NewRenderer(&output, countFormat)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NoError(renderer.Write(testResult))
+	assert.NoError(renderer.Write(testResult))
+	assert.NoError(renderer.Flush())
+	assert.Equal("3 rows\n3 rows\n2 results\n", output.String())
+
+	assert.Panics(func() {
+		RegisterRenderer(CSV, func(writer io.Writer) Renderer { return nil })
+	}, "Built in formats can't be replaced")
+
+	_, err = NewRenderer(&output, Format("xml"))
+	assert.ErrorContains(err, "Unknown format xml")
+}
+
+func TestNewRendererBuiltIn(t *testing.T) {
+	assert := assert.New(t)
+
+	var output bytes.Buffer
+	renderer, err := NewRenderer(&output, CSV)
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.NoError(renderer.Write(testResult))
+	assert.NoError(renderer.Flush())
+
+	var expected bytes.Buffer
+	assert.NoError(Write(&expected, CSV, testResult))
+	assert.Equal(expected.String(), output.String())
+}
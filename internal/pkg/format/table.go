@@ -0,0 +1,96 @@
+package format
+
+import (
+	"io"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/rivo/uniseg"
+)
+
+// Bordered text table, as the mysql client prints
+// Rows are held until closed, as every value is needed to size the columns
+// ex:
+// +----+-------+
+// | id | name  |
+// +----+-------+
+// | 1  | Alice |
+// +----+-------+
+type tableWriter struct {
+	writer  io.Writer
+	columns []string
+	rows    [][]string
+}
+
+func newTableWriter(writer io.Writer, columns []string) *tableWriter {
+	return &tableWriter{writer: writer, columns: columns}
+}
+
+func (tableWriter *tableWriter) WriteRow(row map[string]*db.NullString) error {
+	values := rowValues(tableWriter.columns, row)
+	for idx, value := range values {
+		// Newlines would break the table apart
+		values[idx] = strings.ReplaceAll(value, "\n", "\\n")
+	}
+
+	tableWriter.rows = append(tableWriter.rows, values)
+	return nil
+}
+
+func (tableWriter *tableWriter) Close() error {
+	widths := getColumnWidths(tableWriter.columns, tableWriter.rows)
+
+	var table strings.Builder
+
+	writeBorder := func() {
+		table.WriteRune('+')
+		for _, width := range widths {
+			table.WriteString(strings.Repeat("-", width+2))
+			table.WriteRune('+')
+		}
+		table.WriteRune('\n')
+	}
+	writeRow := func(values []string) {
+		table.WriteRune('|')
+		for columnIdx, value := range values {
+			table.WriteRune(' ')
+			table.WriteString(padCell(value, widths[columnIdx]))
+			table.WriteString(" |")
+		}
+		table.WriteRune('\n')
+	}
+
+	writeBorder()
+	writeRow(tableWriter.columns)
+	writeBorder()
+	for _, row := range tableWriter.rows {
+		writeRow(row)
+	}
+	if len(tableWriter.rows) > 0 {
+		writeBorder()
+	}
+
+	_, err := io.WriteString(tableWriter.writer, table.String())
+	return err
+}
+
+// Widest value of each column, header included, in terminal cells
+func getColumnWidths(columns []string, rows [][]string) []int {
+	widths := make([]int, len(columns))
+	for columnIdx, columnName := range columns {
+		widths[columnIdx] = uniseg.StringWidth(columnName)
+	}
+
+	for _, row := range rows {
+		for columnIdx, value := range row {
+			widths[columnIdx] = max(widths[columnIdx], uniseg.StringWidth(value))
+		}
+	}
+
+	return widths
+}
+
+// Pad with spaces to fill width terminal cells
+func padCell(value string, width int) string {
+	return value + strings.Repeat(" ", width-uniseg.StringWidth(value))
+}
@@ -0,0 +1,145 @@
+package history
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Starts the first line of an encrypted history file, followed by the salt and a value to check the key with
+const encryptedHeaderPrefix = "sql-history-encrypted v1 "
+
+// Sealed into the header, so a wrong passphrase is reported rather than every entry being dropped as unreadable
+const keyCheckValue = "sql-history"
+
+const (
+	saltSize = 16
+	keySize  = 32
+	// scrypt cost parameters recommended for interactive use
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+func isEncryptedHeader(line []byte) bool {
+	return bytes.HasPrefix(line, []byte(encryptedHeaderPrefix))
+}
+
+// Derive a key for a new file, with a fresh salt
+func (file *File) createHeader(passphrase string) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return errors.Join(
+			errors.New("Failed to generate history encryption salt"),
+			err,
+		)
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	file.aead = aead
+
+	file.header = fmt.Appendf(
+		nil,
+		"%s%s %s\n",
+		encryptedHeaderPrefix,
+		base64.StdEncoding.EncodeToString(salt),
+		file.encryptLine([]byte(keyCheckValue)),
+	)
+
+	return nil
+}
+
+// Derive the key of an existing file from its header, checking the passphrase is the one it was saved with
+func (file *File) readHeader(header []byte, passphrase string) error {
+	fields := bytes.Fields(bytes.TrimPrefix(header, []byte(encryptedHeaderPrefix)))
+	if len(fields) != 2 {
+		return errors.New("Malformed encryption header")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(string(fields[0]))
+	if err != nil {
+		return errors.New("Malformed encryption header")
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	file.aead = aead
+
+	if checkValue, err := file.decryptLine(fields[1]); err != nil || string(checkValue) != keyCheckValue {
+		return errors.New("Wrong passphrase or key")
+	}
+	file.header = append(bytes.Clone(header), '\n')
+
+	return nil
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Seal a line with a fresh nonce, encoded so it stays on a single line
+func (file *File) encryptLine(line []byte) []byte {
+	nonce := make([]byte, file.aead.NonceSize())
+	// Never fails, see crypto/rand.Read
+	_, _ = rand.Read(nonce)
+
+	sealed := file.aead.Seal(nonce, nonce, line, nil)
+
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(encoded, sealed)
+
+	return encoded
+}
+
+func (file *File) decryptLine(line []byte) ([]byte, error) {
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	sealedLength, err := base64.StdEncoding.Decode(sealed, line)
+	if err != nil {
+		return nil, err
+	}
+	sealed = sealed[:sealedLength]
+
+	nonceSize := file.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("Encrypted line is too short")
+	}
+
+	return file.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
+
+// An encrypted file starts with its header, written along with the first entry
+func (file *File) writeHeaderIfEmpty(historyFile *os.File) error {
+	if file.header == nil {
+		return nil
+	}
+
+	info, err := historyFile.Stat()
+	if err != nil || info.Size() > 0 {
+		return err
+	}
+
+	_, err = historyFile.Write(file.header)
+	return err
+}
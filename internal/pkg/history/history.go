@@ -0,0 +1,207 @@
+package history
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A query as saved to the history file
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Query string    `json:"query"`
+}
+
+// How much saved history is kept, older entries are dropped first. 0 for no limit
+type Retention struct {
+	MaxEntries int
+	MaxAge     time.Duration
+}
+
+// Queries saved between sessions, one JSON object per line so each is appended as it's run
+// When encrypted, each line is sealed on its own, after a header line holding what the key is derived with
+type File struct {
+	path string
+	// Nil when entries are saved as plain text
+	aead cipher.AEAD
+	// First line of an encrypted file, empty when entries are saved as plain text
+	header []byte
+}
+
+// Read the saved history, oldest first, dropping entries beyond the retention limits from the file
+// A missing file is not an error, it's created when the first entry is saved
+func Open(path string, retention Retention, now time.Time) (*File, []Entry, error) {
+	return open(path, retention, "", now)
+}
+
+// Same as Open, with entries encrypted with a key derived from passphrase
+// A history saved before encryption was turned on is encrypted as it's read
+func OpenEncrypted(path string, retention Retention, passphrase string, now time.Time) (*File, []Entry, error) {
+	if passphrase == "" {
+		return nil, nil, errors.New("History passphrase must not be empty")
+	}
+
+	return open(path, retention, passphrase, now)
+}
+
+func open(path string, retention Retention, passphrase string, now time.Time) (*File, []Entry, error) {
+	file := &File{path: path}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, errors.Join(
+			fmt.Errorf("Failed to read history from %s", path),
+			err,
+		)
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	isEncrypted := isEncryptedHeader(lines[0])
+	isPruned := false
+
+	switch {
+	case isEncrypted && passphrase == "":
+		{
+			return nil, nil, fmt.Errorf("History in %s is encrypted, set history.encryption to read it", path)
+		}
+	case isEncrypted:
+		{
+			if err := file.readHeader(lines[0], passphrase); err != nil {
+				return nil, nil, errors.Join(
+					fmt.Errorf("Failed to decrypt history in %s", path),
+					err,
+				)
+			}
+			lines = lines[1:]
+		}
+	case passphrase != "":
+		{
+			if err := file.createHeader(passphrase); err != nil {
+				return nil, nil, err
+			}
+			// Saved as plain text before encryption was turned on
+			isPruned = len(content) > 0
+		}
+	}
+
+	var entries []Entry
+
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		if isEncrypted {
+			var err error
+			// Lines which can't be decrypted, such as one cut short by a crash, are dropped
+			if line, err = file.decryptLine(line); err != nil {
+				isPruned = true
+				continue
+			}
+		}
+
+		var entry Entry
+		// Lines which can't be read, such as one cut short by a crash, are dropped
+		if err := json.Unmarshal(line, &entry); err != nil {
+			isPruned = true
+			continue
+		}
+
+		if retention.MaxAge > 0 && now.Sub(entry.Time) > retention.MaxAge {
+			isPruned = true
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if retention.MaxEntries > 0 && len(entries) > retention.MaxEntries {
+		entries = entries[len(entries)-retention.MaxEntries:]
+		isPruned = true
+	}
+
+	if isPruned {
+		if err := file.rewrite(entries); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return file, entries, nil
+}
+
+// Save an entry to the end of the file, creating it if needed
+func (file *File) Append(entry Entry) error {
+	line, err := file.encodeEntry(entry)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(file.path), 0o700)
+	}
+
+	var historyFile *os.File
+	if err == nil {
+		// Queries may hold sensitive values, so only the user can read them
+		historyFile, err = os.OpenFile(file.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	}
+	if err == nil {
+		err = file.writeHeaderIfEmpty(historyFile)
+		if err == nil {
+			_, err = historyFile.Write(line)
+		}
+		err = errors.Join(err, historyFile.Close())
+	}
+
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to save history to %s", file.path),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Replace the file with only the entries given, through a temporary file so it's never left half written
+func (file *File) rewrite(entries []Entry) error {
+	var content bytes.Buffer
+	if file.header != nil {
+		content.Write(file.header)
+	}
+	for _, entry := range entries {
+		line, err := file.encodeEntry(entry)
+		if err != nil {
+			return err
+		}
+		content.Write(line)
+	}
+
+	temporaryPath := fmt.Sprint(file.path, ".tmp")
+	err := os.WriteFile(temporaryPath, content.Bytes(), 0o600)
+	if err == nil {
+		err = os.Rename(temporaryPath, file.path)
+	}
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to prune history in %s", file.path),
+			err,
+		)
+	}
+
+	return nil
+}
+
+func (file *File) encodeEntry(entry Entry) ([]byte, error) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.aead != nil {
+		line = file.encryptLine(line)
+	}
+
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,193 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/history"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpen(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	lines := []string{
+		`{"time":"2024-05-01T12:00:00Z","query":"SELECT 1;"}`,
+		`{"time":"2024-05-30T12:00:00Z","query":"SELECT 2;"}`,
+		`not json`,
+		`{"time":"2024-05-31T12:00:00Z","query":"SELECT 3;"}`,
+		`{"time":"2024-06-01T11:00:00Z","query":"SELECT 4;"}`,
+	}
+
+	var tests = []struct {
+		Name      string
+		Retention history.Retention
+		Expected  []string
+	}{
+		{
+			Name:     "No limits",
+			Expected: []string{"SELECT 1;", "SELECT 2;", "SELECT 3;", "SELECT 4;"},
+		},
+		{
+			Name:      "Max age",
+			Retention: history.Retention{MaxAge: 7 * day},
+			Expected:  []string{"SELECT 2;", "SELECT 3;", "SELECT 4;"},
+		},
+		{
+			Name:      "Max entries",
+			Retention: history.Retention{MaxEntries: 2},
+			Expected:  []string{"SELECT 3;", "SELECT 4;"},
+		},
+		{
+			Name:      "Max age and entries",
+			Retention: history.Retention{MaxEntries: 1, MaxAge: 7 * day},
+			Expected:  []string{"SELECT 4;"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			path := filepath.Join(t.TempDir(), "history")
+			err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600)
+			if !assert.NoError(err) {
+				return
+			}
+
+			_, entries, err := history.Open(path, test.Retention, now)
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(test.Expected, getQueries(entries))
+
+			// Dropped entries are removed from the file too
+			_, entries, err = history.Open(path, history.Retention{}, now)
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(test.Expected, getQueries(entries))
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "sql", "history")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Missing file and directory are created on the first append
+	file, entries, err := history.Open(path, history.Retention{}, now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Empty(entries)
+
+	assert.NoError(file.Append(history.Entry{Time: now, Query: "SELECT 1;"}))
+	assert.NoError(file.Append(history.Entry{Time: now, Query: "SELECT\n  2;"}))
+
+	info, err := os.Stat(path)
+	if assert.NoError(err) {
+		assert.Equal(os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	_, entries, err = history.Open(path, history.Retention{}, now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"SELECT 1;", "SELECT\n  2;"}, getQueries(entries))
+}
+
+func getQueries(entries []history.Entry) (queries []string) {
+	for _, entry := range entries {
+		queries = append(queries, entry.Query)
+	}
+
+	return queries
+}
+
+func TestOpenEncrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "history")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// Saved as plain text before encryption was turned on
+	plainFile, _, err := history.Open(path, history.Retention{}, now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NoError(plainFile.Append(history.Entry{Time: now, Query: "SELECT * FROM customers WHERE email = 'ada@example.com';"}))
+
+	file, entries, err := history.OpenEncrypted(path, history.Retention{}, "correct horse", now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"SELECT * FROM customers WHERE email = 'ada@example.com';"}, getQueries(entries))
+	assert.NoError(file.Append(history.Entry{Time: now, Query: "SELECT 2;"}))
+
+	// Cut short by a crash
+	historyFile, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if assert.NoError(err) {
+		_, err = historyFile.WriteString("c2VhbGVk\n")
+		assert.NoError(err)
+		assert.NoError(historyFile.Close())
+	}
+
+	contents, err := os.ReadFile(path)
+	if assert.NoError(err) {
+		assert.NotContains(string(contents), "ada@example.com")
+		assert.NotContains(string(contents), "SELECT")
+	}
+
+	_, entries, err = history.OpenEncrypted(path, history.Retention{}, "correct horse", now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"SELECT * FROM customers WHERE email = 'ada@example.com';", "SELECT 2;"}, getQueries(entries))
+
+	_, _, err = history.OpenEncrypted(path, history.Retention{}, "wrong horse", now)
+	assert.ErrorContains(err, "Wrong passphrase or key")
+
+	_, _, err = history.Open(path, history.Retention{}, now)
+	assert.ErrorContains(err, "is encrypted")
+
+	_, _, err = history.OpenEncrypted(path, history.Retention{}, "", now)
+	assert.Error(err)
+}
+
+func TestOpenEncryptedMissingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "sql", "history")
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	file, entries, err := history.OpenEncrypted(path, history.Retention{}, "correct horse", now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Empty(entries)
+
+	assert.NoError(file.Append(history.Entry{Time: now, Query: "SELECT 1;"}))
+	assert.NoError(file.Append(history.Entry{Time: now, Query: "SELECT 2;"}))
+
+	_, entries, err = history.OpenEncrypted(path, history.Retention{MaxEntries: 1}, "correct horse", now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"SELECT 2;"}, getQueries(entries))
+
+	// Still encrypted after being pruned
+	_, entries, err = history.OpenEncrypted(path, history.Retention{}, "correct horse", now)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal([]string{"SELECT 2;"}, getQueries(entries))
+}
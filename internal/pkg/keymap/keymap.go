@@ -0,0 +1,389 @@
+package keymap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// How the query text area is edited
+type Mode string
+
+const (
+	// Readline style kills and yanks on top of the text area's own keys
+	Emacs Mode = "emacs"
+	// Only the text area's own keys
+	Default Mode = "default"
+	// Normal, insert and visual modes as in vim, starting in insert mode
+	Vim Mode = "vim"
+)
+
+// Something the application does in response to a key, independent of which key
+type Action string
+
+const (
+	FocusResults     Action = "focus_results"
+	OpenBookmarks    Action = "open_bookmarks"
+	ScrollUp         Action = "scroll_up"
+	ScrollDown       Action = "scroll_down"
+	ScrollLeft       Action = "scroll_left"
+	ScrollRight      Action = "scroll_right"
+	ScrollPrevResult Action = "scroll_previous_result"
+	ScrollNextResult Action = "scroll_next_result"
+	ScrollFirst      Action = "scroll_first_result"
+	ScrollLast       Action = "scroll_last_result"
+	// Show or hide the tree of databases, tables, columns and indexes
+	ToggleSchemaBrowser Action = "toggle_schema_browser"
+	// List every key, including the ones which can't be configured
+	ShowHelp Action = "show_help"
+	// Search commands such as exporting a result or switching database
+	OpenCommandPalette Action = "open_command_palette"
+	// Mask result values, such as while sharing the screen
+	TogglePrivacyMode Action = "toggle_privacy_mode"
+	// Highlight every match across the results, moving between them with n and N
+	SearchResults Action = "search_results"
+)
+
+// Keys for each action unless configured otherwise
+var defaultBindings = map[Action][]string{
+	FocusResults:        {"Ctrl+T"},
+	OpenBookmarks:       {"Alt+'"},
+	ScrollUp:            {"Ctrl+Up", "Alt+Up"},
+	ScrollDown:          {"Ctrl+Down", "Alt+Down"},
+	ScrollLeft:          {"Ctrl+Left", "Alt+Left"},
+	ScrollRight:         {"Ctrl+Right", "Alt+Right"},
+	ScrollPrevResult:    {"Ctrl+PgUp", "Alt+PgUp"},
+	ScrollNextResult:    {"Ctrl+PgDn", "Alt+PgDn"},
+	ScrollFirst:         {"Ctrl+Home", "Alt+Home"},
+	ScrollLast:          {"Ctrl+End", "Alt+End"},
+	ToggleSchemaBrowser: {"Ctrl+O"},
+	ShowHelp:            {"F1"},
+	OpenCommandPalette:  {"Ctrl+P"},
+	TogglePrivacyMode:   {"Alt+P"},
+	SearchResults:       {"Alt+/"},
+}
+
+// What each action does, for the help overlay
+var actionDescriptions = map[Action]string{
+	FocusResults:        "focus the latest result table",
+	OpenBookmarks:       "open bookmarks",
+	ScrollUp:            "scroll the results up",
+	ScrollDown:          "scroll the results down",
+	ScrollLeft:          "scroll the results left",
+	ScrollRight:         "scroll the results right",
+	ScrollPrevResult:    "scroll to the previous result",
+	ScrollNextResult:    "scroll to the next result",
+	ScrollFirst:         "scroll to the first result",
+	ScrollLast:          "scroll to the last result",
+	ToggleSchemaBrowser: "show or hide the schema browser",
+	ShowHelp:            "show keyboard shortcuts",
+	OpenCommandPalette:  "open the command palette",
+	TogglePrivacyMode:   "mask or show result values",
+	SearchResults:       "search every result",
+}
+
+// Keys the query text area handles itself, which an action bound to them would shadow
+// Alt+Arrow keys are left out, as scrolling the results has always taken them
+var textAreaKeys = map[string]string{
+	"Ctrl+A": "move to the start of the line",
+	"Ctrl+E": "move to the end of the line",
+	"Ctrl+B": "page up",
+	"Ctrl+F": "page down",
+	"Ctrl+D": "delete",
+	"Ctrl+L": "select all",
+	"Ctrl+Q": "copy",
+	"Ctrl+X": "cut",
+	"Ctrl+V": "paste",
+	"Ctrl+Z": "undo",
+	"Ctrl+K": "delete to the end of the line",
+	"Ctrl+W": "delete the word before the cursor",
+	"Ctrl+U": "delete the line",
+	"Ctrl+Y": "redo",
+	"Alt+B":  "move back a word",
+	"Alt+F":  "move forward a word",
+	"Ctrl+C": "quit",
+}
+
+// Keys taken by emacs mode, in addition to or replacing the text area's own
+var emacsKeys = map[string]string{
+	"Ctrl+W":        "kill the word before the cursor",
+	"Ctrl+U":        "kill to the start of the line",
+	"Ctrl+K":        "kill to the end of the line",
+	"Ctrl+Y":        "yank",
+	"Alt+Y":         "yank an earlier kill",
+	"Alt+D":         "kill the word after the cursor",
+	"Alt+Backspace": "kill the word before the cursor",
+}
+
+// Keys taken by vim mode, in addition to the text area's own
+var vimKeys = map[string]string{
+	"Ctrl+R": "redo",
+}
+
+// Plain keys of vim's normal and visual modes, only listed for the help as they can't be bound to actions anyway
+var vimNormalKeys = []KeyDescription{
+	{Key: "Escape", Description: "normal mode"},
+	{Key: "i / a / I / A", Description: "insert before / after the cursor, at the start / end of the line"},
+	{Key: "o / O", Description: "open a line below / above"},
+	{Key: "v", Description: "visual mode, y / d / c on the selection"},
+	{Key: "h / j / k / l", Description: "move left / down / up / right"},
+	{Key: "w / b / e", Description: "next word / previous word / end of word"},
+	{Key: "0 / ^ / $", Description: "start / first non-blank / end of the line"},
+	{Key: "gg / G", Description: "first / last line"},
+	{Key: "x", Description: "delete the character under the cursor"},
+	{Key: "d / y / c + motion", Description: "delete / yank / change, ex: dw, y$, cb"},
+	{Key: "dd / yy / cc", Description: "delete / yank / change the line"},
+	{Key: "D / C", Description: "delete / change to the end of the line"},
+	{Key: "p / P", Description: "paste after / before the cursor"},
+	{Key: "u", Description: "undo"},
+}
+
+// Every action which can be bound
+func Actions() []Action {
+	actions := make([]Action, 0, len(defaultBindings))
+	for action := range defaultBindings {
+		actions = append(actions, action)
+	}
+	slices.Sort(actions)
+
+	return actions
+}
+
+// Which action each key runs, along with the editing mode
+type Keymap struct {
+	Mode Mode
+	// Keyed by the name tcell gives the key, see getKeyName
+	bindings map[string]Action
+	// Keys of each action as configured, for showing to the user
+	actionKeys map[Action][]string
+}
+
+// A key and what it does, for listing keys
+type KeyDescription struct {
+	Key         string
+	Description string
+}
+
+// Build the keymap for a mode, with bindings replacing the default keys of their actions
+// Every problem is reported at once: unknown modes, actions or keys, a key bound to several actions,
+// and keys which would shadow a key the query text area or mode already uses
+func Build(mode Mode, bindings map[string]string) (*Keymap, error) {
+	var keymapErrors []error
+
+	if mode == "" {
+		mode = Emacs
+	}
+	if mode != Emacs && mode != Default && mode != Vim {
+		keymapErrors = append(keymapErrors, fmt.Errorf("Unknown keymap mode %s, expected %s, %s or %s", mode, Emacs, Default, Vim))
+	}
+
+	actionKeys := make(map[Action][]string, len(defaultBindings))
+	for action, keys := range defaultBindings {
+		actionKeys[action] = keys
+	}
+
+	for rawAction, key := range bindings {
+		action := Action(rawAction)
+		if _, exists := defaultBindings[action]; !exists {
+			keymapErrors = append(keymapErrors, fmt.Errorf("Unknown keybinding action %s", rawAction))
+			continue
+		}
+
+		actionKeys[action] = []string{key}
+	}
+
+	reservedKeys := map[string]string{}
+	for key, description := range getModeKeys(mode) {
+		reservedKeys[mustGetKeyName(key)] = description
+	}
+
+	keymap := &Keymap{Mode: mode, bindings: map[string]Action{}, actionKeys: actionKeys}
+	for _, action := range Actions() {
+		for _, key := range actionKeys[action] {
+			name, err := getKeyName(key)
+			if err != nil {
+				keymapErrors = append(keymapErrors, fmt.Errorf("Keybinding for %s: %w", action, err))
+				continue
+			}
+
+			if otherAction, isBound := keymap.bindings[name]; isBound {
+				keymapErrors = append(keymapErrors, fmt.Errorf("%s is bound to both %s and %s", key, otherAction, action))
+				continue
+			}
+
+			if description, isReserved := reservedKeys[name]; isReserved {
+				keymapErrors = append(keymapErrors, fmt.Errorf("%s for %s would shadow %s, which the query editor uses it for", key, action, description))
+				continue
+			}
+
+			keymap.bindings[name] = action
+		}
+	}
+
+	if len(keymapErrors) > 0 {
+		return nil, errors.Join(keymapErrors...)
+	}
+
+	return keymap, nil
+}
+
+// Action bound to the key pressed, if any
+func (keymap *Keymap) Lookup(event *tcell.EventKey) (action Action, isBound bool) {
+	action, isBound = keymap.bindings[event.Name()]
+	return action, isBound
+}
+
+// Keys bound to an action, as configured
+func (keymap *Keymap) Keys(action Action) []string {
+	return keymap.actionKeys[action]
+}
+
+// What an action does, in a few words
+func Describe(action Action) string {
+	return actionDescriptions[action]
+}
+
+// Keys the query text area handles itself in the keymap's mode, sorted by key
+// In vim mode, followed by the keys of its normal mode
+func (keymap *Keymap) EditingKeys() []KeyDescription {
+	modeKeys := getModeKeys(keymap.Mode)
+
+	keyDescriptions := make([]KeyDescription, 0, len(modeKeys))
+	for key, description := range modeKeys {
+		keyDescriptions = append(keyDescriptions, KeyDescription{Key: key, Description: description})
+	}
+	slices.SortFunc(keyDescriptions, func(a, b KeyDescription) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	if keymap.Mode == Vim {
+		keyDescriptions = append(keyDescriptions, vimNormalKeys...)
+	}
+
+	return keyDescriptions
+}
+
+// The text area's own keys, along with or replaced by those of the mode
+func getModeKeys(mode Mode) map[string]string {
+	modeKeys := make(map[string]string, len(textAreaKeys)+len(emacsKeys))
+	for key, description := range textAreaKeys {
+		modeKeys[key] = description
+	}
+
+	var extraKeys map[string]string
+	switch mode {
+	case Emacs:
+		{
+			extraKeys = emacsKeys
+		}
+	case Vim:
+		{
+			extraKeys = vimKeys
+		}
+	}
+	for key, description := range extraKeys {
+		modeKeys[key] = description
+	}
+
+	return modeKeys
+}
+
+// Turn a key such as Ctrl+T, Alt+' or F2 into the name tcell gives the same key when pressed
+// So configured keys can be compared against events without tracking every way a key can be reported
+func getKeyName(key string) (string, error) {
+	parts := strings.Split(key, "+")
+	baseKey := parts[len(parts)-1]
+	// Allow binding the + key itself, ex: Alt++
+	if baseKey == "" && len(parts) > 1 {
+		parts = parts[:len(parts)-1]
+		parts[len(parts)-1] = "+"
+		baseKey = "+"
+	}
+
+	var modifiers tcell.ModMask
+	for _, modifier := range parts[:len(parts)-1] {
+		switch strings.ToLower(modifier) {
+		case "ctrl":
+			{
+				modifiers |= tcell.ModCtrl
+			}
+		case "alt":
+			{
+				modifiers |= tcell.ModAlt
+			}
+		case "shift":
+			{
+				modifiers |= tcell.ModShift
+			}
+		default:
+			{
+				return "", fmt.Errorf("Unknown modifier %s in %s, expected Ctrl, Alt or Shift", modifier, key)
+			}
+		}
+	}
+
+	var event *tcell.EventKey
+	if utf8.RuneCountInString(baseKey) == 1 {
+		char, _ := utf8.DecodeRuneInString(baseKey)
+		lowerChar := []rune(strings.ToLower(baseKey))[0]
+
+		if modifiers&tcell.ModCtrl != 0 && lowerChar >= 'a' && lowerChar <= 'z' {
+			// Ctrl+Letter is reported as its own key rather than a rune
+			event = tcell.NewEventKey(tcell.KeyCtrlA+tcell.Key(lowerChar-'a'), 0, modifiers)
+		} else {
+			if modifiers&tcell.ModShift == 0 {
+				char = lowerChar
+			}
+			event = tcell.NewEventKey(tcell.KeyRune, char, modifiers)
+		}
+	} else {
+		namedKey, exists := getNamedKey(baseKey)
+		if !exists {
+			return "", fmt.Errorf("Unknown key %s in %s", baseKey, key)
+		}
+
+		event = tcell.NewEventKey(namedKey, 0, modifiers)
+	}
+
+	// Anything else would stop the key being typed into queries
+	isFunctionKey := event.Key() >= tcell.KeyF1 && event.Key() <= tcell.KeyF64
+	if modifiers&(tcell.ModCtrl|tcell.ModAlt) == 0 && !isFunctionKey {
+		return "", fmt.Errorf("%s needs a Ctrl or Alt modifier, unless it's a function key", key)
+	}
+
+	return event.Name(), nil
+}
+
+func mustGetKeyName(key string) string {
+	name, err := getKeyName(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return name
+}
+
+// Case insensitive lookup of keys such as Up, PgDn or F2
+func getNamedKey(baseKey string) (tcell.Key, bool) {
+	// Backspace is reported as Backspace2 by most terminals
+	if strings.EqualFold(baseKey, "Backspace") {
+		return tcell.KeyBackspace2, true
+	}
+
+	for namedKey, name := range tcell.KeyNames {
+		// Ctrl+Letter keys are handled separately, and some share a code with keys such as Backspace
+		if strings.HasPrefix(name, "Ctrl-") {
+			continue
+		}
+
+		if strings.EqualFold(name, baseKey) {
+			return namedKey, true
+		}
+	}
+
+	return 0, false
+}
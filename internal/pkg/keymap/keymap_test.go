@@ -0,0 +1,143 @@
+package keymap_test
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	var tests = []struct {
+		Name           string
+		Mode           keymap.Mode
+		Bindings       map[string]string
+		ExpectedErrors []string
+	}{
+		{"Defaults", "", nil, nil},
+		{"Default mode", keymap.Default, nil, nil},
+		{"Rebinding", keymap.Emacs, map[string]string{"focus_results": "Ctrl+G", "open_bookmarks": "F2"}, nil},
+		{"Freed default key", keymap.Emacs, map[string]string{"focus_results": "Ctrl+G", "open_bookmarks": "Ctrl+T"}, nil},
+		{"Unknown mode", "vscode", nil, []string{"Unknown keymap mode vscode, expected emacs, default or vim"}},
+		{"Unknown action", keymap.Emacs, map[string]string{"explode": "Ctrl+G"}, []string{"Unknown keybinding action explode"}},
+		{
+			"Bound twice",
+			keymap.Emacs,
+			map[string]string{"open_bookmarks": "ctrl+t"},
+			[]string{"ctrl+t is bound to both focus_results and open_bookmarks"},
+		},
+		{
+			"Shadows text area",
+			keymap.Default,
+			map[string]string{"focus_results": "Ctrl+Z"},
+			[]string{"Ctrl+Z for focus_results would shadow undo, which the query editor uses it for"},
+		},
+		{
+			"Shadows emacs mode",
+			keymap.Emacs,
+			map[string]string{"focus_results": "Alt+Y"},
+			[]string{"Alt+Y for focus_results would shadow yank an earlier kill, which the query editor uses it for"},
+		},
+		{"Free outside emacs mode", keymap.Default, map[string]string{"focus_results": "Alt+Y"}, nil},
+		{
+			"Shadows vim mode",
+			keymap.Vim,
+			map[string]string{"focus_results": "Ctrl+R"},
+			[]string{"Ctrl+R for focus_results would shadow redo, which the query editor uses it for"},
+		},
+		{"Free outside vim mode", keymap.Emacs, map[string]string{"focus_results": "Ctrl+R"}, nil},
+		{
+			"Plain key",
+			keymap.Emacs,
+			map[string]string{"focus_results": "g"},
+			[]string{"Keybinding for focus_results: g needs a Ctrl or Alt modifier, unless it's a function key"},
+		},
+		{
+			"Unknown key",
+			keymap.Emacs,
+			map[string]string{"focus_results": "Hyper+Space"},
+			[]string{"Keybinding for focus_results: Unknown modifier Hyper in Hyper+Space, expected Ctrl, Alt or Shift"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			builtKeymap, err := keymap.Build(test.Mode, test.Bindings)
+			if len(test.ExpectedErrors) == 0 {
+				assert.NoError(err)
+				assert.NotNil(builtKeymap)
+				return
+			}
+
+			if assert.Error(err) {
+				for _, expectedError := range test.ExpectedErrors {
+					assert.Contains(err.Error(), expectedError)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	assert := assert.New(t)
+
+	builtKeymap, err := keymap.Build(keymap.Emacs, map[string]string{"scroll_up": "Alt+K", "focus_results": "F2"})
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(keymap.Emacs, builtKeymap.Mode)
+
+	var tests = []struct {
+		Event          *tcell.EventKey
+		ExpectedAction keymap.Action
+		ExpectedBound  bool
+	}{
+		// Terminals report Ctrl+Letter as a control character
+		{tcell.NewEventKey(tcell.KeyRune, 20, tcell.ModNone), "", false},
+		{tcell.NewEventKey(tcell.KeyF2, 0, tcell.ModNone), keymap.FocusResults, true},
+		{tcell.NewEventKey(tcell.KeyRune, 'k', tcell.ModAlt), keymap.ScrollUp, true},
+		{tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModAlt), "", false},
+		{tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModCtrl), keymap.ScrollDown, true},
+		{tcell.NewEventKey(tcell.KeyRune, '\'', tcell.ModAlt), keymap.OpenBookmarks, true},
+		{tcell.NewEventKey(tcell.KeyRune, 'k', tcell.ModNone), "", false},
+	}
+
+	for _, test := range tests {
+		action, isBound := builtKeymap.Lookup(test.Event)
+		assert.Equal(test.ExpectedBound, isBound, test.Event.Name())
+		assert.Equal(test.ExpectedAction, action, test.Event.Name())
+	}
+}
+
+func TestKeyDescriptions(t *testing.T) {
+	assert := assert.New(t)
+
+	builtKeymap, err := keymap.Build(keymap.Default, map[string]string{"show_help": "F2"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	for _, action := range keymap.Actions() {
+		assert.NotEmpty(keymap.Describe(action), action)
+	}
+	assert.Equal([]string{"F2"}, builtKeymap.Keys(keymap.ShowHelp))
+	assert.Equal([]string{"Ctrl+P"}, builtKeymap.Keys(keymap.OpenCommandPalette))
+
+	defaultKeys := builtKeymap.EditingKeys()
+	assert.Contains(defaultKeys, keymap.KeyDescription{Key: "Ctrl+Y", Description: "redo"})
+	assert.NotContains(defaultKeys, keymap.KeyDescription{Key: "Alt+Y", Description: "yank an earlier kill"})
+
+	emacsKeymap, err := keymap.Build(keymap.Emacs, nil)
+	if !assert.NoError(err) {
+		return
+	}
+
+	emacsKeys := emacsKeymap.EditingKeys()
+	assert.Contains(emacsKeys, keymap.KeyDescription{Key: "Ctrl+Y", Description: "yank"})
+	assert.Contains(emacsKeys, keymap.KeyDescription{Key: "Alt+Y", Description: "yank an earlier kill"})
+}
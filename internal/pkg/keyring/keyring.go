@@ -0,0 +1,121 @@
+// Secrets kept in the operating system's credential store, rather than in a file
+// Uses the Keychain through security on macOS, and the Secret Service through secret-tool on Linux
+package keyring
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var ErrNotFound = errors.New("Secret not found in the keyring")
+
+// Run a command with input on stdin, returning what it printed
+// A variable so tests can stand in for the credential store
+var runCommand = func(input string, name string, args ...string) (string, error) {
+	command := exec.Command(name, args...)
+	command.Stdin = strings.NewReader(input)
+
+	var stderr bytes.Buffer
+	command.Stderr = &stderr
+
+	output, err := command.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			// Both tools exit without a message when nothing is stored
+			return "", ErrNotFound
+		}
+
+		return "", errors.Join(
+			fmt.Errorf("%s failed", name),
+			err,
+			errors.New(strings.TrimSpace(stderr.String())),
+		)
+	}
+
+	return string(output), nil
+}
+
+// Read a secret, ErrNotFound when none is stored for the service and account
+func Get(service string, account string) (string, error) {
+	var output string
+	var err error
+
+	switch runtime.GOOS {
+	case "darwin":
+		{
+			output, err = runCommand("", "security", "find-generic-password", "-s", service, "-a", account, "-w")
+		}
+	case "linux":
+		{
+			output, err = runCommand("", "secret-tool", "lookup", "service", service, "account", account)
+		}
+	default:
+		{
+			return "", unsupportedError()
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimRight(output, "\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+
+	return secret, nil
+}
+
+// Store a secret, replacing any already stored for the service and account
+func Set(service string, account string, secret string) error {
+	var err error
+
+	switch runtime.GOOS {
+	case "darwin":
+		{
+			_, err = runCommand("", "security", "add-generic-password", "-U", "-s", service, "-a", account, "-w", secret)
+		}
+	case "linux":
+		{
+			label := fmt.Sprint(service, " ", account)
+			_, err = runCommand(secret, "secret-tool", "store", "--label", label, "service", service, "account", account)
+		}
+	default:
+		{
+			return unsupportedError()
+		}
+	}
+
+	return err
+}
+
+// Read a secret, storing a new random one the first time
+func GetOrCreate(service string, account string) (string, error) {
+	secret, err := Get(service, account)
+	if !errors.Is(err, ErrNotFound) {
+		return secret, err
+	}
+
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	secret = base64.StdEncoding.EncodeToString(randomBytes)
+
+	if err := Set(service, account, secret); err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func unsupportedError() error {
+	return fmt.Errorf("The keyring isn't supported on %s", runtime.GOOS)
+}
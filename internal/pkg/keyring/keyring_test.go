@@ -0,0 +1,59 @@
+package keyring
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrCreate(t *testing.T) {
+	if runtime.GOOS != "darwin" && runtime.GOOS != "linux" {
+		t.Skip("The keyring isn't supported on", runtime.GOOS)
+	}
+
+	assert := assert.New(t)
+
+	// Stands in for the credential store, keeping what's stored in memory
+	var stored string
+	var commands []string
+	originalRunCommand := runCommand
+	runCommand = func(input string, name string, args ...string) (string, error) {
+		commands = append(commands, args[0])
+
+		switch args[0] {
+		case "find-generic-password", "lookup":
+			{
+				if stored == "" {
+					return "", ErrNotFound
+				}
+
+				return stored + "\n", nil
+			}
+		case "store":
+			{
+				stored = input
+			}
+		case "add-generic-password":
+			{
+				stored = args[len(args)-1]
+			}
+		}
+
+		return "", nil
+	}
+	t.Cleanup(func() {
+		runCommand = originalRunCommand
+	})
+
+	secret, err := GetOrCreate("sql", "history")
+	assert.NoError(err)
+	assert.Len(secret, 44)
+	assert.Len(commands, 2)
+
+	// Read back rather than replaced
+	secretAgain, err := GetOrCreate("sql", "history")
+	assert.NoError(err)
+	assert.Equal(secret, secretAgain)
+	assert.Len(commands, 3)
+}
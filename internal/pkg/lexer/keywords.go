@@ -0,0 +1,41 @@
+package lexer
+
+import (
+	"slices"
+	"strings"
+)
+
+// Keywords MySQL reserves, so they can't be unquoted names, plus END to close CASE
+// Non-reserved keywords are left out, as uppercasing a table named after one would break it on MySQL,
+// where table names are case sensitive on most platforms
+var reservedKeywords = map[string]bool{
+	"ADD": true, "ALL": true, "ALTER": true, "AND": true, "AS": true, "ASC": true,
+	"BETWEEN": true, "BY": true, "CASE": true, "CHECK": true, "COLUMN": true, "CONSTRAINT": true,
+	"CREATE": true, "CROSS": true, "DATABASE": true, "DEFAULT": true, "DELETE": true, "DESC": true,
+	"DESCRIBE": true, "DISTINCT": true, "DROP": true, "ELSE": true, "END": true, "EXISTS": true,
+	"EXPLAIN": true, "FALSE": true, "FOREIGN": true, "FROM": true, "GRANT": true,
+	"GROUP": true, "HAVING": true, "IN": true, "INDEX": true, "INNER": true, "INSERT": true,
+	"INTERVAL": true, "INTO": true, "IS": true, "JOIN": true, "KEY": true, "LEFT": true,
+	"LIKE": true, "LIMIT": true, "NATURAL": true, "NOT": true, "NULL": true, "ON": true,
+	"OR": true, "ORDER": true, "OUTER": true, "OVER": true, "PARTITION": true, "PRIMARY": true,
+	"RECURSIVE": true, "REFERENCES": true, "REVOKE": true, "RIGHT": true, "SELECT": true, "SET": true,
+	"SHOW": true, "TABLE": true, "THEN": true, "TRUE": true, "UNION": true, "UNIQUE": true,
+	"UPDATE": true, "USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WINDOW": true,
+	"WITH": true,
+}
+
+// Case insensitive check for a reserved keyword, such as select or FROM
+func IsReservedKeyword(word string) bool {
+	return reservedKeywords[strings.ToUpper(word)]
+}
+
+// Every reserved keyword, uppercase and sorted
+func ReservedKeywords() []string {
+	keywords := make([]string, 0, len(reservedKeywords))
+	for keyword := range reservedKeywords {
+		keywords = append(keywords, keyword)
+	}
+	slices.Sort(keywords)
+
+	return keywords
+}
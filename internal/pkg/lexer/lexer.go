@@ -0,0 +1,323 @@
+package lexer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+type TokenKind int
+
+const (
+	Whitespace TokenKind = iota + 1
+	Comment
+	// Keywords and unquoted identifiers
+	Word
+	QuotedIdentifier
+	String
+	Number
+	// Placeholders such as $1 (Postgres) or ? (MySQL)
+	Parameter
+	// Operators and punctuation
+	Operator
+)
+
+type Token struct {
+	Kind TokenKind
+	Text string
+	// Byte offset of the token in the tokenized statement
+	Start int
+}
+
+// Case insensitive check for a keyword, such as SELECT
+func (token Token) IsKeyword(keyword string) bool {
+	return token.Kind == Word && strings.EqualFold(token.Text, keyword)
+}
+
+func (token Token) IsOperator(operator string) bool {
+	return token.Kind == Operator && token.Text == operator
+}
+
+// Whether the token affects the meaning of a statement, as opposed to whitespace or comments
+func (token Token) IsSignificant() bool {
+	return token.Kind != Whitespace && token.Kind != Comment
+}
+
+// Operators longer than a single character, longest first so they match greedily
+var multiCharOperators = []string{"->>", "::", "||", "<=", ">=", "<>", "!=", "->", ":=", "=>", "<<", ">>", "&&"}
+
+// Split a statement into tokens, following the quoting and comment rules of flavor
+// Never fails, unterminated strings or comments run until the end of the statement
+func Tokenize(flavor conn.DBFlavor, statement string) (tokens []Token) {
+	tokenizer := tokenizer{flavor: flavor, statement: statement}
+
+	for tokenizer.position < len(statement) {
+		start := tokenizer.position
+		kind := tokenizer.next()
+
+		tokens = append(tokens, Token{
+			Kind:  kind,
+			Text:  statement[start:tokenizer.position],
+			Start: start,
+		})
+	}
+
+	return tokens
+}
+
+// Only the significant tokens of a statement, see Token.IsSignificant
+func SignificantTokens(tokens []Token) (significantTokens []Token) {
+	for _, token := range tokens {
+		if token.IsSignificant() {
+			significantTokens = append(significantTokens, token)
+		}
+	}
+
+	return significantTokens
+}
+
+type tokenizer struct {
+	flavor    conn.DBFlavor
+	statement string
+	position  int
+}
+
+func (tokenizer *tokenizer) peek(offset int) byte {
+	if tokenizer.position+offset >= len(tokenizer.statement) {
+		return 0
+	}
+
+	return tokenizer.statement[tokenizer.position+offset]
+}
+
+func (tokenizer *tokenizer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(tokenizer.statement[tokenizer.position:], prefix)
+}
+
+// Advance until just past the first occurrence of end, or to the end of the statement
+func (tokenizer *tokenizer) skipPast(end string) {
+	endIdx := strings.Index(tokenizer.statement[tokenizer.position:], end)
+	if endIdx == -1 {
+		tokenizer.position = len(tokenizer.statement)
+		return
+	}
+
+	tokenizer.position += endIdx + len(end)
+}
+
+// Consume the token at the current position, returning its kind
+func (tokenizer *tokenizer) next() TokenKind {
+	char, charWidth := utf8.DecodeRuneInString(tokenizer.statement[tokenizer.position:])
+
+	switch {
+	case unicode.IsSpace(char):
+		{
+			for tokenizer.position < len(tokenizer.statement) {
+				char, charWidth := utf8.DecodeRuneInString(tokenizer.statement[tokenizer.position:])
+				if !unicode.IsSpace(char) {
+					break
+				}
+				tokenizer.position += charWidth
+			}
+
+			return Whitespace
+		}
+	case tokenizer.hasPrefix("--") || (char == '#' && tokenizer.flavor == conn.MySQL):
+		{
+			tokenizer.skipPast("\n")
+			return Comment
+		}
+	case tokenizer.hasPrefix("/*"):
+		{
+			tokenizer.position += 2
+			tokenizer.skipPast("*/")
+			return Comment
+		}
+	case char == '\'':
+		{
+			tokenizer.readQuoted('\'', tokenizer.flavor == conn.MySQL)
+			return String
+		}
+	case (char == 'E' || char == 'e') && tokenizer.peek(1) == '\'' && tokenizer.flavor == conn.PostgreSQL:
+		{
+			// Escape string, backslashes escape the next character
+			tokenizer.position++
+			tokenizer.readQuoted('\'', true)
+			return String
+		}
+	case char == '"':
+		{
+			// Double quotes are for strings in MySQL, unless ANSI_QUOTES is on
+			if tokenizer.flavor == conn.MySQL {
+				tokenizer.readQuoted('"', true)
+				return String
+			}
+
+			tokenizer.readQuoted('"', false)
+			return QuotedIdentifier
+		}
+	case char == '`':
+		{
+			tokenizer.readQuoted('`', false)
+			return QuotedIdentifier
+		}
+	case char == '$' && tokenizer.flavor == conn.PostgreSQL:
+		{
+			return tokenizer.readDollar()
+		}
+	case char == '?' && tokenizer.flavor == conn.MySQL:
+		{
+			tokenizer.position++
+			return Parameter
+		}
+	case isDigit(char) || (char == '.' && isDigit(rune(tokenizer.peek(1)))):
+		{
+			tokenizer.readNumber()
+			return Number
+		}
+	case isWordStart(char):
+		{
+			for tokenizer.position < len(tokenizer.statement) {
+				char, charWidth := utf8.DecodeRuneInString(tokenizer.statement[tokenizer.position:])
+				if !isWordStart(char) && !isDigit(char) && char != '$' {
+					break
+				}
+				tokenizer.position += charWidth
+			}
+
+			return Word
+		}
+	}
+
+	for _, operator := range multiCharOperators {
+		if tokenizer.hasPrefix(operator) {
+			tokenizer.position += len(operator)
+			return Operator
+		}
+	}
+
+	tokenizer.position += charWidth
+	return Operator
+}
+
+// Read a quoted string or identifier starting at the current position
+// A doubled quote is always an escaped quote, and a backslash escapes the next character if allowed
+func (tokenizer *tokenizer) readQuoted(quote byte, backslashEscapes bool) {
+	tokenizer.position++
+
+	for tokenizer.position < len(tokenizer.statement) {
+		char := tokenizer.statement[tokenizer.position]
+
+		switch {
+		case char == '\\' && backslashEscapes:
+			{
+				tokenizer.position += 2
+			}
+		case char == quote && tokenizer.peek(1) == quote:
+			{
+				tokenizer.position += 2
+			}
+		case char == quote:
+			{
+				tokenizer.position++
+				return
+			}
+		default:
+			{
+				tokenizer.position++
+			}
+		}
+	}
+
+	tokenizer.position = min(tokenizer.position, len(tokenizer.statement))
+}
+
+// Either a positional parameter such as $1, or a dollar quoted string such as $tag$text$tag$
+func (tokenizer *tokenizer) readDollar() TokenKind {
+	if isDigit(rune(tokenizer.peek(1))) {
+		tokenizer.position++
+		for isDigit(rune(tokenizer.peek(0))) {
+			tokenizer.position++
+		}
+
+		return Parameter
+	}
+
+	tagEnd := tokenizer.position + 1
+	for tagEnd < len(tokenizer.statement) {
+		char, charWidth := utf8.DecodeRuneInString(tokenizer.statement[tagEnd:])
+		if !isWordStart(char) && !isDigit(char) {
+			break
+		}
+		tagEnd += charWidth
+	}
+
+	// Not a dollar quote after all, just a stray $
+	if tagEnd >= len(tokenizer.statement) || tokenizer.statement[tagEnd] != '$' {
+		tokenizer.position++
+		return Operator
+	}
+
+	tag := tokenizer.statement[tokenizer.position : tagEnd+1]
+	tokenizer.position = tagEnd + 1
+	tokenizer.skipPast(tag)
+
+	return String
+}
+
+func (tokenizer *tokenizer) readNumber() {
+	for tokenizer.position < len(tokenizer.statement) {
+		char := rune(tokenizer.peek(0))
+
+		switch {
+		case isDigit(char) || char == '.':
+			{
+				tokenizer.position++
+			}
+		case (char == 'e' || char == 'E') && (isDigit(rune(tokenizer.peek(1))) || ((tokenizer.peek(1) == '-' || tokenizer.peek(1) == '+') && isDigit(rune(tokenizer.peek(2))))):
+			{
+				tokenizer.position += 2
+			}
+		default:
+			{
+				return
+			}
+		}
+	}
+}
+
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
+}
+
+func isWordStart(char rune) bool {
+	return char == '_' || unicode.IsLetter(char)
+}
+
+// Split a script into its statements on semicolons, ignoring those within strings, identifiers and comments
+// Statements are trimmed and without their semicolon, those with nothing but comments are left out
+func SplitStatements(flavor conn.DBFlavor, script string) (statements []string) {
+	statementStart := 0
+	hasSignificantToken := false
+
+	addStatement := func(statementEnd int) {
+		if hasSignificantToken {
+			statements = append(statements, strings.TrimSpace(script[statementStart:statementEnd]))
+		}
+		hasSignificantToken = false
+	}
+
+	for _, token := range Tokenize(flavor, script) {
+		if token.IsOperator(";") {
+			addStatement(token.Start)
+			statementStart = token.Start + len(token.Text)
+		} else if token.IsSignificant() {
+			hasSignificantToken = true
+		}
+	}
+	addStatement(len(script))
+
+	return statements
+}
@@ -0,0 +1,223 @@
+package lexer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/stretchr/testify/assert"
+)
+
+type expectedToken struct {
+	Kind lexer.TokenKind
+	Text string
+}
+
+func TestTokenize(t *testing.T) {
+	var tests = []struct {
+		Name           string
+		Flavor         conn.DBFlavor
+		Statement      string
+		ExpectedTokens []expectedToken
+	}{
+		{
+			Name:      "Simple Select",
+			Flavor:    conn.PostgreSQL,
+			Statement: "SELECT id FROM users WHERE id >= 10;",
+			ExpectedTokens: []expectedToken{
+				{lexer.Word, "SELECT"},
+				{lexer.Word, "id"},
+				{lexer.Word, "FROM"},
+				{lexer.Word, "users"},
+				{lexer.Word, "WHERE"},
+				{lexer.Word, "id"},
+				{lexer.Operator, ">="},
+				{lexer.Number, "10"},
+				{lexer.Operator, ";"},
+			},
+		},
+		{
+			Name:      "Postgres Quoting",
+			Flavor:    conn.PostgreSQL,
+			Statement: `SELECT "Name", 'it''s; fine', E'a\'b', $1::text, $tag$ a ; b $tag$`,
+			ExpectedTokens: []expectedToken{
+				{lexer.Word, "SELECT"},
+				{lexer.QuotedIdentifier, `"Name"`},
+				{lexer.Operator, ","},
+				{lexer.String, `'it''s; fine'`},
+				{lexer.Operator, ","},
+				{lexer.String, `E'a\'b'`},
+				{lexer.Operator, ","},
+				{lexer.Parameter, "$1"},
+				{lexer.Operator, "::"},
+				{lexer.Word, "text"},
+				{lexer.Operator, ","},
+				{lexer.String, "$tag$ a ; b $tag$"},
+			},
+		},
+		{
+			Name:      "MySQL Quoting",
+			Flavor:    conn.MySQL,
+			Statement: "SELECT `order`, \"a\\\"b\", 'c\\'d' FROM t WHERE id = ? # trailing ; comment",
+			ExpectedTokens: []expectedToken{
+				{lexer.Word, "SELECT"},
+				{lexer.QuotedIdentifier, "`order`"},
+				{lexer.Operator, ","},
+				{lexer.String, `"a\"b"`},
+				{lexer.Operator, ","},
+				{lexer.String, `'c\'d'`},
+				{lexer.Word, "FROM"},
+				{lexer.Word, "t"},
+				{lexer.Word, "WHERE"},
+				{lexer.Word, "id"},
+				{lexer.Operator, "="},
+				{lexer.Parameter, "?"},
+			},
+		},
+		{
+			Name:      "Comments and Unicode",
+			Flavor:    conn.PostgreSQL,
+			Statement: "/* héllo; */ SELECT naïve -- done;\n, 1.5e3",
+			ExpectedTokens: []expectedToken{
+				{lexer.Word, "SELECT"},
+				{lexer.Word, "naïve"},
+				{lexer.Operator, ","},
+				{lexer.Number, "1.5e3"},
+			},
+		},
+		{
+			Name:      "Unterminated String",
+			Flavor:    conn.PostgreSQL,
+			Statement: "SELECT 'abc",
+			ExpectedTokens: []expectedToken{
+				{lexer.Word, "SELECT"},
+				{lexer.String, "'abc"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test := test
+			assert := assert.New(t)
+
+			tokens := lexer.Tokenize(test.Flavor, test.Statement)
+
+			// Tokens cover the whole statement, in order
+			var rebuiltStatement strings.Builder
+			for _, token := range tokens {
+				assert.Equal(rebuiltStatement.Len(), token.Start)
+				rebuiltStatement.WriteString(token.Text)
+			}
+			assert.Equal(test.Statement, rebuiltStatement.String())
+
+			actualTokens := []expectedToken{}
+			for _, token := range lexer.SignificantTokens(tokens) {
+				actualTokens = append(actualTokens, expectedToken{token.Kind, token.Text})
+			}
+			assert.Equal(test.ExpectedTokens, actualTokens)
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	var tests = []struct {
+		Name               string
+		Flavor             conn.DBFlavor
+		Script             string
+		ExpectedStatements []string
+	}{
+		{
+			Name:               "Single statement without semicolon",
+			Flavor:             conn.MySQL,
+			Script:             "SELECT 1",
+			ExpectedStatements: []string{"SELECT 1"},
+		},
+		{
+			Name:   "Multiple statements",
+			Flavor: conn.MySQL,
+			Script: "CREATE TABLE a (id INT);\nINSERT INTO a VALUES (1), (2);\n\n",
+			ExpectedStatements: []string{
+				"CREATE TABLE a (id INT)",
+				"INSERT INTO a VALUES (1), (2)",
+			},
+		},
+		{
+			Name:   "Semicolons in strings, identifiers and comments",
+			Flavor: conn.MySQL,
+			Script: "-- setup; of things\nINSERT INTO `a;b` VALUES ('x;y', \"z\\\";\"); /* done; */",
+			ExpectedStatements: []string{
+				"-- setup; of things\nINSERT INTO `a;b` VALUES ('x;y', \"z\\\";\")",
+			},
+		},
+		{
+			Name:   "Postgres function body",
+			Flavor: conn.PostgreSQL,
+			Script: "CREATE FUNCTION one() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql; SELECT one();",
+			ExpectedStatements: []string{
+				"CREATE FUNCTION one() RETURNS int AS $$ BEGIN RETURN 1; END; $$ LANGUAGE plpgsql",
+				"SELECT one()",
+			},
+		},
+		{
+			Name:               "Only comments and empty statements",
+			Flavor:             conn.PostgreSQL,
+			Script:             "-- nothing to see\n;;  ;",
+			ExpectedStatements: nil,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.ExpectedStatements, lexer.SplitStatements(test.Flavor, test.Script))
+		})
+	}
+}
+
+func TestIsReservedKeyword(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(lexer.IsReservedKeyword("SELECT"))
+	assert.True(lexer.IsReservedKeyword("from"))
+	assert.True(lexer.IsReservedKeyword("Where"))
+	// Could be a table name, so never treated as a keyword
+	assert.False(lexer.IsReservedKeyword("user"))
+	assert.False(lexer.IsReservedKeyword("users"))
+	assert.False(lexer.IsReservedKeyword(""))
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	var tests = []struct {
+		Name       string
+		Flavor     conn.DBFlavor
+		Identifier string
+		Quoting    lexer.IdentifierQuoting
+		Expected   string
+	}{
+		{"Always quoted on MySQL", conn.MySQL, "users", lexer.QuoteAlways, "`users`"},
+		{"Always quoted on Postgres", conn.PostgreSQL, "users", lexer.QuoteAlways, `"users"`},
+		{"Escapes quotes", conn.PostgreSQL, `say "hi"`, lexer.QuoteAlways, `"say ""hi"""`},
+		{"Plain name left unquoted", conn.PostgreSQL, "order_items", lexer.QuoteWhenNeeded, "order_items"},
+		{"Mixed case on Postgres", conn.PostgreSQL, "OrderItems", lexer.QuoteWhenNeeded, `"OrderItems"`},
+		{"Mixed case on MySQL", conn.MySQL, "OrderItems", lexer.QuoteWhenNeeded, "OrderItems"},
+		{"Reserved word", conn.MySQL, "order", lexer.QuoteWhenNeeded, "`order`"},
+		{"Reserved on Postgres only", conn.PostgreSQL, "user", lexer.QuoteWhenNeeded, `"user"`},
+		{"Special characters", conn.MySQL, "first name", lexer.QuoteWhenNeeded, "`first name`"},
+		{"Leading digit", conn.PostgreSQL, "2fa_codes", lexer.QuoteWhenNeeded, `"2fa_codes"`},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.Expected, lexer.QuoteIdentifier(test.Flavor, test.Identifier, test.Quoting))
+		})
+	}
+}
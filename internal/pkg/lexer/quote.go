@@ -0,0 +1,85 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// When table and column names are quoted in generated statements
+type IdentifierQuoting string
+
+const (
+	// Quote every name
+	QuoteAlways IdentifierQuoting = "always"
+	// Only quote names which would be misread unquoted: mixed case, reserved words or special characters
+	QuoteWhenNeeded IdentifierQuoting = "needed"
+)
+
+// Reserved by MySQL or Postgres beyond reservedKeywords, which would fail as unquoted names
+// Kept apart as uppercasing them as they're typed would break valid statements
+var reservedNameKeywords = map[string]bool{
+	"ANALYSE": true, "ANALYZE": true, "ARRAY": true, "BOTH": true, "CAST": true, "CHANGE": true,
+	"COLLATE": true, "CURRENT_DATE": true, "CURRENT_ROLE": true, "CURRENT_TIME": true,
+	"CURRENT_TIMESTAMP": true, "CURRENT_USER": true, "DEFERRABLE": true, "DO": true, "EXCEPT": true,
+	"FETCH": true, "FOR": true, "INITIALLY": true, "INTERSECT": true, "LATERAL": true, "LEADING": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "OFFSET": true, "ONLY": true, "PLACING": true,
+	"READ": true, "RENAME": true, "REPLACE": true, "RETURNING": true, "SESSION_USER": true,
+	"SOME": true, "TO": true, "TRAILING": true, "USER": true, "VARIADIC": true,
+}
+
+func ValidateIdentifierQuoting(quoting IdentifierQuoting) error {
+	if quoting != QuoteAlways && quoting != QuoteWhenNeeded {
+		return fmt.Errorf("Unknown identifier quoting %s, expected %s or %s", quoting, QuoteAlways, QuoteWhenNeeded)
+	}
+
+	return nil
+}
+
+// Quote a table or column name with backticks for MySQL or double quotes for Postgres
+// With QuoteWhenNeeded, names which read back the same unquoted are left as they are
+func QuoteIdentifier(flavor conn.DBFlavor, identifier string, quoting IdentifierQuoting) string {
+	if quoting == QuoteWhenNeeded && !IdentifierNeedsQuoting(flavor, identifier) {
+		return identifier
+	}
+
+	if flavor == conn.MySQL {
+		return fmt.Sprint("`", strings.ReplaceAll(identifier, "`", "``"), "`")
+	}
+
+	return fmt.Sprint(`"`, strings.ReplaceAll(identifier, `"`, `""`), `"`)
+}
+
+// Whether a name must be quoted to be read back exactly as written
+// Postgres folds unquoted names to lowercase, so Users is only Users when quoted
+func IdentifierNeedsQuoting(flavor conn.DBFlavor, identifier string) bool {
+	if identifier == "" || isDigit(rune(identifier[0])) {
+		return true
+	}
+
+	for _, char := range identifier {
+		switch {
+		case char == '_' || isDigit(char) || (char >= 'a' && char <= 'z'):
+			{
+				continue
+			}
+		case char >= 'A' && char <= 'Z':
+			{
+				if flavor == conn.PostgreSQL {
+					return true
+				}
+			}
+		case char == '$' && flavor == conn.MySQL:
+			{
+				continue
+			}
+		default:
+			{
+				return true
+			}
+		}
+	}
+
+	return IsReservedKeyword(identifier) || reservedNameKeywords[strings.ToUpper(identifier)]
+}
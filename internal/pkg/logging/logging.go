@@ -0,0 +1,84 @@
+// Internal logs of what the client does on the user's behalf, such as connecting, reconnecting and rewriting statements
+// Written to a file rather than stderr, which would draw over the terminal UI
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// How much is logged, from everything to only errors
+type Level string
+
+const (
+	// Nothing is logged, the default
+	Off   Level = "off"
+	Debug Level = "debug"
+	Info  Level = "info"
+	Warn  Level = "warn"
+	Error Level = "error"
+)
+
+var Levels = []Level{Off, Debug, Info, Warn, Error}
+
+var slogLevels = map[Level]slog.Level{
+	Debug: slog.LevelDebug,
+	Info:  slog.LevelInfo,
+	Warn:  slog.LevelWarn,
+	Error: slog.LevelError,
+}
+
+// Every level for messages, ex: off, debug, info
+func List() string {
+	names := make([]string, len(Levels))
+	for idx, level := range Levels {
+		names[idx] = string(level)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func ParseLevel(name string) (Level, error) {
+	level := Level(strings.ToLower(name))
+	if level != Off {
+		if _, isLevel := slogLevels[level]; !isLevel {
+			return "", fmt.Errorf("Log level must be one of %s, got %s", List(), name)
+		}
+	}
+
+	return level, nil
+}
+
+// Send the default slog logger to the file at path, appending to what earlier sessions logged
+// When off, everything logged is discarded and no file is created
+// Close the returned closer once done logging
+func Setup(level Level, path string) (io.Closer, error) {
+	if level == Off || level == "" {
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		return io.NopCloser(nil), nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to create log directory"),
+			err,
+		)
+	}
+
+	logFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to open log file"),
+			err,
+		)
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(logFile, &slog.HandlerOptions{Level: slogLevels[level]})))
+
+	return logFile, nil
+}
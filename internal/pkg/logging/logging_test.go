@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	level, err := ParseLevel("Warn")
+	assert.NoError(err)
+	assert.Equal(Warn, level)
+
+	level, err = ParseLevel("off")
+	assert.NoError(err)
+	assert.Equal(Off, level)
+
+	_, err = ParseLevel("verbose")
+	assert.ErrorContains(err, "Log level must be one of off, debug, info, warn, error")
+}
+
+func TestSetup(t *testing.T) {
+	assert := assert.New(t)
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	path := filepath.Join(t.TempDir(), "logs", "sql.log")
+
+	logFile, err := Setup(Info, path)
+	if !assert.NoError(err) {
+		return
+	}
+	slog.Debug("Left out")
+	slog.Info("Connected", "connection", "root@localhost")
+	assert.NoError(logFile.Close())
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Contains(string(contents), `msg=Connected connection=root@localhost`)
+	assert.NotContains(string(contents), "Left out")
+}
+
+func TestSetupOff(t *testing.T) {
+	assert := assert.New(t)
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	path := filepath.Join(t.TempDir(), "sql.log")
+
+	logFile, err := Setup(Off, path)
+	if !assert.NoError(err) {
+		return
+	}
+	slog.Error("Discarded")
+	assert.NoError(logFile.Close())
+
+	assert.NoFileExists(path)
+}
@@ -0,0 +1,180 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Follow ADD for keys, indexes and constraints added without a name
+var unnamedAdditionKeywords = []string{"PRIMARY", "UNIQUE", "INDEX", "KEY", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL", "EXCLUDE"}
+
+// Undo a schema change, for the common cases of creating something or adding a column
+// Anything else gets a TODO comment to fill in by hand, as undoing it may need data which is gone
+func GenerateDown(flavor conn.DBFlavor, statement string) string {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	todo := fmt.Sprint("-- TODO: undo ", strings.Join(strings.Fields(statement), " "))
+
+	if len(tokens) < 3 {
+		return todo
+	}
+
+	switch {
+	case tokens[0].IsKeyword("CREATE"):
+		{
+			if down, ok := generateCreateDown(flavor, tokens[1:]); ok {
+				return down
+			}
+		}
+	case tokens[0].IsKeyword("ALTER") && tokens[1].IsKeyword("TABLE"):
+		{
+			if down, ok := generateAlterTableDown(tokens[2:]); ok {
+				return down
+			}
+		}
+	}
+
+	return todo
+}
+
+// CREATE [UNIQUE] INDEX, TABLE or VIEW, with tokens following CREATE
+func generateCreateDown(flavor conn.DBFlavor, tokens []lexer.Token) (string, bool) {
+	tokens = skipKeywords(tokens, "OR", "REPLACE", "TEMPORARY", "TEMP", "UNLOGGED", "UNIQUE")
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	kind := strings.ToUpper(tokens[0].Text)
+	switch kind {
+	case "TABLE", "VIEW", "INDEX", "SEQUENCE", "SCHEMA", "DATABASE":
+	case "MATERIALIZED":
+		{
+			if len(tokens) < 2 || !tokens[1].IsKeyword("VIEW") {
+				return "", false
+			}
+			kind = "MATERIALIZED VIEW"
+			tokens = tokens[1:]
+		}
+	default:
+		{
+			return "", false
+		}
+	}
+
+	tokens = skipKeywords(tokens[1:], "CONCURRENTLY", "IF", "NOT", "EXISTS")
+	name, tokens := readName(tokens)
+	if name == "" {
+		return "", false
+	}
+
+	// MySQL indexes belong to a table, named after ON
+	if kind == "INDEX" && flavor == conn.MySQL {
+		if len(tokens) == 0 || !tokens[0].IsKeyword("ON") {
+			return "", false
+		}
+
+		tableName, _ := readName(tokens[1:])
+		return fmt.Sprintf("DROP INDEX %s ON %s", name, tableName), tableName != ""
+	}
+
+	return fmt.Sprintf("DROP %s %s", kind, name), true
+}
+
+// ALTER TABLE <table> ADD [COLUMN] <column> or ADD CONSTRAINT <name>, with tokens following ALTER TABLE
+func generateAlterTableDown(tokens []lexer.Token) (string, bool) {
+	tokens = skipKeywords(tokens, "IF", "EXISTS", "ONLY")
+	tableName, tokens := readName(tokens)
+	if tableName == "" || len(tokens) == 0 || !tokens[0].IsKeyword("ADD") {
+		return "", false
+	}
+	tokens = tokens[1:]
+
+	kind := "COLUMN"
+	switch {
+	case len(tokens) > 0 && tokens[0].IsKeyword("CONSTRAINT"):
+		{
+			kind = "CONSTRAINT"
+			tokens = tokens[1:]
+		}
+	case len(tokens) > 0 && tokens[0].IsKeyword("COLUMN"):
+		{
+			tokens = tokens[1:]
+		}
+	}
+
+	// Unnamed keys and indexes can't be dropped without knowing the name the database gave them
+	if len(skipKeywords(tokens, unnamedAdditionKeywords...)) != len(tokens) {
+		return "", false
+	}
+
+	tokens = skipKeywords(tokens, "IF", "NOT", "EXISTS")
+	name, tokens := readName(tokens)
+	// More changes in the same statement, such as another ADD, aren't undone
+	if name == "" || containsOperator(tokens, ",") {
+		return "", false
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s DROP %s %s", tableName, kind, name), true
+}
+
+func skipKeywords(tokens []lexer.Token, keywords ...string) []lexer.Token {
+	for len(tokens) > 0 {
+		isSkipped := false
+		for _, keyword := range keywords {
+			if tokens[0].IsKeyword(keyword) {
+				isSkipped = true
+				break
+			}
+		}
+
+		if !isSkipped {
+			break
+		}
+		tokens = tokens[1:]
+	}
+
+	return tokens
+}
+
+// Read a possibly qualified name, ex: public."Users", returning it as written along with the tokens after it
+func readName(tokens []lexer.Token) (string, []lexer.Token) {
+	var name strings.Builder
+
+	for len(tokens) > 0 && (tokens[0].Kind == lexer.Word || tokens[0].Kind == lexer.QuotedIdentifier) {
+		name.WriteString(tokens[0].Text)
+		tokens = tokens[1:]
+
+		if len(tokens) < 2 || !tokens[0].IsOperator(".") {
+			break
+		}
+		name.WriteString(".")
+		tokens = tokens[1:]
+	}
+
+	return name.String(), tokens
+}
+
+// Whether an operator appears outside of parentheses
+func containsOperator(tokens []lexer.Token, operator string) bool {
+	depth := 0
+	for _, token := range tokens {
+		switch {
+		case token.IsOperator("("):
+			{
+				depth++
+			}
+		case token.IsOperator(")"):
+			{
+				depth--
+			}
+		case depth == 0 && token.IsOperator(operator):
+			{
+				return true
+			}
+		}
+	}
+
+	return false
+}
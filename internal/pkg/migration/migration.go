@@ -0,0 +1,159 @@
+// Migration files for schema changes made interactively, so they can be tracked with the rest of a project's migrations
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+)
+
+// Migration tool the files are written for
+type Format string
+
+const (
+	// Separate <version>_<name>.up.sql and .down.sql files, see github.com/golang-migrate/migrate
+	GolangMigrate Format = "golang-migrate"
+	// A single <version>_<name>.sql file with annotated up and down sections, see github.com/pressly/goose
+	Goose Format = "goose"
+)
+
+// Every migration written during a session goes into the same file, ex: 20240131120000_interactive.up.sql
+const migrationName = "interactive"
+
+// Sorts in the order migrations were written, as both tools expect
+const versionFormat = "20060102150405"
+
+// Statements worth keeping in a migration, rather than changes to data
+var migratableKeywords = []string{"CREATE", "ALTER", "DROP", "RENAME", "COMMENT"}
+
+func Validate(format Format) error {
+	if format != GolangMigrate && format != Goose {
+		return fmt.Errorf("Unknown migrations format %s, expected %s or %s", format, GolangMigrate, Goose)
+	}
+
+	return nil
+}
+
+// Collects the schema changes of a session into a migration, rewriting its files as each is added
+type Writer struct {
+	directory string
+	format    Format
+	// Set when the first statement is added
+	version string
+	up      []string
+	down    []string
+}
+
+func NewWriter(directory string, format Format) *Writer {
+	return &Writer{
+		directory: directory,
+		format:    format,
+	}
+}
+
+// Whether a statement changes the schema, and so could be added to a migration
+func IsMigratable(flavor conn.DBFlavor, statement string) bool {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	return len(tokens) > 0 && slices.ContainsFunc(migratableKeywords, tokens[0].IsKeyword)
+}
+
+// Add a statement to the session's migration, along with a down migration undoing it where possible
+// Returns the path of the file the statement was added to
+func (writer *Writer) Add(flavor conn.DBFlavor, statement string) (string, error) {
+	if writer.version == "" {
+		writer.version = time.Now().UTC().Format(versionFormat)
+	}
+
+	statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+	up := append(slices.Clone(writer.up), statement)
+	// Undone in reverse order
+	down := append([]string{GenerateDown(flavor, statement)}, writer.down...)
+
+	path, err := writer.write(up, down)
+	if err != nil {
+		return "", errors.Join(
+			errors.New("Failed to write migration"),
+			err,
+		)
+	}
+
+	writer.up, writer.down = up, down
+	return path, nil
+}
+
+func (writer *Writer) write(up []string, down []string) (string, error) {
+	err := os.MkdirAll(writer.directory, 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	basePath := filepath.Join(writer.directory, fmt.Sprint(writer.version, "_", migrationName))
+
+	switch writer.format {
+	case GolangMigrate:
+		{
+			upPath := fmt.Sprint(basePath, ".up.sql")
+			err = os.WriteFile(upPath, []byte(joinStatements(up)), 0o644)
+			if err == nil {
+				err = os.WriteFile(fmt.Sprint(basePath, ".down.sql"), []byte(joinStatements(down)), 0o644)
+			}
+
+			return upPath, err
+		}
+	case Goose:
+		{
+			path := fmt.Sprint(basePath, ".sql")
+			contents := fmt.Sprintf(
+				"-- +goose Up\n%s-- +goose Down\n%s",
+				joinGooseStatements(up),
+				joinGooseStatements(down),
+			)
+
+			return path, os.WriteFile(path, []byte(contents), 0o644)
+		}
+	default:
+		{
+			return "", Validate(writer.format)
+		}
+	}
+}
+
+func joinStatements(statements []string) string {
+	var joined strings.Builder
+	for _, statement := range statements {
+		joined.WriteString(terminateStatement(statement))
+		joined.WriteString("\n\n")
+	}
+
+	return joined.String()
+}
+
+// Goose splits on semicolons, so statements containing their own, such as function bodies, are marked as a whole
+func joinGooseStatements(statements []string) string {
+	var joined strings.Builder
+	for _, statement := range statements {
+		if strings.Contains(statement, ";") && !strings.HasPrefix(statement, "--") {
+			fmt.Fprintf(&joined, "-- +goose StatementBegin\n%s\n-- +goose StatementEnd\n\n", terminateStatement(statement))
+		} else {
+			fmt.Fprint(&joined, terminateStatement(statement), "\n\n")
+		}
+	}
+
+	return joined.String()
+}
+
+// Comments, such as stubs for statements which couldn't be undone automatically, are left as they are
+func terminateStatement(statement string) string {
+	if strings.HasPrefix(statement, "--") {
+		return statement
+	}
+
+	return fmt.Sprint(statement, ";")
+}
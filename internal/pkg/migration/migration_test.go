@@ -0,0 +1,154 @@
+package migration_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/migration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDown(t *testing.T) {
+	var tests = []struct {
+		Name      string
+		Flavor    conn.DBFlavor
+		Statement string
+		Expected  string
+	}{
+		{
+			Name:      "Create table",
+			Flavor:    conn.PostgreSQL,
+			Statement: "CREATE TABLE IF NOT EXISTS public.users (id int, name text)",
+			Expected:  "DROP TABLE public.users",
+		},
+		{
+			Name:      "Create Postgres index",
+			Flavor:    conn.PostgreSQL,
+			Statement: `CREATE UNIQUE INDEX CONCURRENTLY "users_Name" ON users (name)`,
+			Expected:  `DROP INDEX "users_Name"`,
+		},
+		{
+			Name:      "Create MySQL index",
+			Flavor:    conn.MySQL,
+			Statement: "CREATE INDEX users_name ON `users` (name)",
+			Expected:  "DROP INDEX users_name ON `users`",
+		},
+		{
+			Name:      "Create materialized view",
+			Flavor:    conn.PostgreSQL,
+			Statement: "CREATE MATERIALIZED VIEW totals AS SELECT 1",
+			Expected:  "DROP MATERIALIZED VIEW totals",
+		},
+		{
+			Name:      "Add column",
+			Flavor:    conn.MySQL,
+			Statement: "ALTER TABLE users ADD COLUMN email varchar(255) NOT NULL",
+			Expected:  "ALTER TABLE users DROP COLUMN email",
+		},
+		{
+			Name:      "Add named constraint",
+			Flavor:    conn.PostgreSQL,
+			Statement: "ALTER TABLE orders ADD CONSTRAINT orders_user_fk FOREIGN KEY (user_id) REFERENCES users (id)",
+			Expected:  "ALTER TABLE orders DROP CONSTRAINT orders_user_fk",
+		},
+		{
+			Name:      "Add unnamed key",
+			Flavor:    conn.MySQL,
+			Statement: "ALTER TABLE users ADD UNIQUE (email)",
+			Expected:  "-- TODO: undo ALTER TABLE users ADD UNIQUE (email)",
+		},
+		{
+			Name:      "Several changes at once",
+			Flavor:    conn.MySQL,
+			Statement: "ALTER TABLE users ADD a int, ADD b int",
+			Expected:  "-- TODO: undo ALTER TABLE users ADD a int, ADD b int",
+		},
+		{
+			Name:      "Drop table",
+			Flavor:    conn.PostgreSQL,
+			Statement: "DROP TABLE users",
+			Expected:  "-- TODO: undo DROP TABLE users",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			assert.Equal(test.Expected, migration.GenerateDown(test.Flavor, test.Statement))
+		})
+	}
+}
+
+func TestIsMigratable(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(migration.IsMigratable(conn.PostgreSQL, "create table foo (id int)"))
+	assert.True(migration.IsMigratable(conn.MySQL, "/* add */ ALTER TABLE foo ADD bar int"))
+	assert.False(migration.IsMigratable(conn.MySQL, "TRUNCATE foo"))
+	assert.False(migration.IsMigratable(conn.MySQL, "INSERT INTO foo VALUES (1)"))
+}
+
+func TestWriter(t *testing.T) {
+	var tests = []struct {
+		Format        migration.Format
+		ExpectedFiles map[string]string
+	}{
+		{
+			Format: migration.GolangMigrate,
+			ExpectedFiles: map[string]string{
+				".up.sql":   "CREATE TABLE foo (id int);\n\nALTER TABLE foo ADD bar int;\n\n",
+				".down.sql": "ALTER TABLE foo DROP COLUMN bar;\n\nDROP TABLE foo;\n\n",
+			},
+		},
+		{
+			Format: migration.Goose,
+			ExpectedFiles: map[string]string{
+				".sql": "-- +goose Up\n" +
+					"CREATE TABLE foo (id int);\n\n" +
+					"ALTER TABLE foo ADD bar int;\n\n" +
+					"-- +goose Down\n" +
+					"ALTER TABLE foo DROP COLUMN bar;\n\n" +
+					"DROP TABLE foo;\n\n",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(string(test.Format), func(t *testing.T) {
+			assert := assert.New(t)
+
+			directory := filepath.Join(t.TempDir(), "migrations")
+			writer := migration.NewWriter(directory, test.Format)
+
+			firstPath, err := writer.Add(conn.PostgreSQL, "CREATE TABLE foo (id int);")
+			assert.NoError(err)
+			secondPath, err := writer.Add(conn.PostgreSQL, "ALTER TABLE foo ADD bar int")
+			assert.NoError(err)
+			assert.Equal(firstPath, secondPath, "Statements of a session go into the same migration")
+
+			entries, err := os.ReadDir(directory)
+			assert.NoError(err)
+			assert.Len(entries, len(test.ExpectedFiles))
+
+			for suffix, expectedContents := range test.ExpectedFiles {
+				paths, err := filepath.Glob(filepath.Join(directory, fmt.Sprint("*_interactive", suffix)))
+				assert.NoError(err)
+				if !assert.Len(paths, 1, suffix) {
+					continue
+				}
+
+				contents, err := os.ReadFile(paths[0])
+				assert.NoError(err)
+				assert.Equal(expectedContents, string(contents))
+			}
+		})
+	}
+}
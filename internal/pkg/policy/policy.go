@@ -0,0 +1,143 @@
+// Checks statements before they're sent to the database, so organizations can enforce their own guardrails
+// such as "no SELECT * on PII tables" client-side
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// How long to wait for output once the command is killed
+const waitDelay = 100 * time.Millisecond
+
+// What a hook decided about a statement
+type Verdict string
+
+const (
+	// Send the statement as it is
+	Allow Verdict = "allow"
+	// Refuse to send the statement
+	Deny Verdict = "deny"
+	// Send the statement the hook returned instead
+	Rewrite Verdict = "rewrite"
+)
+
+// A statement about to be sent, and where to
+type Request struct {
+	Statement string `json:"statement"`
+	// MySQL or PostgreSQL
+	Flavor   string `json:"flavor"`
+	Database string `json:"database"`
+	User     string `json:"user"`
+}
+
+type Decision struct {
+	Verdict Verdict `json:"decision"`
+	// Sent in place of the original, only for Rewrite
+	Statement string `json:"statement"`
+	// Shown when a statement is denied
+	Reason string `json:"reason"`
+}
+
+// Decides whether each statement may be sent before it is
+type Hook interface {
+	Check(request Request) (Decision, error)
+}
+
+// Runs a command for each statement, such as a local script
+// The request is written to its stdin as JSON, and it prints its decision as JSON, ex:
+// {"decision": "rewrite", "statement": "SELECT id FROM users"}
+// Exiting without printing anything allows the statement, while a non-zero exit denies it with what was printed to stderr
+type CommandHook struct {
+	command string
+	timeout time.Duration
+}
+
+// The command is run through sh, so it may include arguments or pipes
+func NewCommandHook(command string, timeout time.Duration) *CommandHook {
+	return &CommandHook{
+		command: command,
+		timeout: timeout,
+	}
+}
+
+func (hook *CommandHook) Check(request Request) (Decision, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hook.timeout)
+	defer cancel()
+
+	command := exec.CommandContext(ctx, "sh", "-c", hook.command)
+	command.Stdin = bytes.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	// Processes the command started could otherwise keep its output open past the timeout
+	command.WaitDelay = waitDelay
+
+	if err := command.Run(); err != nil {
+		if ctx.Err() != nil {
+			return Decision{}, fmt.Errorf("Policy hook took longer than %s", hook.timeout)
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return Decision{Verdict: Deny, Reason: strings.TrimSpace(stderr.String())}, nil
+		}
+
+		return Decision{}, errors.Join(
+			errors.New("Failed to run policy hook"),
+			err,
+		)
+	}
+
+	return parseDecision(stdout.Bytes())
+}
+
+func parseDecision(output []byte) (Decision, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return Decision{Verdict: Allow}, nil
+	}
+
+	var decision Decision
+	if err := json.Unmarshal(output, &decision); err != nil {
+		return Decision{}, errors.Join(
+			errors.New("Failed to parse policy hook output"),
+			err,
+		)
+	}
+
+	switch decision.Verdict {
+	case Allow, Deny:
+		{
+		}
+	case Rewrite:
+		{
+			if strings.TrimSpace(decision.Statement) == "" {
+				return Decision{}, errors.New("Policy hook rewrote the statement without returning one")
+			}
+		}
+	default:
+		{
+			return Decision{}, fmt.Errorf(
+				"Unknown policy decision %q, expected %s, %s or %s",
+				decision.Verdict,
+				Allow,
+				Deny,
+				Rewrite,
+			)
+		}
+	}
+
+	return decision, nil
+}
@@ -0,0 +1,66 @@
+package policy_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandHookCheck(t *testing.T) {
+	var tests = []struct {
+		Name             string
+		Command          string
+		ExpectedDecision policy.Decision
+		ExpectedError    string
+	}{
+		{"No output", "cat > /dev/null", policy.Decision{Verdict: policy.Allow}, ""},
+		{"Allow", `echo '{"decision": "allow"}'`, policy.Decision{Verdict: policy.Allow}, ""},
+		{
+			"Rewrite from input",
+			`sed 's/.*"statement":"\([^"]*\)".*/{"decision": "rewrite", "statement": "\1 LIMIT 10"}/'`,
+			policy.Decision{Verdict: policy.Rewrite, Statement: "SELECT * FROM users LIMIT 10"},
+			"",
+		},
+		{
+			"Deny",
+			`echo '{"decision": "deny", "reason": "no SELECT * on users"}'`,
+			policy.Decision{Verdict: policy.Deny, Reason: "no SELECT * on users"},
+			"",
+		},
+		{
+			"Non-zero exit",
+			`echo "users has PII" >&2; exit 1`,
+			policy.Decision{Verdict: policy.Deny, Reason: "users has PII"},
+			"",
+		},
+		{"Rewrite without statement", `echo '{"decision": "rewrite"}'`, policy.Decision{}, "without returning one"},
+		{"Unknown decision", `echo '{"decision": "maybe"}'`, policy.Decision{}, `Unknown policy decision "maybe"`},
+		{"Malformed", "echo allow", policy.Decision{}, "Failed to parse policy hook output"},
+		{"Timeout", "sleep 5", policy.Decision{}, "Policy hook took longer than 100ms"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			hook := policy.NewCommandHook(test.Command, 100*time.Millisecond)
+			decision, err := hook.Check(policy.Request{
+				Statement: "SELECT * FROM users",
+				Flavor:    "PostgreSQL",
+				Database:  "test",
+				User:      "user",
+			})
+			if test.ExpectedError != "" {
+				assert.ErrorContains(err, test.ExpectedError)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.ExpectedDecision, decision)
+		})
+	}
+}
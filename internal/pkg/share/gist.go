@@ -0,0 +1,48 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+type gistRequest struct {
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+type gistResponse struct {
+	HTMLURL string `json:"html_url"`
+}
+
+// Results may be sensitive, so gists are secret, only reachable with the link
+func (uploader *Uploader) uploadGist(ctx context.Context, paste Paste) (string, error) {
+	request := gistRequest{
+		Description: "Shared from sql",
+		Public:      false,
+		Files: map[string]gistFile{
+			paste.Filename: {Content: string(paste.Content)},
+		},
+	}
+	headers := map[string]string{
+		"Accept":        "application/vnd.github+json",
+		"Authorization": fmt.Sprint("Bearer ", uploader.token),
+	}
+
+	var response gistResponse
+	err := uploader.postJSON(ctx, request, headers, &response)
+	if err != nil {
+		return "", err
+	}
+
+	if response.HTMLURL == "" {
+		return "", errors.New("gist responded without a link")
+	}
+
+	return response.HTMLURL, nil
+}
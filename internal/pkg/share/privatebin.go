@@ -0,0 +1,184 @@
+package share
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Encryption parameters of PrivateBin's v2 paste format
+const (
+	privateBinIterations = 100000
+	privateBinKeySize    = 256
+	privateBinTagSize    = 128
+	privateBinIVSize     = 16
+	privateBinSaltSize   = 8
+	// Shared results expire, rather than staying up indefinitely
+	privateBinExpiry = "1week"
+)
+
+type privateBinRequest struct {
+	Version int            `json:"v"`
+	AData   []any          `json:"adata"`
+	CT      string         `json:"ct"`
+	Meta    privateBinMeta `json:"meta"`
+}
+
+type privateBinMeta struct {
+	Expire string `json:"expire"`
+}
+
+type privateBinResponse struct {
+	Status  int    `json:"status"`
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// PrivateBin only ever sees the encrypted paste, the key is kept in the link's fragment which browsers don't send
+func (uploader *Uploader) uploadPrivateBin(ctx context.Context, paste Paste) (string, error) {
+	key := make([]byte, privateBinKeySize/8)
+	iv := make([]byte, privateBinIVSize)
+	salt := make([]byte, privateBinSaltSize)
+	for _, randomBytes := range [][]byte{key, iv, salt} {
+		if _, err := rand.Read(randomBytes); err != nil {
+			return "", errors.Join(
+				errors.New("Failed to generate encryption key"),
+				err,
+			)
+		}
+	}
+
+	request, err := encryptPrivateBinPaste(paste, key, iv, salt)
+	if err != nil {
+		return "", err
+	}
+
+	headers := map[string]string{
+		// PrivateBin only answers with JSON when asked this way
+		"X-Requested-With": "JSONHttpRequest",
+	}
+	if uploader.token != "" {
+		headers["Authorization"] = fmt.Sprint("Bearer ", uploader.token)
+	}
+
+	var response privateBinResponse
+	err = uploader.postJSON(ctx, request, headers, &response)
+	if err != nil {
+		return "", err
+	}
+
+	if response.Status != 0 || response.ID == "" {
+		return "", fmt.Errorf("PrivateBin rejected the paste: %s", response.Message)
+	}
+
+	return fmt.Sprintf("%s?%s#%s", strings.TrimSuffix(uploader.endpoint, "/")+"/", response.ID, encodeBase58(key)), nil
+}
+
+func encryptPrivateBinPaste(paste Paste, key, iv, salt []byte) (*privateBinRequest, error) {
+	plaintext, err := json.Marshal(map[string]string{"paste": string(paste.Content)})
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to encode paste"),
+			err,
+		)
+	}
+
+	// PrivateBin calls raw deflate zlib
+	var compressed bytes.Buffer
+	compressor, _ := flate.NewWriter(&compressed, flate.DefaultCompression)
+	_, err = compressor.Write(plaintext)
+	if err == nil {
+		err = compressor.Close()
+	}
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to compress paste"),
+			err,
+		)
+	}
+
+	aData := []any{
+		[]any{
+			base64.StdEncoding.EncodeToString(iv),
+			base64.StdEncoding.EncodeToString(salt),
+			privateBinIterations,
+			privateBinKeySize,
+			privateBinTagSize,
+			"aes",
+			"gcm",
+			"zlib",
+		},
+		"plaintext",
+		// Discussion and burn after reading, both off
+		0,
+		0,
+	}
+	// The parameters are authenticated along with the paste, exactly as sent
+	additionalData, err := json.Marshal(aData)
+	if err != nil {
+		return nil, errors.Join(
+			errors.New("Failed to encode paste"),
+			err,
+		)
+	}
+
+	derivedKey := pbkdf2.Key(key, salt, privateBinIterations, privateBinKeySize/8, sha256.New)
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, privateBinIVSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := gcm.Seal(nil, iv, compressed.Bytes(), additionalData)
+
+	return &privateBinRequest{
+		Version: 2,
+		AData:   aData,
+		CT:      base64.StdEncoding.EncodeToString(ciphertext),
+		Meta:    privateBinMeta{Expire: privateBinExpiry},
+	}, nil
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58 as used by Bitcoin, which PrivateBin uses for keys in links
+func encodeBase58(data []byte) string {
+	number := new(big.Int).SetBytes(data)
+	radix := big.NewInt(int64(len(base58Alphabet)))
+	remainder := new(big.Int)
+
+	var encoded []byte
+	for number.Sign() > 0 {
+		number.DivMod(number, radix, remainder)
+		encoded = append(encoded, base58Alphabet[remainder.Int64()])
+	}
+
+	// Each leading zero byte is kept as a leading 1
+	for _, dataByte := range data {
+		if dataByte != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	for left, right := 0, len(encoded)-1; left < right; left, right = left+1, right-1 {
+		encoded[left], encoded[right] = encoded[right], encoded[left]
+	}
+
+	return string(encoded)
+}
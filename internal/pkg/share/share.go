@@ -0,0 +1,151 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Paste service a result export is uploaded to
+type Service string
+
+const (
+	// GitHub gists, always uploaded as secret gists
+	Gist Service = "gist"
+	// A PrivateBin instance, encrypted before upload with the key only in the link
+	PrivateBin Service = "privatebin"
+)
+
+// Used for gists unless another endpoint, such as GitHub Enterprise, is configured
+const defaultGistEndpoint = "https://api.github.com/gists"
+
+// How long to wait on the paste service before giving up
+const uploadTimeout = 15 * time.Second
+
+// A result export to upload, ex: result.csv
+type Paste struct {
+	Filename string
+	Content  []byte
+}
+
+// Uploads result exports to the configured paste service
+type Uploader struct {
+	service  Service
+	endpoint string
+	token    string
+}
+
+// Check a service can be used with the endpoint and token given, before anything is uploaded
+func Validate(service Service, endpoint string, token string) error {
+	switch service {
+	case Gist:
+		{
+			if token == "" {
+				return errors.New("Sharing to gists needs a GitHub token with the gist scope")
+			}
+		}
+	case PrivateBin:
+		{
+			if endpoint == "" {
+				return errors.New("Sharing to PrivateBin needs the URL of the instance as the endpoint")
+			}
+		}
+	default:
+		{
+			return fmt.Errorf("Unknown share service %s, expected %s or %s", service, Gist, PrivateBin)
+		}
+	}
+
+	return nil
+}
+
+func NewUploader(service Service, endpoint string, token string) *Uploader {
+	if service == Gist && endpoint == "" {
+		endpoint = defaultGistEndpoint
+	}
+
+	return &Uploader{
+		service:  service,
+		endpoint: endpoint,
+		token:    token,
+	}
+}
+
+func (uploader *Uploader) Service() Service {
+	return uploader.service
+}
+
+// Upload the paste, returning a link to it
+func (uploader *Uploader) Upload(paste Paste) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	switch uploader.service {
+	case Gist:
+		{
+			return uploader.uploadGist(ctx, paste)
+		}
+	case PrivateBin:
+		{
+			return uploader.uploadPrivateBin(ctx, paste)
+		}
+	default:
+		{
+			return "", fmt.Errorf("Unknown share service %s", uploader.service)
+		}
+	}
+}
+
+// POST a JSON body to the endpoint, decoding the JSON response into response
+func (uploader *Uploader) postJSON(ctx context.Context, body any, headers map[string]string, response any) error {
+	encodedBody, err := json.Marshal(body)
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to encode upload"),
+			err,
+		)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, uploader.endpoint, bytes.NewReader(encodedBody))
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to create upload request"),
+			err,
+		)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+
+	httpResponse, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to upload to %s", uploader.service),
+			err,
+		)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with %s", uploader.service, httpResponse.Status)
+	}
+
+	responseBody, err := io.ReadAll(httpResponse.Body)
+	if err == nil {
+		err = json.Unmarshal(responseBody, response)
+	}
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Unexpected response from %s", uploader.service),
+			err,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package share
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(Validate(Gist, "", "token"))
+	assert.Error(Validate(Gist, "", ""), "gists need a token")
+	assert.NoError(Validate(PrivateBin, "https://paste.example.com", ""))
+	assert.Error(Validate(PrivateBin, "", ""), "PrivateBin needs an instance")
+	assert.Error(Validate("pastebin", "https://paste.example.com", "token"))
+}
+
+func TestUploadGist(t *testing.T) {
+	assert := assert.New(t)
+
+	var sentRequest gistRequest
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal("Bearer secret", request.Header.Get("Authorization"))
+		assert.NoError(json.NewDecoder(request.Body).Decode(&sentRequest))
+
+		_, _ = writer.Write([]byte(`{"html_url": "https://gist.github.com/abc"}`))
+	}))
+	defer server.Close()
+
+	uploader := NewUploader(Gist, server.URL, "secret")
+	link, err := uploader.Upload(Paste{Filename: "result.csv", Content: []byte("id\n1")})
+
+	assert.NoError(err)
+	assert.Equal("https://gist.github.com/abc", link)
+	assert.False(sentRequest.Public, "results are shared as secret gists")
+	assert.Equal(map[string]gistFile{"result.csv": {Content: "id\n1"}}, sentRequest.Files)
+}
+
+func TestUploadFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewUploader(Gist, server.URL, "expired").Upload(Paste{Filename: "result.md"})
+	assert.ErrorContains(err, "401")
+}
+
+func TestUploadPrivateBin(t *testing.T) {
+	assert := assert.New(t)
+
+	var sentRequest privateBinRequest
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal("JSONHttpRequest", request.Header.Get("X-Requested-With"))
+		assert.NoError(json.NewDecoder(request.Body).Decode(&sentRequest))
+
+		_, _ = writer.Write([]byte(`{"status": 0, "id": "f00d"}`))
+	}))
+	defer server.Close()
+
+	link, err := NewUploader(PrivateBin, server.URL, "").Upload(Paste{Filename: "result.csv", Content: []byte("id\n1")})
+
+	assert.NoError(err)
+	assert.True(strings.HasPrefix(link, server.URL+"/?f00d#"), link)
+	assert.Equal(2, sentRequest.Version)
+}
+
+func TestEncryptPrivateBinPaste(t *testing.T) {
+	assert := assert.New(t)
+
+	key := bytes.Repeat([]byte{1}, 32)
+	iv := bytes.Repeat([]byte{2}, privateBinIVSize)
+	salt := bytes.Repeat([]byte{3}, privateBinSaltSize)
+
+	request, err := encryptPrivateBinPaste(Paste{Content: []byte("| id |\n| --- |")}, key, iv, salt)
+	if !assert.NoError(err) {
+		return
+	}
+
+	// Decrypt the same way a PrivateBin client would
+	additionalData, err := json.Marshal(request.AData)
+	assert.NoError(err)
+	ciphertext, err := base64.StdEncoding.DecodeString(request.CT)
+	assert.NoError(err)
+
+	block, err := aes.NewCipher(pbkdf2.Key(key, salt, privateBinIterations, 32, sha256.New))
+	assert.NoError(err)
+	gcm, err := cipher.NewGCMWithNonceSize(block, privateBinIVSize)
+	assert.NoError(err)
+
+	compressed, err := gcm.Open(nil, iv, ciphertext, additionalData)
+	if !assert.NoError(err) {
+		return
+	}
+	plaintext, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	assert.NoError(err)
+
+	assert.JSONEq(`{"paste": "| id |\n| --- |"}`, string(plaintext))
+}
+
+func TestEncodeBase58(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2NEpo7TZRRrLZSi2U", encodeBase58([]byte("Hello World!")))
+	assert.Equal("12NEpo7TZRRrLZSi2U", encodeBase58(append([]byte{0}, []byte("Hello World!")...)))
+}
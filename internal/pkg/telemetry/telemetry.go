@@ -0,0 +1,130 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Bumped whenever the shape of Payload changes
+const payloadVersion = 1
+
+// How long to wait on the endpoint when exiting, so a slow endpoint never holds up the terminal
+const sendTimeout = 3 * time.Second
+
+// Everything sent, in full
+// Deliberately coarse: no query text, identifiers, hosts, users or anything else specific to a session
+type Payload struct {
+	Version int    `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	Flavor  string `json:"flavor"`
+	// Times each feature was used, ex: "\\erd" or "SELECT"
+	Features map[string]int `json:"features"`
+}
+
+// Counts feature usage for the session
+// Usage is always counted in memory, so it can be shown even while off, but only sent when enabled
+type Recorder struct {
+	mutex    sync.Mutex
+	enabled  bool
+	endpoint string
+	flavor   string
+	features map[string]int
+}
+
+func NewRecorder(enabled bool, endpoint string, flavor string) *Recorder {
+	return &Recorder{
+		enabled:  enabled,
+		endpoint: endpoint,
+		flavor:   flavor,
+		features: map[string]int{},
+	}
+}
+
+func (recorder *Recorder) Enabled() bool {
+	return recorder.enabled
+}
+
+func (recorder *Recorder) Endpoint() string {
+	return recorder.endpoint
+}
+
+func (recorder *Recorder) Record(feature string) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.features[feature]++
+}
+
+// Exactly what would be sent right now
+func (recorder *Recorder) Payload() Payload {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	features := make(map[string]int, len(recorder.features))
+	for feature, count := range recorder.features {
+		features[feature] = count
+	}
+
+	return Payload{
+		Version:  payloadVersion,
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		Flavor:   recorder.flavor,
+		Features: features,
+	}
+}
+
+// Send usage to the endpoint, when enabled and anything was used
+func (recorder *Recorder) Send() error {
+	if !recorder.enabled {
+		return nil
+	}
+
+	payload := recorder.Payload()
+	if len(payload.Features) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to encode telemetry"),
+			err,
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, recorder.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to create telemetry request"),
+			err,
+		)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return errors.Join(
+			errors.New("Failed to send telemetry"),
+			err,
+		)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("Telemetry endpoint responded with %s", response.Status)
+	}
+
+	return nil
+}
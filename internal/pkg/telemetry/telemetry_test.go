@@ -0,0 +1,67 @@
+package telemetry_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderSend(t *testing.T) {
+	var tests = []struct {
+		Name         string
+		Enabled      bool
+		Features     []string
+		ExpectedSent map[string]int
+	}{
+		{
+			Name:     "Disabled",
+			Enabled:  false,
+			Features: []string{"SELECT"},
+		},
+		{
+			Name:    "Nothing Used",
+			Enabled: true,
+		},
+		{
+			Name:         "Enabled",
+			Enabled:      true,
+			Features:     []string{"SELECT", `\erd`, "SELECT"},
+			ExpectedSent: map[string]int{"SELECT": 2, `\erd`: 1},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var sentPayload *telemetry.Payload
+			server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				sentPayload = &telemetry.Payload{}
+				assert.NoError(json.NewDecoder(request.Body).Decode(sentPayload))
+			}))
+			defer server.Close()
+
+			recorder := telemetry.NewRecorder(test.Enabled, server.URL, "mysql")
+			for _, feature := range test.Features {
+				recorder.Record(feature)
+			}
+
+			assert.NoError(recorder.Send())
+
+			if test.ExpectedSent == nil {
+				assert.Nil(sentPayload)
+				return
+			}
+
+			assert.Equal(recorder.Payload(), *sentPayload)
+			assert.Equal(test.ExpectedSent, sentPayload.Features)
+			assert.Equal("mysql", sentPayload.Flavor)
+		})
+	}
+}
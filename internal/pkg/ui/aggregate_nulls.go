@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// Marks the header of an aggregate column containing NULLs, which is easily misread as zero
+const nullAggregateIndicator = "∅"
+
+// Columns named after an aggregate which is NULL when it has no values to work over, such as SUM over no rows
+// MySQL names such columns after the whole expression, ex: SUM(amount), while Postgres only uses the function, ex: sum
+// COUNT is left out as it's zero instead
+var nullableAggregateRegExp = regexp.MustCompile(
+	`(?i)^\s*(sum|avg|min|max|stddev(_pop|_samp)?|std|variance|var_pop|var_samp|bit_and|bit_or|bool_and|bool_or|every|array_agg|string_agg|group_concat)\s*(\(.*\))?\s*$`,
+)
+
+// Whether a column is an aggregate with NULLs among the rows read so far
+// Columns of a table are never taken as aggregates, even when named like one
+func isNullAggregateColumn(result *db.QueryResult, column int) bool {
+	if column < 0 || column >= len(result.Columns) {
+		return false
+	}
+	if column < len(result.ColumnTypes) && result.ColumnTypes[column].Table != "" {
+		return false
+	}
+
+	name := result.Columns[column]
+	if !nullableAggregateRegExp.MatchString(name) {
+		return false
+	}
+
+	for _, row := range result.Rows {
+		if value := row[name]; value == nil || !value.Valid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Explains what a NULL from an aggregate means, for the cell inspector
+func formatNullAggregateNote(result *db.QueryResult, column int) string {
+	return fmt.Sprintf(
+		"%s %s is NULL where it had no values to aggregate, such as no rows or only NULLs, not where they add up to zero. Wrap it in COALESCE(..., 0) for zero instead",
+		nullAggregateIndicator,
+		result.Columns[column],
+	)
+}
+
+// Ex: sum ∅ ▲
+func formatColumnHeader(result *db.QueryResult, column int, sort *resultSort) string {
+	name := result.Columns[column]
+	if isNullAggregateColumn(result, column) {
+		name = fmt.Sprint(name, " ", nullAggregateIndicator)
+	}
+
+	return formatSortedHeader(name, column, sort)
+}
+
+// Set the text of every header of a block's table, as the rows read or the sort changed
+func refreshColumnHeaders(block *resultBlock) {
+	for column := range block.result.Columns {
+		block.table.GetCell(0, column).SetText(formatColumnHeader(block.result, column, block.sort))
+	}
+}
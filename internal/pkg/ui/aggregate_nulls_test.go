@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// Single column result, with "NULL" values read as NULL
+func newTestNullableResult(column string, values ...string) *db.QueryResult {
+	result := &db.QueryResult{Columns: []string{column}}
+	for _, value := range values {
+		result.Rows = append(result.Rows, map[string]*db.NullString{
+			column: {NullString: sql.NullString{String: value, Valid: value != "NULL"}},
+		})
+	}
+
+	return result
+}
+
+func TestIsNullAggregateColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		column   string
+		table    string
+		values   []string
+		expected bool
+	}{
+		{"MySQL expression", "SUM(amount)", "", []string{"10", "NULL"}, true},
+		{"Postgres function name", "avg", "", []string{"NULL"}, true},
+		{"Without NULLs", "sum", "", []string{"10", "0"}, false},
+		{"COUNT is zero instead", "COUNT(*)", "", []string{"NULL"}, false},
+		{"Not an aggregate", "amount", "", []string{"NULL"}, false},
+		{"Table column named like one", "max", "limits", []string{"NULL"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := newTestNullableResult(test.column, test.values...)
+			result.ColumnTypes = []db.ColumnType{{Table: test.table}}
+
+			assert.Equal(t, test.expected, isNullAggregateColumn(result, 0))
+		})
+	}
+}
+
+func TestFormatColumnHeader(t *testing.T) {
+	result := newTestNullableResult("sum", "NULL")
+
+	assert.Equal(t, "sum ∅", formatColumnHeader(result, 0, nil))
+	assert.Equal(t, "sum ∅ ▼", formatColumnHeader(result, 0, &resultSort{column: 0, descending: true}))
+}
+
+func TestAppInspectNullAggregate(t *testing.T) {
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT SUM(amount) FROM refunds;": newTestNullableResult("SUM(amount)", "NULL"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT SUM(amount) FROM refunds;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("SUM(amount) ∅")
+
+	driver.pressKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("no values to aggregate")
+}
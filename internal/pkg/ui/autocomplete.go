@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const completionsPageName = "completions"
+
+// Keywords followed by a table name, so only tables are suggested after them
+var tableKeywords = map[string]bool{
+	"FROM": true, "JOIN": true, "UPDATE": true, "INTO": true, "TABLE": true, "DESCRIBE": true,
+}
+
+// Keywords worth completing, the reserved keywords plus a few common ones which aren't
+var completionKeywords = getCompletionKeywords()
+
+func getCompletionKeywords() []string {
+	keywords := append(
+		lexer.ReservedKeywords(),
+		"BEGIN", "COMMIT", "COUNT", "OFFSET", "RETURNING", "ROLLBACK", "TRUNCATE", "VIEW",
+	)
+	slices.Sort(keywords)
+
+	return keywords
+}
+
+// The word before the cursor and what it could be completed to
+type completion struct {
+	// Byte offset in the query the word starts at, replaced along with the word
+	start int
+	// As typed so far, may be empty after a qualifier such as users.
+	prefix string
+	// Columns first, then tables, then keywords, each as they'd be inserted
+	candidates []string
+}
+
+// Complete the word before the cursor, or list the choices when there are several
+// Returns false when there's no word before the cursor, so Tab is typed as usual
+func (app *App) autocomplete() bool {
+	_, start, end := app.queryTextArea.GetSelection()
+	if start != end {
+		return false
+	}
+
+	// Without the schema, keywords can still be completed
+	schema, err := app.db.GetSchema()
+	if err != nil {
+		schema = nil
+	}
+
+	flavor := app.db.GetConnectionInfo().Flavor
+	completion, canComplete := getCompletion(flavor, app.queryTextArea.GetText(), end, schema, app.uppercaseKeywords)
+	if !canComplete {
+		return false
+	}
+
+	switch len(completion.candidates) {
+	case 0:
+		{
+			return true
+		}
+	case 1:
+		{
+			app.insertCompletion(completion, completion.candidates[0])
+			return true
+		}
+	}
+
+	// Fill in as much as every candidate agrees on, before offering a choice
+	if commonPrefix := getCommonPrefix(completion.candidates); len(commonPrefix) > len(completion.prefix) {
+		app.insertCompletion(completion, commonPrefix)
+		return true
+	}
+
+	app.openCompletions(completion)
+	return true
+}
+
+func (app *App) insertCompletion(completion completion, text string) {
+	_, _, end := app.queryTextArea.GetSelection()
+
+	app.queryTextArea.Replace(completion.start, end, text)
+	app.queryTextArea.Select(completion.start+len(text), completion.start+len(text))
+}
+
+// List the candidates to pick from, closing without a choice leaves the query as it was
+func (app *App) openCompletions(completion completion) {
+	closeCompletions := func() {
+		app.pages.RemovePage(completionsPageName)
+		app.tviewApp.SetFocus(app.queryTextArea)
+	}
+
+	completionList := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+
+	completionList.
+		SetBorder(true).
+		SetTitle(" Completions ").
+		SetBackgroundColor(ColorBackground)
+
+	for _, candidate := range completion.candidates {
+		candidate := candidate
+
+		completionList.AddItem(candidate, "", 0, func() {
+			closeCompletions()
+			app.insertCompletion(completion, candidate)
+		})
+	}
+
+	completionList.SetDoneFunc(closeCompletions)
+	completionList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Tab cycles through the candidates, as it opened them
+		if event.Key() == tcell.KeyBacktab {
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}
+		if event.Key() == tcell.KeyTab {
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		}
+
+		return event
+	})
+
+	app.pages.AddPage(completionsPageName, NewModal(completionList), true, true)
+	app.tviewApp.SetFocus(completionList)
+}
+
+// What the word before the cursor could be completed to, from the keywords and the tables and columns of schema
+// After a qualifier such as u. in SELECT u. FROM users u, only the columns of that table are suggested
+// Schema may be nil, in which case only keywords are suggested
+func getCompletion(flavor conn.DBFlavor, text string, cursor int, schema *db.Schema, uppercaseKeywords bool) (completion, bool) {
+	statementStart, statementEnd := getStatementBounds(flavor, text, cursor)
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, text[statementStart:cursor]))
+	if len(tokens) == 0 {
+		return completion{}, false
+	}
+
+	// Only whole tokens ending at the cursor are completed, not ones inside strings or comments
+	lastToken := tokens[len(tokens)-1]
+	if statementStart+lastToken.Start+len(lastToken.Text) != cursor {
+		return completion{}, false
+	}
+
+	var result completion
+	var previousTokens []lexer.Token
+	switch {
+	case lastToken.Kind == lexer.Word:
+		{
+			result.start = statementStart + lastToken.Start
+			result.prefix = lastToken.Text
+			previousTokens = tokens[:len(tokens)-1]
+		}
+	case lastToken.IsOperator("."):
+		{
+			result.start = cursor
+			previousTokens = tokens
+		}
+	default:
+		{
+			return completion{}, false
+		}
+	}
+
+	var tableNames []string
+	if schema != nil {
+		for tableName := range schema.Tables {
+			tableNames = append(tableNames, tableName)
+		}
+		slices.Sort(tableNames)
+	}
+
+	var candidates []string
+	addCandidates := func(names []string, quote bool) {
+		for _, name := range names {
+			if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(result.prefix)) {
+				continue
+			}
+			if quote {
+				name = lexer.QuoteIdentifier(flavor, name, lexer.QuoteWhenNeeded)
+			}
+			if !slices.Contains(candidates, name) {
+				candidates = append(candidates, name)
+			}
+		}
+	}
+
+	// Columns of a qualified name, ex: u.na in SELECT u.na FROM users u
+	if len(previousTokens) > 1 && previousTokens[len(previousTokens)-1].IsOperator(".") {
+		qualifier := previousTokens[len(previousTokens)-2]
+		if schema == nil || qualifier.Kind != lexer.Word {
+			return result, true
+		}
+
+		aliases := getTableAliases(lexer.SignificantTokens(lexer.Tokenize(flavor, text[statementStart:statementEnd])))
+		tableName, isAlias := aliases[strings.ToLower(qualifier.Text)]
+		if !isAlias {
+			tableName = qualifier.Text
+		}
+
+		addCandidates(getTableColumns(schema, tableName), true)
+		result.candidates = candidates
+		return result, true
+	}
+
+	isTableExpected := len(previousTokens) > 0 &&
+		previousTokens[len(previousTokens)-1].Kind == lexer.Word &&
+		tableKeywords[strings.ToUpper(previousTokens[len(previousTokens)-1].Text)]
+
+	if !isTableExpected && schema != nil {
+		// Columns of the tables the statement uses, or every column before any are named
+		aliases := getTableAliases(lexer.SignificantTokens(lexer.Tokenize(flavor, text[statementStart:statementEnd])))
+		usedTables := tableNames
+		if len(aliases) > 0 {
+			usedTables = nil
+			for _, tableName := range aliases {
+				usedTables = append(usedTables, tableName)
+			}
+			slices.Sort(usedTables)
+		}
+
+		var columnNames []string
+		for _, tableName := range usedTables {
+			columnNames = append(columnNames, getTableColumns(schema, tableName)...)
+		}
+		slices.SortFunc(columnNames, func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+
+		addCandidates(columnNames, true)
+	}
+
+	addCandidates(tableNames, true)
+
+	if !isTableExpected {
+		// Keywords follow the case being typed in, unless they're uppercased anyway
+		keywords := completionKeywords
+		if !uppercaseKeywords && result.prefix != "" && result.prefix == strings.ToLower(result.prefix) {
+			keywords = make([]string, len(completionKeywords))
+			for idx, keyword := range completionKeywords {
+				keywords[idx] = strings.ToLower(keyword)
+			}
+		}
+
+		addCandidates(keywords, false)
+	}
+
+	// Nothing to add to a word which is already complete
+	if len(candidates) == 1 && candidates[0] == result.prefix {
+		candidates = nil
+	}
+
+	result.candidates = candidates
+	return result, true
+}
+
+// Byte offsets of the statement the cursor is in, when the query holds several
+func getStatementBounds(flavor conn.DBFlavor, text string, cursor int) (start int, end int) {
+	end = len(text)
+	for _, token := range lexer.Tokenize(flavor, text) {
+		if !token.IsOperator(";") {
+			continue
+		}
+
+		if token.Start < cursor {
+			start = token.Start + 1
+		} else {
+			end = token.Start
+			break
+		}
+	}
+
+	return start, end
+}
+
+// Tables named after FROM, JOIN, UPDATE and INTO, by lowercase alias or name, ex: u -> users in FROM users AS u
+func getTableAliases(tokens []lexer.Token) map[string]string {
+	aliases := make(map[string]string)
+
+	for idx := 0; idx < len(tokens); idx++ {
+		if tokens[idx].Kind != lexer.Word || !tableKeywords[strings.ToUpper(tokens[idx].Text)] {
+			continue
+		}
+
+		for idx+1 < len(tokens) && tokens[idx+1].Kind == lexer.Word {
+			tableIdx := idx + 1
+			// Only the table of a qualified name, ex: orders in sales.orders
+			for tableIdx+2 < len(tokens) && tokens[tableIdx+1].IsOperator(".") && tokens[tableIdx+2].Kind == lexer.Word {
+				tableIdx += 2
+			}
+
+			tableName := tokens[tableIdx].Text
+			if lexer.IsReservedKeyword(tableName) {
+				break
+			}
+			aliases[strings.ToLower(tableName)] = tableName
+			idx = tableIdx
+
+			aliasIdx := idx + 1
+			if aliasIdx < len(tokens) && tokens[aliasIdx].IsKeyword("AS") {
+				aliasIdx++
+			}
+			if aliasIdx < len(tokens) && tokens[aliasIdx].Kind == lexer.Word && !lexer.IsReservedKeyword(tokens[aliasIdx].Text) {
+				aliases[strings.ToLower(tokens[aliasIdx].Text)] = tableName
+				idx = aliasIdx
+			}
+
+			// More tables may follow a comma, ex: FROM users u, orders o
+			if idx+1 >= len(tokens) || !tokens[idx+1].IsOperator(",") {
+				break
+			}
+			idx++
+		}
+	}
+
+	return aliases
+}
+
+// Column names of a table, matching its name case insensitively as unquoted names are
+func getTableColumns(schema *db.Schema, tableName string) (columnNames []string) {
+	for name, table := range schema.Tables {
+		if !strings.EqualFold(name, tableName) {
+			continue
+		}
+
+		for _, column := range table.Columns {
+			columnNames = append(columnNames, column.Name)
+		}
+	}
+
+	return columnNames
+}
+
+// Longest prefix every candidate starts with, ignoring case, in the case of the first candidate
+func getCommonPrefix(candidates []string) string {
+	commonPrefix := []rune(candidates[0])
+	for _, candidate := range candidates[1:] {
+		candidateRunes := []rune(strings.ToLower(candidate))
+
+		length := 0
+		for length < len(commonPrefix) && length < len(candidateRunes) &&
+			strings.ToLower(string(commonPrefix[length])) == string(candidateRunes[length]) {
+			length++
+		}
+		commonPrefix = commonPrefix[:length]
+	}
+
+	return string(commonPrefix)
+}
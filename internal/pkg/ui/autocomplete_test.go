@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCompletion(t *testing.T) {
+	schema := &db.Schema{
+		Flavor: conn.PostgreSQL,
+		Tables: map[string]*db.TableSchema{
+			"users": {
+				Columns: []db.ColumnSchema{{Name: "id"}, {Name: "name"}, {Name: "Nickname"}},
+			},
+			"orders": {
+				Columns: []db.ColumnSchema{{Name: "id"}, {Name: "user_id"}, {Name: "total"}},
+			},
+		},
+	}
+
+	var tests = []struct {
+		Name               string
+		Text               string
+		Cursor             int
+		Schema             *db.Schema
+		ExpectedStart      int
+		ExpectedCandidates []string
+		ExpectedOk         bool
+	}{
+		{"Keyword", "SEL", 3, schema, 0, []string{"SELECT"}, true},
+		{"Keyword in the case typed", "select * fr", 11, schema, 9, []string{"from"}, true},
+		{"Table after FROM", "SELECT * FROM u", 15, schema, 14, []string{"users"}, true},
+		{"Columns of the tables used", "SELECT n FROM users", 8, schema, 7, []string{"name", `"Nickname"`, "natural", "not", "null"}, true},
+		{"Columns of an alias", "SELECT o. FROM orders o JOIN users u ON u.id = o.user_id", 9, schema, 9, []string{"id", "user_id", "total"}, true},
+		{"Columns of a table", "SELECT users.n", 14, schema, 13, []string{"name", `"Nickname"`}, true},
+		{"Every column before any table", "SELECT to", 9, schema, 7, []string{"total"}, true},
+		{"Only the statement at the cursor", "SELECT * FROM orders; SELECT I FROM users", 30, schema, 29, []string{"id", "IN", "INDEX", "INNER", "INSERT", "INTERVAL", "INTO", "IS"}, true},
+		{"Without a schema", "SELECT * FROM u", 15, nil, 14, nil, true},
+		{"Already complete", "SELECT", 6, schema, 0, nil, true},
+		{"After a space", "SELECT ", 7, schema, 0, nil, false},
+		{"Inside a string", "SELECT 'us", 10, schema, 0, nil, false},
+		{"Empty", "", 0, schema, 0, nil, false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			completion, ok := getCompletion(conn.PostgreSQL, test.Text, test.Cursor, test.Schema, false)
+			assert.Equal(test.ExpectedOk, ok)
+			assert.Equal(test.ExpectedStart, completion.start)
+			assert.Equal(test.ExpectedCandidates, completion.candidates)
+		})
+	}
+}
+
+func TestGetCommonPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("user_", getCommonPrefix([]string{"user_id", "user_name"}))
+	assert.Equal("IN", getCommonPrefix([]string{"INSERT", "into", "INNER"}))
+	assert.Equal("", getCommonPrefix([]string{"id", "name"}))
+}
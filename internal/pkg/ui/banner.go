@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+const (
+	showTablesButtonLabel    = "Show tables"
+	schemaBrowserButtonLabel = "Open schema browser"
+	dismissBannerButtonLabel = "Dismiss"
+)
+
+// Show what's connected to above the first result, so it's clear where statements will run
+func (app *App) showBanner(serverInfo *db.ServerInfo) {
+	connectionInfo := app.db.GetConnectionInfo()
+
+	bannerText := NewTextView(TextViewPrimary).
+		SetText(fmt.Sprintf("Connected to %s as %s\n%s", serverInfo.Version, connectionInfo.Label(), formatServerInfo(serverInfo)))
+
+	banner := NewGrid().SetGap(0, 2)
+	bannerHeight := 2
+
+	showTablesButton := NewButton(showTablesButtonLabel).SetSelectedFunc(func() {
+		app.commitQuery("SHOW TABLES;")
+		app.tviewApp.SetFocus(app.queryTextArea)
+	})
+	schemaBrowserButton := NewButton(schemaBrowserButtonLabel).SetSelectedFunc(app.openSchemaBrowser)
+	dismissButton := NewButton(dismissBannerButtonLabel).SetSelectedFunc(func() {
+		app.resultContainer.RemoveItem(banner)
+		app.tviewApp.SetFocus(app.queryTextArea)
+	})
+
+	banner.
+		SetRows(1, 1).
+		SetColumns(0, len(showTablesButtonLabel), len(schemaBrowserButtonLabel), len(dismissBannerButtonLabel)).
+		AddItem(bannerText, 0, 0, 2, 1, 0, 0, false).
+		AddItem(showTablesButton, 0, 1, 1, 1, 0, 0, true).
+		AddItem(schemaBrowserButton, 0, 2, 1, 1, 0, 0, true).
+		AddItem(dismissButton, 0, 3, 1, 1, 0, 0, true)
+
+	// Writes will be refused, which is easy to mistake for a hanging or broken statement
+	if serverInfo.ReadOnly {
+		readOnlyWarning := NewTextView(TextViewError).SetText(readOnlyServerWarning)
+		banner.
+			SetRows(1, 1, 1).
+			AddItem(readOnlyWarning, 2, 0, 1, 4, 0, 0, false)
+		bannerHeight++
+	}
+
+	app.resultContainer.AddBlock(banner, bannerHeight)
+}
+
+// Ex: UTF8 | Time zone UTC | Read-write | Up 3d 4h | 12 connections
+func formatServerInfo(serverInfo *db.ServerInfo) string {
+	var details []string
+
+	if serverInfo.CharacterSet != "" {
+		details = append(details, serverInfo.CharacterSet)
+	}
+	if serverInfo.TimeZone != "" {
+		details = append(details, fmt.Sprint("Time zone ", serverInfo.TimeZone))
+	}
+
+	if serverInfo.ReadOnly {
+		details = append(details, "Read-only")
+	} else {
+		details = append(details, "Read-write")
+	}
+
+	if serverInfo.Uptime > 0 {
+		details = append(details, fmt.Sprint("Up ", formatUptime(serverInfo.Uptime)))
+	}
+
+	switch {
+	case serverInfo.Connections == 1:
+		{
+			details = append(details, "1 connection")
+		}
+	case serverInfo.Connections >= 0:
+		{
+			details = append(details, fmt.Sprintf("%d connections", serverInfo.Connections))
+		}
+	}
+
+	return strings.Join(details, " | ")
+}
+
+// Only the two largest units, ex: 3d 4h, 5h 12m or 42m
+func formatUptime(uptime time.Duration) string {
+	days := int(uptime.Hours()) / 24
+	hours := int(uptime.Hours()) % 24
+	minutes := int(uptime.Minutes()) % 60
+
+	switch {
+	case days > 0:
+		{
+			return fmt.Sprintf("%dd %dh", days, hours)
+		}
+	case hours > 0:
+		{
+			return fmt.Sprintf("%dh %dm", hours, minutes)
+		}
+	default:
+		{
+			return fmt.Sprintf("%dm", minutes)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	bookmarksPageName = "bookmarks"
+	bookmarkIndicator = "★"
+	// Queries are cut off in the bookmark list past this length
+	bookmarkQueryPreviewLength = 80
+)
+
+func (app *App) toggleBookmark(block *resultBlock) {
+	if block == nil {
+		return
+	}
+
+	block.bookmarked = !block.bookmarked
+	block.queryTextView.SetText(formatQueryText(block))
+}
+
+func (app *App) getBookmarkedBlocks() (bookmarkedBlocks []*resultBlock) {
+	for _, block := range app.resultBlocks {
+		if block.bookmarked {
+			bookmarkedBlocks = append(bookmarkedBlocks, block)
+		}
+	}
+
+	return bookmarkedBlocks
+}
+
+// Open a quick-jump palette listing all bookmarked blocks
+// Closing the palette without a selection returns focus to returnFocus
+func (app *App) openBookmarks(returnFocus tview.Primitive) {
+	closeBookmarks := func() {
+		app.pages.RemovePage(bookmarksPageName)
+	}
+
+	bookmarkList := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+
+	bookmarkList.
+		SetBorder(true).
+		SetTitle(" Bookmarks ").
+		SetBackgroundColor(ColorBackground)
+
+	bookmarkedBlocks := app.getBookmarkedBlocks()
+	if len(bookmarkedBlocks) == 0 {
+		bookmarkList.AddItem(
+			fmt.Sprintf("No bookmarks, press '%c' on a result table to add one", toggleBookmarkKey),
+			"",
+			0,
+			nil,
+		)
+	}
+
+	for idx, block := range bookmarkedBlocks {
+		block := block
+
+		var shortcut rune
+		if idx < 9 {
+			shortcut = rune('1' + idx)
+		}
+
+		bookmarkList.AddItem(getQueryPreview(block.query), "", shortcut, func() {
+			closeBookmarks()
+			app.jumpToBlock(block)
+		})
+	}
+
+	bookmarkList.SetDoneFunc(func() {
+		closeBookmarks()
+		app.tviewApp.SetFocus(returnFocus)
+	})
+	bookmarkList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// Pressing the same key again closes the palette
+		if event.Key() == tcell.KeyRune && event.Rune() == openBookmarksKey {
+			closeBookmarks()
+			app.tviewApp.SetFocus(returnFocus)
+			return nil
+		}
+
+		return event
+	})
+
+	app.pages.AddPage(bookmarksPageName, NewModal(bookmarkList), true, true)
+	app.tviewApp.SetFocus(bookmarkList)
+}
+
+// Scroll a block into view, focusing its table if it has one
+func (app *App) jumpToBlock(block *resultBlock) {
+	app.resultContainer.ScrollToBlockOf(block.queryView)
+
+	if block.isTableShown() {
+		app.focusResultTable(block.table)
+		return
+	}
+
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// Single line version of a query, for display in lists
+func getQueryPreview(query string) string {
+	preview := strings.Join(strings.Fields(query), " ")
+
+	if len([]rune(preview)) > bookmarkQueryPreviewLength {
+		preview = string([]rune(preview)[:bookmarkQueryPreviewLength-1]) + "…"
+	}
+
+	return preview
+}
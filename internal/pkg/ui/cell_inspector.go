@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	cellInspectorPageName = "cell-inspector"
+	// Lines below the value for a note on what it means
+	cellInspectorNoteHeight = 3
+	copyValueLabel          = "Copy"
+	copiedValueLabel        = "Copied"
+)
+
+// Show the full value of a result cell in a modal, wrapped, with JSON objects and arrays indented
+// Closing the modal returns focus to the table
+func (app *App) openCellInspector(table *tview.Table, row, column int) {
+	closeInspector := func() {
+		app.pages.RemovePage(cellInspectorPageName)
+		app.tviewApp.SetFocus(table)
+	}
+
+	columnName := table.GetCell(0, column).Text
+	value := getCellValue(table, row, column)
+	shownValue := value
+	isNull := isNullCell(table, row, column)
+	note := ""
+	// The same text as the cell, so privacy mode isn't undone by inspecting it
+	if block := app.getResultBlockForTable(table); block != nil && block.result != nil {
+		columnName = block.result.Columns[column]
+		shownValue = table.GetCell(row, column).Text
+
+		if isNull && isNullAggregateColumn(block.result, column) {
+			note = formatNullAggregateNote(block.result, column)
+		}
+	}
+
+	title := fmt.Sprintf(" %s (row %d) ", columnName, row)
+	if isNull {
+		// The configured token may be empty, or not read as NULL
+		title = fmt.Sprintf(" %s (row %d, NULL) ", columnName, row)
+	} else if indentedValue, isJSON := indentJSON(shownValue); isJSON {
+		shownValue = indentedValue
+		title = fmt.Sprintf(" %s (row %d, JSON) ", columnName, row)
+	}
+
+	valueView := NewTextView(TextViewPrimary).
+		SetText(shownValue).
+		SetWrap(true).
+		SetWordWrap(true).
+		SetScrollable(true)
+
+	// Copies the value as it is in the result, rather than indented
+	copyButton := NewButton(copyValueLabel)
+	copyValue := func() {
+		if app.copyToClipboard([]byte(value)) {
+			copyButton.SetLabel(copiedValueLabel)
+		}
+	}
+	copyButton.
+		SetSelectedFunc(func() {
+			copyValue()
+			app.tviewApp.SetFocus(valueView)
+		}).
+		SetExitFunc(func(key tcell.Key) {
+			app.tviewApp.SetFocus(valueView)
+		})
+
+	valueView.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyTab || key == tcell.KeyBacktab {
+			app.tviewApp.SetFocus(copyButton)
+			return
+		}
+
+		closeInspector()
+	})
+	valueView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == copyCellKey {
+			copyValue()
+			return nil
+		}
+
+		return event
+	})
+
+	buttonRow := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(copyButton, len(copyValueLabel)+2, 0, false)
+
+	inspector := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(valueView, 0, 1, true)
+	if note != "" {
+		noteView := NewTextView(TextViewSecondary).
+			SetText(note).
+			SetWrap(true).
+			SetWordWrap(true)
+		inspector.AddItem(noteView, cellInspectorNoteHeight, 0, false)
+	}
+	inspector.AddItem(buttonRow, 1, 0, false)
+
+	inspector.
+		SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(cellInspectorPageName, NewModal(inspector), true, true)
+	app.tviewApp.SetFocus(valueView)
+}
+
+// A JSON object or array indented two spaces a level, so nested values can be followed
+// Other values are left as they are, even when valid JSON such as a number
+func indentJSON(value string) (indented string, isJSON bool) {
+	trimmedValue := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmedValue, "{") && !strings.HasPrefix(trimmedValue, "[") {
+		return value, false
+	}
+
+	var indentedValue bytes.Buffer
+	if err := json.Indent(&indentedValue, []byte(trimmedValue), "", "  "); err != nil {
+		return value, false
+	}
+
+	return indentedValue.String(), true
+}
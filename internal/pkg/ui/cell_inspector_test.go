@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndentJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		value          string
+		expectedValue  string
+		expectedIsJSON bool
+	}{
+		{"Object", `{"id":1,"tags":["a"]}`, "{\n  \"id\": 1,\n  \"tags\": [\n    \"a\"\n  ]\n}", true},
+		{"Array with spacing", ` [1, 2] `, "[\n  1,\n  2\n]", true},
+		{"Number", "42", "42", false},
+		{"Invalid", `{"id":`, `{"id":`, false},
+		{"Text", "hello", "hello", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			value, isJSON := indentJSON(test.value)
+			assert.Equal(t, test.expectedValue, value)
+			assert.Equal(t, test.expectedIsJSON, isJSON)
+		})
+	}
+}
+
+func TestAppInspectJSONCell(t *testing.T) {
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT settings FROM users;": newTestResult("settings", `{"theme":"dark","beta":true}`),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT settings FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("1 row in")
+
+	driver.pressKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("settings (row 1, JSON)")
+	driver.waitForScreen(`"theme": "dark",`)
+	driver.waitForScreen(copyValueLabel)
+
+	// Escape goes back to the table
+	driver.pressKey(tcell.KeyEscape, 0, tcell.ModNone)
+	driver.waitFor("the inspector to close", func() bool {
+		isOpen := true
+		driver.read(func() { isOpen = driver.app.pages.HasPage(cellInspectorPageName) })
+		return !isOpen
+	})
+}
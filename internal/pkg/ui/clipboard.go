@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"errors"
+	"sync"
+
+	"golang.design/x/clipboard"
+)
+
+var (
+	initClipboardOnce sync.Once
+	// Set when the clipboard couldn't be set up, such as over SSH without a display
+	clipboardInitErr error
+)
+
+// Write text to the system clipboard
+// Set up on first use rather than at startup, as probing for it can be slow and many sessions never copy anything
+func writeClipboard(content []byte) error {
+	initClipboardOnce.Do(func() {
+		clipboardInitErr = clipboard.Init()
+	})
+	if clipboardInitErr != nil {
+		return errors.Join(
+			errors.New("No access to clipboard"),
+			clipboardInitErr,
+		)
+	}
+
+	clipboard.Write(clipboard.FmtText, content)
+	return nil
+}
+
+// Copy to the system clipboard, telling the user when it isn't available
+func (app *App) copyToClipboard(content []byte) (copied bool) {
+	if err := writeClipboard(content); err != nil {
+		app.showMessage(err.Error(), app.tviewApp.GetFocus())
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Show type information for a result column in the status bar, while its header is selected or hovered
+func (app *App) registerColumnHints(table *tview.Table, result *db.QueryResult) {
+	isHoveringHeader := false
+
+	table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseMove {
+			return action, event
+		}
+
+		hint := ""
+		if x, y := event.Position(); table.InRect(x, y) {
+			if row, column := table.CellAt(x, y); row >= 0 && row < app.getFirstResultRow() && column >= 0 {
+				hint = getColumnHint(result, column)
+			}
+		}
+
+		if hint != "" {
+			isHoveringHeader = true
+			app.setColumnHint(hint)
+		} else if isHoveringHeader {
+			isHoveringHeader = false
+			app.setColumnHint("")
+		}
+
+		return action, event
+	})
+}
+
+func (app *App) setColumnHint(hint string) {
+	if hint == app.columnHint {
+		return
+	}
+
+	app.columnHint = hint
+	app.updateStatusBar()
+}
+
+// Describe a result column, ex: id: BIGINT, not null, from users
+// Empty when there's no type information for the column
+func getColumnHint(result *db.QueryResult, column int) string {
+	if column < 0 || column >= len(result.ColumnTypes) || column >= len(result.Columns) {
+		return ""
+	}
+
+	columnType := result.ColumnTypes[column]
+
+	databaseType := columnType.DatabaseType
+	if databaseType == "" {
+		databaseType = "unknown type"
+	}
+	details := []string{databaseType}
+
+	if columnType.Nullable != nil {
+		if *columnType.Nullable {
+			details = append(details, "nullable")
+		} else {
+			details = append(details, "not null")
+		}
+	}
+
+	if columnType.Table != "" {
+		details = append(details, fmt.Sprint("from ", columnType.Table))
+	}
+
+	if isNullAggregateColumn(result, column) {
+		details = append(details, "NULL where there was nothing to aggregate, not zero")
+	}
+
+	return fmt.Sprintf("%s: %s", result.Columns[column], strings.Join(details, ", "))
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetColumnHint(t *testing.T) {
+	assert := assert.New(t)
+
+	notNull := false
+	nullable := true
+	result := &db.QueryResult{
+		Columns: []string{"id", "email", "total"},
+		ColumnTypes: []db.ColumnType{
+			{DatabaseType: "BIGINT", Nullable: &notNull, Table: "users"},
+			{DatabaseType: "VARCHAR", Nullable: &nullable},
+			{},
+		},
+	}
+
+	assert.Equal("id: BIGINT, not null, from users", getColumnHint(result, 0))
+	assert.Equal("email: VARCHAR, nullable", getColumnHint(result, 1))
+	assert.Equal("total: unknown type", getColumnHint(result, 2))
+	assert.Equal("", getColumnHint(result, 3))
+	assert.Equal("", getColumnHint(result, -1))
+
+	// Results which didn't come from the database have no type information
+	assert.Equal("", getColumnHint(&db.QueryResult{Columns: []string{"id"}}, 0))
+}
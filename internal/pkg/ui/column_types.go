@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Row of result tables below the header showing each column's type, when turned on
+const columnTypeRow = 1
+
+// Row of result tables the result's first row is on, after the header and the column types when shown
+func (app *App) getFirstResultRow() int {
+	if app.display.ColumnTypes {
+		return columnTypeRow + 1
+	}
+
+	return 1
+}
+
+// Each column's type as the database names it, ex: bigint, blank when the driver doesn't report it
+// The row can't be selected, so moving the cell cursor skips over it
+func addColumnTypeRow(table *tview.Table, result *db.QueryResult) {
+	for columnIdx := range result.Columns {
+		table.SetCell(
+			columnTypeRow,
+			columnIdx,
+			tview.NewTableCell(strings.ToLower(getDatabaseType(result, columnIdx))).
+				SetTextColor(ColorSecondary).
+				SetAttributes(tcell.AttrDim).
+				SetSelectable(false),
+		)
+	}
+}
+
+// Move the cell cursor between the header and the first row, keeping its column
+// The table would otherwise look for a selectable cell along the column types row, ending up in another column
+// Returns whether the cursor was moved
+func (app *App) skipColumnTypeRow(table *tview.Table, direction int) bool {
+	if !app.display.ColumnTypes {
+		return false
+	}
+
+	row, column := table.GetSelection()
+	if row+direction != columnTypeRow {
+		return false
+	}
+
+	table.Select(row+direction*2, column)
+	return true
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResultViewHeightColumnTypes(t *testing.T) {
+	result := newTestResult("id", "1", "2", "3")
+
+	app := &App{display: config.DisplayConfig{ColumnTypes: true}}
+	assert.Equal(t, (3+2)*2+1+2, app.getResultViewHeight(result))
+
+	app.display.CompactTables = true
+	assert.Equal(t, 3+2+2, app.getResultViewHeight(result))
+}
+
+func TestAppColumnTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.Default()
+	cfg.Display.ColumnTypes = true
+
+	result := newTestResultRows(
+		[]string{"id", "name"},
+		[]string{"1", "ada"},
+		[]string{"2", "grace"},
+	)
+	result.ColumnTypes = []db.ColumnType{{DatabaseType: "BIGINT"}, {DatabaseType: "VARCHAR"}}
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT id, name FROM people;": result,
+	}}
+	driver := startTestAppWithConfig(t, database, cfg)
+
+	driver.typeText("SELECT id, name FROM people;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("grace")
+
+	// Types are in a row of their own, right above the first row
+	bigintColumn, typeLine, _ := driver.findOnScreen("bigint")
+	_, varcharLine, _ := driver.findOnScreen("varchar")
+	adaColumn, adaLine, _ := driver.findOnScreen("ada")
+	assert.Equal(typeLine, varcharLine)
+	assert.Equal(typeLine+2, adaLine)
+	assert.Less(bigintColumn, adaColumn)
+
+	driver.pressKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)
+	driver.pressKey(tcell.KeyRight, 0, tcell.ModNone)
+
+	selection := func() (row int, column int) {
+		driver.read(func() {
+			row, column = driver.app.resultBlocks[0].table.GetSelection()
+		})
+		return row, column
+	}
+	driver.waitFor("the first row's name to be selected", func() bool {
+		row, column := selection()
+		return row == 2 && column == 1
+	})
+
+	// Up and Down skip over the types, staying in the same column
+	driver.pressKey(tcell.KeyUp, 0, tcell.ModNone)
+	driver.waitFor("the name header to be selected", func() bool {
+		row, column := selection()
+		return row == 0 && column == 1
+	})
+
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.waitFor("the first row's name to be selected again", func() bool {
+		row, column := selection()
+		return row == 2 && column == 1
+	})
+
+	driver.pressKey(tcell.KeyRune, selectRowKey, tcell.ModNone)
+	driver.waitForScreen("1 selected")
+
+	var selectedNames []string
+	driver.read(func() {
+		for _, row := range driver.app.getSelectedResult(driver.app.resultBlocks[0]).Rows {
+			selectedNames = append(selectedNames, row["name"].ToString())
+		}
+	})
+	assert.Equal([]string{"ada"}, selectedNames)
+}
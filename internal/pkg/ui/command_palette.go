@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const commandPalettePageName = "command_palette"
+
+// Something the command palette can run, found by searching its name
+type paletteCommand struct {
+	name string
+	run  func()
+}
+
+// Search every command by name, running the one chosen
+func (app *App) openCommandPalette() {
+	app.openPalette(" Commands ", app.getPaletteCommands())
+}
+
+func (app *App) getPaletteCommands() []paletteCommand {
+	safeModeCommand := paletteCommand{"Turn on safe mode", func() { app.commitQuery(`\safe on`) }}
+	if app.db.IsSafeMode() {
+		safeModeCommand = paletteCommand{"Turn off safe mode", func() { app.commitQuery(`\safe off`) }}
+	}
+
+	privacyModeCommand := paletteCommand{"Turn on privacy mode", app.togglePrivacyMode}
+	if app.privacyMode {
+		privacyModeCommand = paletteCommand{"Turn off privacy mode", app.togglePrivacyMode}
+	}
+
+	return []paletteCommand{
+		{"Export latest result", app.exportLatestResult},
+		{"Clear results", app.clearResults},
+		{"Switch database", app.openDatabasePalette},
+		safeModeCommand,
+		privacyModeCommand,
+		{"Toggle schema browser", app.toggleSchemaBrowser},
+		{"Focus latest result", app.focusLatestResultTable},
+		{"Search results", app.openResultsSearch},
+		{"Open bookmarks", func() { app.openBookmarks(app.queryTextArea) }},
+		{"Show keyboard shortcuts", func() { app.showHelp(app.queryTextArea) }},
+		{"Write bug report", func() { app.commitQuery(`\bugreport`) }},
+	}
+}
+
+// List the server's databases, switching to the one chosen
+func (app *App) openDatabasePalette() {
+	databases, err := app.querySchemaNames("SHOW DATABASES")
+	if err != nil {
+		app.showMessage(err.Error(), app.queryTextArea)
+		return
+	}
+
+	flavor := app.db.GetConnectionInfo().Flavor
+	commands := make([]paletteCommand, 0, len(databases))
+	for _, database := range databases {
+		statement := fmt.Sprint("USE ", lexer.QuoteIdentifier(flavor, database, lexer.QuoteWhenNeeded))
+		commands = append(commands, paletteCommand{database, func() { app.commitQuery(statement) }})
+	}
+
+	app.openPalette(" Switch database ", commands)
+}
+
+func (app *App) exportLatestResult() {
+	for idx := len(app.resultBlocks) - 1; idx >= 0; idx-- {
+		if block := app.resultBlocks[idx]; block.result != nil && len(block.result.Columns) > 0 {
+			app.openExport(block)
+			return
+		}
+	}
+
+	app.showMessage("No result to export", app.queryTextArea)
+}
+
+// Filter field above a list of commands
+// Typing narrows the list, the arrow keys move through it, and Enter runs the selected command
+func (app *App) openPalette(title string, commands []paletteCommand) {
+	closePalette := func() {
+		app.pages.RemovePage(commandPalettePageName)
+		app.tviewApp.SetFocus(app.queryTextArea)
+	}
+
+	commandList := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+	commandList.SetBackgroundColor(ColorBackground)
+
+	var filteredCommands []paletteCommand
+	showCommands := func(filter string) {
+		filteredCommands = filterPaletteCommands(commands, filter)
+
+		commandList.Clear()
+		for _, command := range filteredCommands {
+			commandList.AddItem(command.name, "", 0, nil)
+		}
+	}
+	showCommands("")
+
+	filterField := tview.NewInputField().
+		SetLabel("> ").
+		SetPlaceholder("Search").
+		SetChangedFunc(showCommands)
+
+	filterField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if action, isBound := app.keymap.Lookup(event); isBound && action == keymap.OpenCommandPalette {
+			closePalette()
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			{
+				closePalette()
+				return nil
+			}
+		case tcell.KeyEnter:
+			{
+				selected := commandList.GetCurrentItem()
+				if selected < 0 || selected >= len(filteredCommands) {
+					return nil
+				}
+
+				closePalette()
+				filteredCommands[selected].run()
+				return nil
+			}
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			{
+				// The list moves while typing stays in the filter
+				commandList.InputHandler()(event, nil)
+				return nil
+			}
+		}
+
+		return event
+	})
+
+	palette := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(filterField, 1, 0, true).
+		AddItem(commandList, 0, 1, false)
+	palette.
+		SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(commandPalettePageName, NewModal(palette), true, true)
+	app.tviewApp.SetFocus(filterField)
+}
+
+// Commands whose name contains every word of the filter, ignoring case
+func filterPaletteCommands(commands []paletteCommand, filter string) []paletteCommand {
+	words := strings.Fields(strings.ToLower(filter))
+
+	var filteredCommands []paletteCommand
+	for _, command := range commands {
+		name := strings.ToLower(command.name)
+
+		isMatch := true
+		for _, word := range words {
+			if !strings.Contains(name, word) {
+				isMatch = false
+				break
+			}
+		}
+
+		if isMatch {
+			filteredCommands = append(filteredCommands, command)
+		}
+	}
+
+	return filteredCommands
+}
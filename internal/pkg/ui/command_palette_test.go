@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterPaletteCommands(t *testing.T) {
+	commands := []paletteCommand{
+		{name: "Export latest result"},
+		{name: "Clear results"},
+		{name: "Switch database"},
+	}
+
+	var tests = []struct {
+		Filter        string
+		ExpectedNames []string
+	}{
+		{"", []string{"Export latest result", "Clear results", "Switch database"}},
+		{"result", []string{"Export latest result", "Clear results"}},
+		{"RESULT exp", []string{"Export latest result"}},
+		{"  db  ", nil},
+	}
+
+	for _, test := range tests {
+		var names []string
+		for _, command := range filterPaletteCommands(commands, test.Filter) {
+			names = append(names, command.name)
+		}
+
+		assert.Equal(t, test.ExpectedNames, names, test.Filter)
+	}
+}
+
+func TestAppCommandPalette(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT 1;":      newTestResult("?column?", "1"),
+		"SHOW DATABASES": newTestResult("Database", "analytics", "test"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT 1;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("?column?")
+
+	driver.pressKey(tcell.KeyCtrlP, 0, tcell.ModCtrl)
+	driver.waitForScreen("Switch database")
+	driver.typeText("clear")
+	driver.waitFor("the commands to be filtered", func() bool {
+		return !strings.Contains(driver.screenText(), "Switch database")
+	})
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the results to be cleared", func() bool {
+		return !strings.Contains(driver.screenText(), "?column?")
+	})
+
+	driver.pressKey(tcell.KeyCtrlP, 0, tcell.ModCtrl)
+	driver.typeText("database")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen(" Switch database ")
+	driver.waitForScreen("analytics")
+
+	// Down past analytics to the second database
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the database to be switched", func() bool {
+		statements := database.getStatements()
+		return statements[len(statements)-1] == "USE test"
+	})
+
+	driver.pressKey(tcell.KeyCtrlP, 0, tcell.ModCtrl)
+	driver.typeText("safe")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("safe mode to be turned on", func() bool {
+		statements := database.getStatements()
+		return statements[len(statements)-1] == `\safe on`
+	})
+
+	assert.Equal(
+		[]string{"SELECT 1;", "SHOW DATABASES", "USE test", `\safe on`},
+		database.getStatements(),
+	)
+}
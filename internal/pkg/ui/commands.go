@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// Commands starting with a backslash which need the UI, such as to open a form
+// Checked before a statement is sent to the database client, which handles all other commands
+type uiCommand func(app *App, argument string)
+
+var uiCommands map[string]uiCommand
+
+// Filled in on init, as the commands run statements which in turn check for commands
+func init() {
+	uiCommands = map[string]uiCommand{
+		"insert":  (*App).insertCommand,
+		"watch":   (*App).watchCommand,
+		"restore": (*App).restoreCommand,
+	}
+}
+
+func isUICommand(statement string) bool {
+	name, _, isMetaCommand := db.ParseMetaCommand(statement)
+	_, exists := uiCommands[name]
+
+	return isMetaCommand && exists
+}
+
+// Run the statement if it's a UI command, reporting whether it was
+func (app *App) runUICommand(statement string) (isUICommand bool) {
+	name, argument, isMetaCommand := db.ParseMetaCommand(statement)
+	if !isMetaCommand {
+		return false
+	}
+
+	command, exists := uiCommands[name]
+	if !exists {
+		return false
+	}
+
+	app.addHistoryEntry(statement)
+	command(app, argument)
+
+	return true
+}
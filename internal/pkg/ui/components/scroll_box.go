@@ -1,13 +1,19 @@
 package components
 
 import (
+	"slices"
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/rivo/uniseg"
 )
 
 type scrollBoxItem struct {
 	Item        tview.Primitive
 	FixedHeight int
+	// Whether this item is the first in a group of related items (i.e. a query and its result)
+	StartsBlock bool
 }
 
 type ScrollBox struct {
@@ -16,18 +22,71 @@ type ScrollBox struct {
 	yOffset int
 	// Scroll all table items
 	xOffset int
+
+	xOffsetScrollFactor int
+	yOffsetScrollFactor int
+
+	// Mouse scroll acceleration state
+	accelerate        bool
+	lastMouseScrollAt time.Time
+	mouseScrollStreak int
+
+	// Called after the user scrolls down, nil when not set
+	scrollDownFunc func()
+
+	// Called with items which took longer than slowDrawThreshold to draw, nil when not set
+	slowDrawFunc      func(item tview.Primitive, duration time.Duration)
+	slowDrawThreshold time.Duration
 }
 
 func NewScrollBox() *ScrollBox {
 	scrollBox := &ScrollBox{
-		Box:     tview.NewBox(),
-		yOffset: 0,
-		xOffset: 0,
+		Box:                 tview.NewBox(),
+		yOffset:             0,
+		xOffset:             0,
+		xOffsetScrollFactor: defaultXOffsetScrollFactor,
+		yOffsetScrollFactor: defaultYOffsetScrollFactor,
 	}
 
 	return scrollBox
 }
 
+// Set how many rows and columns are moved per scroll step
+func (scrollBox *ScrollBox) SetScrollFactors(rows, columns int) *ScrollBox {
+	scrollBox.yOffsetScrollFactor = max(rows, 1)
+	scrollBox.xOffsetScrollFactor = max(columns, 1)
+
+	return scrollBox
+}
+
+// When enabled, mouse scrolling starts at a single row/column per event,
+// speeding up to the full scroll factor as events arrive in quick succession (i.e. trackpad momentum)
+func (scrollBox *ScrollBox) SetAcceleration(accelerate bool) *ScrollBox {
+	scrollBox.accelerate = accelerate
+	return scrollBox
+}
+
+// Called after each scroll down by the user, even when already at the bottom
+// Such as to load more of a result once its end is in view
+func (scrollBox *ScrollBox) SetScrollDownFunc(handler func()) *ScrollBox {
+	scrollBox.scrollDownFunc = handler
+	return scrollBox
+}
+
+// Called after drawing with each item which took longer than threshold to draw
+// Such as to replace an item too large to draw responsively
+func (scrollBox *ScrollBox) SetSlowDrawFunc(threshold time.Duration, handler func(item tview.Primitive, duration time.Duration)) *ScrollBox {
+	scrollBox.slowDrawThreshold = threshold
+	scrollBox.slowDrawFunc = handler
+	return scrollBox
+}
+
+func (scrollBox *ScrollBox) scrolledDown() {
+	if scrollBox.scrollDownFunc != nil {
+		scrollBox.scrollDownFunc()
+	}
+}
+
 func (scrollBox *ScrollBox) AddItem(item tview.Primitive, fixedHeight int) *ScrollBox {
 	scrollBox.items = append(scrollBox.items, &scrollBoxItem{
 		Item:        item,
@@ -38,6 +97,75 @@ func (scrollBox *ScrollBox) AddItem(item tview.Primitive, fixedHeight int) *Scro
 	return scrollBox
 }
 
+// Add an item which starts a new block, subsequent items added belong to this block
+// Blocks can be jumped between, rather than scrolling line by line
+func (scrollBox *ScrollBox) AddBlock(item tview.Primitive, fixedHeight int) *ScrollBox {
+	scrollBox.AddItem(item, fixedHeight)
+	scrollBox.items[len(scrollBox.items)-1].StartsBlock = true
+
+	return scrollBox
+}
+
+// Swap an item for another, keeping its place and whether it starts a block
+func (scrollBox *ScrollBox) ReplaceItem(target tview.Primitive, replacement tview.Primitive, fixedHeight int) *ScrollBox {
+	for _, item := range scrollBox.items {
+		if item.Item == target {
+			item.Item = replacement
+			item.FixedHeight = fixedHeight
+			break
+		}
+	}
+
+	// As the offset is relative to the bottom, items above what's visible can change without moving it
+	scrollBox.setYOffset(scrollBox.yOffset)
+	return scrollBox
+}
+
+// Change the height of an item, such as a table which rows were added to, keeping the visible lines in place
+func (scrollBox *ScrollBox) ResizeItem(target tview.Primitive, fixedHeight int) *ScrollBox {
+	firstVisibleLine := scrollBox.getFirstVisibleLine()
+
+	for _, item := range scrollBox.items {
+		if item.Item == target {
+			item.FixedHeight = fixedHeight
+			break
+		}
+	}
+
+	scrollBox.scrollLineToTop(firstVisibleLine)
+	return scrollBox
+}
+
+// Whether the last line of an item is within the visible lines
+func (scrollBox *ScrollBox) IsItemEndVisible(target tview.Primitive) bool {
+	_, _, _, height := scrollBox.GetInnerRect()
+	firstVisibleLine := scrollBox.getFirstVisibleLine()
+
+	itemTop := 0
+	for _, item := range scrollBox.items {
+		if item.Item == target {
+			itemEnd := itemTop + item.FixedHeight - 1
+			return itemEnd >= firstVisibleLine && itemEnd < firstVisibleLine+height
+		}
+
+		itemTop += item.FixedHeight
+	}
+
+	return false
+}
+
+func (scrollBox *ScrollBox) RemoveItem(target tview.Primitive) *ScrollBox {
+	for idx, item := range scrollBox.items {
+		if item.Item == target {
+			scrollBox.items = slices.Delete(scrollBox.items, idx, idx+1)
+			break
+		}
+	}
+
+	scrollBox.setYOffset(scrollBox.yOffset)
+	return scrollBox
+}
+
 func (scrollBox *ScrollBox) ClearItems() *ScrollBox {
 	scrollBox.items = nil
 	return scrollBox
@@ -50,24 +178,60 @@ func (scrollBox *ScrollBox) ClearOffsets() *ScrollBox {
 	return scrollBox
 }
 
-const xOffsetScrollFactor = 2
+const (
+	defaultXOffsetScrollFactor = 2
+	defaultYOffsetScrollFactor = 5
+)
 
 func (scrollBox *ScrollBox) ScrollRight() {
-	scrollBox.setXOffset(scrollBox.xOffset + xOffsetScrollFactor)
+	scrollBox.setXOffset(scrollBox.xOffset + scrollBox.xOffsetScrollFactor)
 }
 
 func (scrollBox *ScrollBox) ScrollLeft() {
-	scrollBox.setXOffset(scrollBox.xOffset - xOffsetScrollFactor)
+	scrollBox.setXOffset(scrollBox.xOffset - scrollBox.xOffsetScrollFactor)
 }
 
-const yOffsetScrollFactor = 5
-
 func (scrollBox *ScrollBox) ScrollUp() {
-	scrollBox.setYOffset(scrollBox.yOffset + yOffsetScrollFactor)
+	scrollBox.setYOffset(scrollBox.yOffset + scrollBox.yOffsetScrollFactor)
 }
 
 func (scrollBox *ScrollBox) ScrollDown() {
-	scrollBox.setYOffset(scrollBox.yOffset - yOffsetScrollFactor)
+	scrollBox.setYOffset(scrollBox.yOffset - scrollBox.yOffsetScrollFactor)
+	scrollBox.scrolledDown()
+}
+
+// Mouse scroll events further apart than this start a new gesture
+const mouseScrollGestureInterval = 80 * time.Millisecond
+
+// Get how far a single mouse scroll event should move, given the full scroll factor
+func (scrollBox *ScrollBox) getMouseScrollStep(scrollFactor int) int {
+	if !scrollBox.accelerate {
+		return scrollFactor
+	}
+
+	now := time.Now()
+	if now.Sub(scrollBox.lastMouseScrollAt) > mouseScrollGestureInterval {
+		scrollBox.mouseScrollStreak = 0
+	} else {
+		scrollBox.mouseScrollStreak += 1
+	}
+	scrollBox.lastMouseScrollAt = now
+
+	return min(1+scrollBox.mouseScrollStreak, scrollFactor)
+}
+
+func (scrollBox *ScrollBox) mouseScrollVertical(direction int) {
+	step := scrollBox.getMouseScrollStep(scrollBox.yOffsetScrollFactor)
+	scrollBox.setYOffset(scrollBox.yOffset + direction*step)
+
+	if direction < 0 {
+		scrollBox.scrolledDown()
+	}
+}
+
+func (scrollBox *ScrollBox) mouseScrollHorizontal(direction int) {
+	step := scrollBox.getMouseScrollStep(scrollBox.xOffsetScrollFactor)
+	scrollBox.setXOffset(scrollBox.xOffset + direction*step)
 }
 
 // X offset is relative to the left
@@ -128,6 +292,151 @@ func (scrollBox *ScrollBox) setYOffset(offset int) *ScrollBox {
 	return scrollBox
 }
 
+// Adjust the Y offset so that a line within one of our items is visible
+// Line is relative to the top of the item
+func (scrollBox *ScrollBox) ScrollItemIntoView(target tview.Primitive, line int) {
+	itemSizeSum := scrollBox.getItemSizeSum()
+	_, _, _, height := scrollBox.GetInnerRect()
+
+	// Find the position of the line, counting from the top of all items
+	linePosition := -1
+	{
+		itemTop := 0
+		for _, item := range scrollBox.items {
+			if item.Item == target {
+				linePosition = itemTop + line
+				break
+			}
+
+			itemTop += item.FixedHeight
+		}
+	}
+	if linePosition < 0 {
+		return
+	}
+
+	// Range of lines currently visible, counting from the top of all items
+	firstVisibleLine := itemSizeSum - height - scrollBox.yOffset
+	lastVisibleLine := itemSizeSum - scrollBox.yOffset - 1
+
+	if linePosition < firstVisibleLine {
+		scrollBox.setYOffset(itemSizeSum - height - linePosition)
+	} else if linePosition > lastVisibleLine {
+		scrollBox.setYOffset(itemSizeSum - 1 - linePosition)
+	}
+}
+
+// Adjust the X offset so that a column of a table is visible, leaving it alone when it already is
+func (scrollBox *ScrollBox) ScrollColumnIntoView(target *tview.Table, column int) {
+	if column < scrollBox.xOffset {
+		scrollBox.setXOffset(column)
+		return
+	}
+
+	_, _, width, _ := scrollBox.GetInnerRect()
+
+	// Each column is as wide as its widest cell, with a border before it
+	columnEnd := 1
+	for columnIdx := scrollBox.xOffset; columnIdx <= column; columnIdx++ {
+		columnWidth := 0
+		for row := 0; row < target.GetRowCount(); row++ {
+			columnWidth = max(columnWidth, uniseg.StringWidth(target.GetCell(row, columnIdx).Text))
+		}
+
+		columnEnd += columnWidth + 1
+	}
+
+	if columnEnd > width {
+		scrollBox.setXOffset(column)
+	}
+}
+
+// Get the line each block starts at, counting from the top of all items
+func (scrollBox *ScrollBox) getBlockStartLines() (blockStartLines []int) {
+	itemTop := 0
+	for _, item := range scrollBox.items {
+		if item.StartsBlock {
+			blockStartLines = append(blockStartLines, itemTop)
+		}
+
+		itemTop += item.FixedHeight
+	}
+
+	return blockStartLines
+}
+
+// Get the topmost visible line, counting from the top of all items
+func (scrollBox *ScrollBox) getFirstVisibleLine() int {
+	_, _, _, height := scrollBox.GetInnerRect()
+	return max(scrollBox.getItemSizeSum()-height-scrollBox.yOffset, 0)
+}
+
+// Scroll so the given line is at the top of the container, as far as scrolling allows
+func (scrollBox *ScrollBox) scrollLineToTop(line int) {
+	_, _, _, height := scrollBox.GetInnerRect()
+	scrollBox.setYOffset(scrollBox.getItemSizeSum() - height - line)
+}
+
+// Jump to the start of the block above the top of the container
+func (scrollBox *ScrollBox) ScrollToPrevBlock() {
+	firstVisibleLine := scrollBox.getFirstVisibleLine()
+	blockStartLines := scrollBox.getBlockStartLines()
+
+	for idx := len(blockStartLines) - 1; idx >= 0; idx-- {
+		if blockStartLines[idx] < firstVisibleLine {
+			scrollBox.scrollLineToTop(blockStartLines[idx])
+			return
+		}
+	}
+}
+
+// Jump to the start of the block below the top of the container
+func (scrollBox *ScrollBox) ScrollToNextBlock() {
+	firstVisibleLine := scrollBox.getFirstVisibleLine()
+
+	for _, blockStartLine := range scrollBox.getBlockStartLines() {
+		if blockStartLine > firstVisibleLine {
+			scrollBox.scrollLineToTop(blockStartLine)
+			return
+		}
+	}
+}
+
+// Jump to the start of the block containing the given item
+func (scrollBox *ScrollBox) ScrollToBlockOf(target tview.Primitive) {
+	itemTop := 0
+	blockStartLine := 0
+	for _, item := range scrollBox.items {
+		if item.StartsBlock {
+			blockStartLine = itemTop
+		}
+		if item.Item == target {
+			scrollBox.scrollLineToTop(blockStartLine)
+			return
+		}
+
+		itemTop += item.FixedHeight
+	}
+}
+
+func (scrollBox *ScrollBox) ScrollToFirstBlock() {
+	blockStartLines := scrollBox.getBlockStartLines()
+	if len(blockStartLines) == 0 {
+		return
+	}
+
+	scrollBox.scrollLineToTop(blockStartLines[0])
+}
+
+func (scrollBox *ScrollBox) ScrollToLastBlock() {
+	blockStartLines := scrollBox.getBlockStartLines()
+	if len(blockStartLines) == 0 {
+		return
+	}
+
+	scrollBox.scrollLineToTop(blockStartLines[len(blockStartLines)-1])
+}
+
 func (scrollBox *ScrollBox) getItemSizeSum() (itemSizeSum int) {
 	for _, item := range scrollBox.items {
 		itemSizeSum += item.FixedHeight
@@ -158,27 +467,73 @@ func (scrollBox *ScrollBox) Draw(screen tcell.Screen) {
 		currentY += scrollBox.yOffset
 	}
 
+	type slowDraw struct {
+		item     tview.Primitive
+		duration time.Duration
+	}
+	var slowDraws []slowDraw
+
 	for _, item := range scrollBox.items {
 		if item.Item != nil {
 			// Handle x offsets
 			switch v := item.Item.(type) {
 			case *tview.Table:
 				{
-					v.SetOffset(0, scrollBox.xOffset)
+					// A focused table manages its own offset, to keep the selected cell visible
+					if !v.HasFocus() {
+						v.SetOffset(0, scrollBox.xOffset)
+					}
 					break
 				}
 			}
 
 			item.Item.SetRect(x, currentY, width, item.FixedHeight)
+
+			drawStartedAt := time.Now()
 			item.Item.Draw(screen)
+			if duration := time.Since(drawStartedAt); scrollBox.slowDrawFunc != nil && duration > scrollBox.slowDrawThreshold {
+				slowDraws = append(slowDraws, slowDraw{item.Item, duration})
+			}
 		}
 
 		currentY += item.FixedHeight
 	}
+
+	// Only once drawing is done, as the handler may change the items
+	for _, slowDraw := range slowDraws {
+		scrollBox.slowDrawFunc(slowDraw.item, slowDraw.duration)
+	}
+}
+
+// Returns the item which currently has focus, if any
+func (scrollBox *ScrollBox) getFocusedItem() tview.Primitive {
+	for _, item := range scrollBox.items {
+		if item.Item != nil && item.Item.HasFocus() {
+			return item.Item
+		}
+	}
+
+	return nil
+}
+
+func (scrollBox *ScrollBox) HasFocus() bool {
+	if scrollBox.getFocusedItem() != nil {
+		return true
+	}
+
+	return scrollBox.Box.HasFocus()
 }
 
 func (scrollBox *ScrollBox) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
 	return scrollBox.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		// Items such as tables in navigation mode handle their own input
+		if focusedItem := scrollBox.getFocusedItem(); focusedItem != nil {
+			if handler := focusedItem.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+			return
+		}
+
 		switch event.Key() {
 		case tcell.KeyUp:
 			{
@@ -200,34 +555,65 @@ func (scrollBox *ScrollBox) InputHandler() func(event *tcell.EventKey, setFocus
 				scrollBox.ScrollRight()
 				break
 			}
+		case tcell.KeyPgUp:
+			{
+				scrollBox.ScrollToPrevBlock()
+				break
+			}
+		case tcell.KeyPgDn:
+			{
+				scrollBox.ScrollToNextBlock()
+				break
+			}
+		case tcell.KeyHome:
+			{
+				scrollBox.ScrollToFirstBlock()
+				break
+			}
+		case tcell.KeyEnd:
+			{
+				scrollBox.ScrollToLastBlock()
+				break
+			}
 		}
 	})
 }
 
 func (scrollBox *ScrollBox) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
 	return scrollBox.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		// Holding shift turns a vertical mouse wheel into a horizontal one
+		isShiftScroll := event.Modifiers()&tcell.ModShift != 0
+
 		switch action {
 		case tview.MouseScrollDown:
 			{
-				scrollBox.ScrollDown()
+				if isShiftScroll {
+					scrollBox.mouseScrollHorizontal(+1)
+				} else {
+					scrollBox.mouseScrollVertical(-1)
+				}
 				consumed = true
 				break
 			}
 		case tview.MouseScrollUp:
 			{
-				scrollBox.ScrollUp()
+				if isShiftScroll {
+					scrollBox.mouseScrollHorizontal(-1)
+				} else {
+					scrollBox.mouseScrollVertical(+1)
+				}
 				consumed = true
 				break
 			}
 		case tview.MouseScrollRight:
 			{
-				scrollBox.ScrollRight()
+				scrollBox.mouseScrollHorizontal(+1)
 				consumed = true
 				break
 			}
 		case tview.MouseScrollLeft:
 			{
-				scrollBox.ScrollLeft()
+				scrollBox.mouseScrollHorizontal(-1)
 				consumed = true
 				break
 			}
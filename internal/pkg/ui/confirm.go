@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"github.com/rivo/tview"
+)
+
+const (
+	confirmPageName    = "confirm"
+	confirmButtonLabel = "Run"
+	cancelButtonLabel  = "Cancel"
+	messagePageName    = "message"
+)
+
+// Ask before doing something with side effects, such as running a follow-up statement
+// Focus returns to the query text area either way
+func (app *App) confirm(message string, onConfirm func()) {
+	app.confirmOrCancel(message, onConfirm, nil)
+}
+
+// Like confirm, also running onCancel when cancelled, if set
+func (app *App) confirmOrCancel(message string, onConfirm func(), onCancel func()) {
+	confirmModal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{confirmButtonLabel, cancelButtonLabel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			app.pages.RemovePage(confirmPageName)
+			app.tviewApp.SetFocus(app.queryTextArea)
+
+			if buttonLabel == confirmButtonLabel {
+				onConfirm()
+			} else if onCancel != nil {
+				onCancel()
+			}
+		})
+
+	confirmModal.SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(confirmPageName, confirmModal, true, true)
+	app.tviewApp.SetFocus(confirmModal)
+}
+
+// Tell the user something, such as why an action isn't available
+// Dismissing the message returns focus to returnFocus
+func (app *App) showMessage(message string, returnFocus tview.Primitive) {
+	messageModal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			app.pages.RemovePage(messagePageName)
+			app.tviewApp.SetFocus(returnFocus)
+		})
+
+	messageModal.SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(messagePageName, messageModal, true, true)
+	app.tviewApp.SetFocus(messageModal)
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Copy a block's query, when it ran and its result in one go, as markdown for pasting into notes or incident timelines
+func (app *App) createCopyBlockButton(block *resultBlock) *tview.Button {
+	return NewButton("Copy Block").
+		SetSelectedFunc(func() {
+			app.copyToClipboard([]byte(formatBlockForCopy(block)))
+		})
+}
+
+// Query in a SQL code block, a line with when it ran and how long it took,
+// then the result as a markdown table, or the output in a code block when there's no table
+func formatBlockForCopy(block *resultBlock) string {
+	var blockString strings.Builder
+
+	fmt.Fprintf(&blockString, "```sql\n%s\n```\n", strings.TrimSpace(block.query))
+
+	if timingLine := formatBlockTimingLine(block); timingLine != "" {
+		fmt.Fprintf(&blockString, "\n%s\n", timingLine)
+	}
+
+	blockString.WriteRune('\n')
+	if block.err == nil && block.result != nil && len(block.result.Columns) > 0 {
+		blockString.Write(block.result.ToMarkdown())
+	} else {
+		fmt.Fprintf(&blockString, "```\n%s\n```\n", strings.TrimSpace(block.getNoResultsOutput()))
+	}
+
+	return blockString.String()
+}
+
+// Ex: Ran at 2024-05-01 14:03:22 UTC on shop in 0.03s, 3 rows
+// In UTC, so timelines pieced together from several people line up
+func formatBlockTimingLine(block *resultBlock) string {
+	if block.ranAt.IsZero() {
+		return ""
+	}
+
+	timingLine := fmt.Sprint("Ran at ", block.ranAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	if block.database != "" {
+		timingLine = fmt.Sprint(timingLine, " on ", block.database)
+	}
+	timingLine = fmt.Sprintf("%s in %.2fs", timingLine, block.duration.Seconds())
+
+	switch {
+	case block.err != nil:
+		{
+			return fmt.Sprint(timingLine, ", failed")
+		}
+	case block.result != nil:
+		{
+			return fmt.Sprint(timingLine, ", ", formatRowCount(len(block.result.Rows)))
+		}
+	default:
+		{
+			return timingLine
+		}
+	}
+}
+
+func formatRowCount(count int) string {
+	if count == 1 {
+		return "1 row"
+	}
+
+	return fmt.Sprintf("%d rows", count)
+}
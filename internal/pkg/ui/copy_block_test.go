@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBlockForCopy(t *testing.T) {
+	ranAt := time.Date(2024, 5, 1, 14, 3, 22, 0, time.UTC)
+
+	var tests = []struct {
+		Name     string
+		Block    *resultBlock
+		Expected string
+	}{
+		{
+			Name: "Result table",
+			Block: &resultBlock{
+				query: "SELECT id FROM users;",
+				result: &db.QueryResult{
+					Columns: []string{"id"},
+					Rows: []map[string]*db.NullString{
+						{"id": {NullString: sql.NullString{String: "1", Valid: true}}},
+					},
+				},
+				ranAt:    ranAt,
+				duration: 30 * time.Millisecond,
+				database: "shop",
+			},
+			Expected: "```sql\nSELECT id FROM users;\n```\n\n" +
+				"Ran at 2024-05-01 14:03:22 UTC on shop in 0.03s, 1 row\n\n" +
+				"| id  |\n| --- |\n| 1   |\n",
+		},
+		{
+			Name: "Error",
+			Block: &resultBlock{
+				query:    "SELECT nope;",
+				err:      errors.New("Query Failed"),
+				ranAt:    ranAt,
+				duration: 10 * time.Millisecond,
+			},
+			Expected: "```sql\nSELECT nope;\n```\n\n" +
+				"Ran at 2024-05-01 14:03:22 UTC in 0.01s, failed\n\n" +
+				"```\nQuery Failed\n```\n",
+		},
+		{
+			Name:  "Not timed",
+			Block: &resultBlock{query: "BEGIN"},
+			Expected: "```sql\nBEGIN\n```\n\n" +
+				"```\n" + "Success: 0 results returned" + "\n```\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.Expected, formatBlockForCopy(test.Block))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+)
+
+// What the UI needs of the database client, implemented by *db.DBClient
+// Kept as an interface so the UI can be driven in tests without a database
+type DB interface {
+	Query(statement string) (*db.QueryResult, error)
+	Exec(statement string) (*db.ExecResult, error)
+	IsExecStatement(statement string) bool
+	IsWriteStatement(statement string) bool
+	// Most rows the statement is expected to read, from EXPLAIN. Not estimated when it can't be explained
+	EstimateRows(statement string) (estimate int64, isEstimated bool, err error)
+	SplitStatements(script string) []string
+	Lint(statement string) []string
+	// Results still being read
+	CloseRowStreams()
+	GetRowLimit() int
+
+	GetConnectionInfo() conn.ConnectionInfo
+	GetServerInfo() (*db.ServerInfo, error)
+	GetSearchPath() string
+	// Whether updates and deletes without a WHERE clause are refused
+	IsSafeMode() bool
+	GetSchema() (*db.Schema, error)
+	GetCachedSchema() *db.Schema
+	GetTableSchema(tableName string) (*db.TableSchema, error)
+
+	InTransaction() bool
+	BeginTransaction() error
+	CommitTransaction() error
+	RollbackTransaction() error
+
+	InsertRow(tableName string, columns []string, values []*db.NullString) (string, *db.QueryResult, error)
+	GetRowEditTarget(query string, resultColumns []string) (*db.RowEditTarget, error)
+	BuildRowUpdate(target *db.RowEditTarget, row map[string]*db.NullString, changes map[string]*db.NullString) (string, error)
+	Restore(argument string, onProgress db.RestoreProgress) (*db.QueryResult, error)
+}
+
+var _ DB = (*db.DBClient)(nil)
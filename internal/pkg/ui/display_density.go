@@ -0,0 +1,42 @@
+package ui
+
+import "github.com/azvaliev/sql/internal/pkg/db"
+
+// Blank lines after each result, along with the line its view ends on
+func (app *App) getSpacingLines() int {
+	if app.display.CompactSpacing {
+		return 1
+	}
+
+	return 2
+}
+
+// The status line, and a blank line after it unless spacing is compact
+func (app *App) getStatusLineHeight() int {
+	if app.display.CompactSpacing {
+		return 1
+	}
+
+	return 2
+}
+
+// Lines a result's table takes, along with the spacing after it
+func (app *App) getResultViewHeight(result *db.QueryResult) int {
+	if app.display.CompactTables {
+		// The header and column types, then a line per row
+		return len(result.Rows) + app.getFirstResultRow() + app.getSpacingLines()
+	}
+
+	// Borders above, below and between every row
+	return (len(result.Rows)+app.getFirstResultRow())*2 + 1 + app.getSpacingLines()
+}
+
+// Line of a result's table a row is drawn on, counting from the top of the table
+func (app *App) getTableRowLine(row int) int {
+	if app.display.CompactTables {
+		return row
+	}
+
+	// With borders, each row takes up two lines, after the top border
+	return row*2 + 1
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResultViewHeight(t *testing.T) {
+	result := newTestResult("id", "1", "2", "3")
+
+	app := &App{display: config.DisplayConfig{}}
+	assert.Equal(t, 3*2+3+2, app.getResultViewHeight(result))
+
+	app.display = config.DisplayConfig{CompactSpacing: true, CompactTables: true}
+	assert.Equal(t, 3+1+1, app.getResultViewHeight(result))
+}
+
+func TestFormatQueryTextHidden(t *testing.T) {
+	block := &resultBlock{query: "SELECT 1;", hideQuery: true, collapsed: true}
+	assert.Equal(t, collapsedIndicator, formatQueryText(block))
+
+	block.hideQuery = false
+	assert.Equal(t, "> SELECT 1; "+collapsedIndicator, formatQueryText(block))
+}
+
+func TestAppCompactDisplay(t *testing.T) {
+	cfg := config.Default()
+	cfg.Display = config.DisplayConfig{EchoQueries: false, CompactSpacing: true, CompactTables: true}
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name FROM people;": newTestResult("name", "ada", "bob"),
+	}}
+	driver := startTestAppWithConfig(t, database, cfg)
+
+	driver.typeText("SELECT name FROM people;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("bob")
+
+	assert.NotContains(t, driver.screenText(), "> SELECT")
+
+	// Without borders, each row is on the line right after the one before it
+	_, adaLine, _ := driver.findOnScreen("ada")
+	_, bobLine, _ := driver.findOnScreen("bob")
+	assert.Equal(t, adaLine+1, bobLine)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Ask before running a statement EXPLAIN estimates will read more rows than the configured limit
+// Statements which can't be estimated, such as DDL, or whose EXPLAIN fails are run without asking
+func (app *App) confirmLargeScan(query string, onConfirm func()) {
+	if app.explainGuardRows <= 0 {
+		onConfirm()
+		return
+	}
+
+	var largestStatement string
+	var largestEstimate int64
+	for _, statement := range app.db.SplitStatements(query) {
+		statement, _ = getVerticalStatement(app.db.GetConnectionInfo().Flavor, statement)
+		estimate, isEstimated, err := app.db.EstimateRows(statement)
+		if err != nil || !isEstimated || estimate <= largestEstimate {
+			continue
+		}
+
+		largestStatement, largestEstimate = statement, estimate
+	}
+
+	if largestEstimate <= app.explainGuardRows {
+		onConfirm()
+		return
+	}
+
+	app.confirmOrCancel(
+		fmt.Sprintf(
+			"%s is estimated to read about %s rows, more than the %s allowed without asking. Run anyway?",
+			largestStatement,
+			formatApproximateCount(largestEstimate),
+			formatApproximateCount(app.explainGuardRows),
+		),
+		onConfirm,
+		func() {
+			app.restoreQuery(query)
+		},
+	)
+}
+
+// Ex: 950, 12K, 3.4M or 1.2B
+func formatApproximateCount(count int64) string {
+	units := []struct {
+		size   float64
+		suffix string
+	}{
+		{1e9, "B"},
+		{1e6, "M"},
+		{1e3, "K"},
+	}
+
+	for _, unit := range units {
+		if float64(count) >= unit.size {
+			scaled := float64(count) / unit.size
+			precision := 1
+			if scaled >= 10 {
+				precision = 0
+			}
+
+			return fmt.Sprint(strconv.FormatFloat(scaled, 'f', precision, 64), unit.suffix)
+		}
+	}
+
+	return strconv.FormatInt(count, 10)
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatApproximateCount(t *testing.T) {
+	var tests = []struct {
+		Count    int64
+		Expected string
+	}{
+		{950, "950"},
+		{1000, "1.0K"},
+		{12400, "12K"},
+		{3400000, "3.4M"},
+		{1200000000, "1.2B"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Expected, func(t *testing.T) {
+			assert.Equal(t, test.Expected, formatApproximateCount(test.Count))
+		})
+	}
+}
+
+func TestAppExplainGuard(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := config.Default()
+	cfg.ExplainGuardRows = 1000000
+
+	database := &fakeDB{estimates: map[string]int64{
+		"SELECT * FROM users;":  5000,
+		"SELECT * FROM events;": 1200000000,
+	}}
+	driver := startTestAppWithConfig(t, database, cfg)
+
+	// Under the limit, so run without asking
+	driver.typeText("SELECT * FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the small query to run", func() bool {
+		return len(database.getStatements()) == 1
+	})
+
+	driver.typeText("SELECT * FROM events;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("about 1.2B rows")
+	driver.click(cancelButtonLabel)
+	driver.waitFor("the large query to be put back", func() bool {
+		return driver.queryText() == "SELECT * FROM events;"
+	})
+	assert.Len(database.getStatements(), 1)
+
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("about 1.2B rows")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the large query to run", func() bool {
+		return len(database.getStatements()) == 2
+	})
+	assert.Equal("SELECT * FROM events;", database.getStatements()[1])
+}
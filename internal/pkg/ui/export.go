@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/format"
+	"github.com/rivo/tview"
+)
+
+const (
+	exportButtonLabel = "Export…"
+	exportPageName    = "export"
+	// Rows read from the database at a time while exporting, each page is written before the next is read
+	exportPageSize = 1000
+)
+
+// Writes a result to a file in the chosen format
+func (app *App) createExportButton(block *resultBlock) *tview.Button {
+	return NewButton(exportButtonLabel).
+		SetSelectedFunc(func() {
+			app.openExport(block)
+		})
+}
+
+// Ask where to write the result, and in which format
+// Only the selected rows are written when any are selected
+func (app *App) openExport(block *resultBlock) {
+	result := app.getSelectedResult(block)
+	title := " Export result "
+	if result != block.result {
+		title = fmt.Sprintf(" Export selected rows (%d) ", len(result.Rows))
+	}
+
+	closeExport := func() {
+		app.pages.RemovePage(exportPageName)
+		app.tviewApp.SetFocus(app.queryTextArea)
+	}
+
+	formatNames := make([]string, len(format.Formats))
+	for idx, exportFormat := range format.Formats {
+		formatNames[idx] = string(exportFormat)
+	}
+
+	pathField := tview.NewInputField().
+		SetLabel("File").
+		SetPlaceholder("result.csv")
+	formatField := tview.NewDropDown().
+		SetLabel("Format").
+		SetOptions(formatNames, nil).
+		SetCurrentOption(1)
+
+	form := tview.NewForm().
+		AddFormItem(pathField).
+		AddFormItem(formatField)
+
+	form.AddButton("Export", func() {
+		path := strings.TrimSpace(pathField.GetText())
+		if path == "" {
+			app.showMessage("Enter a file to export to", form)
+			return
+		}
+		_, formatName := formatField.GetCurrentOption()
+
+		closeExport()
+
+		rowCount, err := exportResult(path, format.Format(formatName), result, exportPageSize)
+		if result == block.result {
+			app.refreshExportedBlock(block, path)
+		}
+		if err != nil {
+			app.showMessage(err.Error(), app.queryTextArea)
+			return
+		}
+
+		app.showMessage(fmt.Sprintf("Exported %s to %s", formatRowCount(rowCount), path), app.queryTextArea)
+	})
+	form.AddButton("Cancel", closeExport)
+	form.SetCancelFunc(closeExport)
+
+	form.
+		SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(exportPageName, NewModal(form), true, true)
+	app.tviewApp.SetFocus(form)
+}
+
+// Write every row of a result to a new file, returning how many were written
+// Rows not yet read are read a page at a time and written as they come, rather than being kept in the result
+func exportResult(path string, exportFormat format.Format, result *db.QueryResult, pageSize int) (rowCount int, err error) {
+	// Never overwrite, an earlier export or anything else may be at the path
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return 0, errors.Join(
+			errors.New("Failed to create export file"),
+			err,
+		)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	rowWriter, err := format.NewRowWriter(file, exportFormat, result.Columns)
+	if err != nil {
+		return 0, err
+	}
+
+	writeRows := func(rows []map[string]*db.NullString) error {
+		for _, row := range rows {
+			if err := rowWriter.WriteRow(row); err != nil {
+				return err
+			}
+			rowCount++
+		}
+
+		return nil
+	}
+
+	if err := writeRows(result.Rows); err != nil {
+		return rowCount, err
+	}
+	if err := result.ReadRemainingRows(pageSize, writeRows); err != nil {
+		return rowCount, err
+	}
+
+	return rowCount, rowWriter.Close()
+}
+
+// Note on the query line when rows were exported without being added to the table
+func (app *App) refreshExportedBlock(block *resultBlock, path string) {
+	if !block.canLoadMoreRows || block.result.Stream != nil {
+		return
+	}
+
+	block.exportedTo = path
+	block.queryTextView.SetText(formatQueryText(block))
+	app.refreshFinishedResults()
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/format"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportResult(t *testing.T) {
+	assert := assert.New(t)
+
+	path := filepath.Join(t.TempDir(), "users.jsonl")
+	result := newTestResult("name", "ada", "grace")
+
+	rowCount, err := exportResult(path, format.JSONL, result, exportPageSize)
+	assert.NoError(err)
+	assert.Equal(2, rowCount)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("{\"name\":\"ada\"}\n{\"name\":\"grace\"}\n", string(contents))
+
+	// An earlier export isn't overwritten
+	_, err = exportResult(path, format.CSV, result, exportPageSize)
+	assert.ErrorContains(err, "Failed to create export file")
+
+	contents, err = os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("{\"name\":\"ada\"}\n{\"name\":\"grace\"}\n", string(contents))
+}
+
+func TestAppExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.csv")
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name FROM users;": newTestResult("name", "ada"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT name FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	driver.click(exportButtonLabel)
+	driver.waitForScreen("Export result")
+	driver.typeText(path)
+	driver.click("Export     Cancel")
+	driver.waitForScreen("Exported 1 row to")
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "name\nada\n", string(contents))
+}
@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Stands in for the database client, answering queries with canned results
+type fakeDB struct {
+	mutex sync.Mutex
+	// Results by statement, statements without one succeed without rows
+	results map[string]*db.QueryResult
+	// Every statement run, in order
+	statements []string
+	// Empty when not set
+	schema *db.Schema
+	// Nil to start without the banner, as when offline
+	serverInfo *db.ServerInfo
+	// Rows EXPLAIN estimates by statement, statements without one aren't estimated
+	estimates map[string]int64
+}
+
+func (fake *fakeDB) Query(statement string) (*db.QueryResult, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	fake.statements = append(fake.statements, statement)
+	return fake.results[statement], nil
+}
+
+func (fake *fakeDB) getStatements() []string {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	return append([]string(nil), fake.statements...)
+}
+
+func (fake *fakeDB) Exec(statement string) (*db.ExecResult, error) {
+	return nil, fmt.Errorf("Unexpected exec of %s", statement)
+}
+
+func (fake *fakeDB) IsExecStatement(statement string) bool { return false }
+
+func (fake *fakeDB) IsWriteStatement(statement string) bool {
+	keyword, _, _ := strings.Cut(strings.ToUpper(strings.TrimSpace(statement)), " ")
+	return keyword == "INSERT" || keyword == "UPDATE" || keyword == "DELETE"
+}
+
+func (fake *fakeDB) EstimateRows(statement string) (int64, bool, error) {
+	estimate, isEstimated := fake.estimates[statement]
+	return estimate, isEstimated, nil
+}
+
+func (fake *fakeDB) SplitStatements(script string) []string {
+	statements := lexer.SplitStatements(conn.PostgreSQL, script)
+	for idx, statement := range statements {
+		statements[idx] = fmt.Sprint(statement, ";")
+	}
+
+	return statements
+}
+
+func (fake *fakeDB) Lint(statement string) []string { return nil }
+func (fake *fakeDB) CloseRowStreams()               {}
+func (fake *fakeDB) GetRowLimit() int               { return 0 }
+
+func (fake *fakeDB) GetConnectionInfo() conn.ConnectionInfo {
+	return conn.ConnectionInfo{Flavor: conn.PostgreSQL, User: "user", Host: "localhost", Port: 5432, Database: "test"}
+}
+
+func (fake *fakeDB) GetServerInfo() (*db.ServerInfo, error) {
+	if fake.serverInfo == nil {
+		return nil, fmt.Errorf("Not connected")
+	}
+
+	return fake.serverInfo, nil
+}
+
+func (fake *fakeDB) GetSearchPath() string { return "public" }
+func (fake *fakeDB) IsSafeMode() bool      { return false }
+
+func (fake *fakeDB) GetSchema() (*db.Schema, error) {
+	if fake.schema == nil {
+		return &db.Schema{}, nil
+	}
+
+	return fake.schema, nil
+}
+
+func (fake *fakeDB) GetCachedSchema() *db.Schema { return fake.schema }
+
+func (fake *fakeDB) GetTableSchema(string) (*db.TableSchema, error) {
+	return nil, fmt.Errorf("No tables")
+}
+
+func (fake *fakeDB) InTransaction() bool        { return false }
+func (fake *fakeDB) BeginTransaction() error    { return nil }
+func (fake *fakeDB) CommitTransaction() error   { return nil }
+func (fake *fakeDB) RollbackTransaction() error { return nil }
+
+func (fake *fakeDB) InsertRow(string, []string, []*db.NullString) (string, *db.QueryResult, error) {
+	return "", nil, fmt.Errorf("Not supported")
+}
+
+func (fake *fakeDB) GetRowEditTarget(string, []string) (*db.RowEditTarget, error) {
+	return nil, fmt.Errorf("Not supported")
+}
+
+func (fake *fakeDB) BuildRowUpdate(*db.RowEditTarget, map[string]*db.NullString, map[string]*db.NullString) (string, error) {
+	return "", fmt.Errorf("Not supported")
+}
+
+func (fake *fakeDB) Restore(string, db.RestoreProgress) (*db.QueryResult, error) {
+	return nil, fmt.Errorf("Not supported")
+}
+
+// Runs the app headless on a simulated screen, feeding it keys and mouse clicks as a user would
+type testDriver struct {
+	t      *testing.T
+	app    *App
+	screen tcell.SimulationScreen
+	// When the last click was, as clicks close together are taken as a double click
+	lastClick time.Time
+}
+
+const (
+	testScreenWidth  = 120
+	testScreenHeight = 40
+	// How long to wait for the app to react to input before failing
+	testDriverTimeout = 2 * time.Second
+)
+
+func startTestApp(t *testing.T, database DB) *testDriver {
+	return startTestAppWithConfig(t, database, config.Default())
+}
+
+func startTestAppWithConfig(t *testing.T, database DB, cfg config.Config) *testDriver {
+	screen := tcell.NewSimulationScreen("UTF-8")
+
+	cfg.TerminalTitle = false
+	// Tests shouldn't read or add to the user's history
+	cfg.History.Persist = false
+
+	app := Init(database, cfg)
+	app.tviewApp.SetScreen(screen)
+	screen.SetSize(testScreenWidth, testScreenHeight)
+
+	done := make(chan error)
+	go func() {
+		done <- app.Run()
+	}()
+	t.Cleanup(func() {
+		app.tviewApp.Stop()
+		<-done
+	})
+
+	return &testDriver{t: t, app: app, screen: screen}
+}
+
+func (driver *testDriver) typeText(text string) {
+	for _, char := range text {
+		driver.pressKey(tcell.KeyRune, char, tcell.ModNone)
+	}
+}
+
+func (driver *testDriver) pressKey(key tcell.Key, char rune, modifiers tcell.ModMask) {
+	driver.screen.PostEventWait(tcell.NewEventKey(key, char, modifiers))
+}
+
+// Click the first place text is shown on screen
+func (driver *testDriver) click(text string) {
+	var x, y int
+	driver.waitFor(fmt.Sprintf("%q to be on screen", text), func() bool {
+		var found bool
+		x, y, found = driver.findOnScreen(text)
+		return found
+	})
+
+	// With some leeway, as the app may handle the last click a little after it was sent
+	time.Sleep(time.Until(driver.lastClick.Add(tview.DoubleClickInterval + 100*time.Millisecond)))
+
+	driver.screen.PostEventWait(tcell.NewEventMouse(x, y, tcell.Button1, tcell.ModNone))
+	driver.screen.PostEventWait(tcell.NewEventMouse(x, y, tcell.ButtonNone, tcell.ModNone))
+	driver.lastClick = time.Now()
+}
+
+// Read app state on the UI goroutine
+func (driver *testDriver) read(reader func()) {
+	driver.app.tviewApp.QueueUpdate(reader)
+}
+
+func (driver *testDriver) queryText() (text string) {
+	driver.read(func() {
+		text = driver.app.queryTextArea.GetText()
+	})
+
+	return text
+}
+
+// Wait for the app to catch up with the input so far, failing the test once it takes too long
+func (driver *testDriver) waitFor(description string, condition func() bool) {
+	driver.t.Helper()
+
+	deadline := time.Now().Add(testDriverTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			driver.t.Fatalf("Timed out waiting for %s\n\nScreen:\n%s", description, driver.screenText())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func (driver *testDriver) waitForScreen(text string) {
+	driver.t.Helper()
+
+	driver.waitFor(fmt.Sprintf("%q to be on screen", text), func() bool {
+		return strings.Contains(driver.screenText(), text)
+	})
+}
+
+// What's on screen, a line per row
+func (driver *testDriver) screenText() string {
+	var text strings.Builder
+
+	// The screen is drawn to on the UI goroutine, so it's read there too
+	driver.read(func() {
+		cells, width, height := driver.screen.GetContents()
+		for y := range height {
+			for x := range width {
+				runes := cells[y*width+x].Runes
+				if len(runes) == 0 {
+					text.WriteRune(' ')
+					continue
+				}
+				text.WriteString(string(runes))
+			}
+			text.WriteRune('\n')
+		}
+	})
+
+	return text.String()
+}
+
+func (driver *testDriver) findOnScreen(text string) (x, y int, found bool) {
+	for y, line := range strings.Split(driver.screenText(), "\n") {
+		if x := strings.Index(line, text); x != -1 {
+			// Columns count cells, not bytes
+			return len([]rune(line[:x])), y, true
+		}
+	}
+
+	return 0, 0, false
+}
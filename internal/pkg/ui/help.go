@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	helpPageName = "help"
+	// Opens the help from an empty query text area, where it can't be part of a query
+	helpKey = '?'
+)
+
+// Keys which aren't part of the keymap, so are the same whatever is configured
+var (
+	resultsSearchKeys = []keymap.KeyDescription{
+		{Key: fmt.Sprintf("%c / %c", nextMatchKey, previousMatchKey), Description: "next / previous match"},
+		{Key: "Escape", Description: "stop searching, any other key stops it too"},
+	}
+	queryEditorKeys = []keymap.KeyDescription{
+		{Key: "Enter", Description: fmt.Sprintf("run the query, once it ends with ; or with %s to show it as records", verticalTerminator)},
+		{Key: "Up / Down", Description: "previous / next query from history"},
+		{Key: "Tab", Description: "complete the word before the cursor"},
+		{Key: string(helpKey), Description: "show keyboard shortcuts, when nothing is typed"},
+	}
+	resultTableKeys = []keymap.KeyDescription{
+		{Key: "Arrow keys", Description: "move the cell cursor"},
+		{Key: "Enter", Description: "inspect the cell, or sort by the column on a header"},
+		{Key: string(copyCellKey), Description: "copy the cell, or the selected rows as CSV"},
+		{Key: "Space", Description: "select or deselect the row"},
+		{Key: "Shift+Up / Down", Description: "select rows while moving"},
+		{Key: string(sortColumnKey), Description: "sort by the column, ascending, descending, then as read"},
+		{Key: string(searchResultKey), Description: "search the result"},
+		{Key: fmt.Sprintf("%c / %c", nextMatchKey, previousMatchKey), Description: "next / previous match"},
+		{Key: "Tab / Shift+Tab", Description: "next / previous result table"},
+		{Key: string(editRowKey), Description: "edit the row"},
+		{Key: string(toggleBookmarkKey), Description: "bookmark the result"},
+		{Key: string(openBookmarksKey), Description: "open bookmarks"},
+		{Key: "Escape", Description: "deselect every row, or back to the query editor"},
+	}
+	cellInspectorKeys = []keymap.KeyDescription{
+		{Key: "Arrow keys", Description: "scroll the value"},
+		{Key: string(copyCellKey), Description: "copy the value, as it is in the result"},
+		{Key: "Tab", Description: "move to the copy button"},
+		{Key: "Escape / Enter", Description: "back to the table"},
+	}
+	schemaBrowserKeys = []keymap.KeyDescription{
+		{Key: "Enter", Description: "insert the name into the query"},
+		{Key: "Right / Left", Description: "expand / collapse"},
+		{Key: "Escape", Description: "back to the query editor"},
+	}
+)
+
+// Overlay listing every key, including configured keybindings
+func (app *App) showHelp(returnFocus tview.Primitive) {
+	closeHelp := func() {
+		app.pages.RemovePage(helpPageName)
+		app.tviewApp.SetFocus(returnFocus)
+	}
+
+	helpView := tview.NewTextView().
+		SetDynamicColors(false).
+		SetText(getHelpText(app.keymap))
+
+	helpView.
+		SetBorder(true).
+		SetTitle(" Keyboard shortcuts ").
+		SetBackgroundColor(ColorBackground)
+
+	helpView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		isHelpAction := false
+		if action, isBound := app.keymap.Lookup(event); isBound {
+			isHelpAction = action == keymap.ShowHelp
+		}
+
+		isHelpKey := event.Key() == tcell.KeyRune && event.Rune() == helpKey
+		if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter || isHelpKey || isHelpAction {
+			closeHelp()
+			return nil
+		}
+
+		// Arrow keys and Page Up / Down scroll through the list
+		return event
+	})
+
+	app.pages.AddPage(helpPageName, NewModal(helpView), true, true)
+	app.tviewApp.SetFocus(helpView)
+}
+
+// Every key grouped by where it applies, with the keymap's actions and editing keys as configured
+func getHelpText(keys *keymap.Keymap) string {
+	var actionKeys []keymap.KeyDescription
+	for _, action := range keymap.Actions() {
+		actionKeys = append(actionKeys, keymap.KeyDescription{
+			Key:         strings.Join(keys.Keys(action), " / "),
+			Description: keymap.Describe(action),
+		})
+	}
+
+	sections := []struct {
+		title string
+		keys  []keymap.KeyDescription
+	}{
+		{"Keybindings, set in the config file", actionKeys},
+		{"Query editor", queryEditorKeys},
+		{fmt.Sprintf("Editing (%s mode)", keys.Mode), keys.EditingKeys()},
+		{"Result tables", resultTableKeys},
+		{"Searching the results", resultsSearchKeys},
+		{"Cell inspector", cellInspectorKeys},
+		{"Schema browser", schemaBrowserKeys},
+	}
+
+	keyWidth := 0
+	for _, section := range sections {
+		for _, key := range section.keys {
+			keyWidth = max(keyWidth, len(key.Key))
+		}
+	}
+
+	var helpText strings.Builder
+	for idx, section := range sections {
+		if idx > 0 {
+			helpText.WriteString("\n")
+		}
+
+		helpText.WriteString(section.title)
+		helpText.WriteString("\n")
+		for _, key := range section.keys {
+			fmt.Fprintf(&helpText, "  %-*s  %s\n", keyWidth, key.Key, key.Description)
+		}
+	}
+
+	return helpText.String()
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHelpText(t *testing.T) {
+	assert := assert.New(t)
+
+	configuredKeymap, err := keymap.Build(keymap.Default, map[string]string{"focus_results": "F2"})
+	if !assert.NoError(err) {
+		return
+	}
+
+	helpText := getHelpText(configuredKeymap)
+	assert.Regexp(`F2 +focus the latest result table`, helpText)
+	assert.Regexp(`Ctrl\+PgUp / Alt\+PgUp +scroll to the previous result`, helpText)
+	assert.Contains(helpText, "Editing (default mode)")
+	assert.Regexp(`Ctrl\+Y +redo`, helpText)
+	assert.Regexp(`\n  y +copy the cell`, helpText)
+}
+
+func TestAppHelp(t *testing.T) {
+	driver := startTestApp(t, &fakeDB{})
+
+	driver.pressKey(tcell.KeyF1, 0, tcell.ModNone)
+	driver.waitForScreen(" Keyboard shortcuts ")
+	driver.pressKey(tcell.KeyEscape, 0, tcell.ModNone)
+	driver.waitFor("the help to close", func() bool {
+		return !strings.Contains(driver.screenText(), " Keyboard shortcuts ")
+	})
+
+	// ? opens the help only while nothing is typed
+	driver.typeText("?")
+	driver.waitForScreen(" Keyboard shortcuts ")
+	driver.typeText("?")
+	driver.waitFor("the help to close", func() bool {
+		return !strings.Contains(driver.screenText(), " Keyboard shortcuts ")
+	})
+	assert.Equal(t, "", driver.queryText())
+
+	driver.typeText("SELECT ?")
+	driver.waitFor("? to be typed", func() bool {
+		return driver.queryText() == "SELECT ?"
+	})
+	assert.NotContains(t, driver.screenText(), " Keyboard shortcuts ")
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/history"
+)
+
+// Load queries saved by earlier sessions, so Up goes back through them too
+// When the file can't be read, history is only kept for this session
+func (app *App) loadHistoryFile(cfg config.Config) {
+	path, retention := cfg.GetHistoryFile()
+	if path == "" {
+		return
+	}
+
+	passphrase, err := cfg.GetHistoryPassphrase()
+	if err != nil {
+		app.addResultBlock(&resultBlock{query: path, err: err})
+		return
+	}
+
+	var historyFile *history.File
+	var entries []history.Entry
+	if passphrase != "" {
+		historyFile, entries, err = history.OpenEncrypted(path, retention, passphrase, time.Now())
+	} else {
+		historyFile, entries, err = history.Open(path, retention, time.Now())
+	}
+	if err != nil {
+		app.addResultBlock(&resultBlock{query: path, err: err})
+		return
+	}
+
+	// Only as many as fit, older ones would be overwritten anyway
+	if len(entries) > cfg.History.Size {
+		entries = entries[len(entries)-cfg.History.Size:]
+	}
+	for _, entry := range entries {
+		app.queryHistory.AddEntry(entry.Query)
+	}
+
+	app.historyFile = historyFile
+}
+
+// Add the query to history, saving it for later sessions when configured
+func (app *App) addHistoryEntry(query string) {
+	app.queryHistory.AddEntry(query)
+
+	if app.historyFile == nil {
+		return
+	}
+
+	err := app.historyFile.Append(history.Entry{Time: time.Now(), Query: query})
+	if err != nil {
+		// Reported once, rather than after every query
+		app.historyFile = nil
+		app.addResultBlock(&resultBlock{query: query, err: err})
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/rivo/tview"
+)
+
+const insertFormPageName = "insert-form"
+
+// \insert <table>
+// Open a form with a field per column of the table, inserting the filled in values as a new row
+// Fields left empty are omitted from the INSERT, so the column gets its default
+func (app *App) insertCommand(argument string) {
+	tableName := strings.TrimSpace(strings.TrimSuffix(argument, ";"))
+	if tableName == "" {
+		app.showMessage(`Usage: \insert <table>`, app.queryTextArea)
+		return
+	}
+
+	table, err := app.db.GetTableSchema(tableName)
+	if err != nil {
+		app.showMessage(err.Error(), app.queryTextArea)
+		return
+	}
+
+	closeInsertForm := func() {
+		app.pages.RemovePage(insertFormPageName)
+		app.tviewApp.SetFocus(app.queryTextArea)
+	}
+
+	form := tview.NewForm()
+	for _, column := range table.Columns {
+		form.AddFormItem(
+			tview.NewInputField().
+				SetLabel(column.Name).
+				SetPlaceholder(column.Describe()),
+		)
+	}
+
+	form.AddButton("Insert", func() {
+		var columns []string
+		var values []*db.NullString
+		for idx, column := range table.Columns {
+			text := form.GetFormItem(idx).(*tview.InputField).GetText()
+			if text == "" {
+				continue
+			}
+
+			value := &db.NullString{}
+			if text != nullInputValue {
+				value.NullString = sql.NullString{String: text, Valid: true}
+			}
+
+			columns = append(columns, column.Name)
+			values = append(values, value)
+		}
+
+		closeInsertForm()
+
+		block := &resultBlock{}
+		block.query, block.result, block.err = app.db.InsertRow(tableName, columns, values)
+		app.addResultBlock(block)
+	})
+	form.AddButton("Cancel", closeInsertForm)
+	form.SetCancelFunc(closeInsertForm)
+
+	form.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" Insert into %s (empty for default, %s for NULL) ", tableName, nullInputValue)).
+		SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(insertFormPageName, NewModal(form), true, true)
+	app.tviewApp.SetFocus(form)
+}
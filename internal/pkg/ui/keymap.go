@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+)
+
+func (app *App) runKeymapAction(action keymap.Action) {
+	switch action {
+	case keymap.FocusResults:
+		{
+			app.focusLatestResultTable()
+		}
+	case keymap.OpenBookmarks:
+		{
+			app.openBookmarks(app.queryTextArea)
+		}
+	case keymap.ScrollUp:
+		{
+			app.resultContainer.ScrollUp()
+		}
+	case keymap.ScrollDown:
+		{
+			app.resultContainer.ScrollDown()
+		}
+	case keymap.ScrollLeft:
+		{
+			app.resultContainer.ScrollLeft()
+		}
+	case keymap.ScrollRight:
+		{
+			app.resultContainer.ScrollRight()
+		}
+	case keymap.ScrollPrevResult:
+		{
+			app.resultContainer.ScrollToPrevBlock()
+		}
+	case keymap.ScrollNextResult:
+		{
+			app.resultContainer.ScrollToNextBlock()
+		}
+	case keymap.ScrollFirst:
+		{
+			app.resultContainer.ScrollToFirstBlock()
+		}
+	case keymap.ScrollLast:
+		{
+			app.resultContainer.ScrollToLastBlock()
+		}
+	case keymap.ToggleSchemaBrowser:
+		{
+			app.toggleSchemaBrowser()
+		}
+	case keymap.ShowHelp:
+		{
+			app.showHelp(app.queryTextArea)
+		}
+	case keymap.OpenCommandPalette:
+		{
+			app.openCommandPalette()
+		}
+	case keymap.TogglePrivacyMode:
+		{
+			app.togglePrivacyMode()
+		}
+	case keymap.SearchResults:
+		{
+			app.openResultsSearch()
+		}
+	}
+}
+
+// Shown in the status bar, so it's clear which keys the query text area responds to
+func (app *App) getInputModeLabel() string {
+	if app.keymap.Mode == keymap.Vim {
+		return app.vim.getModeLabel()
+	}
+
+	return strings.ToUpper(string(app.keymap.Mode))
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/azvaliev/sql/internal/pkg/migration"
+	"github.com/rivo/tview"
+)
+
+const (
+	migrationPageName        = "migration"
+	addMigrationButtonLabel  = "Add"
+	skipMigrationButtonLabel = "Skip"
+)
+
+// Offer to save schema changes which just ran as migrations, when a migrations directory is configured
+func (app *App) offerMigrations(blocks ...*resultBlock) {
+	if app.migrationWriter == nil {
+		return
+	}
+
+	flavor := app.db.GetConnectionInfo().Flavor
+	blocks = slices.DeleteFunc(slices.Clone(blocks), func(block *resultBlock) bool {
+		return block.err != nil || !migration.IsMigratable(flavor, block.query)
+	})
+	if len(blocks) == 0 {
+		return
+	}
+
+	// Offered one at a time, the next once this one is answered
+	block, remainingBlocks := blocks[0], blocks[1:]
+
+	migrationModal := tview.NewModal().
+		SetText(fmt.Sprint("Add this schema change to the session's migration?\n\n", block.query)).
+		AddButtons([]string{addMigrationButtonLabel, skipMigrationButtonLabel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			app.pages.RemovePage(migrationPageName)
+			app.tviewApp.SetFocus(app.queryTextArea)
+
+			if buttonLabel != addMigrationButtonLabel {
+				app.offerMigrations(remainingBlocks...)
+				return
+			}
+
+			path, err := app.migrationWriter.Add(flavor, block.query)
+			if err != nil {
+				app.showMessage(err.Error(), app.queryTextArea)
+				return
+			}
+
+			block.queryTextView.SetText(fmt.Sprintf("%s (added to %s)", formatQueryText(block), path))
+			app.offerMigrations(remainingBlocks...)
+		})
+
+	migrationModal.SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(migrationPageName, migrationModal, true, true)
+	app.tviewApp.SetFocus(migrationModal)
+}
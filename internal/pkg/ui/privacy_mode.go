@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/rivo/tview"
+)
+
+const privacyModeLabel = "PRIVATE"
+
+// Mask every result value, or show them again, such as while sharing the screen
+// Values are kept as they are, so copying or exporting them still works
+func (app *App) togglePrivacyMode() {
+	app.privacyMode = !app.privacyMode
+
+	for _, block := range app.resultBlocks {
+		if block.table != nil && block.result != nil {
+			app.refreshResultCells(block.table, block.result)
+		}
+
+		app.refreshRecordView(block)
+
+		if summaryView, isSummary := block.resultItem.(*tview.TextView); isSummary && block.summarized {
+			summaryView.SetText(formatResultSummary(block.result, block.drawDuration, app.privacyMode, app.display.NullToken))
+		}
+	}
+
+	app.updateStatusBar()
+}
+
+// Set the text of every result cell from its value, masked in privacy mode
+func (app *App) refreshResultCells(table *tview.Table, result *db.QueryResult) {
+	for row := app.getFirstResultRow(); row < table.GetRowCount(); row++ {
+		for column := 0; column < table.GetColumnCount(); column++ {
+			cell := table.GetCell(row, column)
+			if value, ok := cell.GetReference().(*db.NullString); ok {
+				cell.SetText(app.getResultValueText(value, getDatabaseType(result, column)))
+			}
+		}
+	}
+}
+
+// Text shown for a result value, with NULL as the configured token, which isn't masked as it says nothing about the data
+func (app *App) getResultValueText(value *db.NullString, databaseType string) string {
+	if !value.Valid {
+		return value.ToStringWithNull(app.display.NullToken)
+	}
+
+	return app.getResultCellText(value.String, databaseType)
+}
+
+func (app *App) getResultCellText(value string, databaseType string) string {
+	if !app.privacyMode {
+		return value
+	}
+
+	return maskValue(value, databaseType)
+}
+
+// What the value is, rather than the value itself, ex: ‹varchar, 12›
+func maskValue(value string, databaseType string) string {
+	length := utf8.RuneCountInString(value)
+	if databaseType == "" {
+		return fmt.Sprintf("‹%d›", length)
+	}
+
+	return fmt.Sprintf("‹%s, %d›", strings.ToLower(databaseType), length)
+}
+
+// Empty when the driver doesn't report the column's type
+func getDatabaseType(result *db.QueryResult, column int) string {
+	if column < 0 || column >= len(result.ColumnTypes) {
+		return ""
+	}
+
+	return result.ColumnTypes[column].DatabaseType
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskValue(t *testing.T) {
+	var tests = []struct {
+		Value        string
+		DatabaseType string
+		Expected     string
+	}{
+		{"ada@example.com", "VARCHAR", "‹varchar, 15›"},
+		{"héllo", "TEXT", "‹text, 5›"},
+		{"42", "", "‹2›"},
+		// Only real NULLs are left unmasked, see getResultValueText
+		{"NULL", "TEXT", "‹text, 4›"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Expected, maskValue(test.Value, test.DatabaseType), test.Value)
+	}
+}
+
+func TestGetResultValueText(t *testing.T) {
+	app := &App{display: config.DisplayConfig{NullToken: "∅"}}
+	null := &db.NullString{}
+	text := &db.NullString{NullString: sql.NullString{String: "NULL", Valid: true}}
+
+	assert.Equal(t, "∅", app.getResultValueText(null, "TEXT"))
+	assert.Equal(t, "NULL", app.getResultValueText(text, "TEXT"))
+
+	app.privacyMode = true
+	assert.Equal(t, "∅", app.getResultValueText(null, "TEXT"))
+	assert.Equal(t, "‹text, 4›", app.getResultValueText(text, "TEXT"))
+}
+
+func TestAppPrivacyMode(t *testing.T) {
+	result := newTestResult("email", "ada@example.com")
+	result.ColumnTypes = []db.ColumnType{{DatabaseType: "VARCHAR"}}
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT email FROM users;": result,
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT email FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("ada@example.com")
+
+	driver.pressKey(tcell.KeyRune, 'p', tcell.ModAlt)
+	driver.waitForScreen("‹varchar, 15›")
+	driver.waitForScreen(privacyModeLabel)
+	assert.NotContains(t, driver.screenText(), "ada@example.com")
+
+	// Results arriving while on are masked too
+	driver.typeText("SELECT email FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the second result", func() bool {
+		return strings.Count(driver.screenText(), "‹varchar, 15›") == 2
+	})
+	assert.NotContains(t, driver.screenText(), "ada@example.com")
+
+	driver.pressKey(tcell.KeyRune, 'p', tcell.ModAlt)
+	driver.waitFor("the values to be shown again", func() bool {
+		screen := driver.screenText()
+		return strings.Count(screen, "ada@example.com") == 2 && !strings.Contains(screen, privacyModeLabel)
+	})
+}
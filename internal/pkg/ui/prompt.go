@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+)
+
+// Title of the query text area when no prompt is configured
+const defaultPrompt = "Query"
+
+// Render the configured prompt into the title of the query text area, to reflect the current session state
+func (app *App) updatePrompt() {
+	app.queryTextArea.SetTitle(app.renderPrompt())
+}
+
+func (app *App) renderPrompt() string {
+	if app.prompt == nil {
+		return defaultPrompt
+	}
+
+	connectionInfo := app.db.GetConnectionInfo()
+	variables := config.PromptVariables{
+		User:     connectionInfo.User,
+		Host:     connectionInfo.Host,
+		Port:     connectionInfo.Port,
+		Database: connectionInfo.Database,
+		Flavor:   connectionInfo.Flavor.Name(),
+	}
+	if app.db.InTransaction() {
+		variables.TxState = "TX"
+	}
+
+	return renderPromptTemplate(app.prompt, &variables)
+}
+
+func renderPromptTemplate(prompt *template.Template, variables *config.PromptVariables) string {
+	var rendered strings.Builder
+	if err := prompt.Execute(&rendered, variables); err != nil {
+		return defaultPrompt
+	}
+
+	// Titles are a single line, and trailing space from empty variables looks odd next to the border
+	return strings.TrimSpace(strings.ReplaceAll(rendered.String(), "\n", " "))
+}
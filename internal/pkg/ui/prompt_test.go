@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPromptTemplate(t *testing.T) {
+	variables := config.PromptVariables{
+		User:     "root",
+		Host:     "localhost",
+		Port:     3306,
+		Database: "example",
+		Flavor:   "MySQL",
+	}
+
+	var tests = []struct {
+		Name           string
+		Prompt         string
+		TxState        string
+		ExpectedPrompt string
+	}{
+		{
+			Name:           "Outside Transaction",
+			Prompt:         "{{.User}}@{{.Host}}:{{.Database}} [{{.Flavor}}] {{.TxState}}",
+			ExpectedPrompt: "root@localhost:example [MySQL]",
+		},
+		{
+			Name:           "In Transaction",
+			Prompt:         "{{.User}}@{{.Host}}:{{.Database}} [{{.Flavor}}] {{.TxState}}",
+			TxState:        "TX",
+			ExpectedPrompt: "root@localhost:example [MySQL] TX",
+		},
+		{
+			Name:           "Multiline",
+			Prompt:         "{{.Host}}:{{.Port}}\n{{.Database}}",
+			ExpectedPrompt: "localhost:3306 example",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			cfg := config.Default()
+			cfg.Prompt = test.Prompt
+			prompt, err := cfg.ParsePrompt()
+			assert.NoError(err)
+
+			testVariables := variables
+			testVariables.TxState = test.TxState
+			assert.Equal(test.ExpectedPrompt, renderPromptTemplate(prompt, &testVariables))
+		})
+	}
+}
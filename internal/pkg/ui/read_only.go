@@ -0,0 +1,30 @@
+package ui
+
+const readOnlyServerWarning = "⚠ Read-only server, such as a replica: writes will be refused"
+
+// Ask before sending writes to a read-only server, as the error or wait they end in is easily mistaken for a bug
+// With safe mode on, writes are already being made with care, so they're sent as-is
+func (app *App) confirmReadOnlyWrite(query string, onConfirm func()) {
+	if !app.readOnlyServer || app.db.IsSafeMode() || !app.hasWriteStatement(query) {
+		onConfirm()
+		return
+	}
+
+	app.confirmOrCancel(
+		"Connected to a read-only server, such as a replica, so this will fail. Run anyway?",
+		onConfirm,
+		func() {
+			app.restoreQuery(query)
+		},
+	)
+}
+
+func (app *App) hasWriteStatement(query string) bool {
+	for _, statement := range app.db.SplitStatements(query) {
+		if app.db.IsWriteStatement(statement) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Most killed pieces of text kept for yanking back
+const killRingSize = 16
+
+type readlineAction int
+
+const (
+	readlineOther readlineAction = iota
+	readlineKill
+	readlineYank
+)
+
+// Shell style editing for a text area: word movement, kills into a kill ring and yanking them back
+// Consecutive kills are joined into one entry, the same as readline
+type readline struct {
+	textArea *tview.TextArea
+	// Most recent kill last
+	killRing   []string
+	lastAction readlineAction
+	// Where the last yank was inserted and which entry it was, so Alt+Y can swap it for an earlier one
+	yankStart int
+	yankEnd   int
+	yankIdx   int
+}
+
+func newReadline(textArea *tview.TextArea) *readline {
+	return &readline{textArea: textArea}
+}
+
+// Handle a readline key, returning false when it isn't one
+func (editor *readline) handleKey(event *tcell.EventKey) bool {
+	action := readlineOther
+
+	switch {
+	case event.Key() == tcell.KeyCtrlW:
+		{
+			editor.killBackward(findUnixWordStart)
+			action = readlineKill
+		}
+	case event.Key() == tcell.KeyCtrlU:
+		{
+			editor.killBackward(findLineStart)
+			action = readlineKill
+		}
+	case event.Key() == tcell.KeyCtrlK:
+		{
+			editor.killForward(findKillLineEnd)
+			action = readlineKill
+		}
+	case event.Key() == tcell.KeyCtrlY:
+		{
+			if !editor.yank() {
+				return true
+			}
+			action = readlineYank
+		}
+	case event.Modifiers() == tcell.ModAlt && (event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2):
+		{
+			editor.killBackward(findWordStart)
+			action = readlineKill
+		}
+	case event.Modifiers() == tcell.ModAlt && event.Key() == tcell.KeyRune:
+		{
+			switch event.Rune() {
+			case 'b':
+				{
+					editor.move(findWordStart)
+				}
+			case 'f':
+				{
+					editor.move(findWordEnd)
+				}
+			case 'd':
+				{
+					editor.killForward(findWordEnd)
+					action = readlineKill
+				}
+			case 'y':
+				{
+					if !editor.yankPop() {
+						return true
+					}
+					action = readlineYank
+				}
+			default:
+				{
+					return false
+				}
+			}
+		}
+	default:
+		{
+			return false
+		}
+	}
+
+	editor.lastAction = action
+	return true
+}
+
+// Anything else typed breaks up consecutive kills and yanks
+func (editor *readline) resetAction() {
+	editor.lastAction = readlineOther
+}
+
+func (editor *readline) getCursor() (text string, cursor int) {
+	_, start, end := editor.textArea.GetSelection()
+	return editor.textArea.GetText(), max(start, end)
+}
+
+func (editor *readline) move(findPosition func(text string, cursor int) int) {
+	text, cursor := editor.getCursor()
+	position := findPosition(text, cursor)
+	editor.textArea.Select(position, position)
+}
+
+func (editor *readline) killBackward(findStart func(text string, cursor int) int) {
+	text, cursor := editor.getCursor()
+	start := findStart(text, cursor)
+	if start == cursor {
+		return
+	}
+
+	editor.addKill(text[start:cursor], true)
+	editor.textArea.Replace(start, cursor, "")
+}
+
+func (editor *readline) killForward(findEnd func(text string, cursor int) int) {
+	text, cursor := editor.getCursor()
+	end := findEnd(text, cursor)
+	if end == cursor {
+		return
+	}
+
+	editor.addKill(text[cursor:end], false)
+	editor.textArea.Replace(cursor, end, "")
+	editor.textArea.Select(cursor, cursor)
+}
+
+func (editor *readline) addKill(killed string, isBackward bool) {
+	if editor.lastAction == readlineKill && len(editor.killRing) > 0 {
+		lastIdx := len(editor.killRing) - 1
+		if isBackward {
+			editor.killRing[lastIdx] = killed + editor.killRing[lastIdx]
+		} else {
+			editor.killRing[lastIdx] += killed
+		}
+		return
+	}
+
+	editor.killRing = append(editor.killRing, killed)
+	if len(editor.killRing) > killRingSize {
+		editor.killRing = editor.killRing[1:]
+	}
+}
+
+// Insert the most recent kill at the cursor
+func (editor *readline) yank() bool {
+	if len(editor.killRing) == 0 {
+		return false
+	}
+
+	editor.yankIdx = len(editor.killRing) - 1
+	editor.insertYank(editor.killRing[editor.yankIdx])
+	return true
+}
+
+// Swap the text just yanked for the kill before it, cycling through the ring
+func (editor *readline) yankPop() bool {
+	if editor.lastAction != readlineYank || len(editor.killRing) == 0 {
+		return false
+	}
+
+	editor.textArea.Replace(editor.yankStart, editor.yankEnd, "")
+	editor.yankIdx = (editor.yankIdx - 1 + len(editor.killRing)) % len(editor.killRing)
+	editor.insertYank(editor.killRing[editor.yankIdx])
+	return true
+}
+
+func (editor *readline) insertYank(yanked string) {
+	_, cursor := editor.getCursor()
+
+	editor.textArea.Replace(cursor, cursor, yanked)
+	editor.yankStart, editor.yankEnd = cursor, cursor+len(yanked)
+}
+
+func isWordRune(char rune) bool {
+	return unicode.IsLetter(char) || unicode.IsDigit(char) || char == '_'
+}
+
+// Start of the word before the cursor, skipping anything in between, for Alt+B and Alt+Backspace
+func findWordStart(text string, cursor int) int {
+	position := skipBackward(text, cursor, func(char rune) bool { return !isWordRune(char) })
+	return skipBackward(text, position, isWordRune)
+}
+
+// End of the word after the cursor, skipping anything in between, for Alt+F and Alt+D
+func findWordEnd(text string, cursor int) int {
+	position := skipForward(text, cursor, func(char rune) bool { return !isWordRune(char) })
+	return skipForward(text, position, isWordRune)
+}
+
+// Start of the whitespace separated word before the cursor, for Ctrl+W
+func findUnixWordStart(text string, cursor int) int {
+	position := skipBackward(text, cursor, unicode.IsSpace)
+	return skipBackward(text, position, func(char rune) bool { return !unicode.IsSpace(char) })
+}
+
+// Start of the line the cursor is on, for Ctrl+U
+func findLineStart(text string, cursor int) int {
+	return strings.LastIndexByte(text[:cursor], '\n') + 1
+}
+
+// End of the line the cursor is on for Ctrl+K, or past the newline when already at the end, so lines can be joined
+func findKillLineEnd(text string, cursor int) int {
+	lineLength := strings.IndexByte(text[cursor:], '\n')
+	switch lineLength {
+	case -1:
+		{
+			return len(text)
+		}
+	case 0:
+		{
+			return cursor + 1
+		}
+	default:
+		{
+			return cursor + lineLength
+		}
+	}
+}
+
+func skipBackward(text string, position int, shouldSkip func(char rune) bool) int {
+	for position > 0 {
+		char, size := utf8.DecodeLastRuneInString(text[:position])
+		if !shouldSkip(char) {
+			break
+		}
+		position -= size
+	}
+
+	return position
+}
+
+func skipForward(text string, position int, shouldSkip func(char rune) bool) int {
+	for position < len(text) {
+		char, size := utf8.DecodeRuneInString(text[position:])
+		if !shouldSkip(char) {
+			break
+		}
+		position += size
+	}
+
+	return position
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadlineBoundaries(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Find     func(text string, cursor int) int
+		Text     string
+		Cursor   int
+		Expected int
+	}{
+		{"Word start skips punctuation", findWordStart, "SELECT foo.bar_baz", 18, 11},
+		{"Word start from separator", findWordStart, "SELECT foo.bar", 11, 7},
+		{"Word end", findWordEnd, "SELECT foo.bar", 6, 10},
+		{"Word end with unicode", findWordEnd, "SELECT 'héllo'", 7, 14},
+		{"Unix word start", findUnixWordStart, "SELECT foo.bar  ", 16, 7},
+		{"Line start", findLineStart, "SELECT *\nFROM foo", 13, 9},
+		{"Line start on first line", findLineStart, "SELECT *", 4, 0},
+		{"Kill line end", findKillLineEnd, "SELECT *\nFROM foo", 2, 8},
+		{"Kill line end joins lines", findKillLineEnd, "SELECT *\nFROM foo", 8, 9},
+		{"Kill line end on last line", findKillLineEnd, "SELECT *\nFROM foo", 11, 17},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.Expected, test.Find(test.Text, test.Cursor))
+		})
+	}
+}
+
+func TestReadlineKillRing(t *testing.T) {
+	assert := assert.New(t)
+
+	textArea := tview.NewTextArea().SetText("SELECT id FROM users", true)
+	editor := newReadline(textArea)
+
+	// Text areas only place the cursor once they've been laid out
+	screen := tcell.NewSimulationScreen("")
+	if !assert.NoError(screen.Init()) {
+		return
+	}
+	defer screen.Fini()
+	textArea.SetRect(0, 0, 80, 5)
+	textArea.Draw(screen)
+
+	ctrl := func(key tcell.Key) *tcell.EventKey {
+		return tcell.NewEventKey(key, 0, tcell.ModCtrl)
+	}
+	alt := func(char rune) *tcell.EventKey {
+		return tcell.NewEventKey(tcell.KeyRune, char, tcell.ModAlt)
+	}
+
+	// Consecutive kills are yanked back together
+	assert.True(editor.handleKey(ctrl(tcell.KeyCtrlW)))
+	assert.True(editor.handleKey(ctrl(tcell.KeyCtrlW)))
+	assert.Equal("SELECT id ", textArea.GetText())
+
+	assert.True(editor.handleKey(ctrl(tcell.KeyCtrlY)))
+	assert.Equal("SELECT id FROM users", textArea.GetText())
+
+	// Moving breaks up kills
+	editor.resetAction()
+	assert.True(editor.handleKey(alt('b')))
+	assert.True(editor.handleKey(alt('b')))
+	assert.True(editor.handleKey(ctrl(tcell.KeyCtrlK)))
+	assert.Equal("SELECT id ", textArea.GetText())
+
+	assert.True(editor.handleKey(ctrl(tcell.KeyCtrlU)))
+	assert.Equal("", textArea.GetText())
+
+	// Ctrl+K then Ctrl+U are joined, so the previous kill is the earlier Ctrl+W
+	assert.True(editor.handleKey(ctrl(tcell.KeyCtrlY)))
+	assert.Equal("SELECT id FROM users", textArea.GetText())
+	assert.True(editor.handleKey(alt('y')))
+	assert.Equal("FROM users", textArea.GetText())
+
+	editor.resetAction()
+	assert.False(editor.handleKey(ctrl(tcell.KeyCtrlT)))
+	assert.False(editor.handleKey(alt('\'')))
+}
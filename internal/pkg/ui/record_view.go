@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/rivo/tview"
+	"github.com/rivo/uniseg"
+)
+
+// Kept short, as they share the query line with the copy buttons
+const (
+	showAsRecordsLabel   = "Records"
+	showAsTableLabel     = "Table"
+	previousRecordsLabel = "Prev"
+	nextRecordsLabel     = "Next"
+)
+
+// Ends a statement in place of a semicolon, showing its result as records, as in the mysql client
+const verticalTerminator = `\G`
+
+// Whether the statement's tokens end in \G
+func isVerticalTerminator(tokens []lexer.Token) bool {
+	if len(tokens) < 2 {
+		return false
+	}
+
+	backslash, letter := tokens[len(tokens)-2], tokens[len(tokens)-1]
+	return backslash.IsOperator(`\`) && letter.Text == "G" && letter.Start == backslash.Start+len(backslash.Text)
+}
+
+// The statement to send for one ending in \G, with a semicolon in its place
+// Statements split from a script have a semicolon added after it, which is dropped too
+func getVerticalStatement(flavor conn.DBFlavor, statement string) (sentStatement string, isVertical bool) {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, statement))
+	if len(tokens) > 0 && tokens[len(tokens)-1].IsOperator(";") {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	if !isVerticalTerminator(tokens) {
+		return statement, false
+	}
+
+	backslash := tokens[len(tokens)-2]
+	return fmt.Sprint(strings.TrimRightFunc(statement[:backslash.Start], unicode.IsSpace), ";"), true
+}
+
+// Whether a result's table would be so much wider than the results that it's shown as records instead
+func (app *App) isWideResult(result *db.QueryResult) bool {
+	_, _, containerWidth, _ := app.resultContainer.GetInnerRect()
+	if app.wideResults.WidthFactor == 0 || containerWidth == 0 {
+		return false
+	}
+
+	return getTableWidth(result) > containerWidth*app.wideResults.WidthFactor
+}
+
+// Width of a result drawn as a table, each column as wide as its widest value, with a border between them
+func getTableWidth(result *db.QueryResult) int {
+	width := 1
+	for _, column := range result.Columns {
+		columnWidth := uniseg.StringWidth(column)
+		for _, row := range result.Rows {
+			columnWidth = max(columnWidth, uniseg.StringWidth(row[column].ToString()))
+		}
+
+		width += columnWidth + 1
+	}
+
+	return width
+}
+
+// Text view of the block's current page of records, in place of its table
+func (app *App) createRecordView(block *resultBlock) (view *tview.TextView, lines int) {
+	view, lines = app.createNoResultView(app.formatRecords(block.result, block.recordPage))
+	block.recordView = view
+
+	return view, lines
+}
+
+// Redraw the records shown, such as after more rows were read
+func (app *App) refreshRecordView(block *resultBlock) {
+	if block.recordView == nil {
+		return
+	}
+
+	recordView, height := app.createRecordView(block)
+	app.resultContainer.ReplaceItem(block.resultItem, recordView, block.getResultItemHeight(height))
+	block.resultItem = recordView
+}
+
+// Replace a block's table with a page of its records
+func (app *App) showRecords(block *resultBlock) {
+	if block.table.HasFocus() {
+		app.blurResultTable(block.table)
+	}
+
+	recordView, height := app.createRecordView(block)
+	app.resultContainer.ReplaceItem(block.table, recordView, block.getResultItemHeight(height))
+	block.resultItem = recordView
+
+	app.refreshQueryView(block)
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// Put back the table of a block shown as records
+func (app *App) showTable(block *resultBlock) {
+	app.resultContainer.ReplaceItem(block.recordView, block.table, block.getResultItemHeight(app.getResultViewHeight(block.result)))
+	block.resultItem = block.table
+	block.recordView = nil
+
+	app.refreshQueryView(block)
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// Move to another page of records, reading more rows when it goes past those read so far
+func (app *App) showRecordPage(block *resultBlock, page int) {
+	if (page+1)*app.wideResults.RecordsPerPage > len(block.result.Rows) && block.result.HasMoreRows() {
+		app.fetchResultRows(block)
+	}
+
+	block.recordPage = page
+	app.refreshRecordView(block)
+	app.refreshQueryView(block)
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// Buttons to switch between the table and records of a result, and between pages of records
+func (app *App) createRecordViewButtons(block *resultBlock) (buttons []*tview.Button) {
+	if block.table == nil {
+		return buttons
+	}
+
+	if block.recordView == nil {
+		return []*tview.Button{
+			NewButton(showAsRecordsLabel).SetSelectedFunc(func() { app.showRecords(block) }),
+		}
+	}
+
+	if block.recordPage > 0 {
+		buttons = append(buttons, NewButton(previousRecordsLabel).SetSelectedFunc(func() {
+			app.showRecordPage(block, block.recordPage-1)
+		}))
+	}
+	if (block.recordPage+1)*app.wideResults.RecordsPerPage < len(block.result.Rows) || block.result.HasMoreRows() {
+		buttons = append(buttons, NewButton(nextRecordsLabel).SetSelectedFunc(func() {
+			app.showRecordPage(block, block.recordPage+1)
+		}))
+	}
+
+	return append(buttons, NewButton(showAsTableLabel).SetSelectedFunc(func() { app.showTable(block) }))
+}
+
+// A page of a result's rows, each column on its own line, ex:
+// Records 1 to 2 of 40
+//
+// -[ Record 1 ]-
+// id   | 1
+// name | ada
+func (app *App) formatRecords(result *db.QueryResult, page int) string {
+	if len(result.Rows) == 0 {
+		return NoResultsMessage
+	}
+
+	firstRecord := min(page*app.wideResults.RecordsPerPage, len(result.Rows)-1)
+	lastRecord := min(firstRecord+app.wideResults.RecordsPerPage, len(result.Rows))
+
+	columnWidth := 0
+	for _, column := range result.Columns {
+		columnWidth = max(columnWidth, uniseg.StringWidth(column))
+	}
+
+	var records strings.Builder
+	fmt.Fprintf(&records, "Records %d to %d of %d\n", firstRecord+1, lastRecord, len(result.Rows))
+
+	for idx, row := range result.Rows[firstRecord:lastRecord] {
+		fmt.Fprintf(&records, "\n-[ Record %d ]-\n", firstRecord+idx+1)
+
+		for columnIdx, column := range result.Columns {
+			value := app.getResultValueText(row[column], getDatabaseType(result, columnIdx))
+			padding := strings.Repeat(" ", columnWidth-uniseg.StringWidth(column))
+
+			// Lines after the first line up under it
+			value = strings.ReplaceAll(value, "\n", fmt.Sprint("\n", strings.Repeat(" ", columnWidth), " | "))
+			fmt.Fprintf(&records, "%s%s | %s\n", column, padding, value)
+		}
+	}
+
+	return records.String()
+}
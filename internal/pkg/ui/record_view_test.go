@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTableWidth(t *testing.T) {
+	result := newTestResultRows(
+		[]string{"id", "email"},
+		[]string{"1", "ada@example.com"},
+		[]string{"10", "ken"},
+	)
+
+	// |id|ada@example.com|
+	assert.Equal(t, 1+3+16, getTableWidth(result))
+}
+
+func TestFormatRecords(t *testing.T) {
+	app := &App{wideResults: config.WideResultsConfig{RecordsPerPage: 2}}
+	result := newTestResultRows(
+		[]string{"id", "note"},
+		[]string{"1", "first"},
+		[]string{"2", "two\nlines"},
+		[]string{"3", "third"},
+	)
+
+	assert.Equal(
+		t,
+		"Records 1 to 2 of 3\n"+
+			"\n-[ Record 1 ]-\n"+
+			"id   | 1\n"+
+			"note | first\n"+
+			"\n-[ Record 2 ]-\n"+
+			"id   | 2\n"+
+			"note | two\n"+
+			"     | lines\n",
+		app.formatRecords(result, 0),
+	)
+	assert.Equal(
+		t,
+		"Records 3 to 3 of 3\n"+
+			"\n-[ Record 3 ]-\n"+
+			"id   | 3\n"+
+			"note | third\n",
+		app.formatRecords(result, 1),
+	)
+}
+
+func TestGetVerticalStatement(t *testing.T) {
+	tests := []struct {
+		statement          string
+		expectedStatement  string
+		expectedIsVertical bool
+	}{
+		{`SELECT * FROM users\G`, "SELECT * FROM users;", true},
+		{`SELECT * FROM users \G;`, "SELECT * FROM users;", true},
+		{"SELECT * FROM users;", "SELECT * FROM users;", false},
+		{`SELECT '\G';`, `SELECT '\G';`, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.statement, func(t *testing.T) {
+			statement, isVertical := getVerticalStatement(conn.MySQL, test.statement)
+			assert.Equal(t, test.expectedStatement, statement)
+			assert.Equal(t, test.expectedIsVertical, isVertical)
+		})
+	}
+}
+
+func TestAppVerticalTerminator(t *testing.T) {
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name FROM users;": newTestResult("name", "ada", "grace"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText(`SELECT name FROM users\G`)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("-[ Record 2 ]-")
+	assert.Equal(t, []string{"SELECT name FROM users;"}, database.getStatements())
+
+	// Any result can be switched between a table and records
+	driver.click(showAsTableLabel)
+	driver.waitFor("the table to be shown", func() bool {
+		return !strings.Contains(driver.screenText(), "Record 1")
+	})
+	driver.waitForScreen("grace")
+}
+
+func TestAppWideResultShownAsRecords(t *testing.T) {
+	// Over three times the width of the screen
+	columns := make([]string, 6)
+	values := make([]string, 6)
+	for idx := range columns {
+		columns[idx] = strings.Repeat(string(rune('a'+idx)), 5)
+		values[idx] = strings.Repeat("x", 70)
+	}
+	rows := make([][]string, 3)
+	for idx := range rows {
+		rows[idx] = values
+	}
+
+	cfg := config.Default()
+	cfg.WideResults.RecordsPerPage = 2
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT * FROM wide;": newTestResultRows(columns, rows...),
+		"SELECT 1;":           newTestResult("?column?", "1"),
+	}}
+	driver := startTestAppWithConfig(t, database, cfg)
+
+	driver.typeText("SELECT * FROM wide;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("Records 1 to 2 of 3")
+	driver.waitForScreen("-[ Record 2 ]-")
+
+	driver.click(nextRecordsLabel)
+	driver.waitForScreen("Records 3 to 3 of 3")
+	driver.click(showAsTableLabel)
+	driver.waitFor("the table to be shown", func() bool {
+		return !strings.Contains(driver.screenText(), "Records 3 to 3")
+	})
+	driver.waitForScreen(showAsRecordsLabel)
+
+	// Narrow results are still shown as tables
+	driver.typeText("SELECT 1;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("?column?")
+	assert.NotContains(t, driver.screenText(), "Record 1")
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// Redraws requested within this long of each other are drawn together, around 30 times a second at most
+const redrawInterval = 33 * time.Millisecond
+
+// Batches redraw requests, so a burst of changes such as a large output being set draws once rather than per change
+// Shared by every component which needs the screen redrawn after changing outside of an input event
+type redrawCoalescer struct {
+	dirty atomic.Bool
+	stop  chan struct{}
+}
+
+func newRedrawCoalescer() *redrawCoalescer {
+	return &redrawCoalescer{stop: make(chan struct{})}
+}
+
+// Mark the screen as needing a redraw, safe to call from any goroutine
+func (coalescer *redrawCoalescer) request() {
+	coalescer.dirty.Store(true)
+}
+
+// Redraw on each tick when requested since the last, until stopped
+func (coalescer *redrawCoalescer) run(tviewApp *tview.Application) {
+	ticker := time.NewTicker(redrawInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-coalescer.stop:
+			{
+				return
+			}
+		case <-ticker.C:
+			{
+				if coalescer.dirty.Swap(false) {
+					tviewApp.QueueUpdateDraw(func() {})
+				}
+			}
+		}
+	}
+}
+
+func (coalescer *redrawCoalescer) close() {
+	close(coalescer.stop)
+}
+
+// Redraw the screen soon, batched with any other changes made around the same time
+func (app *App) requestRedraw() {
+	// Not running, such as in tests
+	if app.redraw == nil {
+		return
+	}
+
+	app.redraw.request()
+}
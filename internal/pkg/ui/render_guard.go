@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/rivo/tview"
+)
+
+const (
+	// Results taking longer than this to draw make typing noticeably lag, so they're summarized instead
+	slowDrawThreshold = 250 * time.Millisecond
+	// How much of a summarized result is shown
+	summaryRowCount    = 5
+	summaryValueLength = 60
+	openFullViewLabel  = "Open Full View"
+)
+
+// Called by the result container with items which were slow to draw
+// A result table is replaced with a summary, unless the full view was asked for
+func (app *App) handleSlowDraw(item tview.Primitive, duration time.Duration) {
+	for _, block := range app.resultBlocks {
+		if block.table == nil || block.resultItem != block.table || block.table != item || block.showFullView {
+			continue
+		}
+
+		// Drawing is still in progress, so the table is swapped out afterwards
+		go app.tviewApp.QueueUpdateDraw(func() {
+			app.summarizeResult(block, duration)
+		})
+		return
+	}
+}
+
+// Replace a block's table with a summary of it
+func (app *App) summarizeResult(block *resultBlock, duration time.Duration) {
+	// May have been summarized or collapsed while this was queued
+	if block.table == nil || block.resultItem != block.table {
+		return
+	}
+
+	if block.table.HasFocus() {
+		app.blurResultTable(block.table)
+	}
+
+	summaryView, height := app.createNoResultView(formatResultSummary(block.result, duration, app.privacyMode, app.display.NullToken))
+	app.resultContainer.ReplaceItem(block.table, summaryView, block.getResultItemHeight(height))
+	block.resultItem = summaryView
+	block.summarized = true
+	block.drawDuration = duration
+
+	app.refreshQueryView(block)
+}
+
+// Put back the table of a summarized block, drawing it however long it takes
+func (app *App) openFullResultView(block *resultBlock) {
+	if !block.summarized || block.collapsed {
+		return
+	}
+
+	app.resultContainer.ReplaceItem(block.resultItem, block.table, block.getResultItemHeight(app.getResultViewHeight(block.result)))
+	block.resultItem = block.table
+	block.summarized = false
+	block.showFullView = true
+
+	app.refreshQueryView(block)
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+func (app *App) createOpenFullViewButton(block *resultBlock) *tview.Button {
+	return NewButton(openFullViewLabel).
+		SetSelectedFunc(func() {
+			app.openFullResultView(block)
+		})
+}
+
+// Describe a result too large to draw, with its first few rows cut short, or masked in privacy mode
+func formatResultSummary(result *db.QueryResult, duration time.Duration, isMasked bool, nullToken string) string {
+	longestValue := 0
+	for _, row := range result.Rows {
+		for _, column := range result.Columns {
+			longestValue = max(longestValue, utf8.RuneCountInString(row[column].ToString()))
+		}
+	}
+
+	var summary strings.Builder
+	fmt.Fprintf(
+		&summary,
+		"Drawing this result took %.2fs, so it's summarized to keep the terminal responsive. %s shows all of it\n",
+		duration.Seconds(),
+		openFullViewLabel,
+	)
+	fmt.Fprintf(
+		&summary,
+		"%d rows, %d columns, the longest value is %d characters\n",
+		len(result.Rows),
+		len(result.Columns),
+		longestValue,
+	)
+
+	for _, row := range result.Rows[:min(len(result.Rows), summaryRowCount)] {
+		values := make([]string, len(result.Columns))
+		for idx, column := range result.Columns {
+			value := row[column].ToStringWithNull(nullToken)
+			if isMasked && row[column].Valid {
+				value = maskValue(value, getDatabaseType(result, idx))
+			}
+
+			values[idx] = fmt.Sprint(column, ": ", truncateSummaryValue(value))
+		}
+
+		fmt.Fprint(&summary, "\n", strings.Join(values, " | "))
+	}
+
+	return fmt.Sprint(summary.String(), "\n")
+}
+
+// Cut a value down to a single line of at most summaryValueLength characters
+func truncateSummaryValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+
+	if utf8.RuneCountInString(value) <= summaryValueLength {
+		return value
+	}
+
+	return fmt.Sprint(string([]rune(value)[:summaryValueLength]), "…")
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatResultSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	body := strings.Repeat("lorem ipsum\n", 1000)
+	result := &db.QueryResult{
+		Columns: []string{"id", "body"},
+		Rows: []map[string]*db.NullString{
+			{
+				"id":   {NullString: sql.NullString{String: "1", Valid: true}},
+				"body": {NullString: sql.NullString{String: body, Valid: true}},
+			},
+			{
+				"id":   {NullString: sql.NullString{String: "2", Valid: true}},
+				"body": {NullString: sql.NullString{String: "short", Valid: true}},
+			},
+		},
+	}
+
+	assert.Equal(
+		"Drawing this result took 1.50s, so it's summarized to keep the terminal responsive. Open Full View shows all of it\n"+
+			"2 rows, 2 columns, the longest value is 12000 characters\n\n"+
+			"id: 1 | body: lorem ipsum lorem ipsum lorem ipsum lorem ipsum lorem ipsum …\n"+
+			"id: 2 | body: short\n",
+		formatResultSummary(result, 1500*time.Millisecond, false, db.DefaultNullToken),
+	)
+}
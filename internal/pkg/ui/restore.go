@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	restorePageName = "restore"
+	// How often progress is redrawn, a dump can have thousands of quick statements
+	restoreProgressInterval = 100 * time.Millisecond
+)
+
+// \restore <file>
+// Run the statements of a SQL file in the background, showing progress until it's done
+func (app *App) restoreCommand(argument string) {
+	// Nothing else may use the connection while restoring
+	// Results still being read hold it too, so they're closed here rather than from the goroutine
+	app.stopWatch()
+	app.db.CloseRowStreams()
+	app.refreshFinishedResults()
+
+	progressModal := tview.NewModal().
+		SetText(fmt.Sprint("Restoring ", argument))
+	progressModal.SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(restorePageName, progressModal, true, true)
+	app.tviewApp.SetFocus(progressModal)
+
+	go func() {
+		block := &resultBlock{
+			query:    fmt.Sprint(`\restore `, argument),
+			ranAt:    time.Now(),
+			database: app.db.GetConnectionInfo().Database,
+		}
+
+		var lastProgressAt time.Time
+		block.result, block.err = app.db.Restore(argument, func(executedStatements int, totalStatements int) {
+			if time.Since(lastProgressAt) < restoreProgressInterval {
+				return
+			}
+			lastProgressAt = time.Now()
+
+			app.tviewApp.QueueUpdateDraw(func() {
+				progressModal.SetText(fmt.Sprintf(
+					"Restoring %s\n\n%d of %d statements",
+					argument,
+					executedStatements,
+					totalStatements,
+				))
+			})
+		})
+		block.duration = time.Since(block.ranAt)
+
+		app.tviewApp.QueueUpdateDraw(func() {
+			app.pages.RemovePage(restorePageName)
+			app.tviewApp.SetFocus(app.queryTextArea)
+
+			app.addResultBlock(block)
+			// The file may have started or ended a transaction
+			app.updateStatusBar()
+		})
+	}()
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	resultSearchPageName = "result-search"
+	resultSearchWidth    = 50
+)
+
+// Move navigation to the next result table with rows, or the previous one when direction is -1
+// Wraps around, so Tab keeps cycling through every result
+func (app *App) focusAdjacentResultTable(table *tview.Table, direction int) {
+	current := -1
+	for idx, block := range app.resultBlocks {
+		if block.table == table {
+			current = idx
+			break
+		}
+	}
+	if current < 0 {
+		return
+	}
+
+	for step := 1; step < len(app.resultBlocks); step++ {
+		idx := (current + step*direction + len(app.resultBlocks)) % len(app.resultBlocks)
+		nextBlock := app.resultBlocks[idx]
+		if !nextBlock.isTableShown() || nextBlock.table.GetRowCount() <= app.getFirstResultRow() {
+			continue
+		}
+
+		table.SetSelectable(false, false)
+		app.focusResultTable(nextBlock.table)
+		return
+	}
+}
+
+// Ask what to search a result table for, moving to the first cell containing it from the selected one on
+func (app *App) openResultSearch(block *resultBlock) {
+	app.openSearchField(" Search result ", block.searchTerm, block.table, func(searchTerm string) {
+		block.searchTerm = searchTerm
+		app.findResultMatch(block, 1, true)
+	})
+}
+
+// Small field to type what to search for, calling onSearch once Enter is pressed with something typed
+func (app *App) openSearchField(title string, searchTerm string, returnFocus tview.Primitive, onSearch func(searchTerm string)) {
+	closeSearch := func() {
+		app.pages.RemovePage(resultSearchPageName)
+		app.tviewApp.SetFocus(returnFocus)
+	}
+
+	searchField := tview.NewInputField().
+		SetLabel("/").
+		SetText(searchTerm)
+	searchField.
+		SetDoneFunc(func(key tcell.Key) {
+			closeSearch()
+
+			if key == tcell.KeyEnter && searchField.GetText() != "" {
+				onSearch(searchField.GetText())
+			}
+		}).
+		SetBorder(true).
+		SetTitle(title).
+		SetBackgroundColor(ColorBackground)
+
+	searchModal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(
+			tview.NewFlex().
+				SetDirection(tview.FlexRow).
+				AddItem(nil, 0, 1, false).
+				AddItem(searchField, 3, 0, true).
+				AddItem(nil, 0, 1, false),
+			resultSearchWidth,
+			0,
+			true,
+		).
+		AddItem(nil, 0, 1, false)
+
+	app.pages.AddPage(resultSearchPageName, searchModal, true, true)
+	app.tviewApp.SetFocus(searchField)
+}
+
+// Select the next cell containing the block's search term, ignoring case, or the previous one when direction is -1
+// Only rows read so far are searched. The selected cell counts as a match when includeSelected is set
+func (app *App) findResultMatch(block *resultBlock, direction int, includeSelected bool) {
+	if block.searchTerm == "" {
+		return
+	}
+
+	row, column := block.table.GetSelection()
+	match, found := findTableMatch(block.table, app.getFirstResultRow(), block.searchTerm, row, column, direction, includeSelected)
+	if !found {
+		app.showMessage(fmt.Sprintf("No cell contains %q", block.searchTerm), block.table)
+		return
+	}
+
+	block.table.Select(match.row, match.column)
+}
+
+type tableCellPosition struct {
+	row    int
+	column int
+}
+
+// Search the table's cells in reading order from the given one, wrapping around past either end
+// Rows above firstRow, such as the header, aren't searched
+// Cells are searched as shown, so masked values aren't in privacy mode
+func findTableMatch(
+	table *tview.Table,
+	firstRow int,
+	searchTerm string,
+	row int,
+	column int,
+	direction int,
+	includeSelected bool,
+) (match tableCellPosition, found bool) {
+	columnCount := table.GetColumnCount()
+	cellCount := (table.GetRowCount() - firstRow) * columnCount
+	if cellCount <= 0 {
+		return match, false
+	}
+
+	searchTerm = strings.ToLower(searchTerm)
+	start := max(row-firstRow, 0)*columnCount + column
+
+	firstStep := 1
+	if includeSelected {
+		firstStep = 0
+	}
+
+	for step := firstStep; step <= cellCount; step++ {
+		idx := ((start+step*direction)%cellCount + cellCount) % cellCount
+		cellRow, cellColumn := idx/columnCount+firstRow, idx%columnCount
+
+		if strings.Contains(strings.ToLower(table.GetCell(cellRow, cellColumn).Text), searchTerm) {
+			return tableCellPosition{cellRow, cellColumn}, true
+		}
+	}
+
+	return match, false
+}
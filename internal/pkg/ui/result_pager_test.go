@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTableMatch(t *testing.T) {
+	table := tview.NewTable()
+	for rowIdx, row := range [][]string{
+		{"id", "name"},
+		{"1", "Ada"},
+		{"2", "Grace"},
+		{"3", "ada lovelace"},
+	} {
+		for columnIdx, value := range row {
+			table.SetCell(rowIdx, columnIdx, tview.NewTableCell(value))
+		}
+	}
+
+	var tests = []struct {
+		Name            string
+		SearchTerm      string
+		Row             int
+		Column          int
+		Direction       int
+		IncludeSelected bool
+		Expected        tableCellPosition
+		ExpectedFound   bool
+	}{
+		{"Selected cell", "ada", 1, 1, 1, true, tableCellPosition{1, 1}, true},
+		{"Next match", "ADA", 1, 1, 1, false, tableCellPosition{3, 1}, true},
+		{"Wraps past the end", "ada", 3, 1, 1, false, tableCellPosition{1, 1}, true},
+		{"Previous match wraps past the start", "ada", 1, 1, -1, false, tableCellPosition{3, 1}, true},
+		{"Header isn't searched", "name", 1, 0, 1, true, tableCellPosition{}, false},
+	}
+
+	for _, test := range tests {
+		match, found := findTableMatch(table, 1, test.SearchTerm, test.Row, test.Column, test.Direction, test.IncludeSelected)
+
+		assert.Equal(t, test.ExpectedFound, found, test.Name)
+		assert.Equal(t, test.Expected, match, test.Name)
+	}
+}
+
+func TestAppResultPager(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name FROM users;": newTestResult("name", "ada", "grace", "linus"),
+		"SELECT 1;":               newTestResult("?column?", "1"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT name FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("linus")
+	driver.typeText("SELECT 1;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("?column?")
+
+	getSelection := func() (tableIdx, row int) {
+		driver.read(func() {
+			for idx, block := range driver.app.resultBlocks {
+				if block.table.HasFocus() {
+					tableIdx = idx
+					row, _ = block.table.GetSelection()
+				}
+			}
+		})
+		return tableIdx, row
+	}
+
+	// Focuses the latest result, then Tab wraps around to the first
+	driver.pressKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.waitFor("the first result to be focused", func() bool {
+		tableIdx, row := getSelection()
+		return tableIdx == 0 && row == 1
+	})
+
+	driver.typeText("/")
+	driver.waitForScreen(" Search result ")
+	driver.typeText("LIN")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the match to be selected", func() bool {
+		tableIdx, row := getSelection()
+		return tableIdx == 0 && row == 3
+	})
+
+	// ada and grace both contain an a, wrapping around from linus
+	driver.typeText("/")
+	driver.pressKey(tcell.KeyCtrlU, 0, tcell.ModCtrl)
+	driver.typeText("a")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the first match to be selected", func() bool {
+		_, row := getSelection()
+		return row == 1
+	})
+	driver.typeText("n")
+	driver.waitFor("the next match to be selected", func() bool {
+		_, row := getSelection()
+		return row == 2
+	})
+	driver.typeText("N")
+	driver.waitFor("the previous match to be selected", func() bool {
+		_, row := getSelection()
+		return row == 1
+	})
+
+	driver.typeText("/")
+	driver.pressKey(tcell.KeyCtrlU, 0, tcell.ModCtrl)
+	driver.typeText("zzz")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen(`No cell contains "zzz"`)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	driver.pressKey(tcell.KeyBacktab, 0, tcell.ModShift)
+	driver.waitFor("the latest result to be focused again", func() bool {
+		tableIdx, _ := getSelection()
+		return tableIdx == 1
+	})
+	assert.Equal(2, len(database.getStatements()))
+}
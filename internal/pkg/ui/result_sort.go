@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+const (
+	sortAscendingIndicator  = "▲"
+	sortDescendingIndicator = "▼"
+)
+
+// Column a result's rows were sorted by, after being read
+type resultSort struct {
+	column     int
+	descending bool
+}
+
+// How the values of a column are compared when sorting
+type sortKind int
+
+const (
+	sortText sortKind = iota
+	sortNumeric
+	sortTime
+)
+
+// Database types compared as numbers, without a size such as (10,2) and with UNSIGNED dropped for MySQL
+var numericTypes = []string{
+	"INT", "INT2", "INT4", "INT8", "INTEGER", "TINYINT", "SMALLINT", "MEDIUMINT", "BIGINT",
+	"SERIAL", "BIGSERIAL", "OID", "YEAR",
+	"NUMERIC", "DECIMAL", "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "REAL",
+}
+
+// Database types compared as points in time
+var timeTypes = []string{"DATE", "TIME", "TIMETZ", "DATETIME", "TIMESTAMP", "TIMESTAMPTZ"}
+
+// How drivers write times, tried in turn
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999Z07:00",
+	"15:04:05.999999999",
+}
+
+// Sort the rows read so far by a column, ascending, then descending, then back to the order they were read in
+func (app *App) toggleResultSort(block *resultBlock, column int) {
+	if block == nil || block.table == nil || column < 0 || column >= len(block.result.Columns) {
+		return
+	}
+
+	switch {
+	case block.sort == nil || block.sort.column != column:
+		{
+			block.sort = &resultSort{column: column}
+		}
+	case !block.sort.descending:
+		{
+			block.sort.descending = true
+		}
+	default:
+		{
+			block.sort = nil
+		}
+	}
+
+	app.applyResultSort(block)
+}
+
+// Reorder the block's rows as its sort says, redrawing them
+// The result's rows themselves are reordered, so copying, exporting and editing rows follow what's shown
+func (app *App) applyResultSort(block *resultBlock) {
+	if block.unsortedRows == nil {
+		block.unsortedRows = slices.Clone(block.result.Rows)
+	}
+
+	rows := slices.Clone(block.unsortedRows)
+	if block.sort != nil {
+		sortRows(block.result, rows, *block.sort)
+	} else {
+		block.unsortedRows = nil
+	}
+	block.result.Rows = rows
+
+	// Both refer to rows by where they were in the table
+	app.clearRowSelection(block)
+	app.stopResultsSearch()
+
+	app.addResultRows(block.table, block.result, 0)
+	refreshColumnHeaders(block)
+	app.refreshRecordView(block)
+}
+
+// Ex: name ▲
+func formatSortedHeader(name string, column int, sort *resultSort) string {
+	if sort == nil || sort.column != column {
+		return name
+	}
+
+	if sort.descending {
+		return fmt.Sprint(name, " ", sortDescendingIndicator)
+	}
+
+	return fmt.Sprint(name, " ", sortAscendingIndicator)
+}
+
+// A row's value in the sorted column, parsed up front so it's done once per row
+type sortKey struct {
+	row    map[string]*db.NullString
+	isNull bool
+	text   string
+	number *big.Rat
+	time   *time.Time
+}
+
+// Sort rows in place by a column of the result, keeping rows with equal values in the order they were
+// NULL is taken as larger than any value, as Postgres does
+func sortRows(result *db.QueryResult, rows []map[string]*db.NullString, sort resultSort) {
+	column := result.Columns[sort.column]
+	kind := getSortKind(result, sort.column)
+
+	keys := make([]sortKey, len(rows))
+	for idx, row := range rows {
+		keys[idx] = getSortKey(row, column, kind)
+	}
+
+	slices.SortStableFunc(keys, func(a, b sortKey) int {
+		comparison := compareSortKeys(a, b)
+		if sort.descending {
+			return -comparison
+		}
+
+		return comparison
+	})
+
+	for idx, key := range keys {
+		rows[idx] = key.row
+	}
+}
+
+// From the column's database type, or when the driver doesn't report one, whether every value is a number
+func getSortKind(result *db.QueryResult, column int) sortKind {
+	databaseType := strings.ToUpper(getDatabaseType(result, column))
+	databaseType = strings.TrimPrefix(databaseType, "UNSIGNED ")
+	if baseType, _, hasSize := strings.Cut(databaseType, "("); hasSize {
+		databaseType = strings.TrimSpace(baseType)
+	}
+
+	switch {
+	case slices.Contains(numericTypes, databaseType):
+		{
+			return sortNumeric
+		}
+	case slices.Contains(timeTypes, databaseType):
+		{
+			return sortTime
+		}
+	case databaseType != "":
+		{
+			return sortText
+		}
+	}
+
+	name := result.Columns[column]
+	for _, row := range result.Rows {
+		if value := row[name]; value != nil && value.Valid {
+			if _, isNumber := new(big.Rat).SetString(value.String); !isNumber {
+				return sortText
+			}
+		}
+	}
+
+	return sortNumeric
+}
+
+func getSortKey(row map[string]*db.NullString, column string, kind sortKind) sortKey {
+	value := row[column]
+	if value == nil || !value.Valid {
+		return sortKey{row: row, isNull: true}
+	}
+
+	key := sortKey{row: row, text: value.String}
+	switch kind {
+	case sortNumeric:
+		{
+			if number, isNumber := new(big.Rat).SetString(value.String); isNumber {
+				key.number = number
+			}
+		}
+	case sortTime:
+		{
+			for _, layout := range timeLayouts {
+				if parsedTime, err := time.Parse(layout, value.String); err == nil {
+					key.time = &parsedTime
+					break
+				}
+			}
+		}
+	}
+
+	return key
+}
+
+// Values which couldn't be parsed, such as NaN, are compared as text
+func compareSortKeys(a, b sortKey) int {
+	switch {
+	case a.isNull || b.isNull:
+		{
+			if a.isNull == b.isNull {
+				return 0
+			}
+			if a.isNull {
+				return 1
+			}
+			return -1
+		}
+	case a.number != nil && b.number != nil:
+		{
+			return a.number.Cmp(b.number)
+		}
+	case a.time != nil && b.time != nil:
+		{
+			return a.time.Compare(*b.time)
+		}
+	}
+
+	// Ignoring case first, so "b" doesn't come after "Z"
+	if comparison := strings.Compare(strings.ToLower(a.text), strings.ToLower(b.text)); comparison != 0 {
+		return comparison
+	}
+
+	return strings.Compare(a.text, b.text)
+}
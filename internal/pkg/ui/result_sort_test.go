@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortRows(t *testing.T) {
+	var tests = []struct {
+		Name         string
+		DatabaseType string
+		Values       []string
+		Descending   bool
+		Expected     []string
+	}{
+		{"Numbers by value", "INT8", []string{"10", "9", "-1", "NULL"}, false, []string{"-1", "9", "10", "NULL"}},
+		{"Decimals descending", "NUMERIC(10,2)", []string{"1.50", "10.25", "NULL", "2"}, true, []string{"NULL", "10.25", "2", "1.50"}},
+		{"Unsigned MySQL integers", "UNSIGNED BIGINT", []string{"200", "30"}, false, []string{"30", "200"}},
+		{"Times across offsets", "TIMESTAMPTZ", []string{"2024-05-01T10:00:00+02:00", "2024-05-01T09:00:00Z"}, false, []string{"2024-05-01T10:00:00+02:00", "2024-05-01T09:00:00Z"}},
+		{"Text ignoring case", "VARCHAR", []string{"b", "Z", "a", "10", "9"}, false, []string{"10", "9", "a", "b", "Z"}},
+		{"Numbers without a type", "", []string{"10", "9", "NULL"}, false, []string{"9", "10", "NULL"}},
+		{"Text without a type", "", []string{"10", "9", "x"}, false, []string{"10", "9", "x"}},
+	}
+
+	for _, test := range tests {
+		result := &db.QueryResult{
+			Columns:     []string{"value"},
+			ColumnTypes: []db.ColumnType{{DatabaseType: test.DatabaseType}},
+		}
+		for _, value := range test.Values {
+			result.Rows = append(result.Rows, map[string]*db.NullString{
+				"value": {NullString: sql.NullString{String: value, Valid: value != "NULL"}},
+			})
+		}
+
+		sortRows(result, result.Rows, resultSort{column: 0, descending: test.Descending})
+
+		var sortedValues []string
+		for _, row := range result.Rows {
+			sortedValues = append(sortedValues, row["value"].ToString())
+		}
+		assert.Equal(t, test.Expected, sortedValues, test.Name)
+	}
+}
+
+func TestAppResultSort(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT * FROM people;": newTestResult("name", "grace", "ada", "linus"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT * FROM people;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("linus")
+
+	getNames := func() (names []string) {
+		driver.read(func() {
+			for _, row := range driver.app.resultBlocks[0].result.Rows {
+				names = append(names, row["name"].ToString())
+			}
+		})
+		return names
+	}
+
+	// Clicking the header sorts ascending
+	driver.click("name")
+	driver.waitForScreen("name ▲")
+	assert.Equal([]string{"ada", "grace", "linus"}, getNames())
+
+	// Then from the keyboard, descending and back to the order read
+	driver.pressKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)
+	driver.typeText("s")
+	driver.waitForScreen("name ▼")
+	assert.Equal([]string{"linus", "grace", "ada"}, getNames())
+
+	driver.typeText("s")
+	driver.waitFor("the sort to be removed", func() bool {
+		names := getNames()
+		return len(names) == 3 && names[0] == "grace"
+	})
+	assert.Equal([]string{"grace", "ada", "linus"}, getNames())
+}
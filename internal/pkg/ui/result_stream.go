@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+const loadMoreRowsLabel = "Load more rows"
+
+// Read the next page of results whose table ends within view
+func (app *App) fetchVisibleResultRows() {
+	for _, block := range app.resultBlocks {
+		if block.table != nil && block.result.HasMoreRows() && app.resultContainer.IsItemEndVisible(block.table) {
+			app.fetchResultRows(block)
+		}
+	}
+}
+
+// Read the next page of a block's result, adding the rows to its table
+func (app *App) fetchResultRows(block *resultBlock) {
+	firstNewRow := len(block.result.Rows)
+	_, block.fetchErr = block.result.FetchRows(app.db.GetRowLimit())
+
+	// New rows take their place in the sort rather than going at the end
+	if block.sort != nil {
+		block.unsortedRows = append(block.unsortedRows, block.result.Rows[firstNewRow:]...)
+		app.applyResultSort(block)
+	} else {
+		app.addResultRows(block.table, block.result, firstNewRow)
+		// The new rows may be the first with NULLs
+		refreshColumnHeaders(block)
+	}
+	app.resultContainer.ResizeItem(block.table, block.getResultItemHeight(app.getResultViewHeight(block.result)))
+
+	block.queryTextView.SetText(formatQueryText(block))
+	app.refreshStatusLine(block)
+	app.refreshRecordView(block)
+	app.refreshFinishedResults()
+
+	app.enforceScrollback()
+}
+
+// Drop the load more button of blocks with nothing more to read
+// Either every row was read, or the rest were discarded as the connection was needed for another statement
+func (app *App) refreshFinishedResults() {
+	for _, block := range app.resultBlocks {
+		if block.canLoadMoreRows && !block.collapsed && !block.result.HasMoreRows() {
+			app.refreshQueryView(block)
+		}
+	}
+}
+
+// Reads the next rows of a result, up to the row limit
+func (app *App) createLoadMoreRowsButton(block *resultBlock) *tview.Button {
+	return NewButton(loadMoreRowsLabel).
+		SetSelectedFunc(func() {
+			app.fetchResultRows(block)
+			// The button is gone once every row is read, so focus can't stay on it
+			app.tviewApp.SetFocus(app.queryTextArea)
+		})
+}
+
+// Rebuild the query line of a block, such as to drop the load more button once it no longer applies
+func (app *App) refreshQueryView(block *resultBlock) {
+	queryView, height := app.createQueryViewWithActions(block, QueryWithResultsActions)
+	app.resultContainer.ReplaceItem(block.queryView, queryView, height)
+	block.queryView = queryView
+}
+
+// Describes how much of a result has been read, empty once all of it has
+func formatResultStreamState(block *resultBlock) string {
+	if block.fetchErr != nil {
+		return fmt.Sprintf(" (first %d rows, reading more failed: %s)", len(block.result.Rows), block.fetchErr)
+	}
+
+	if block.result.Stream == nil {
+		if block.exportedTo != "" {
+			return fmt.Sprintf(" (first %d rows, the rest were exported to %s)", len(block.result.Rows), block.exportedTo)
+		}
+
+		return ""
+	}
+
+	if block.result.Stream.Interrupted() {
+		return fmt.Sprintf(" (first %d rows, the rest were discarded when another statement ran)", len(block.result.Rows))
+	}
+
+	return fmt.Sprintf(" (first %d rows, scroll down or load more for the rest)", len(block.result.Rows))
+}
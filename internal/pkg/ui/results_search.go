@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Where a search term was found, in a block's query or one of its table's cells
+type resultsSearchMatch struct {
+	block *resultBlock
+	// -1 for a match in the query
+	row    int
+	column int
+}
+
+// Matches highlighted across the results, while n and N move between them
+type resultsSearch struct {
+	searchTerm string
+	matches    []resultsSearchMatch
+	current    int
+}
+
+// Ask what to search the results for, starting search mode at the first match
+func (app *App) openResultsSearch() {
+	searchTerm := ""
+	if app.resultsSearch != nil {
+		searchTerm = app.resultsSearch.searchTerm
+	}
+
+	app.openSearchField(" Search results ", searchTerm, app.queryTextArea, app.startResultsSearch)
+}
+
+// Highlight every query and cell containing the search term, ignoring case, and scroll to the first
+func (app *App) startResultsSearch(searchTerm string) {
+	app.stopResultsSearch()
+
+	matches := app.findResultsMatches(searchTerm)
+	if len(matches) == 0 {
+		app.showMessage(fmt.Sprintf("Nothing in the results contains %q", searchTerm), app.queryTextArea)
+		return
+	}
+
+	app.resultsSearch = &resultsSearch{searchTerm: searchTerm, matches: matches}
+	for _, match := range matches {
+		setMatchHighlight(match, ColorSearchMatch)
+	}
+	app.showResultsMatch(0)
+}
+
+// Every match in reading order, from the oldest result down
+// Tables are searched as shown, while summarized results and results shown as records only have their query searched
+func (app *App) findResultsMatches(searchTerm string) (matches []resultsSearchMatch) {
+	searchTerm = strings.ToLower(searchTerm)
+
+	for _, block := range app.resultBlocks {
+		if block.collapsed {
+			continue
+		}
+
+		if !block.hideQuery && strings.Contains(strings.ToLower(block.query), searchTerm) {
+			matches = append(matches, resultsSearchMatch{block: block, row: -1})
+		}
+
+		if !block.isTableShown() {
+			continue
+		}
+
+		for row := app.getFirstResultRow(); row < block.table.GetRowCount(); row++ {
+			for column := 0; column < block.table.GetColumnCount(); column++ {
+				if strings.Contains(strings.ToLower(block.table.GetCell(row, column).Text), searchTerm) {
+					matches = append(matches, resultsSearchMatch{block: block, row: row, column: column})
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// Move to another match, wrapping around past either end
+func (app *App) showResultsMatch(current int) {
+	search := app.resultsSearch
+
+	setMatchHighlight(search.matches[search.current], ColorSearchMatch)
+	search.current = (current + len(search.matches)) % len(search.matches)
+
+	match := search.matches[search.current]
+	setMatchHighlight(match, ColorSearchCurrent)
+
+	// The result may have been released from the scrollback since
+	if !match.block.collapsed {
+		if match.row < 0 {
+			app.resultContainer.ScrollToBlockOf(match.block.queryView)
+		} else {
+			app.resultContainer.ScrollItemIntoView(match.block.table, app.getTableRowLine(match.row))
+			app.resultContainer.ScrollColumnIntoView(match.block.table, match.column)
+		}
+	}
+
+	app.updateStatusBar()
+}
+
+// Leave search mode, removing the highlights
+func (app *App) stopResultsSearch() {
+	if app.resultsSearch == nil {
+		return
+	}
+
+	for _, match := range app.resultsSearch.matches {
+		clearMatchHighlight(match)
+	}
+	app.resultsSearch = nil
+
+	app.updateStatusBar()
+}
+
+// In search mode, n and N move between matches while any other key leaves it, going on to do what it normally would
+func (app *App) handleResultsSearchKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyRune && event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0 {
+		switch event.Rune() {
+		case nextMatchKey:
+			{
+				app.showResultsMatch(app.resultsSearch.current + 1)
+				return nil
+			}
+		case previousMatchKey:
+			{
+				app.showResultsMatch(app.resultsSearch.current - 1)
+				return nil
+			}
+		}
+	}
+
+	app.stopResultsSearch()
+	if event.Key() == tcell.KeyEscape {
+		return nil
+	}
+
+	return event
+}
+
+// Ex: Match 2 of 5 for "ada", n / N for the next / previous, Escape to stop
+func formatResultsSearchStatus(search *resultsSearch) string {
+	return fmt.Sprintf(
+		"Match %d of %d for %q, %c / %c for the next / previous, Escape to stop",
+		search.current+1,
+		len(search.matches),
+		search.searchTerm,
+		nextMatchKey,
+		previousMatchKey,
+	)
+}
+
+func setMatchHighlight(match resultsSearchMatch, color tcell.Color) {
+	if match.block.collapsed {
+		return
+	}
+
+	if match.row < 0 {
+		match.block.queryTextView.SetTextColor(color)
+		return
+	}
+
+	match.block.table.GetCell(match.row, match.column).SetBackgroundColor(color)
+}
+
+// Put back how the match looked before it was highlighted, keeping selected rows shown as selected
+func clearMatchHighlight(match resultsSearchMatch) {
+	if match.block.collapsed {
+		return
+	}
+
+	if match.row < 0 {
+		match.block.queryTextView.SetTextColor(ColorSecondary)
+		return
+	}
+
+	cell := match.block.table.GetCell(match.row, match.column)
+	if match.block.selectedRows[match.row] {
+		cell.SetBackgroundColor(ColorSelectedRow)
+	} else {
+		cell.SetTransparency(true)
+	}
+}
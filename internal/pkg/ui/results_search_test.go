@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppResultsSearch(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name FROM users;": newTestResult("name", "ada", "grace", "adam"),
+		"SELECT 'ada';":           newTestResult("?column?", "ada"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT name FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("grace")
+	driver.typeText("SELECT 'ada';")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("?column?")
+
+	driver.pressKey(tcell.KeyRune, '/', tcell.ModAlt)
+	driver.waitForScreen(" Search results ")
+	driver.typeText("ADA")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	// Two cells of the first result, then the second's query and cell
+	driver.waitForScreen(`Match 1 of 4 for "ADA"`)
+	driver.typeText("nn")
+	driver.waitForScreen(`Match 3 of 4 for "ADA"`)
+
+	var currentMatch resultsSearchMatch
+	driver.read(func() {
+		currentMatch = driver.app.resultsSearch.matches[driver.app.resultsSearch.current]
+	})
+	assert.Equal(-1, currentMatch.row)
+	assert.Equal("SELECT 'ada';", currentMatch.block.query)
+
+	// Wraps around past the first match
+	driver.typeText("NNN")
+	driver.waitForScreen(`Match 4 of 4 for "ADA"`)
+
+	// Any other key leaves search mode and is typed as usual
+	driver.typeText("S")
+	driver.waitFor("search mode to end", func() bool {
+		var isSearching bool
+		driver.read(func() {
+			isSearching = driver.app.resultsSearch != nil
+		})
+		return !isSearching && driver.queryText() == "S"
+	})
+
+	// Highlights are removed along with it
+	driver.read(func() {
+		cell := driver.app.resultBlocks[0].table.GetCell(1, 0)
+		assert.True(cell.Transparent)
+	})
+}
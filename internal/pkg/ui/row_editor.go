@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/rivo/tview"
+)
+
+const (
+	rowEditorPageName = "row-editor"
+	// Typed into a field to set the column to NULL
+	nullInputValue = `\N`
+)
+
+// Open a form for the selected row of a result, which generates an UPDATE for the changed columns
+// The UPDATE is only run once confirmed
+func (app *App) openRowEditor(block *resultBlock, row int) {
+	firstRow := app.getFirstResultRow()
+	if block == nil || block.result == nil || row < firstRow || row >= firstRow+len(block.result.Rows) {
+		return
+	}
+
+	// The form shows every value in full
+	if app.privacyMode {
+		app.showMessage("Turn off privacy mode to edit rows", block.table)
+		return
+	}
+
+	target, err := app.db.GetRowEditTarget(block.query, block.result.Columns)
+	if err != nil {
+		app.showMessage(err.Error(), block.table)
+		return
+	}
+
+	resultRow := block.result.Rows[row-firstRow]
+
+	closeRowEditor := func() {
+		app.pages.RemovePage(rowEditorPageName)
+		app.tviewApp.SetFocus(block.table)
+	}
+
+	form := tview.NewForm()
+	for _, column := range target.Columns {
+		value := resultRow[column]
+
+		initialText := value.String
+		if !value.Valid {
+			initialText = nullInputValue
+		}
+
+		form.AddInputField(column, initialText, 0, nil, nil)
+	}
+
+	form.AddButton("Save", func() {
+		changes := make(map[string]*db.NullString)
+		for idx, column := range target.Columns {
+			text := form.GetFormItem(idx).(*tview.InputField).GetText()
+
+			value := &db.NullString{}
+			if text != nullInputValue {
+				value.NullString = sql.NullString{String: text, Valid: true}
+			}
+
+			originalValue := resultRow[column]
+			if value.Valid != originalValue.Valid || value.String != originalValue.String {
+				changes[column] = value
+			}
+		}
+
+		statement, err := app.db.BuildRowUpdate(target, resultRow, changes)
+		if err != nil {
+			app.showMessage(err.Error(), form)
+			return
+		}
+
+		closeRowEditor()
+		app.confirm(fmt.Sprint("Run ", statement), func() {
+			app.commitQuery(statement)
+		})
+	})
+	form.AddButton("Cancel", closeRowEditor)
+	form.SetCancelFunc(closeRowEditor)
+
+	form.
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" Edit %s (%s for NULL) ", target.Table, nullInputValue)).
+		SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(rowEditorPageName, NewModal(form), true, true)
+	app.tviewApp.SetFocus(form)
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+// Add or remove a row from its block's selection
+func (app *App) toggleRowSelection(block *resultBlock, row int) {
+	app.setRowSelected(block, row, !block.selectedRows[row])
+}
+
+// Rows are numbered as in the table, so the first row of the result is after the header, see getFirstResultRow
+func (app *App) setRowSelected(block *resultBlock, row int, isSelected bool) {
+	if block == nil || block.table == nil || row < app.getFirstResultRow() || row >= block.table.GetRowCount() {
+		return
+	}
+
+	if isSelected {
+		if block.selectedRows == nil {
+			block.selectedRows = map[int]bool{}
+		}
+		block.selectedRows[row] = true
+	} else {
+		delete(block.selectedRows, row)
+	}
+
+	for column := 0; column < block.table.GetColumnCount(); column++ {
+		cell := block.table.GetCell(row, column)
+		if isSelected {
+			cell.SetBackgroundColor(ColorSelectedRow)
+		} else {
+			cell.SetTransparency(true)
+		}
+	}
+
+	app.refreshStatusLine(block)
+}
+
+// Select the row next to the selected cell as well as the cell's own row, moving onto it
+func (app *App) extendRowSelection(block *resultBlock, direction int) {
+	row, column := block.table.GetSelection()
+	nextRow := row + direction
+	if nextRow < app.getFirstResultRow() || nextRow >= block.table.GetRowCount() {
+		return
+	}
+
+	app.setRowSelected(block, row, true)
+	app.setRowSelected(block, nextRow, true)
+	block.table.Select(nextRow, column)
+}
+
+// Deselect every row of the block, returning whether any were selected
+func (app *App) clearRowSelection(block *resultBlock) bool {
+	if block == nil || len(block.selectedRows) == 0 {
+		return false
+	}
+
+	for row := range block.selectedRows {
+		app.setRowSelected(block, row, false)
+	}
+
+	return true
+}
+
+// The block's result with only the selected rows, in the order they appear
+// The whole result when nothing is selected
+func (app *App) getSelectedResult(block *resultBlock) *db.QueryResult {
+	if len(block.selectedRows) == 0 {
+		return block.result
+	}
+
+	selectedResult := &db.QueryResult{
+		Columns:     block.result.Columns,
+		ColumnTypes: block.result.ColumnTypes,
+	}
+	for idx, row := range block.result.Rows {
+		if block.selectedRows[idx+app.getFirstResultRow()] {
+			selectedResult.Rows = append(selectedResult.Rows, row)
+		}
+	}
+
+	return selectedResult
+}
+
+func (app *App) refreshStatusLine(block *resultBlock) {
+	if block.statusLine != nil {
+		block.statusLine.SetText(formatStatusLine(block))
+	}
+}
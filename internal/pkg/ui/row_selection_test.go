@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppRowSelection(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name FROM users;": newTestResult("name", "ada", "grace", "linus", "ken"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT name FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("4 rows in")
+
+	driver.pressKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)
+
+	// Space on ada, then Shift+Down twice takes in grace and linus
+	driver.pressKey(tcell.KeyRune, selectRowKey, tcell.ModNone)
+	driver.waitForScreen("1 selected")
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModShift)
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModShift)
+	driver.waitForScreen("3 selected")
+
+	// Space again on linus, where the cursor ended up, leaves it out
+	driver.pressKey(tcell.KeyRune, selectRowKey, tcell.ModNone)
+	driver.waitForScreen("2 selected")
+
+	var selectedNames []string
+	driver.read(func() {
+		for _, row := range driver.app.getSelectedResult(driver.app.resultBlocks[0]).Rows {
+			selectedNames = append(selectedNames, row["name"].ToString())
+		}
+	})
+	assert.Equal([]string{"ada", "grace"}, selectedNames)
+
+	// The first Escape clears the selection, staying on the table
+	driver.pressKey(tcell.KeyEscape, 0, tcell.ModNone)
+	driver.waitFor("the selection to be cleared", func() bool {
+		var result *db.QueryResult
+		driver.read(func() {
+			block := driver.app.resultBlocks[0]
+			if block.table.HasFocus() {
+				result = driver.app.getSelectedResult(block)
+			}
+		})
+		return result != nil && len(result.Rows) == 4
+	})
+}
@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	schemaBrowserWidth = 32
+	schemaBrowserTitle = " Schema "
+	// Only the connected database's tables can be listed, the statements which list them read the current one
+	otherDatabaseMessage = "USE it to list its tables"
+)
+
+// What a node of the schema browser stands for
+type schemaNodeKind int
+
+const (
+	schemaNodeDatabase schemaNodeKind = iota + 1
+	schemaNodeTable
+	schemaNodeColumn
+	schemaNodeIndex
+	// Explains why there's nothing to list, such as an error, with nothing to insert
+	schemaNodeMessage
+)
+
+// Kept as the reference of each node of the schema browser
+type schemaNode struct {
+	kind schemaNodeKind
+	// Inserted into the query editor, quoted when needed
+	name string
+	// Whether the tables of a database, or the columns and indexes of a table, have been read
+	loaded bool
+}
+
+// Show the schema browser left of the results, or hide it when already shown
+func (app *App) toggleSchemaBrowser() {
+	if app.schemaBrowser != nil {
+		app.closeSchemaBrowser()
+		return
+	}
+
+	app.openSchemaBrowser()
+}
+
+// Tree of databases, their tables, and the columns and indexes of each table
+// Read again each time it's opened, so it's never further out of date than the last time it was shown
+func (app *App) openSchemaBrowser() {
+	if app.schemaBrowser != nil {
+		app.tviewApp.SetFocus(app.schemaBrowser)
+		return
+	}
+
+	root := tview.NewTreeNode("")
+	tree := tview.NewTreeView().
+		SetRoot(root).
+		SetTopLevel(1).
+		SetGraphicsColor(ColorSecondary)
+	tree.
+		SetBorder(true).
+		SetTitle(schemaBrowserTitle).
+		SetBackgroundColor(ColorBackground)
+
+	tree.SetSelectedFunc(app.toggleSchemaNode)
+	tree.SetInputCapture(app.handleSchemaBrowserInput)
+	tree.SetDoneFunc(func(tcell.Key) {
+		app.tviewApp.SetFocus(app.queryTextArea)
+	})
+
+	app.schemaBrowser = tree
+	app.loadSchemaDatabases(root)
+	if children := root.GetChildren(); len(children) > 0 {
+		tree.SetCurrentNode(children[0])
+	}
+
+	app.mainLayout.
+		Clear().
+		AddItem(tree, schemaBrowserWidth, 0, true).
+		AddItem(app.mainArea, 0, 1, false)
+	app.tviewApp.SetFocus(tree)
+}
+
+func (app *App) closeSchemaBrowser() {
+	app.schemaBrowser = nil
+	app.mainLayout.
+		Clear().
+		AddItem(app.mainArea, 0, 1, true)
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// Enter inserts the name of the selected node, the arrow keys expand and collapse it
+func (app *App) handleSchemaBrowserInput(event *tcell.EventKey) *tcell.EventKey {
+	if action, isBound := app.keymap.Lookup(event); isBound && action == keymap.ToggleSchemaBrowser {
+		app.closeSchemaBrowser()
+		return nil
+	}
+
+	node := app.schemaBrowser.GetCurrentNode()
+	if node == nil {
+		return event
+	}
+	reference, _ := node.GetReference().(*schemaNode)
+	if reference == nil {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyEnter:
+		{
+			if reference.kind != schemaNodeMessage {
+				app.insertIdentifier(reference.name)
+			}
+
+			return nil
+		}
+	case tcell.KeyRight:
+		{
+			if isExpandableSchemaNode(reference) && !node.IsExpanded() {
+				app.toggleSchemaNode(node)
+				return nil
+			}
+		}
+	case tcell.KeyLeft:
+		{
+			if isExpandableSchemaNode(reference) && node.IsExpanded() {
+				node.Collapse()
+				return nil
+			}
+
+			// Otherwise up to the table or database it belongs to
+			if path := app.schemaBrowser.GetPath(node); len(path) > 2 {
+				app.schemaBrowser.SetCurrentNode(path[len(path)-2])
+				return nil
+			}
+		}
+	}
+
+	return event
+}
+
+func isExpandableSchemaNode(reference *schemaNode) bool {
+	return reference.kind == schemaNodeDatabase || reference.kind == schemaNodeTable
+}
+
+// Expand or collapse a database or table, reading what's in it the first time
+func (app *App) toggleSchemaNode(node *tview.TreeNode) {
+	reference, _ := node.GetReference().(*schemaNode)
+	if reference == nil || !isExpandableSchemaNode(reference) {
+		return
+	}
+
+	if !reference.loaded {
+		reference.loaded = true
+
+		if reference.kind == schemaNodeDatabase {
+			app.loadSchemaTables(node, reference.name)
+		} else {
+			app.loadSchemaTableDetails(node, reference.name)
+		}
+
+		node.Expand()
+		return
+	}
+
+	node.SetExpanded(!node.IsExpanded())
+}
+
+// Insert a name into the query editor at the cursor, replacing any selection
+func (app *App) insertIdentifier(name string) {
+	identifier := lexer.QuoteIdentifier(app.db.GetConnectionInfo().Flavor, name, lexer.QuoteWhenNeeded)
+
+	_, start, end := app.queryTextArea.GetSelection()
+	app.queryTextArea.Replace(start, end, identifier)
+	app.queryTextArea.Select(start+len(identifier), start+len(identifier))
+
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// List every database, with the connected one expanded to show its tables
+func (app *App) loadSchemaDatabases(root *tview.TreeNode) {
+	currentDatabase := app.db.GetConnectionInfo().Database
+
+	databases, err := app.querySchemaNames("SHOW DATABASES")
+	if err != nil {
+		// The connected database can still be browsed without the others
+		databases = nil
+		if currentDatabase == "" {
+			root.AddChild(newSchemaMessageNode(err.Error(), ColorError))
+			return
+		}
+	}
+	if currentDatabase != "" && !slices.Contains(databases, currentDatabase) {
+		databases = append([]string{currentDatabase}, databases...)
+	}
+
+	for _, database := range databases {
+		databaseNode := newSchemaNode(database, schemaNodeDatabase, ColorPrimary)
+		root.AddChild(databaseNode)
+
+		if database == currentDatabase {
+			databaseNode.SetText(tview.Escape(fmt.Sprint(database, " (current)")))
+			app.toggleSchemaNode(databaseNode)
+		}
+	}
+}
+
+func (app *App) loadSchemaTables(databaseNode *tview.TreeNode, database string) {
+	if database != app.db.GetConnectionInfo().Database {
+		databaseNode.AddChild(newSchemaMessageNode(otherDatabaseMessage, ColorSecondary))
+		return
+	}
+
+	tables, err := app.querySchemaNames("SHOW TABLES")
+	if err != nil {
+		databaseNode.AddChild(newSchemaMessageNode(err.Error(), ColorError))
+		return
+	}
+	if len(tables) == 0 {
+		databaseNode.AddChild(newSchemaMessageNode("No tables", ColorSecondary))
+		return
+	}
+
+	for _, table := range tables {
+		databaseNode.AddChild(newSchemaNode(table, schemaNodeTable, ColorPrimary))
+	}
+}
+
+// Columns with their types, followed by indexes with the columns they cover
+func (app *App) loadSchemaTableDetails(tableNode *tview.TreeNode, table string) {
+	flavor := app.db.GetConnectionInfo().Flavor
+	quotedTable := lexer.QuoteIdentifier(flavor, table, lexer.QuoteWhenNeeded)
+
+	describeResult, err := app.querySchema(fmt.Sprint("DESCRIBE ", quotedTable))
+	if err != nil {
+		tableNode.AddChild(newSchemaMessageNode(err.Error(), ColorError))
+		return
+	}
+
+	for _, row := range describeResult.Rows {
+		column := getSchemaValue(row, "Field")
+		columnNode := newSchemaNode(column, schemaNodeColumn, ColorPrimary)
+		if columnType := getSchemaValue(row, "Type"); columnType != "" {
+			columnNode.SetText(tview.Escape(fmt.Sprint(column, " ", columnType)))
+		}
+
+		tableNode.AddChild(columnNode)
+	}
+
+	// Columns are still worth showing when indexes can't be listed
+	indexesResult, err := app.querySchema(fmt.Sprint("SHOW INDEXES FROM ", quotedTable))
+	if err != nil {
+		return
+	}
+
+	var indexNames []string
+	indexColumns := map[string][]string{}
+	for _, row := range indexesResult.Rows {
+		indexName := getSchemaValue(row, "Key_name")
+		if _, exists := indexColumns[indexName]; !exists {
+			indexNames = append(indexNames, indexName)
+		}
+
+		// Expression indexes have no column name
+		if column := getSchemaValue(row, "Column_name"); column != "" {
+			indexColumns[indexName] = append(indexColumns[indexName], column)
+		} else {
+			indexColumns[indexName] = append(indexColumns[indexName], getSchemaValue(row, "Expression"))
+		}
+	}
+
+	for _, indexName := range indexNames {
+		indexNode := newSchemaNode(indexName, schemaNodeIndex, ColorSecondary).
+			SetText(tview.Escape(fmt.Sprintf("%s (%s)", indexName, strings.Join(indexColumns[indexName], ", "))))
+		tableNode.AddChild(indexNode)
+	}
+}
+
+// Run a statement for the schema browser, reading every row of the result
+func (app *App) querySchema(statement string) (*db.QueryResult, error) {
+	result, err := app.db.Query(statement)
+
+	// Running it closed any result still being read
+	app.refreshFinishedResults()
+
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return &db.QueryResult{}, nil
+	}
+	if _, err := result.FetchRows(0); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Names in the first column of the statement's result, such as the tables of SHOW TABLES
+func (app *App) querySchemaNames(statement string) ([]string, error) {
+	result, err := app.querySchema(statement)
+	if err != nil || len(result.Columns) == 0 {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		names = append(names, getSchemaValue(row, result.Columns[0]))
+	}
+
+	return names, nil
+}
+
+func getSchemaValue(row map[string]*db.NullString, column string) string {
+	value, exists := row[column]
+	if !exists || !value.Valid {
+		return ""
+	}
+
+	return value.String
+}
+
+func newSchemaNode(name string, kind schemaNodeKind, color tcell.Color) *tview.TreeNode {
+	return tview.NewTreeNode(tview.Escape(name)).
+		SetReference(&schemaNode{kind: kind, name: name}).
+		SetColor(color).
+		SetExpanded(false)
+}
+
+func newSchemaMessageNode(message string, color tcell.Color) *tview.TreeNode {
+	return tview.NewTreeNode(tview.Escape(message)).
+		SetReference(&schemaNode{kind: schemaNodeMessage}).
+		SetColor(color)
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// Result with a row per slice of values, in the order of the columns
+func newTestResultRows(columns []string, rows ...[]string) *db.QueryResult {
+	result := &db.QueryResult{Columns: columns}
+	for _, values := range rows {
+		row := map[string]*db.NullString{}
+		for idx, column := range columns {
+			row[column] = &db.NullString{NullString: sql.NullString{String: values[idx], Valid: true}}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result
+}
+
+func TestAppSchemaBrowser(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SHOW DATABASES": newTestResult("Database", "analytics", "test"),
+		"SHOW TABLES":    newTestResult("Table", "users"),
+		"DESCRIBE users": newTestResultRows(
+			[]string{"Field", "Type"},
+			[]string{"id", "integer"},
+			[]string{"email", "text"},
+		),
+		"SHOW INDEXES FROM users": newTestResultRows(
+			[]string{"Key_name", "Column_name"},
+			[]string{"users_pkey", "id"},
+		),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.pressKey(tcell.KeyCtrlO, 0, tcell.ModCtrl)
+	driver.waitForScreen("test (current)")
+	driver.waitForScreen("analytics")
+	driver.waitForScreen("users")
+
+	// Other databases can't be listed without switching to them
+	driver.pressKey(tcell.KeyRight, 0, tcell.ModNone)
+	driver.waitForScreen(otherDatabaseMessage)
+
+	// Down to users, past the current database
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyRight, 0, tcell.ModNone)
+	driver.waitForScreen("email text")
+	driver.waitForScreen("users_pkey (id)")
+
+	// Columns are only read once the table is expanded
+	assert.Equal(
+		[]string{"SHOW DATABASES", "SHOW TABLES", "DESCRIBE users", "SHOW INDEXES FROM users"},
+		database.getStatements(),
+	)
+
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyDown, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the column to be inserted", func() bool {
+		return driver.queryText() == "email"
+	})
+
+	driver.pressKey(tcell.KeyCtrlO, 0, tcell.ModCtrl)
+	driver.waitFor("the schema browser to be hidden", func() bool {
+		return !strings.Contains(driver.screenText(), schemaBrowserTitle)
+	})
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"github.com/azvaliev/sql/internal/pkg/config"
+)
+
+// Shown in place of a result released from the scrollback
+const collapsedIndicator = "(result released from scrollback)"
+
+// Collapse the oldest results while more are kept than the scrollback allows, releasing their rows
+// Bookmarked results, the latest result and a table being navigated are always kept
+func (app *App) enforceScrollback() {
+	retainedBlocks, retainedRows := 0, 0
+	for _, block := range app.resultBlocks {
+		if !block.collapsed {
+			retainedBlocks++
+			retainedRows += block.getRowCount()
+		}
+	}
+
+	for _, block := range app.resultBlocks[:max(len(app.resultBlocks)-1, 0)] {
+		if !isOverScrollback(app.scrollback, retainedBlocks, retainedRows) {
+			return
+		}
+
+		if block.collapsed || block.bookmarked || (block.table != nil && block.table.HasFocus()) {
+			continue
+		}
+
+		retainedBlocks--
+		retainedRows -= block.getRowCount()
+		app.collapseResultBlock(block)
+	}
+}
+
+func isOverScrollback(scrollback config.ScrollbackConfig, blocks int, rows int) bool {
+	return (scrollback.MaxBlocks > 0 && blocks > scrollback.MaxBlocks) ||
+		(scrollback.MaxRows > 0 && rows > scrollback.MaxRows)
+}
+
+// Replace a block with just its query line, dropping everything holding on to its result
+// The action buttons are dropped too, as they reference the result
+func (app *App) collapseResultBlock(block *resultBlock) {
+	if block.result != nil && block.result.Stream != nil {
+		_ = block.result.Stream.Close()
+	}
+
+	block.collapsed = true
+	block.result = nil
+	block.table = nil
+
+	queryTextItem := NewTextView(TextViewSecondary).
+		SetText(formatQueryText(block)).
+		SetWrap(true).
+		SetWordWrap(true)
+
+	// Not laid out yet before the first draw
+	height := 1
+	if _, _, containerWidth, _ := app.resultContainer.GetInnerRect(); containerWidth > 0 {
+		height = getTextLineCount(queryTextItem, containerWidth)
+	}
+
+	collapsedView := NewGrid().
+		SetRows(height).
+		AddItem(queryTextItem, 0, 0, 1, 1, 0, 0, false)
+
+	app.resultContainer.
+		ReplaceItem(block.queryView, collapsedView, height).
+		RemoveItem(block.resultItem)
+	if block.statusLine != nil {
+		app.resultContainer.RemoveItem(block.statusLine)
+	}
+
+	block.queryView = collapsedView
+	block.queryTextView = queryTextItem
+	block.resultItem = nil
+	block.statusLine = nil
+}
+
+// Rows of the block's result, 0 when it has none
+func (block *resultBlock) getRowCount() int {
+	if block.result == nil {
+		return 0
+	}
+
+	return len(block.result.Rows)
+}
+
+// Remove every result, releasing their rows
+func (app *App) clearResults() {
+	// Results still being read hold the connection
+	app.db.CloseRowStreams()
+
+	app.resultBlocks = nil
+	app.resultContainer.ClearItems().ClearOffsets()
+	app.setColumnHint("")
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnforceScrollback(t *testing.T) {
+	var tests = []struct {
+		Name              string
+		Scrollback        config.ScrollbackConfig
+		RowCounts         []int
+		Bookmarked        []int
+		ExpectedCollapsed []bool
+	}{
+		{
+			Name:              "Unlimited",
+			Scrollback:        config.ScrollbackConfig{},
+			RowCounts:         []int{10, 10, 10},
+			ExpectedCollapsed: []bool{false, false, false},
+		},
+		{
+			Name:              "Block limit",
+			Scrollback:        config.ScrollbackConfig{MaxBlocks: 2},
+			RowCounts:         []int{1, 1, 1, 1},
+			ExpectedCollapsed: []bool{true, true, false, false},
+		},
+		{
+			Name:              "Row limit",
+			Scrollback:        config.ScrollbackConfig{MaxRows: 25},
+			RowCounts:         []int{10, 10, 10},
+			ExpectedCollapsed: []bool{true, false, false},
+		},
+		{
+			Name:              "Latest result is kept over the row limit",
+			Scrollback:        config.ScrollbackConfig{MaxRows: 5},
+			RowCounts:         []int{10, 10},
+			ExpectedCollapsed: []bool{true, false},
+		},
+		{
+			Name:              "Bookmarked results are kept",
+			Scrollback:        config.ScrollbackConfig{MaxBlocks: 2},
+			RowCounts:         []int{1, 1, 1, 1},
+			Bookmarked:        []int{0},
+			ExpectedCollapsed: []bool{false, true, true, false},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			app := &App{
+				resultContainer: NewScrollBox(),
+				scrollback:      test.Scrollback,
+			}
+			for blockIdx, rowCount := range test.RowCounts {
+				result := &db.QueryResult{Columns: []string{"id"}}
+				for range rowCount {
+					result.Rows = append(result.Rows, map[string]*db.NullString{"id": {}})
+				}
+
+				app.addResultBlock(&resultBlock{
+					query:      "SELECT id FROM users;",
+					result:     result,
+					bookmarked: slices.Contains(test.Bookmarked, blockIdx),
+				})
+			}
+
+			collapsed := make([]bool, len(app.resultBlocks))
+			for idx, block := range app.resultBlocks {
+				collapsed[idx] = block.collapsed
+				if block.collapsed {
+					assert.Nil(block.result)
+					assert.Contains(block.queryTextView.GetText(true), collapsedIndicator)
+				}
+			}
+			assert.Equal(test.ExpectedCollapsed, collapsed)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/share"
+	"github.com/rivo/tview"
+)
+
+const (
+	sharePageName       = "share"
+	shareCSVLabel       = "CSV"
+	shareMarkdownLabel  = "Markdown"
+	shareButtonLabel    = "Share…"
+	shareUploadingLabel = "Uploading…"
+)
+
+// Offer to upload a result to the configured paste service, nil when sharing isn't configured
+func (app *App) createShareButton(queryResult *db.QueryResult) *tview.Button {
+	if app.shareUploader == nil || len(queryResult.Columns) == 0 {
+		return nil
+	}
+
+	return NewButton(shareButtonLabel).
+		SetSelectedFunc(func() {
+			app.openShare(queryResult)
+		})
+}
+
+// Ask which export of the result to upload
+func (app *App) openShare(queryResult *db.QueryResult) {
+	shareModal := tview.NewModal().
+		SetText(fmt.Sprintf("Upload this result to %s as", app.shareUploader.Service())).
+		AddButtons([]string{shareCSVLabel, shareMarkdownLabel, cancelButtonLabel}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			app.pages.RemovePage(sharePageName)
+			app.tviewApp.SetFocus(app.queryTextArea)
+
+			switch buttonLabel {
+			case shareCSVLabel:
+				{
+					app.shareResult(share.Paste{Filename: "result.csv", Content: queryResult.ToCSV()})
+				}
+			case shareMarkdownLabel:
+				{
+					app.shareResult(share.Paste{Filename: "result.md", Content: queryResult.ToMarkdown()})
+				}
+			}
+		})
+
+	shareModal.SetBackgroundColor(ColorBackground)
+
+	app.pages.AddPage(sharePageName, shareModal, true, true)
+	app.tviewApp.SetFocus(shareModal)
+}
+
+// Upload in the background, so a slow paste service doesn't freeze the application, then copy the link
+func (app *App) shareResult(paste share.Paste) {
+	app.showMessage(shareUploadingLabel, app.queryTextArea)
+
+	go func() {
+		link, err := app.shareUploader.Upload(paste)
+
+		app.tviewApp.QueueUpdateDraw(func() {
+			app.pages.RemovePage(messagePageName)
+
+			if err != nil {
+				app.showMessage(err.Error(), app.queryTextArea)
+				return
+			}
+
+			// The link is still shown when it can't be copied
+			if err := writeClipboard([]byte(link)); err != nil {
+				app.showMessage(fmt.Sprint("Shared, the link couldn't be copied to the clipboard\n", link), app.queryTextArea)
+				return
+			}
+
+			app.showMessage(fmt.Sprint("Link copied to the clipboard\n", link), app.queryTextArea)
+		})
+	}()
+}
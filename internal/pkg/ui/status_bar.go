@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	sandboxButtonLabel  = "Sandbox"
+	commitButtonLabel   = "Commit"
+	rollbackButtonLabel = "Rollback"
+	safeModeLabel       = "SAFE"
+	readOnlyLabel       = "READ-ONLY"
+)
+
+// Rebuild the line between the results and the query text area, to reflect the current session state
+func (app *App) updateStatusBar() {
+	app.statusBar.Clear()
+
+	var status *tview.TextView
+	var buttons []*tview.Button
+
+	if app.db.InTransaction() {
+		status = NewTextView(TextViewError).
+			SetText("Sandbox: statements run in a transaction, commit to keep changes")
+		buttons = []*tview.Button{
+			NewButton(commitButtonLabel).SetSelectedFunc(app.commitSandbox),
+			NewButton(rollbackButtonLabel).SetSelectedFunc(app.rollbackSandbox),
+		}
+	} else {
+		status = NewTextView(TextViewSecondary).
+			SetText(formatAutocommitStatus(app.db.GetSearchPath()))
+		buttons = []*tview.Button{
+			NewButton(sandboxButtonLabel).SetSelectedFunc(app.startSandbox),
+		}
+	}
+
+	if app.watch != nil {
+		// The sandbox takes priority, as it's easy to forget changes haven't been committed
+		if !app.db.InTransaction() {
+			status = NewTextView(TextViewSecondary).
+				SetText(app.watch.describe())
+		}
+		buttons = append(buttons, NewButton(stopWatchLabel).SetSelectedFunc(app.stopWatch))
+	}
+
+	// Warnings about the query being edited take priority, as they're only relevant until it's sent
+	if len(app.lintWarnings) > 0 {
+		status = NewTextView(TextViewError).
+			SetText(formatLintWarnings(app.lintWarnings))
+	}
+
+	// Only set while a result header is selected or hovered, so it's what the user is looking at
+	if app.columnHint != "" {
+		status = NewTextView(TextViewSecondary).
+			SetText(app.columnHint)
+	}
+
+	// Keys mean something else while searching, so that's shown above all else
+	if app.resultsSearch != nil {
+		status = NewTextView(TextViewSecondary).
+			SetText(formatResultsSearchStatus(app.resultsSearch))
+	}
+
+	// The prompt may show the transaction state too
+	app.updatePrompt()
+
+	modeLabel := app.getInputModeLabel()
+	modeIndicator := NewTextView(TextViewSecondary).SetText(modeLabel)
+
+	app.statusBar.AddItem(status, 0, 1, false)
+	// Writes will be refused for the whole session, so it's always shown
+	if app.readOnlyServer {
+		app.statusBar.
+			AddItem(NewTextView(TextViewError).SetText(readOnlyLabel), len(readOnlyLabel), 0, false).
+			AddItem(nil, 2, 0, false)
+	}
+	// Kept in view, as it changes which statements are allowed
+	if app.db.IsSafeMode() {
+		app.statusBar.
+			AddItem(NewTextView(TextViewSecondary).SetText(safeModeLabel), len(safeModeLabel), 0, false).
+			AddItem(nil, 2, 0, false)
+	}
+	// So it's clear whether values are hidden before sharing the screen
+	if app.privacyMode {
+		app.statusBar.
+			AddItem(NewTextView(TextViewSecondary).SetText(privacyModeLabel), len(privacyModeLabel), 0, false).
+			AddItem(nil, 2, 0, false)
+	}
+	app.statusBar.AddItem(modeIndicator, len(modeLabel), 0, false)
+	for _, button := range buttons {
+		app.statusBar.
+			AddItem(nil, 2, 0, false).
+			AddItem(button, len(button.GetLabel()), 0, false)
+	}
+}
+
+// Start a transaction, so following statements can be rolled back
+func (app *App) startSandbox() {
+	app.runTransactionControl("BEGIN", app.db.BeginTransaction)
+}
+
+func (app *App) commitSandbox() {
+	app.runTransactionControl("COMMIT", app.db.CommitTransaction)
+}
+
+func (app *App) rollbackSandbox() {
+	app.runTransactionControl("ROLLBACK", app.db.RollbackTransaction)
+}
+
+// Show the outcome of starting or ending a transaction as a result block
+func (app *App) runTransactionControl(statement string, run func() error) {
+	block := &resultBlock{query: statement}
+	block.err = run()
+
+	app.addResultBlock(block)
+	app.updateStatusBar()
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+// Check the query being edited for syntax from another flavor, as it's typed
+func (app *App) lintQuery() {
+	app.lintWarnings = app.db.Lint(app.queryTextArea.GetText())
+	app.updateStatusBar()
+}
+
+// Unqualified table names depend on the Postgres search_path, so it's kept in view
+func formatAutocommitStatus(searchPath string) string {
+	if searchPath == "" {
+		return "Autocommit"
+	}
+
+	return fmt.Sprint("Autocommit | search_path: ", searchPath)
+}
+
+func formatLintWarnings(warnings []string) string {
+	if len(warnings) == 1 {
+		return fmt.Sprint("⚠ ", warnings[0])
+	}
+
+	return fmt.Sprintf("⚠ %s (+%d more)", warnings[0], len(warnings)-1)
+}
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Keys available while a result table is in navigation mode
+const (
+	copyCellKey       = 'y'
+	toggleBookmarkKey = 'm'
+	openBookmarksKey  = '\''
+	editRowKey        = 'e'
+	selectRowKey      = ' '
+	sortColumnKey     = 's'
+	searchResultKey   = '/'
+	nextMatchKey      = 'n'
+	previousMatchKey  = 'N'
+)
+
+// Focus the most recent result table, so a cell cursor can be moved with the arrow keys
+func (app *App) focusLatestResultTable() {
+	for idx := len(app.resultBlocks) - 1; idx >= 0; idx-- {
+		if block := app.resultBlocks[idx]; block.isTableShown() {
+			app.focusResultTable(block.table)
+			return
+		}
+	}
+}
+
+func (app *App) focusResultTable(table *tview.Table) {
+	// Only the header row, nothing to navigate
+	firstRow := app.getFirstResultRow()
+	if table.GetRowCount() <= firstRow {
+		return
+	}
+
+	table.SetSelectable(true, true)
+
+	// Start on the first row of data, rather than the header
+	if row, _ := table.GetSelection(); row < firstRow {
+		table.Select(firstRow, 0)
+	}
+
+	app.tviewApp.SetFocus(table)
+}
+
+// Leave navigation mode and return to editing the query
+func (app *App) blurResultTable(table *tview.Table) {
+	table.SetSelectable(false, false)
+	app.setColumnHint("")
+	app.tviewApp.SetFocus(app.queryTextArea)
+}
+
+func (app *App) registerResultTableNavigation(table *tview.Table, result *db.QueryResult) {
+	table.
+		SetSelectedFunc(func(row, column int) {
+			// Enter on a header sorts by its column
+			if row == 0 {
+				app.toggleResultSort(app.getResultBlockForTable(table), column)
+				return
+			}
+
+			app.openCellInspector(table, row, column)
+		}).
+		SetSelectionChangedFunc(func(row, column int) {
+			// Moving onto the last row read so far reads the next page
+			if block := app.getResultBlockForTable(table); block != nil && block.result.HasMoreRows() && row == table.GetRowCount()-1 {
+				app.fetchResultRows(block)
+			}
+
+			app.resultContainer.ScrollItemIntoView(table, app.getTableRowLine(row))
+
+			// Selecting a header shows what's known about its column
+			if row == 0 {
+				app.setColumnHint(getColumnHint(result, column))
+			} else {
+				app.setColumnHint("")
+			}
+		})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			{
+				// Clearing the selection first, so it takes a second Escape to leave
+				if app.clearRowSelection(app.getResultBlockForTable(table)) {
+					return nil
+				}
+
+				app.blurResultTable(table)
+				return nil
+			}
+		case tcell.KeyTab:
+			{
+				app.focusAdjacentResultTable(table, 1)
+				return nil
+			}
+		case tcell.KeyBacktab:
+			{
+				app.focusAdjacentResultTable(table, -1)
+				return nil
+			}
+		case tcell.KeyUp, tcell.KeyDown:
+			{
+				direction := 1
+				if event.Key() == tcell.KeyUp {
+					direction = -1
+				}
+
+				if event.Modifiers()&tcell.ModShift == 0 {
+					if app.skipColumnTypeRow(table, direction) {
+						return nil
+					}
+
+					return event
+				}
+
+				if block := app.getResultBlockForTable(table); block != nil {
+					app.extendRowSelection(block, direction)
+				}
+				return nil
+			}
+		case tcell.KeyRune:
+			{
+				switch event.Rune() {
+				case copyCellKey:
+					{
+						// Selected rows are copied rather than the cell
+						if block := app.getResultBlockForTable(table); block != nil && len(block.selectedRows) > 0 {
+							app.copyToClipboard(app.getSelectedResult(block).ToCSVWithOptions(app.csvOptions))
+							return nil
+						}
+
+						row, column := table.GetSelection()
+
+						app.copyToClipboard([]byte(getCellValue(table, row, column)))
+
+						return nil
+					}
+				case sortColumnKey:
+					{
+						_, column := table.GetSelection()
+						app.toggleResultSort(app.getResultBlockForTable(table), column)
+						return nil
+					}
+				case searchResultKey:
+					{
+						if block := app.getResultBlockForTable(table); block != nil {
+							app.openResultSearch(block)
+						}
+						return nil
+					}
+				case nextMatchKey:
+					{
+						if block := app.getResultBlockForTable(table); block != nil {
+							app.findResultMatch(block, 1, false)
+						}
+						return nil
+					}
+				case previousMatchKey:
+					{
+						if block := app.getResultBlockForTable(table); block != nil {
+							app.findResultMatch(block, -1, false)
+						}
+						return nil
+					}
+				case selectRowKey:
+					{
+						row, _ := table.GetSelection()
+						if block := app.getResultBlockForTable(table); block != nil {
+							app.toggleRowSelection(block, row)
+						}
+						return nil
+					}
+				case toggleBookmarkKey:
+					{
+						app.toggleBookmark(app.getResultBlockForTable(table))
+						return nil
+					}
+				case openBookmarksKey:
+					{
+						app.openBookmarks(table)
+						return nil
+					}
+				case editRowKey:
+					{
+						row, _ := table.GetSelection()
+						app.openRowEditor(app.getResultBlockForTable(table), row)
+						return nil
+					}
+				}
+			}
+		}
+
+		return event
+	})
+}
+
+// Whether the block has a table which is drawn, rather than summarized or shown as records
+func (block *resultBlock) isTableShown() bool {
+	return block.table != nil && block.resultItem == block.table
+}
+
+func (app *App) getResultBlockForTable(table *tview.Table) *resultBlock {
+	for _, block := range app.resultBlocks {
+		if block.table == table {
+			return block
+		}
+	}
+
+	return nil
+}
+
+// Get the full value of a result cell, as opposed to the displayed text, with NULL as NULL
+func getCellValue(table *tview.Table, row, column int) string {
+	cell := table.GetCell(row, column)
+
+	if value, ok := cell.GetReference().(*db.NullString); ok {
+		return value.ToString()
+	}
+
+	return cell.Text
+}
+
+// Whether a result cell is NULL, rather than text which may read NULL
+func isNullCell(table *tview.Table, row, column int) bool {
+	value, ok := table.GetCell(row, column).GetReference().(*db.NullString)
+	return ok && !value.Valid
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Escape sequences understood by xterm compatible terminals and tmux
+const (
+	// OSC 2, sets the window title
+	setTitleSequence = "\x1b]2;%s\x07"
+	// Save and restore the title, so the previous one comes back when exiting
+	pushTitleSequence = "\x1b[22;2t"
+	popTitleSequence  = "\x1b[23;2t"
+)
+
+// Shown before the connection in the title while a query runs
+const busyTitleIndicator = "⏳"
+
+// Writes the window title, making sessions distinguishable in the window manager and tmux
+type terminalTitle struct {
+	writer io.Writer
+}
+
+func (title *terminalTitle) push() {
+	io.WriteString(title.writer, pushTitleSequence)
+}
+
+func (title *terminalTitle) pop() {
+	io.WriteString(title.writer, popTitleSequence)
+}
+
+func (title *terminalTitle) set(connectionLabel string, busy bool) {
+	fmt.Fprintf(title.writer, setTitleSequence, formatTerminalTitle(connectionLabel, busy))
+}
+
+func formatTerminalTitle(connectionLabel string, busy bool) string {
+	// Control characters would end the escape sequence early
+	connectionLabel = strings.Map(func(char rune) rune {
+		if unicode.IsControl(char) {
+			return -1
+		}
+
+		return char
+	}, connectionLabel)
+
+	if busy {
+		return fmt.Sprint(busyTitleIndicator, " ", connectionLabel)
+	}
+
+	return connectionLabel
+}
+
+// Update the title to show the connection and whether a query is running, when enabled
+func (app *App) updateTerminalTitle(busy bool) {
+	if app.terminalTitle == nil {
+		return
+	}
+
+	connectionInfo := app.db.GetConnectionInfo()
+	app.terminalTitle.set(connectionInfo.Label(), busy)
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerminalTitleSet(t *testing.T) {
+	var tests = []struct {
+		Name            string
+		ConnectionLabel string
+		Busy            bool
+		ExpectedOutput  string
+	}{
+		{
+			Name:            "Idle",
+			ConnectionLabel: "root@localhost/example",
+			ExpectedOutput:  "\x1b]2;root@localhost/example\x07",
+		},
+		{
+			Name:            "Busy",
+			ConnectionLabel: "root@localhost/example",
+			Busy:            true,
+			ExpectedOutput:  "\x1b]2;⏳ root@localhost/example\x07",
+		},
+		{
+			Name:            "Control Characters",
+			ConnectionLabel: "root@localhost/ex\x07\x1b]2;ample",
+			ExpectedOutput:  "\x1b]2;root@localhost/ex]2;ample\x07",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var output strings.Builder
+			title := terminalTitle{writer: &output}
+			title.set(test.ConnectionLabel, test.Busy)
+
+			assert.Equal(test.ExpectedOutput, output.String())
+		})
+	}
+}
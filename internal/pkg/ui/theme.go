@@ -11,6 +11,11 @@ const (
 	ColorSecondary  = tcell.ColorLightGray
 	ColorBackground = tcell.Color235
 	ColorError      = tcell.ColorRed
+	// Background of the rows selected in a result table
+	ColorSelectedRow = tcell.Color24
+	// Background of cells, and color of queries, matching a search of the results
+	ColorSearchMatch   = tcell.Color58
+	ColorSearchCurrent = tcell.Color136
 )
 
 type TextViewVariant int
@@ -119,3 +124,17 @@ var buttonActiveStyle tcell.Style = tcell.
 	Background(tcell.ColorNone).
 	Foreground(tcell.ColorBlue).
 	Underline(true)
+
+// Center content on top of whatever is currently displayed
+func NewModal(content tview.Primitive) *tview.Flex {
+	column := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(content, 0, 3, true).
+		AddItem(nil, 0, 1, false)
+
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(column, 0, 3, true).
+		AddItem(nil, 0, 1, false)
+}
@@ -1,63 +1,168 @@
 package ui
 
 import (
-	"errors"
 	"fmt"
 	"math"
+	"os"
 	"regexp"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/azvaliev/sql/internal/pkg/config"
 	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/history"
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/azvaliev/sql/internal/pkg/migration"
+	"github.com/azvaliev/sql/internal/pkg/share"
 	"github.com/azvaliev/sql/internal/pkg/ui/components"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/rivo/uniseg"
-	"golang.design/x/clipboard"
 )
 
 type App struct {
-	tviewApp        *tview.Application
+	tviewApp *tview.Application
+	pages    *tview.Pages
+	// Holds the schema browser, when shown, to the left of the main area
+	mainLayout *tview.Flex
+	// Results, status bar and query text area
+	mainArea        *tview.Flex
 	resultContainer *components.ScrollBox
-	queryTextArea   *tview.TextArea
-	db              *db.DBClient
-	queryHistory    *QueryHistory
+	// Session state and controls, between the results and the query text area
+	statusBar *tview.Flex
+	// Result blocks in the order they were added to the result container
+	resultBlocks  []*resultBlock
+	queryTextArea *tview.TextArea
+	// Word movement and kill ring for the query text area, used in emacs mode
+	readline *readline
+	// Normal, insert and visual modes for the query text area, used in vim mode
+	vim *vimEditor
+	// Keys for actions, and how the query text area is edited
+	keymap       *keymap.Keymap
+	db           DB
+	queryHistory *QueryHistory
+	// Nil when history isn't saved between sessions
+	historyFile *history.File
+	// Syntax in the query being edited which isn't valid for the connected flavor
+	lintWarnings []string
+	// Nil when setting the terminal title is disabled
+	terminalTitle *terminalTitle
+	// Template for the title of the query text area, nil to use the default
+	prompt *template.Template
+	// Uppercase keywords in the query text area as they're typed
+	uppercaseKeywords bool
+	// Complete the word before the cursor on Tab
+	autocompleteEnabled bool
+	// Type information for the result column header selected or under the mouse, shown in the status bar
+	columnHint string
+	// Nil when sharing results isn't configured
+	shareUploader *share.Uploader
+	// Statement being re-run by \watch, nil when not watching
+	watch *watch
+	// Limits on results kept in memory
+	scrollback config.ScrollbackConfig
+	// When and how results too wide for the terminal are shown as records
+	wideResults config.WideResultsConfig
+	// How much room each result takes
+	display config.DisplayConfig
+	// Nil when schema changes aren't saved as migrations
+	migrationWriter *migration.Writer
+	// Batches redraws after text changes, nil until running
+	redraw *redrawCoalescer
+	// When several statements are sent at once, whether to stop at the first which fails
+	stopOnError bool
+	// Connected to a server which refuses writes, such as a replica
+	readOnlyServer bool
+	// How Copy as CSV writes results
+	csvOptions db.CSVOptions
+	// Ask before running statements estimated to read more rows than this, 0 to never ask
+	explainGuardRows int64
+	// Tree of databases, tables, columns and indexes, nil when hidden
+	schemaBrowser *tview.TreeView
+	// Result values are masked, showing only their type and length
+	privacyMode bool
+	// Matches of a search of the results, nil outside of search mode
+	resultsSearch *resultsSearch
 }
 
-func MustGetScreenDimensions() (width, height int) {
-	s, err := tcell.NewScreen()
-	if err != nil {
-		panic(fmt.Sprintf("Could not determine screen height for rendering\n%+v", err))
-	}
-
-	width, height = s.Size()
-	return width, height
-}
+const mainPageName = "main"
 
 // Setup initial layout and application structure
-func Init(db *db.DBClient) *App {
+func Init(db DB, cfg config.Config) *App {
 	tviewApp := tview.NewApplication().EnableMouse(true)
 
 	queryTextArea := NewTextArea()
-	queryTextArea.SetTitle("Query").SetBorder(true)
+	queryTextArea.SetTitle(defaultPrompt).SetBorder(true)
 
-	resultContainer := NewScrollBox()
-	_, screenHeight := MustGetScreenDimensions()
+	resultContainer := NewScrollBox().
+		SetScrollFactors(cfg.Scroll.Rows, cfg.Scroll.Columns).
+		SetAcceleration(cfg.Scroll.Accelerate)
+	statusBar := NewFlex()
 
-	box := NewFlex().
-		SetFullScreen(true).
+	// Results take whatever the status bar and query text area leave, so the screen doesn't need measuring up front
+	mainArea := NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(resultContainer, screenHeight-5, 4, false).
+		AddItem(resultContainer, 0, 1, false).
+		AddItem(statusBar, 1, 0, false).
 		AddItem(queryTextArea, 5, 1, true)
+	mainLayout := NewFlex().
+		SetFullScreen(true).
+		AddItem(mainArea, 0, 1, true)
+
+	// Modals such as the cell inspector are layered on top of the main page
+	pages := tview.NewPages().
+		AddPage(mainPageName, mainLayout, true, true)
 
-	tviewApp.SetRoot(box, true)
+	tviewApp.SetRoot(pages, true)
 
 	app := App{
 		tviewApp:        tviewApp,
+		pages:           pages,
+		mainLayout:      mainLayout,
+		mainArea:        mainArea,
 		resultContainer: resultContainer,
+		statusBar:       statusBar,
 		queryTextArea:   queryTextArea,
+		readline:        newReadline(queryTextArea),
 		db:              db,
-		queryHistory:    NewQueryHistory(100),
+		queryHistory:    NewQueryHistory(cfg.History.Size),
+	}
+	app.vim = newVimEditor(queryTextArea, app.updateStatusBar)
+	resultContainer.
+		SetScrollDownFunc(app.fetchVisibleResultRows).
+		SetSlowDrawFunc(slowDrawThreshold, app.handleSlowDraw)
+	app.uppercaseKeywords = cfg.UppercaseKeywords
+	app.autocompleteEnabled = cfg.Autocomplete
+	app.stopOnError = cfg.StopOnError
+	app.csvOptions = cfg.CSVOptions()
+	app.explainGuardRows = cfg.ExplainGuardRows
+	app.scrollback = cfg.Scrollback
+	app.wideResults = cfg.WideResults
+	app.display = cfg.Display
+	app.migrationWriter = cfg.CreateMigrationWriter()
+	if cfg.Share.Service != "" {
+		app.shareUploader = share.NewUploader(cfg.Share.Service, cfg.Share.Endpoint, cfg.Share.Token)
+	}
+	if cfg.TerminalTitle {
+		app.terminalTitle = &terminalTitle{writer: os.Stdout}
+	}
+	// Config is validated before starting, an invalid prompt falls back to the default
+	app.prompt, _ = cfg.ParsePrompt()
+	if configuredKeymap, err := cfg.BuildKeymap(); err == nil {
+		app.keymap = configuredKeymap
+	} else {
+		app.keymap, _ = keymap.Build(keymap.Emacs, nil)
+	}
+	// Skipped when the server can't be reached, such as when started offline
+	if serverInfo, err := db.GetServerInfo(); err == nil {
+		app.readOnlyServer = serverInfo.ReadOnly
+		app.showBanner(serverInfo)
 	}
+	app.loadHistoryFile(cfg)
+	app.updateStatusBar()
 
 	return &app
 }
@@ -65,6 +170,17 @@ func Init(db *db.DBClient) *App {
 // Register listeners and run live app
 func (app *App) Run() (err error) {
 	app.queryTextArea.SetInputCapture(app.handleInputCapture)
+	app.queryTextArea.SetChangedFunc(app.lintQuery)
+
+	if app.terminalTitle != nil {
+		app.terminalTitle.push()
+		defer app.terminalTitle.pop()
+	}
+	app.updateTerminalTitle(false)
+
+	app.redraw = newRedrawCoalescer()
+	go app.redraw.run(app.tviewApp)
+	defer app.redraw.close()
 
 	return app.tviewApp.Run()
 }
@@ -92,51 +208,295 @@ func getTextLineCount(textView *tview.TextView, maxWidth int) int {
 	return int(implicitLines) + newlineCount
 }
 
+// Everything rendered for a single committed query
+type resultBlock struct {
+	query  string
+	result *db.QueryResult
+	// Set instead of result for statements which don't return rows
+	execResult    *db.ExecResult
+	err           error
+	queryView     *tview.Grid
+	queryTextView *tview.TextView
+	// Only set when the query returned columns to display
+	table      *tview.Table
+	bookmarked bool
+	// When the query was run and how long it took, zero for blocks which weren't a query such as sandbox controls
+	ranAt    time.Time
+	duration time.Duration
+	// Database the query ran against, as switching database changes it for every query after
+	database string
+	// Table, error or output shown below the query, nil once collapsed
+	resultItem tview.Primitive
+	// How long the query took and how many rows it returned or affected, below the result
+	// Nil for blocks which weren't a query, and once collapsed
+	statusLine *tview.TextView
+	// Released from the scrollback, leaving only the query line
+	collapsed bool
+	// Query left out of the query line, as it succeeded and echoing queries is turned off
+	hideQuery bool
+	// Set when reading more rows of the result failed
+	fetchErr error
+	// Whether the query line has a button to read more rows
+	canLoadMoreRows bool
+	// File the rows not yet read went to when exported, rather than to the table
+	exportedTo string
+	// Table replaced with a summary as it was too slow to draw
+	summarized bool
+	// How long the table took to draw, once summarized
+	drawDuration time.Duration
+	// Table drawn however long it takes, as asked for after being summarized
+	showFullView bool
+	// Rows of the table which copying and exporting are limited to, numbered as in the table
+	selectedRows map[int]bool
+	// Last searched for in the table, repeated by the next and previous match keys
+	searchTerm string
+	// Sent ending in \G, so shown as records whatever its width
+	isVertical bool
+	// Shown in place of the table while the result is shown as records, nil otherwise
+	recordView *tview.TextView
+	// Page of records shown, from 0
+	recordPage int
+	// Column the rows read so far were sorted by, nil to keep them in the order they were read
+	sort *resultSort
+	// Rows in the order they were read, kept while sorted to go back to
+	unsortedRows []map[string]*db.NullString
+}
+
+// Run a query typed into the query text area, once any checks which ask before running it are confirmed
+func (app *App) submitQuery(query string) {
+	app.confirmReadOnlyWrite(query, func() {
+		app.confirmLargeScan(query, func() {
+			app.commitQuery(query)
+		})
+	})
+}
+
+// Put a query which wasn't run back in the query text area, to be changed or sent elsewhere
+func (app *App) restoreQuery(query string) {
+	app.queryTextArea.SetText(query, true)
+}
+
 func (app *App) commitQuery(query string) {
-	defer app.queryHistory.AddEntry(query)
-	results, err := app.db.Query(query)
+	defer app.addHistoryEntry(query)
+
+	statements := app.db.SplitStatements(query)
+	if len(statements) <= 1 {
+		block := app.runQuery(query)
+		app.offerMigrations(block)
+		return
+	}
+
+	app.runStatements(statements)
+}
+
+// Run several statements sent at once one after another, each as its own result block
+// Unless configured otherwise, the rest aren't run once one fails
+func (app *App) runStatements(statements []string) {
+	var blocks []*resultBlock
+	for idx, statement := range statements {
+		var block *resultBlock
+		if isUICommand(statement) {
+			// They open forms or run in the background, so can't be part of a sequence
+			block = &resultBlock{
+				query: statement,
+				err:   fmt.Errorf("%s can't be run along with other statements, send it on its own", strings.Fields(statement)[0]),
+			}
+			app.addResultBlock(block)
+		} else {
+			block = app.runQuery(statement)
+			blocks = append(blocks, block)
+		}
+
+		if block.err != nil && app.stopOnError && idx < len(statements)-1 {
+			skippedStatements := statements[idx+1:]
+			app.addResultBlock(&resultBlock{
+				query: strings.Join(skippedStatements, "\n"),
+				err: fmt.Errorf(
+					"Stopped as statement %d of %d failed, the remaining %d weren't run",
+					idx+1,
+					len(statements),
+					len(skippedStatements),
+				),
+			})
+			break
+		}
+	}
+
+	app.offerMigrations(blocks...)
+}
+
+// Run a query and render it as a new result block
+func (app *App) runQuery(query string) *resultBlock {
+	connectionInfo := app.db.GetConnectionInfo()
+	query, isVertical := getVerticalStatement(connectionInfo.Flavor, query)
+	block := &resultBlock{query: query, ranAt: time.Now(), database: connectionInfo.Database, isVertical: isVertical}
+
+	app.updateTerminalTitle(true)
+	if app.db.IsExecStatement(query) {
+		block.execResult, block.err = app.db.Exec(query)
+	} else {
+		block.result, block.err = app.db.Query(query)
+	}
+	block.duration = time.Since(block.ranAt)
+	app.updateTerminalTitle(false)
+
+	// Running it closed any result still being read
+	app.refreshFinishedResults()
+
+	app.addResultBlock(block)
+
+	// Query may have started or ended a transaction
+	app.updateStatusBar()
+
+	return block
+}
+
+// Render the query and result of a block at the end of the result container
+func (app *App) addResultBlock(block *resultBlock) {
 	var resultItem tview.Primitive
 	var height int
 
 	var queryAction AvailableActions
-	if err != nil {
-		resultItem, height = app.createErrorView(err)
+	if block.err != nil {
+		resultItem, height = app.createErrorView(block.err)
 		queryAction = QueryNoResultsErrorAction
-	} else if results != nil && len(results.Columns) > 0 {
-		resultItem, height = app.createResultView(results)
+	} else if block.result != nil && len(block.result.Columns) > 0 {
+		block.table, height = app.createResultView(block.result)
+
+		resultItem = block.table
 		queryAction = QueryWithResultsActions
+
+		// Scrolling across several screens to read a single row is slower than reading it as a record
+		if block.isVertical || app.isWideResult(block.result) {
+			resultItem, height = app.createRecordView(block)
+		}
 	} else {
-		resultItem, height = app.createNoResultView()
+		resultItem, height = app.createNoResultView(block.getNoResultsOutput())
 		queryAction = QueryNoResultsErrorAction
 	}
 
+	// Failed queries are still echoed, so it's clear which one the error is for
+	block.hideQuery = !app.display.EchoQueries && block.err == nil
+
 	queryViewWithActions, queryViewWithActionsHeight := app.createQueryViewWithActions(
-		query,
+		block,
 		queryAction,
-		results,
-		err,
 	)
+	block.queryView = queryViewWithActions
+	block.resultItem = resultItem
+	if !block.ranAt.IsZero() {
+		block.statusLine = NewTextView(TextViewSecondary).SetText(formatStatusLine(block))
+	}
+	app.resultBlocks = append(app.resultBlocks, block)
 
-	app.resultContainer.AddItem(
+	app.resultContainer.AddBlock(
 		queryViewWithActions,
 		queryViewWithActionsHeight,
 	)
 	app.resultContainer.AddItem(
 		resultItem,
-		height,
+		block.getResultItemHeight(height),
 	)
+	if block.statusLine != nil {
+		app.resultContainer.AddItem(block.statusLine, app.getStatusLineHeight())
+	}
+
+	app.enforceScrollback()
+}
+
+// Height of the result item for a view of the given height, which the status line takes a line of spacing from
+func (block *resultBlock) getResultItemHeight(height int) int {
+	if block.statusLine == nil {
+		return height
+	}
+
+	return max(height-1, 1)
 }
 
-func mustInitClipboard() {
-	err := clipboard.Init()
+// Ex: 42 rows in 0.031s on shop, or 3 rows affected in 0.005s on shop
+func formatStatusLine(block *resultBlock) string {
+	statusLine := formatStatusOutcome(block)
+	if block.database != "" {
+		statusLine = fmt.Sprint(statusLine, " on ", block.database)
+	}
+
+	if len(block.selectedRows) > 0 {
+		statusLine = fmt.Sprintf("%s, %d selected", statusLine, len(block.selectedRows))
+	}
+
+	return statusLine
+}
+
+// How many rows the query returned or affected and how long it took
+func formatStatusOutcome(block *resultBlock) string {
+	switch {
+	case block.err != nil:
+		{
+			return fmt.Sprintf("Failed after %.3fs", block.duration.Seconds())
+		}
+	case block.execResult != nil:
+		{
+			if block.execResult.RowsAffected < 0 {
+				return fmt.Sprintf("Done in %.3fs", block.execResult.Duration.Seconds())
+			}
+
+			return fmt.Sprintf(
+				"%s affected in %.3fs",
+				formatRowCount(int(block.execResult.RowsAffected)),
+				block.execResult.Duration.Seconds(),
+			)
+		}
+	case block.result != nil && block.result.Status != "":
+		{
+			return fmt.Sprintf("Done in %.3fs", block.result.Duration.Seconds())
+		}
+	case block.result != nil:
+		{
+			return fmt.Sprintf("%s in %.3fs", formatRowCount(len(block.result.Rows)), block.result.Duration.Seconds())
+		}
+	default:
+		{
+			return fmt.Sprintf("Done in %.3fs", block.duration.Seconds())
+		}
+	}
+}
+
+// Marks results reused from the result cache rather than fetched again
+const cachedIndicator = "(cached)"
+
+func formatQueryText(block *resultBlock) string {
+	queryText := ""
+	if !block.hideQuery {
+		queryText = fmt.Sprint("> ", block.query)
+	}
+
+	if block.result != nil && block.result.Cached {
+		queryText = fmt.Sprint(queryText, " ", cachedIndicator)
+	}
+	if block.result != nil && block.result.SchemaCachedAt != nil {
+		queryText = fmt.Sprintf(
+			"%s (stale, database unreachable, schema as of %s)",
+			queryText,
+			block.result.SchemaCachedAt.Local().Format("2006-01-02 15:04"),
+		)
+	}
+
+	if block.result != nil {
+		queryText = fmt.Sprint(queryText, formatResultStreamState(block))
+	}
+
+	if block.collapsed {
+		queryText = fmt.Sprint(queryText, " ", collapsedIndicator)
+	}
+
+	// Without the query, the indicators would start with the space separating them from it
+	queryText = strings.TrimLeft(queryText, " ")
 
-	// TODO: handle this gracefully?
-	if err != nil {
-		panic(errors.Join(
-			errors.New("no access to clipboard"),
-			err,
-		))
+	if block.bookmarked {
+		return fmt.Sprint(bookmarkIndicator, " ", queryText)
 	}
+
+	return queryText
 }
 
 type AvailableActions int
@@ -147,31 +507,49 @@ const (
 )
 
 func (app *App) createQueryViewWithActions(
-	query string,
+	block *resultBlock,
 	queryAction AvailableActions,
-	queryResult *db.QueryResult,
-	queryError error,
 ) (queryView *tview.Grid, fixedHeight int) {
 	queryView = NewGrid().
 		SetGap(0, 2)
 
 	_, _, containerWidth, _ := app.resultContainer.GetInnerRect()
-	queryTextItemWidth := containerWidth / 2
+
+	// Created first, as the query text takes whatever width they leave
+	actionButtons := app.createQueryActionButtons(block, block.getNoResultsOutput(), queryAction)
+	if queryAction == QueryWithResultsActions {
+		actionButtons = append(actionButtons, app.createExportButton(block))
+	}
+	actionButtons = append(actionButtons, app.createCopyBlockButton(block))
+	actionButtons = append(actionButtons, app.createResultActionButtons(block.result)...)
+	block.canLoadMoreRows = block.result != nil && block.result.HasMoreRows()
+	if block.canLoadMoreRows {
+		actionButtons = append(actionButtons, app.createLoadMoreRowsButton(block))
+	}
+	if block.summarized {
+		actionButtons = append(actionButtons, app.createOpenFullViewButton(block))
+	}
+	actionButtons = append(actionButtons, app.createRecordViewButtons(block)...)
+
+	// Half the width goes to the query text, less when the buttons need more room, down to a quarter
+	buttonsWidth := 0
+	for _, button := range actionButtons {
+		buttonsWidth += uniseg.StringWidth(button.GetLabel()) + 2
+	}
+	queryTextItemWidth := max(min(containerWidth/2, containerWidth-buttonsWidth-2), containerWidth/4)
 	gridHeight := 1
 
 	// Create query text item
 	{
-		formattedQueryText := fmt.Sprint("> ", query)
-
 		queryTextItem := NewTextView(TextViewSecondary).
-			SetText(formattedQueryText).
-			SetChangedFunc(func() {
-				app.tviewApp.Draw()
-			}).
+			SetText(formatQueryText(block)).
+			SetChangedFunc(app.requestRedraw).
 			SetWrap(true).
 			SetWordWrap(true)
 
-		gridHeight = getTextLineCount(queryTextItem, queryTextItemWidth)
+		// Still a line for the buttons, even when the query is hidden
+		gridHeight = max(getTextLineCount(queryTextItem, queryTextItemWidth), 1)
+		block.queryTextView = queryTextItem
 
 		queryView.AddItem(
 			queryTextItem,
@@ -190,11 +568,10 @@ func (app *App) createQueryViewWithActions(
 	buttonColumnStartIdx := len(columns)
 
 	// Add all the buttons to the grid
-	actionButtons := createQueryActionButtons(queryResult, queryError, queryAction)
 	for buttonIdx, button := range actionButtons {
 		columnIdx := buttonColumnStartIdx + buttonIdx
 
-		columns = append(columns, len(button.GetLabel()))
+		columns = append(columns, uniseg.StringWidth(button.GetLabel()))
 		queryView.AddItem(
 			button,
 			0,
@@ -213,42 +590,33 @@ func (app *App) createQueryViewWithActions(
 	return queryView, gridHeight
 }
 
-func createQueryActionButtons(queryResult *db.QueryResult, queryError error, queryActions AvailableActions) (buttons []*tview.Button) {
+// Copying a result with rows selected copies only those rows
+func (app *App) createQueryActionButtons(block *resultBlock, noResultsOutput string, queryActions AvailableActions) (buttons []*tview.Button) {
 	switch queryActions {
 	case QueryWithResultsActions:
 		{
 			queryCopyCSVButton := NewButton("Copy as CSV").
 				SetSelectedFunc(func() {
-					mustInitClipboard()
-
-					resultCSV := queryResult.ToCSV()
-					clipboard.Write(clipboard.FmtText, resultCSV)
+					app.copyToClipboard(app.getSelectedResult(block).ToCSVWithOptions(app.csvOptions))
 				})
 
 			queryCopyJSONButton := NewButton("Copy as JSON").
 				SetSelectedFunc(func() {
-					mustInitClipboard()
+					app.copyToClipboard(app.getSelectedResult(block).ToJSON())
+				})
 
-					resultJSON := queryResult.ToJSON()
-					clipboard.Write(clipboard.FmtText, resultJSON)
+			queryCopyMarkdownButton := NewButton("Copy as Markdown").
+				SetSelectedFunc(func() {
+					app.copyToClipboard(app.getSelectedResult(block).ToMarkdown())
 				})
 
-			return []*tview.Button{queryCopyCSVButton, queryCopyJSONButton}
+			return []*tview.Button{queryCopyCSVButton, queryCopyJSONButton, queryCopyMarkdownButton}
 		}
 	case QueryNoResultsErrorAction:
 		{
 			queryCopyResultsButton := NewButton("Copy Output").
 				SetSelectedFunc(func() {
-					mustInitClipboard()
-
-					var result string
-					if queryError != nil {
-						result = queryError.Error()
-					} else {
-						result = NoResultsMessage
-					}
-
-					clipboard.Write(clipboard.FmtText, []byte(result))
+					app.copyToClipboard([]byte(noResultsOutput))
 				})
 
 			return []*tview.Button{queryCopyResultsButton}
@@ -260,46 +628,107 @@ func createQueryActionButtons(queryResult *db.QueryResult, queryError error, que
 	}
 }
 
+// Buttons for the follow-up statements and extra copy formats offered by a result
+func (app *App) createResultActionButtons(queryResult *db.QueryResult) (buttons []*tview.Button) {
+	if queryResult == nil {
+		return buttons
+	}
+
+	for _, action := range queryResult.Actions {
+		action := action
+
+		button := NewButton(action.Label).
+			SetSelectedFunc(func() {
+				app.confirm(fmt.Sprint("Run ", action.Statement), func() {
+					app.commitQuery(action.Statement)
+				})
+			})
+
+		buttons = append(buttons, button)
+	}
+
+	for _, copyFormat := range queryResult.CopyFormats {
+		copyFormat := copyFormat
+
+		button := NewButton(fmt.Sprint("Copy as ", copyFormat.Label)).
+			SetSelectedFunc(func() {
+				app.copyToClipboard(copyFormat.Content)
+			})
+
+		buttons = append(buttons, button)
+	}
+
+	if shareButton := app.createShareButton(queryResult); shareButton != nil {
+		buttons = append(buttons, shareButton)
+	}
+
+	return buttons
+}
+
 func (app *App) createErrorView(dbErr error) (view *tview.TextView, lines int) {
 	errorTextItem := NewTextView(TextViewError).
 		SetText(fmt.Sprint(dbErr, "\n")).
-		SetChangedFunc(func() {
-			app.tviewApp.Draw()
-		}).
+		SetChangedFunc(app.requestRedraw).
 		SetWrap(true)
 
 	_, _, containerWidth, _ := app.resultContainer.GetInnerRect()
 	textLines := getTextLineCount(errorTextItem, containerWidth)
-	linesWithSpacing := textLines + 2
+	linesWithSpacing := textLines + app.getSpacingLines()
 
 	return errorTextItem, linesWithSpacing
 }
 
 const NoResultsMessage string = "Success: 0 results returned\n"
 
-func (app *App) createNoResultView() (view *tview.TextView, lines int) {
+// Text shown for a block without a result table, such as an error or what a statement did
+func (block *resultBlock) getNoResultsOutput() string {
+	if block.err != nil {
+		return block.err.Error()
+	}
+
+	if block.execResult != nil {
+		return fmt.Sprint(block.execResult.Summary(), "\n")
+	}
+
+	if block.result != nil && block.result.Status != "" {
+		return fmt.Sprint(block.result.Summary(), "\n")
+	}
+
+	return NoResultsMessage
+}
+
+func (app *App) createNoResultView(output string) (view *tview.TextView, lines int) {
 	noResultsTextItem := NewTextView(TextViewPrimary).
-		SetText(NoResultsMessage).
-		SetChangedFunc(func() {
-			app.tviewApp.Draw()
-		})
+		SetText(output).
+		SetChangedFunc(app.requestRedraw)
 
 	_, _, containerWidth, _ := app.resultContainer.GetInnerRect()
 	textLines := getTextLineCount(noResultsTextItem, containerWidth)
-	linesWithSpacing := textLines + 2
+	linesWithSpacing := textLines + app.getSpacingLines()
 
 	return noResultsTextItem, linesWithSpacing
 }
 
-func (app *App) createResultCell(value string) *tview.TableCell {
+// NULL is shown dim and italic, so it can't be mistaken for text reading NULL
+func (app *App) createResultCell(table *tview.Table, value *db.NullString, databaseType string) *tview.TableCell {
 	cell := tview.
-		NewTableCell(value).
-		SetAttributes(tcell.AttrDim)
+		NewTableCell(app.getResultValueText(value, databaseType)).
+		SetAttributes(tcell.AttrDim).
+		SetReference(value)
+	if !value.Valid {
+		cell.
+			SetTextColor(ColorSecondary).
+			SetAttributes(tcell.AttrDim | tcell.AttrItalic)
+	}
 
 	cell.
 		SetClickedFunc(func() bool {
-			mustInitClipboard()
-			clipboard.Write(clipboard.FmtText, []byte(value))
+			// In navigation mode, clicking moves the selection instead
+			if rowsSelectable, _ := table.GetSelectable(); rowsSelectable {
+				return false
+			}
+
+			app.copyToClipboard([]byte(value.ToString()))
 
 			// Refocus back on the textarea so that copied content could be used in the next query
 			app.tviewApp.SetFocus(app.queryTextArea)
@@ -312,42 +741,112 @@ func (app *App) createResultCell(value string) *tview.TableCell {
 
 func (app *App) createResultView(result *db.QueryResult) (view *tview.Table, lines int) {
 	resultTable := NewTable()
+	if app.display.CompactTables {
+		resultTable.SetBorders(false)
+	}
+	app.registerResultTableNavigation(resultTable, result)
+	app.registerColumnHints(resultTable, result)
 
-	for columnIdx, column := range result.Columns {
+	for columnIdx := range result.Columns {
 		resultTable.SetCell(
 			0,
 			columnIdx,
-			tview.NewTableCell(column).
-				SetAlign(tview.AlignLeft),
+			// Selectable to show the column's type information
+			tview.NewTableCell(formatColumnHeader(result, columnIdx, nil)).
+				SetAlign(tview.AlignLeft).
+				SetClickedFunc(func() bool {
+					app.toggleResultSort(app.getResultBlockForTable(resultTable), columnIdx)
+					return true
+				}),
 		)
 	}
 
-	for rowIdx, row := range result.Rows {
-		rowIdx := rowIdx + 1
+	if app.display.ColumnTypes {
+		addColumnTypeRow(resultTable, result)
+	}
+
+	app.addResultRows(resultTable, result, 0)
+
+	return resultTable, app.getResultViewHeight(result)
+}
+
+// Add the result's rows from firstRow on to its table, below the header
+func (app *App) addResultRows(table *tview.Table, result *db.QueryResult, firstRow int) {
+	for rowIdx, row := range result.Rows[firstRow:] {
+		rowIdx := firstRow + rowIdx + app.getFirstResultRow()
 		for columnIdx, column := range result.Columns {
 			cellValue := row[column]
 
-			resultTable.SetCell(
+			table.SetCell(
 				rowIdx,
 				columnIdx,
-				app.createResultCell(cellValue.ToString()),
+				app.createResultCell(table, cellValue, getDatabaseType(result, columnIdx)),
 			)
 		}
 	}
+}
 
-	height := len(result.Rows)*2 + 5
+// The query up to its final semicolon, when nothing but whitespace and comments follow it, so it's ready to send
+// Semicolons within strings, quoted identifiers or comments don't count, so neither does one in an unclosed string
+func getTerminatedQuery(flavor conn.DBFlavor, query string) (terminatedQuery string, isTerminated bool) {
+	tokens := lexer.SignificantTokens(lexer.Tokenize(flavor, query))
+	if len(tokens) == 0 {
+		return "", false
+	}
+
+	lastToken := tokens[len(tokens)-1]
+	if !lastToken.IsOperator(";") && !isVerticalTerminator(tokens) {
+		return "", false
+	}
 
-	return resultTable, int(height)
+	return query[:lastToken.Start+len(lastToken.Text)], true
 }
 
 // Intercept text area key presses for shortcuts or committing querys
 func (app *App) handleInputCapture(event *tcell.EventKey) *tcell.EventKey {
+	if app.resultsSearch != nil {
+		if event = app.handleResultsSearchKey(event); event == nil {
+			return nil
+		}
+	}
+
+	if action, isBound := app.keymap.Lookup(event); isBound {
+		app.runKeymapAction(action)
+		return nil
+	}
+
+	// Shell style editing takes precedence over the text area's own bindings for the same keys
+	if app.keymap.Mode == keymap.Emacs && app.readline.handleKey(event) {
+		return nil
+	}
+	app.readline.resetAction()
+
+	// Outside of insert mode, keys move and edit rather than being typed
+	if app.keymap.Mode == keymap.Vim && app.vim.handleKey(event) {
+		return nil
+	}
+
+	// Before keywords are uppercased, so the word is completed as typed
+	if app.autocompleteEnabled && event.Key() == tcell.KeyTab && event.Modifiers() == tcell.ModNone && app.autocomplete() {
+		return nil
+	}
+
+	if app.uppercaseKeywords && isKeywordBoundaryKey(event) {
+		app.uppercaseKeywordBeforeCursor()
+	}
+
 	isNotShortcut := event.Modifiers() != tcell.ModCtrl && event.Modifiers() != tcell.ModAlt
 
 	if isNotShortcut {
 		query := app.queryTextArea.GetText()
 		queryLen := len(strings.TrimSpace(query))
 
+		// No query starts with it, so it's free to open the help until something is typed
+		if queryLen == 0 && event.Key() == tcell.KeyRune && event.Rune() == helpKey {
+			app.showHelp(app.queryTextArea)
+			return nil
+		}
+
 		// user wasn't paginating before
 		// or they have text typed in we want to be careful before removing
 		shouldNotAllowScrollingQueryHistory := queryLen > 0 && !app.queryHistory.IsPositionSet()
@@ -356,14 +855,12 @@ func (app *App) handleInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		// Handle committing the query, if applicable
 		case tcell.KeyEnter:
 			{
-				var lastChar rune
-				if queryLen > 0 {
-					lastChar = rune(query[len(query)-1])
-				}
-
-				shouldCommitQuery := lastChar == ';' && queryLen > 0
-				if shouldCommitQuery {
-					app.commitQuery(query)
+				terminatedQuery, isTerminated := getTerminatedQuery(app.db.GetConnectionInfo().Flavor, query)
+				if isTerminated {
+					query = terminatedQuery
+					if !app.runUICommand(query) {
+						app.submitQuery(query)
+					}
 					app.queryTextArea.SetText("", false)
 
 					return nil
@@ -400,29 +897,5 @@ func (app *App) handleInputCapture(event *tcell.EventKey) *tcell.EventKey {
 		}
 	}
 
-	// Handle shortcuts
-	switch event.Key() {
-	case tcell.KeyUp:
-		{
-			app.resultContainer.ScrollUp()
-			return nil
-		}
-	case tcell.KeyDown:
-		{
-			app.resultContainer.ScrollDown()
-			return nil
-		}
-	case tcell.KeyLeft:
-		{
-			app.resultContainer.ScrollLeft()
-			return nil
-		}
-	case tcell.KeyRight:
-		{
-			app.resultContainer.ScrollRight()
-			return nil
-		}
-	}
-
 	return event
 }
@@ -0,0 +1,395 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatStatusLine(t *testing.T) {
+	var tests = []struct {
+		Name     string
+		Block    *resultBlock
+		Expected string
+	}{
+		{
+			Name: "Rows returned",
+			Block: &resultBlock{
+				result: &db.QueryResult{
+					Columns:  []string{"id"},
+					Rows:     make([]map[string]*db.NullString, 42),
+					Duration: 31 * time.Millisecond,
+				},
+			},
+			Expected: "42 rows in 0.031s",
+		},
+		{
+			Name: "Database ran against",
+			Block: &resultBlock{
+				result: &db.QueryResult{
+					Columns:  []string{"id"},
+					Rows:     make([]map[string]*db.NullString, 3),
+					Duration: 4 * time.Millisecond,
+				},
+				database:     "shop",
+				selectedRows: map[int]bool{0: true},
+			},
+			Expected: "3 rows in 0.004s on shop, 1 selected",
+		},
+		{
+			Name: "Rows affected",
+			Block: &resultBlock{
+				execResult: &db.ExecResult{Status: "1 row updated", RowsAffected: 1, Duration: 5 * time.Millisecond},
+			},
+			Expected: "1 row affected in 0.005s",
+		},
+		{
+			Name: "Rows affected not reported",
+			Block: &resultBlock{
+				execResult: &db.ExecResult{Status: "Statement executed", RowsAffected: -1, Duration: 2 * time.Millisecond},
+			},
+			Expected: "Done in 0.002s",
+		},
+		{
+			Name: "Status without columns",
+			Block: &resultBlock{
+				result: &db.QueryResult{
+					Rows:     []map[string]*db.NullString{},
+					Status:   "Statement executed",
+					Duration: 3 * time.Millisecond,
+				},
+			},
+			Expected: "Done in 0.003s",
+		},
+		{
+			Name: "Error",
+			Block: &resultBlock{
+				err:      errors.New("Query Failed"),
+				duration: 1200 * time.Millisecond,
+			},
+			Expected: "Failed after 1.200s",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+			assert.Equal(test.Expected, formatStatusLine(test.Block))
+		})
+	}
+}
+
+func newTestResult(column string, values ...string) *db.QueryResult {
+	result := &db.QueryResult{Columns: []string{column}}
+	for _, value := range values {
+		result.Rows = append(result.Rows, map[string]*db.NullString{
+			column: {NullString: sql.NullString{String: value, Valid: true}},
+		})
+	}
+
+	return result
+}
+
+func TestAppSubmitsOnSemicolon(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT name\nFROM users;": newTestResult("name", "ada"),
+	}}
+	driver := startTestApp(t, database)
+
+	// Without a semicolon, enter starts a new line
+	driver.typeText("SELECT name")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.typeText("FROM users")
+	driver.waitFor("the second line to be typed", func() bool {
+		return driver.queryText() == "SELECT name\nFROM users"
+	})
+	assert.Empty(database.getStatements())
+
+	driver.typeText(";")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("ada")
+
+	assert.Equal([]string{"SELECT name\nFROM users;"}, database.getStatements())
+	assert.Equal("", driver.queryText())
+	assert.Contains(driver.screenText(), "1 row in")
+}
+
+func TestGetTerminatedQuery(t *testing.T) {
+	var tests = []struct {
+		Flavor                  conn.DBFlavor
+		Query                   string
+		ExpectedTerminatedQuery string
+		ExpectedIsTerminated    bool
+	}{
+		{conn.PostgreSQL, "SELECT 1;", "SELECT 1;", true},
+		{conn.PostgreSQL, "SELECT 1", "", false},
+		{conn.PostgreSQL, "", "", false},
+		{conn.PostgreSQL, "SELECT 1;  \n\t", "SELECT 1;", true},
+		{conn.PostgreSQL, "SELECT 1; -- the answer", "SELECT 1;", true},
+		{conn.PostgreSQL, "SELECT 1; /* the answer */", "SELECT 1;", true},
+		{conn.MySQL, "SELECT 1; # the answer", "SELECT 1;", true},
+		{conn.PostgreSQL, "SELECT 1 -- ends here;", "", false},
+		{conn.PostgreSQL, "SELECT 1 /* ; */", "", false},
+		{conn.PostgreSQL, "SELECT 'a;", "", false},
+		{conn.PostgreSQL, "SELECT 'a;' ", "", false},
+		{conn.PostgreSQL, "SELECT 'a;';", "SELECT 'a;';", true},
+		{conn.PostgreSQL, "SELECT '🎉';", "SELECT '🎉';", true},
+		{conn.PostgreSQL, "SELECT '🎉'", "", false},
+		{conn.PostgreSQL, "SELECT 1; -- 🎉", "SELECT 1;", true},
+		{conn.PostgreSQL, "SELECT 'ü' AS ö;", "SELECT 'ü' AS ö;", true},
+		{conn.MySQL, "SELECT 1\\G  ", "SELECT 1\\G", true},
+		{conn.MySQL, "SELECT '\\G'", "", false},
+		{conn.MySQL, "SELECT \\ G", "", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Query, func(t *testing.T) {
+			assert := assert.New(t)
+
+			terminatedQuery, isTerminated := getTerminatedQuery(test.Flavor, test.Query)
+			assert.Equal(test.ExpectedTerminatedQuery, terminatedQuery)
+			assert.Equal(test.ExpectedIsTerminated, isTerminated)
+		})
+	}
+}
+
+func TestAppSubmitsWithTrailingComment(t *testing.T) {
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT '🎉' AS party;": newTestResult("party", "🎉"),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT '🎉' AS party; -- celebrate")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("1 row in")
+
+	assert.Equal(t, []string{"SELECT '🎉' AS party;"}, database.getStatements())
+	assert.Equal(t, "", driver.queryText())
+}
+
+func TestAppQueryHistoryNavigation(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{}
+	driver := startTestApp(t, database)
+
+	for _, query := range []string{"SELECT 1;", "SELECT 2;"} {
+		driver.typeText(query)
+		driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	}
+	driver.waitFor("both queries to run", func() bool {
+		return len(database.getStatements()) == 2
+	})
+
+	var seenQueries []string
+	for _, key := range []tcell.Key{tcell.KeyUp, tcell.KeyUp, tcell.KeyDown} {
+		previousQuery := driver.queryText()
+		driver.pressKey(key, 0, tcell.ModNone)
+		driver.waitFor("the query text area to change", func() bool {
+			return driver.queryText() != previousQuery
+		})
+		seenQueries = append(seenQueries, driver.queryText())
+	}
+
+	assert.Equal([]string{"SELECT 2;", "SELECT 1;", "SELECT 2;"}, seenQueries)
+}
+
+func TestAppScrollsResults(t *testing.T) {
+	assert := assert.New(t)
+
+	var values []string
+	for idx := range 40 {
+		values = append(values, fmt.Sprintf("value-%02d", idx+1))
+	}
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT value FROM numbers;": newTestResult("value", values...),
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT value FROM numbers;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	// The end of the latest result is in view, the start is scrolled past
+	driver.waitForScreen("value-40")
+	assert.NotContains(driver.screenText(), "value-01")
+
+	driver.pressKey(tcell.KeyHome, 0, tcell.ModCtrl)
+	driver.waitForScreen("value-01")
+	assert.NotContains(driver.screenText(), "value-40")
+}
+
+func TestAppResultActionButton(t *testing.T) {
+	assert := assert.New(t)
+
+	maintenanceResult := newTestResult("table", "users")
+	maintenanceResult.Actions = []db.ResultAction{{Label: "Analyze", Statement: "ANALYZE users;"}}
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		`\maintenance users;`: maintenanceResult,
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText(`\maintenance users;`)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+
+	// Actions only run once confirmed
+	driver.click("Analyze")
+	driver.waitForScreen("Run ANALYZE users;")
+	assert.Equal([]string{`\maintenance users;`}, database.getStatements())
+
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the action to run", func() bool {
+		return len(database.getStatements()) == 2
+	})
+	assert.Equal("ANALYZE users;", database.getStatements()[1])
+}
+
+func TestAppAutocomplete(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{schema: &db.Schema{
+		Tables: map[string]*db.TableSchema{
+			"users": {Columns: []db.ColumnSchema{{Name: "id"}, {Name: "name"}, {Name: "nickname"}}},
+		},
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SEL")
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.typeText(" * FROM u")
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.typeText(" WHERE ni")
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.waitFor("the table and column to be completed", func() bool {
+		return driver.queryText() == "SELECT * FROM users WHERE nickname"
+	})
+
+	// Several matches are listed to pick from
+	driver.typeText(" = 1 AND N")
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.waitForScreen("Completions")
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the second match to be picked", func() bool {
+		return driver.queryText() == "SELECT * FROM users WHERE nickname = 1 AND nickname"
+	})
+
+	// Nothing to complete before the cursor, so a tab is typed
+	driver.typeText(" ")
+	driver.pressKey(tcell.KeyTab, 0, tcell.ModNone)
+	driver.waitFor("a tab to be typed", func() bool {
+		return strings.HasSuffix(driver.queryText(), " \t")
+	})
+	assert.Empty(database.getStatements())
+}
+
+func TestAppBanner(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{serverInfo: &db.ServerInfo{
+		Version:      "PostgreSQL 16.2",
+		CharacterSet: "UTF8",
+		TimeZone:     "UTC",
+		Uptime:       76 * time.Hour,
+		Connections:  3,
+	}}
+	driver := startTestApp(t, database)
+
+	driver.waitForScreen("Connected to PostgreSQL 16.2 as user@localhost/test")
+	driver.waitForScreen("UTF8 | Time zone UTC | Read-write | Up 3d 4h | 3 connections")
+
+	driver.click(showTablesButtonLabel)
+	driver.waitFor("tables to be listed", func() bool {
+		return len(database.getStatements()) == 1
+	})
+	assert.Equal("SHOW TABLES;", database.getStatements()[0])
+
+	driver.click(schemaBrowserButtonLabel)
+	driver.waitForScreen(schemaBrowserTitle)
+	assert.Equal([]string{"SHOW TABLES;", "SHOW DATABASES", "SHOW TABLES"}, database.getStatements())
+
+	driver.click(dismissBannerButtonLabel)
+	driver.waitFor("the banner to be dismissed", func() bool {
+		return !strings.Contains(driver.screenText(), "Connected to")
+	})
+}
+
+func TestAppReadOnlyServer(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{serverInfo: &db.ServerInfo{Version: "PostgreSQL 16.2", ReadOnly: true, Connections: -1}}
+	driver := startTestApp(t, database)
+
+	driver.waitForScreen(readOnlyServerWarning)
+	driver.waitForScreen(readOnlyLabel)
+
+	// Reads are sent as usual
+	driver.typeText("SELECT 1;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the read to run", func() bool {
+		return len(database.getStatements()) == 1
+	})
+
+	// Writes are only sent once confirmed, and kept to edit when cancelled
+	driver.typeText("UPDATE users SET active = false;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.click(cancelButtonLabel)
+	driver.waitFor("the write to be put back", func() bool {
+		return driver.queryText() == "UPDATE users SET active = false;"
+	})
+	assert.Len(database.getStatements(), 1)
+
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("anyway?")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitFor("the write to run", func() bool {
+		return len(database.getStatements()) == 2
+	})
+	assert.Equal("UPDATE users SET active = false;", database.getStatements()[1])
+}
+
+func TestAppNullToken(t *testing.T) {
+	cfg := config.Default()
+	cfg.Display.NullToken = "∅"
+
+	// A real NULL, then text reading NULL
+	result := newTestNullableResult("note", "NULL")
+	result.Rows = append(result.Rows, map[string]*db.NullString{
+		"note": {NullString: sql.NullString{String: "NULL", Valid: true}},
+	})
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT note FROM orders;": result,
+	}}
+	driver := startTestAppWithConfig(t, database, cfg)
+
+	driver.typeText("SELECT note FROM orders;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("2 rows in")
+
+	driver.read(func() {
+		table := driver.app.resultBlocks[0].table
+
+		assert.Equal(t, "∅", table.GetCell(1, 0).Text)
+		assert.True(t, isNullCell(table, 1, 0))
+		_, _, attributes := table.GetCell(1, 0).Style.Decompose()
+		assert.NotZero(t, attributes&tcell.AttrItalic)
+
+		assert.Equal(t, "NULL", table.GetCell(2, 0).Text)
+		assert.False(t, isNullCell(table, 2, 0))
+	})
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/lexer"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Characters which end the word before them, other than whitespace
+const keywordBoundaryChars = "(),;"
+
+// Whether the key ends the word before the cursor, so it may be uppercased
+func isKeywordBoundaryKey(event *tcell.EventKey) bool {
+	switch event.Key() {
+	case tcell.KeyEnter, tcell.KeyTab:
+		{
+			return true
+		}
+	case tcell.KeyRune:
+		{
+			isTyped := event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0
+			return isTyped && (event.Rune() == ' ' || strings.ContainsRune(keywordBoundaryChars, event.Rune()))
+		}
+	default:
+		{
+			return false
+		}
+	}
+}
+
+// Uppercase the keyword just typed before the cursor, if there is one
+func (app *App) uppercaseKeywordBeforeCursor() {
+	_, start, end := app.queryTextArea.GetSelection()
+	if start != end {
+		// Typing replaces the selection
+		return
+	}
+
+	flavor := app.db.GetConnectionInfo().Flavor
+	keywordStart, keyword, isKeyword := getKeywordBeforeCursor(flavor, app.queryTextArea.GetText(), end)
+	if !isKeyword {
+		return
+	}
+
+	app.queryTextArea.Replace(keywordStart, end, keyword)
+	app.queryTextArea.Select(keywordStart+len(keyword), keywordStart+len(keyword))
+}
+
+// Uppercased keyword ending at the cursor and where it starts, using the same tokenizer as statements are run with
+// Words inside strings, comments or quoted identifiers, qualified names such as t.order, and keywords already uppercase are left alone
+func getKeywordBeforeCursor(flavor conn.DBFlavor, text string, cursor int) (start int, keyword string, isKeyword bool) {
+	tokens := lexer.Tokenize(flavor, text[:cursor])
+	if len(tokens) == 0 {
+		return 0, "", false
+	}
+
+	lastToken := tokens[len(tokens)-1]
+	if lastToken.Kind != lexer.Word || !lexer.IsReservedKeyword(lastToken.Text) {
+		return 0, "", false
+	}
+
+	if len(tokens) > 1 && tokens[len(tokens)-2].IsOperator(".") {
+		return 0, "", false
+	}
+
+	keyword = strings.ToUpper(lastToken.Text)
+	if keyword == lastToken.Text {
+		return 0, "", false
+	}
+
+	return lastToken.Start, keyword, true
+}
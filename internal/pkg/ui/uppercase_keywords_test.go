@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKeywordBeforeCursor(t *testing.T) {
+	var tests = []struct {
+		Name            string
+		Flavor          conn.DBFlavor
+		Text            string
+		Cursor          int
+		ExpectedStart   int
+		ExpectedKeyword string
+		ExpectedFound   bool
+	}{
+		{"First keyword", conn.MySQL, "select", 6, 0, "SELECT", true},
+		{"Mixed case keyword", conn.PostgreSQL, "SELECT * From", 13, 9, "FROM", true},
+		{"Cursor in the middle", conn.MySQL, "select * from users", 13, 9, "FROM", true},
+		{"Already uppercase", conn.MySQL, "SELECT", 6, 0, "", false},
+		{"Identifier", conn.MySQL, "select * from users", 19, 0, "", false},
+		{"Non-reserved keyword", conn.MySQL, "select * from user", 18, 0, "", false},
+		{"Inside a string", conn.MySQL, "select 'from", 12, 0, "", false},
+		{"Inside a comment", conn.PostgreSQL, "select 1 -- from", 16, 0, "", false},
+		{"Inside a quoted identifier", conn.PostgreSQL, `select "order`, 13, 0, "", false},
+		{"Qualified name", conn.MySQL, "select t.order", 14, 0, "", false},
+		{"After punctuation", conn.MySQL, "select count(*)as", 17, 15, "AS", true},
+		{"Empty", conn.MySQL, "", 0, 0, "", false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			start, keyword, found := getKeywordBeforeCursor(test.Flavor, test.Text, test.Cursor)
+			assert.Equal(test.ExpectedFound, found)
+			assert.Equal(test.ExpectedStart, start)
+			assert.Equal(test.ExpectedKeyword, keyword)
+		})
+	}
+}
+
+func TestIsKeywordBoundaryKey(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone)))
+	assert.True(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyRune, '(', tcell.ModNone)))
+	assert.True(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyRune, ';', tcell.ModNone)))
+	assert.True(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)))
+	assert.False(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone)))
+	assert.False(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyRune, '_', tcell.ModNone)))
+	assert.False(isKeywordBoundaryKey(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModAlt)))
+}
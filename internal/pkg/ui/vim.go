@@ -0,0 +1,599 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+type vimMode int
+
+const (
+	// Keys are typed into the text area, the same as outside of vim mode
+	vimInsert vimMode = iota
+	vimNormal
+	vimVisual
+)
+
+// Shown in the status bar while in vim mode
+var vimModeLabels = map[vimMode]string{
+	vimInsert: "INSERT",
+	vimNormal: "NORMAL",
+	vimVisual: "VISUAL",
+}
+
+// Modal editing for a text area: motions, operators and a register in normal and visual mode, as in vim
+// Starts in insert mode, so typing a query works as in the other modes until Escape is pressed
+type vimEditor struct {
+	textArea *tview.TextArea
+	mode     vimMode
+	// Called after switching modes, so the status bar can be updated
+	onModeChange func()
+	// Operator (d, y or c) or prefix (g) waiting for the next key
+	pending rune
+	// Last deleted or yanked text, and whether it holds whole lines
+	register         string
+	registerLinewise bool
+	// Where visual mode started, and the character the cursor is on
+	// The text area's selection always starts before it ends, so the cursor is tracked here
+	visualAnchor int
+	cursor       int
+}
+
+func newVimEditor(textArea *tview.TextArea, onModeChange func()) *vimEditor {
+	return &vimEditor{textArea: textArea, onModeChange: onModeChange}
+}
+
+func (editor *vimEditor) getModeLabel() string {
+	return vimModeLabels[editor.mode]
+}
+
+func (editor *vimEditor) setMode(mode vimMode) {
+	editor.mode = mode
+	editor.pending = 0
+	if editor.onModeChange != nil {
+		editor.onModeChange()
+	}
+}
+
+// Handle a key of normal or visual mode, or Escape from insert mode, returning false when the text area should have it
+func (editor *vimEditor) handleKey(event *tcell.EventKey) bool {
+	if editor.mode == vimInsert {
+		if event.Key() != tcell.KeyEscape {
+			return false
+		}
+
+		// As in vim, the cursor ends up on the last character typed rather than after it
+		text, cursor := editor.getCursor()
+		if cursor > findLineStart(text, cursor) {
+			_, size := utf8.DecodeLastRuneInString(text[:cursor])
+			editor.textArea.Select(cursor-size, cursor-size)
+		}
+
+		editor.setMode(vimNormal)
+		return true
+	}
+
+	switch event.Key() {
+	case tcell.KeyEscape:
+		{
+			if editor.mode == vimVisual {
+				editor.textArea.Select(editor.cursor, editor.cursor)
+				editor.setMode(vimNormal)
+			}
+			editor.pending = 0
+
+			return true
+		}
+	case tcell.KeyCtrlR:
+		{
+			// The text area's own redo
+			editor.textArea.InputHandler()(tcell.NewEventKey(tcell.KeyCtrlY, 0, tcell.ModCtrl), nil)
+			return true
+		}
+	case tcell.KeyRune:
+		{
+			// Alt+Key is left for keybindings
+			if event.Modifiers()&(tcell.ModAlt|tcell.ModCtrl) != 0 {
+				return false
+			}
+
+			if editor.mode == vimVisual {
+				editor.handleVisualKey(event.Rune())
+			} else {
+				editor.handleNormalKey(event.Rune())
+			}
+
+			// Keys without a meaning are dropped rather than typed
+			return true
+		}
+	}
+
+	// Arrow keys, Enter and the like behave as they do in insert mode
+	return false
+}
+
+func (editor *vimEditor) handleNormalKey(key rune) {
+	text, cursor := editor.getCursor()
+	cursor = clampVimCursor(text, cursor)
+
+	if operator := editor.pending; operator != 0 {
+		editor.pending = 0
+
+		if operator == 'g' {
+			if key == 'g' {
+				editor.moveTo(text, 0)
+			}
+			return
+		}
+
+		editor.applyOperator(operator, key, text, cursor)
+		return
+	}
+
+	if position, isMotion := findVimMotion(key, text, cursor); isMotion {
+		editor.moveTo(text, position)
+		return
+	}
+
+	lineStart, lineEnd := findLineStart(text, cursor), findLineEnd(text, cursor)
+
+	switch key {
+	case 'g', 'd', 'y', 'c':
+		{
+			editor.pending = key
+		}
+	case 'i':
+		{
+			editor.insertAt(cursor)
+		}
+	case 'a':
+		{
+			editor.insertAt(skipRune(text, cursor, lineEnd))
+		}
+	case 'I':
+		{
+			editor.insertAt(findFirstNonBlank(text, cursor))
+		}
+	case 'A':
+		{
+			editor.insertAt(lineEnd)
+		}
+	case 'o':
+		{
+			editor.textArea.Replace(lineEnd, lineEnd, "\n")
+			editor.insertAt(lineEnd + 1)
+		}
+	case 'O':
+		{
+			editor.textArea.Replace(lineStart, lineStart, "\n")
+			editor.insertAt(lineStart)
+		}
+	case 'v':
+		{
+			editor.visualAnchor, editor.cursor = cursor, cursor
+			editor.setMode(vimVisual)
+			editor.selectVisual(text)
+		}
+	case 'x':
+		{
+			if cursor < lineEnd {
+				editor.deleteRange(text, cursor, skipRune(text, cursor, lineEnd))
+			}
+		}
+	case 'D':
+		{
+			editor.deleteRange(text, cursor, lineEnd)
+		}
+	case 'C':
+		{
+			editor.yankRange(text, cursor, lineEnd, false)
+			editor.textArea.Replace(cursor, lineEnd, "")
+			editor.insertAt(cursor)
+		}
+	case 'p', 'P':
+		{
+			editor.paste(text, cursor, key == 'P')
+		}
+	case 'u':
+		{
+			// The text area's own undo
+			editor.textArea.InputHandler()(tcell.NewEventKey(tcell.KeyCtrlZ, 0, tcell.ModCtrl), nil)
+		}
+	}
+}
+
+func (editor *vimEditor) handleVisualKey(key rune) {
+	text := editor.textArea.GetText()
+
+	if editor.pending == 'g' {
+		editor.pending = 0
+		if key == 'g' {
+			editor.cursor = 0
+			editor.selectVisual(text)
+		}
+		return
+	}
+
+	if position, isMotion := findVimMotion(key, text, editor.cursor); isMotion {
+		editor.cursor = clampVimCursor(text, position)
+		editor.selectVisual(text)
+		return
+	}
+
+	start, end := min(editor.visualAnchor, editor.cursor), max(editor.visualAnchor, editor.cursor)
+	// The character under the cursor is part of the selection
+	end = skipRune(text, end, len(text))
+
+	switch key {
+	case 'g':
+		{
+			editor.pending = key
+		}
+	case 'y':
+		{
+			editor.yankRange(text, start, end, false)
+			editor.textArea.Select(start, start)
+			editor.setMode(vimNormal)
+		}
+	case 'd', 'x':
+		{
+			editor.setMode(vimNormal)
+			editor.deleteRange(text, start, end)
+		}
+	case 'c':
+		{
+			editor.yankRange(text, start, end, false)
+			editor.textArea.Replace(start, end, "")
+			editor.insertAt(start)
+		}
+	}
+}
+
+// Run d, y or c over the text a motion moves across, or over whole lines when the operator is repeated, ex: dd
+func (editor *vimEditor) applyOperator(operator rune, key rune, text string, cursor int) {
+	if key == operator {
+		editor.applyLinewiseOperator(operator, text, cursor, cursor)
+		return
+	}
+
+	// As in vim, cw changes to the end of the word rather than up to the next one
+	if operator == 'c' && key == 'w' {
+		key = 'e'
+	}
+
+	position, isMotion := findVimMotion(key, text, cursor)
+	if !isMotion {
+		return
+	}
+
+	start, end := min(cursor, position), max(cursor, position)
+	switch key {
+	case 'e':
+		{
+			// Up to and including the last character of the word
+			end = skipRune(text, end, len(text))
+		}
+	case 'w':
+		{
+			// The last word of a line doesn't take the newline with it
+			end = min(end, findLineEnd(text, cursor))
+		}
+	case 'j', 'k', 'G':
+		{
+			// Vertical motions take every line they move across
+			editor.applyLinewiseOperator(operator, text, start, end)
+			return
+		}
+	}
+
+	switch operator {
+	case 'd':
+		{
+			editor.deleteRange(text, start, end)
+		}
+	case 'y':
+		{
+			editor.yankRange(text, start, end, false)
+			editor.moveTo(text, start)
+		}
+	case 'c':
+		{
+			editor.yankRange(text, start, end, false)
+			editor.textArea.Replace(start, end, "")
+			editor.insertAt(start)
+		}
+	}
+}
+
+// Run d, y or c over the whole lines from the one start is on to the one end is on
+func (editor *vimEditor) applyLinewiseOperator(operator rune, text string, start int, end int) {
+	lineStart, lineEnd := findLineStart(text, start), findLineEnd(text, end)
+	editor.yankRange(text, lineStart, lineEnd, true)
+
+	switch operator {
+	case 'd':
+		{
+			// Along with a newline, so no empty line is left behind
+			deleteStart, deleteEnd := lineStart, lineEnd
+			if lineEnd < len(text) {
+				deleteEnd++
+			} else if lineStart > 0 {
+				deleteStart--
+			}
+
+			editor.textArea.Replace(deleteStart, deleteEnd, "")
+			remainingText := editor.textArea.GetText()
+			editor.moveTo(remainingText, findFirstNonBlank(remainingText, min(deleteStart, len(remainingText))))
+		}
+	case 'y':
+		{
+			editor.moveTo(text, start)
+		}
+	case 'c':
+		{
+			editor.textArea.Replace(lineStart, lineEnd, "")
+			editor.insertAt(lineStart)
+		}
+	}
+}
+
+func (editor *vimEditor) getCursor() (text string, cursor int) {
+	_, start, end := editor.textArea.GetSelection()
+	return editor.textArea.GetText(), max(start, end)
+}
+
+// Place the cursor on a character, never past the end of its line
+func (editor *vimEditor) moveTo(text string, position int) {
+	position = clampVimCursor(text, position)
+	editor.textArea.Select(position, position)
+}
+
+func (editor *vimEditor) insertAt(position int) {
+	editor.textArea.Select(position, position)
+	editor.setMode(vimInsert)
+}
+
+func (editor *vimEditor) selectVisual(text string) {
+	start, end := min(editor.visualAnchor, editor.cursor), max(editor.visualAnchor, editor.cursor)
+	editor.textArea.Select(start, skipRune(text, end, len(text)))
+}
+
+func (editor *vimEditor) yankRange(text string, start int, end int, isLinewise bool) {
+	editor.register = text[start:end]
+	editor.registerLinewise = isLinewise
+	if isLinewise {
+		editor.register += "\n"
+	}
+}
+
+func (editor *vimEditor) deleteRange(text string, start int, end int) {
+	if start == end {
+		return
+	}
+
+	editor.yankRange(text, start, end, false)
+	editor.textArea.Replace(start, end, "")
+	editor.moveTo(editor.textArea.GetText(), start)
+}
+
+// Put the register after the cursor, or before it, with whole lines going below or above the cursor's line
+func (editor *vimEditor) paste(text string, cursor int, isBefore bool) {
+	if editor.register == "" {
+		return
+	}
+
+	if editor.registerLinewise {
+		position := findLineStart(text, cursor)
+		pasted := editor.register
+		if !isBefore {
+			position = findLineEnd(text, cursor)
+			if position == len(text) {
+				// No newline to paste after on the last line
+				pasted = "\n" + strings.TrimSuffix(pasted, "\n")
+			}
+			position++
+		}
+
+		editor.textArea.Replace(min(position, len(text)), min(position, len(text)), pasted)
+		updatedText := editor.textArea.GetText()
+		editor.moveTo(updatedText, findFirstNonBlank(updatedText, min(position, len(updatedText))))
+		return
+	}
+
+	position := cursor
+	if !isBefore {
+		position = skipRune(text, cursor, findLineEnd(text, cursor))
+	}
+
+	editor.textArea.Replace(position, position, editor.register)
+	// On the last character pasted
+	_, size := utf8.DecodeLastRuneInString(editor.register)
+	editor.moveTo(editor.textArea.GetText(), position+len(editor.register)-size)
+}
+
+// Where a motion key moves the cursor to, if it's one
+func findVimMotion(key rune, text string, cursor int) (position int, isMotion bool) {
+	switch key {
+	case 'h':
+		{
+			if cursor > findLineStart(text, cursor) {
+				_, size := utf8.DecodeLastRuneInString(text[:cursor])
+				return cursor - size, true
+			}
+			return cursor, true
+		}
+	case 'l':
+		{
+			return skipRune(text, cursor, findLineEnd(text, cursor)), true
+		}
+	case 'j':
+		{
+			lineEnd := findLineEnd(text, cursor)
+			if lineEnd == len(text) {
+				return cursor, true
+			}
+			return findSameColumn(text, cursor, lineEnd+1), true
+		}
+	case 'k':
+		{
+			lineStart := findLineStart(text, cursor)
+			if lineStart == 0 {
+				return cursor, true
+			}
+			return findSameColumn(text, cursor, findLineStart(text, lineStart-1)), true
+		}
+	case 'w':
+		{
+			return findVimNextWord(text, cursor), true
+		}
+	case 'b':
+		{
+			return findVimPrevWord(text, cursor), true
+		}
+	case 'e':
+		{
+			return findVimWordEnd(text, cursor), true
+		}
+	case '0':
+		{
+			return findLineStart(text, cursor), true
+		}
+	case '^':
+		{
+			return findFirstNonBlank(text, cursor), true
+		}
+	case '$':
+		{
+			return findLineEnd(text, cursor), true
+		}
+	case 'G':
+		{
+			return findFirstNonBlank(text, len(text)), true
+		}
+	}
+
+	return cursor, false
+}
+
+// Normal mode's cursor is on a character, so it can't be past the last one of a line unless the line is empty
+func clampVimCursor(text string, position int) int {
+	position = min(position, len(text))
+
+	lineStart, lineEnd := findLineStart(text, position), findLineEnd(text, position)
+	if position >= lineEnd && lineEnd > lineStart {
+		_, size := utf8.DecodeLastRuneInString(text[:lineEnd])
+		return lineEnd - size
+	}
+
+	return position
+}
+
+// Position after the character at position, without going past limit
+func skipRune(text string, position int, limit int) int {
+	if position >= limit {
+		return position
+	}
+
+	_, size := utf8.DecodeRuneInString(text[position:])
+	return min(position+size, limit)
+}
+
+// End of the line the cursor is on, before its newline
+func findLineEnd(text string, cursor int) int {
+	lineLength := strings.IndexByte(text[cursor:], '\n')
+	if lineLength == -1 {
+		return len(text)
+	}
+
+	return cursor + lineLength
+}
+
+func findFirstNonBlank(text string, cursor int) int {
+	lineStart := findLineStart(text, cursor)
+	return skipForward(text[:findLineEnd(text, cursor)], lineStart, isBlank)
+}
+
+// The same number of characters into the line starting at lineStart as the cursor is into its own line
+func findSameColumn(text string, cursor int, lineStart int) int {
+	column := utf8.RuneCountInString(text[findLineStart(text, cursor):cursor])
+	lineEnd := findLineEnd(text, lineStart)
+
+	position := lineStart
+	for ; column > 0 && position < lineEnd; column-- {
+		position = skipRune(text, position, lineEnd)
+	}
+
+	return position
+}
+
+func isBlank(char rune) bool {
+	return char == ' ' || char == '\t'
+}
+
+// Words are runs of letters, digits and underscores, or runs of other non-blank characters, as in vim
+func getVimCharClass(char rune) int {
+	switch {
+	case unicode.IsSpace(char):
+		{
+			return 0
+		}
+	case isWordRune(char):
+		{
+			return 1
+		}
+	default:
+		{
+			return 2
+		}
+	}
+}
+
+// Start of the next word, for w
+func findVimNextWord(text string, cursor int) int {
+	if cursor >= len(text) {
+		return cursor
+	}
+
+	char, _ := utf8.DecodeRuneInString(text[cursor:])
+	class := getVimCharClass(char)
+
+	position := cursor
+	if class != 0 {
+		position = skipForward(text, position, func(char rune) bool { return getVimCharClass(char) == class })
+	}
+
+	return skipForward(text, position, unicode.IsSpace)
+}
+
+// Start of the word the cursor is in, or of the one before when already at its start, for b
+func findVimPrevWord(text string, cursor int) int {
+	position := skipBackward(text, cursor, unicode.IsSpace)
+	if position == 0 {
+		return 0
+	}
+
+	char, _ := utf8.DecodeLastRuneInString(text[:position])
+	class := getVimCharClass(char)
+
+	return skipBackward(text, position, func(char rune) bool { return getVimCharClass(char) == class })
+}
+
+// Last character of the word the cursor is in, or of the next one when already at its end, for e
+func findVimWordEnd(text string, cursor int) int {
+	position := skipRune(text, cursor, len(text))
+	position = skipForward(text, position, unicode.IsSpace)
+	if position >= len(text) {
+		return cursor
+	}
+
+	char, _ := utf8.DecodeRuneInString(text[position:])
+	class := getVimCharClass(char)
+	end := skipForward(text, position, func(char rune) bool { return getVimCharClass(char) == class })
+
+	_, size := utf8.DecodeLastRuneInString(text[:end])
+	return end - size
+}
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/config"
+	"github.com/azvaliev/sql/internal/pkg/keymap"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVimMotions(t *testing.T) {
+	const text = "SELECT id, name\n  FROM users"
+
+	var tests = []struct {
+		Key      rune
+		Cursor   int
+		Expected int
+	}{
+		{'w', 0, 7},
+		{'w', 7, 9},
+		{'w', 11, 18},
+		{'b', 11, 9},
+		{'b', 18, 11},
+		{'e', 0, 5},
+		{'e', 5, 8},
+		{'h', 16, 16},
+		{'l', 14, 15},
+		{'j', 7, 23},
+		{'k', 27, 11},
+		{'0', 20, 16},
+		{'^', 16, 18},
+		{'$', 3, 15},
+		{'G', 3, 18},
+	}
+
+	for _, test := range tests {
+		position, isMotion := findVimMotion(test.Key, text, test.Cursor)
+		assert.True(t, isMotion, string(test.Key))
+		assert.Equal(t, test.Expected, position, "%c from %d", test.Key, test.Cursor)
+	}
+
+	_, isMotion := findVimMotion('q', text, 0)
+	assert.False(t, isMotion)
+
+	// Past the end of a line is on its last character
+	assert.Equal(t, 14, clampVimCursor(text, 15))
+	assert.Equal(t, 9, clampVimCursor("SELECT *\n\nFROM users", 9))
+}
+
+func TestVimEditing(t *testing.T) {
+	assert := assert.New(t)
+
+	textArea := tview.NewTextArea().SetText("SELECT id FROM users", true)
+	var modeChanges int
+	editor := newVimEditor(textArea, func() { modeChanges++ })
+
+	// Text areas only place the cursor once they've been laid out
+	screen := tcell.NewSimulationScreen("")
+	if !assert.NoError(screen.Init()) {
+		return
+	}
+	defer screen.Fini()
+	textArea.SetRect(0, 0, 80, 5)
+	textArea.Draw(screen)
+
+	keys := func(keys string) {
+		for _, key := range keys {
+			assert.True(editor.handleKey(tcell.NewEventKey(tcell.KeyRune, key, tcell.ModNone)), string(key))
+		}
+	}
+	// Insert mode leaves keys to the text area
+	insert := func(text string) {
+		for _, char := range text {
+			event := tcell.NewEventKey(tcell.KeyRune, char, tcell.ModNone)
+			assert.False(editor.handleKey(event))
+			textArea.InputHandler()(event, nil)
+		}
+	}
+	escape := func() {
+		assert.True(editor.handleKey(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)))
+	}
+
+	// Typed as usual until Escape
+	insert(";")
+	escape()
+	assert.Equal("NORMAL", editor.getModeLabel())
+	assert.Equal(1, modeChanges)
+
+	keys("x0wdw")
+	assert.Equal("SELECT FROM users", textArea.GetText())
+	keys("P")
+	assert.Equal("SELECT id FROM users", textArea.GetText())
+
+	keys("$x")
+	assert.Equal("SELECT id FROM user", textArea.GetText())
+
+	keys("0cw")
+	assert.Equal("INSERT", editor.getModeLabel())
+	insert("select")
+	escape()
+	assert.Equal("select id FROM user", textArea.GetText())
+
+	keys("yyp")
+	assert.Equal("select id FROM user\nselect id FROM user", textArea.GetText())
+	keys("A")
+	insert("s")
+	escape()
+	assert.Equal("select id FROM user\nselect id FROM users", textArea.GetText())
+
+	keys("ggdd")
+	assert.Equal("select id FROM users", textArea.GetText())
+
+	keys("wvey")
+	assert.Equal("NORMAL", editor.getModeLabel())
+	keys("$p")
+	assert.Equal("select id FROM usersid", textArea.GetText())
+
+	keys("0vlld")
+	assert.Equal("ect id FROM usersid", textArea.GetText())
+
+	keys("u")
+	assert.Equal("select id FROM usersid", textArea.GetText())
+
+	// Keybindings are left alone
+	assert.False(editor.handleKey(tcell.NewEventKey(tcell.KeyRune, '\'', tcell.ModAlt)))
+	assert.False(editor.handleKey(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)))
+}
+
+func TestAppVimMode(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keymap.Mode = keymap.Vim
+	driver := startTestAppWithConfig(t, &fakeDB{}, cfg)
+
+	driver.waitForScreen("INSERT")
+	driver.typeText("SELECT 1")
+	driver.pressKey(tcell.KeyEscape, 0, tcell.ModNone)
+	driver.waitForScreen("NORMAL")
+
+	// Normal mode keys edit rather than being typed
+	driver.typeText("0dwiSELECT 2")
+	driver.waitFor("the query to be edited", func() bool {
+		return driver.queryText() == "SELECT 21"
+	})
+}
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+const (
+	watchUsage        = `Usage: \watch <seconds> [--save <path>] [statement], or \watch stop`
+	watchSaveFlag     = "--save"
+	watchStopArgument = "stop"
+	stopWatchLabel    = "Stop Watch"
+	// Sorts in the order results were saved, and is safe in file names on every platform
+	watchTimestampFormat = "20060102T150405Z"
+)
+
+// Exports a watched result can be saved as, by file extension
+var watchSaveFormats = map[string]func(result *db.QueryResult) []byte{
+	".csv":  (*db.QueryResult).ToCSV,
+	".json": (*db.QueryResult).ToJSON,
+	".md":   (*db.QueryResult).ToMarkdown,
+}
+
+// A statement being re-run on an interval
+type watch struct {
+	statement string
+	interval  time.Duration
+	// Nil when results aren't saved
+	savePath *template.Template
+	// As given, for showing where results are saved
+	rawSavePath string
+	// Counts from 1, available to the save path as {{iteration}}
+	iteration int
+	stop      chan struct{}
+}
+
+type watchOptions struct {
+	interval  time.Duration
+	savePath  string
+	statement string
+}
+
+// \watch <seconds> [--save <path>] [statement]
+// Re-run the statement, or the last query when none is given, every so many seconds until stopped
+// With --save, each result is also written to a file, named from a template such as results/{{timestamp}}.csv
+func (app *App) watchCommand(argument string) {
+	if strings.TrimSuffix(argument, ";") == watchStopArgument {
+		app.stopWatch()
+		return
+	}
+
+	options, err := parseWatchArgument(argument)
+	if err != nil {
+		app.showMessage(fmt.Sprint(err.Error(), "\n\n", watchUsage), app.queryTextArea)
+		return
+	}
+
+	if options.statement == "" {
+		if len(app.resultBlocks) == 0 {
+			app.showMessage(fmt.Sprint("No previous query to watch\n\n", watchUsage), app.queryTextArea)
+			return
+		}
+
+		options.statement = app.resultBlocks[len(app.resultBlocks)-1].query
+	}
+
+	var savePath *template.Template
+	if options.savePath != "" {
+		savePath, err = parseWatchSavePath(options.savePath)
+		if err != nil {
+			app.showMessage(err.Error(), app.queryTextArea)
+			return
+		}
+	}
+
+	// Only one statement is watched at a time
+	app.stopWatch()
+
+	app.watch = &watch{
+		statement:   options.statement,
+		interval:    options.interval,
+		savePath:    savePath,
+		rawSavePath: options.savePath,
+		stop:        make(chan struct{}),
+	}
+	app.updateStatusBar()
+
+	// The first result is shown straight away, rather than after the first interval
+	app.runWatchIteration(app.watch)
+	go app.scheduleWatch(app.watch)
+}
+
+func (app *App) scheduleWatch(activeWatch *watch) {
+	ticker := time.NewTicker(activeWatch.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-activeWatch.stop:
+			{
+				return
+			}
+		case <-ticker.C:
+			{
+				app.tviewApp.QueueUpdateDraw(func() {
+					// May have been stopped while this was queued
+					if app.watch == activeWatch {
+						app.runWatchIteration(activeWatch)
+					}
+				})
+			}
+		}
+	}
+}
+
+// Run the watched statement as a new result block, saving the result when asked to
+func (app *App) runWatchIteration(activeWatch *watch) {
+	activeWatch.iteration++
+
+	block := app.runQuery(activeWatch.statement)
+	if activeWatch.savePath == nil || block.err != nil || block.result == nil {
+		return
+	}
+
+	// Saved results are complete, rather than only the rows read so far
+	for block.result.HasMoreRows() && block.fetchErr == nil {
+		app.fetchResultRows(block)
+	}
+	if block.fetchErr != nil {
+		app.addResultBlock(&resultBlock{query: fmt.Sprint(`\watch `, watchSaveFlag), err: block.fetchErr})
+		return
+	}
+
+	path, err := saveWatchResult(activeWatch.savePath, activeWatch.iteration, block.ranAt, block.result)
+	if err != nil {
+		app.addResultBlock(&resultBlock{query: fmt.Sprint(`\watch `, watchSaveFlag), err: err})
+		return
+	}
+
+	block.queryTextView.SetText(fmt.Sprintf("%s (saved to %s)", formatQueryText(block), path))
+}
+
+func (app *App) stopWatch() {
+	if app.watch == nil {
+		return
+	}
+
+	close(app.watch.stop)
+	app.watch = nil
+	app.updateStatusBar()
+}
+
+// Describes the watch for the status bar, ex: Watching every 5m0s, saving to results/{{timestamp}}.csv
+func (activeWatch *watch) describe() string {
+	description := fmt.Sprint("Watching every ", activeWatch.interval)
+	if activeWatch.savePath != nil {
+		description = fmt.Sprint(description, ", saving to ", activeWatch.rawSavePath)
+	}
+
+	return description
+}
+
+func parseWatchArgument(argument string) (options watchOptions, err error) {
+	rawInterval, rest, _ := strings.Cut(strings.TrimSpace(argument), " ")
+	seconds, err := strconv.Atoi(rawInterval)
+	if err != nil || seconds < 1 {
+		return options, fmt.Errorf("Interval must be a whole number of seconds, at least 1, got %q", rawInterval)
+	}
+	options.interval = time.Duration(seconds) * time.Second
+
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, watchSaveFlag) {
+		options.savePath, rest, _ = strings.Cut(strings.TrimSpace(strings.TrimPrefix(rest, watchSaveFlag)), " ")
+		if options.savePath == "" {
+			return options, errors.New("--save needs a path")
+		}
+	}
+
+	options.statement = strings.TrimSpace(rest)
+	return options, nil
+}
+
+// Parse the path results are saved to, with {{timestamp}} and {{iteration}} available
+// The extension picks the format, so it's checked up front rather than on the first save
+func parseWatchSavePath(rawPath string) (*template.Template, error) {
+	extension := strings.ToLower(filepath.Ext(rawPath))
+	if _, isSupported := watchSaveFormats[extension]; !isSupported {
+		return nil, fmt.Errorf("Can't save results to %s, use a .csv, .json or .md file", rawPath)
+	}
+
+	savePath, err := template.New("save-path").
+		Funcs(template.FuncMap{
+			"timestamp": func() string { return "" },
+			"iteration": func() int { return 0 },
+		}).
+		Parse(rawPath)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("Invalid save path %s", rawPath),
+			err,
+		)
+	}
+
+	return savePath, nil
+}
+
+// Write the result to the file the template names for this iteration, creating directories as needed
+func saveWatchResult(savePath *template.Template, iteration int, ranAt time.Time, result *db.QueryResult) (string, error) {
+	var path strings.Builder
+	err := savePath.
+		Funcs(template.FuncMap{
+			"timestamp": func() string { return ranAt.UTC().Format(watchTimestampFormat) },
+			"iteration": func() int { return iteration },
+		}).
+		Execute(&path, nil)
+	if err != nil {
+		return "", errors.Join(
+			errors.New("Failed to name saved result"),
+			err,
+		)
+	}
+
+	toFormat := watchSaveFormats[strings.ToLower(filepath.Ext(path.String()))]
+	if toFormat == nil {
+		return "", fmt.Errorf("Can't save results to %s, use a .csv, .json or .md file", path.String())
+	}
+
+	if directory := filepath.Dir(path.String()); directory != "." {
+		err = os.MkdirAll(directory, 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(path.String(), toFormat(result), 0o644)
+	}
+	if err != nil {
+		return "", errors.Join(
+			fmt.Errorf("Failed to save result to %s", path.String()),
+			err,
+		)
+	}
+
+	return path.String(), nil
+}
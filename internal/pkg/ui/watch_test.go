@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWatchArgument(t *testing.T) {
+	var tests = []struct {
+		Name        string
+		Argument    string
+		Expected    watchOptions
+		ExpectError bool
+	}{
+		{
+			Name:     "Previous query",
+			Argument: "300",
+			Expected: watchOptions{interval: 300 * time.Second},
+		},
+		{
+			Name:     "Save",
+			Argument: "300 --save results/{{timestamp}}.csv",
+			Expected: watchOptions{interval: 300 * time.Second, savePath: "results/{{timestamp}}.csv"},
+		},
+		{
+			Name:     "Save with statement",
+			Argument: "5 --save out.json SELECT count(*) FROM jobs;",
+			Expected: watchOptions{interval: 5 * time.Second, savePath: "out.json", statement: "SELECT count(*) FROM jobs;"},
+		},
+		{
+			Name:     "Statement",
+			Argument: "10 SELECT 1;",
+			Expected: watchOptions{interval: 10 * time.Second, statement: "SELECT 1;"},
+		},
+		{Name: "Missing interval", Argument: "", ExpectError: true},
+		{Name: "Zero interval", Argument: "0 SELECT 1;", ExpectError: true},
+		{Name: "Missing save path", Argument: "10 --save", ExpectError: true},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			options, err := parseWatchArgument(test.Argument)
+			if test.ExpectError {
+				assert.Error(err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(test.Expected, options)
+		})
+	}
+}
+
+func TestSaveWatchResult(t *testing.T) {
+	assert := assert.New(t)
+	directory := t.TempDir()
+
+	_, err := parseWatchSavePath(filepath.Join(directory, "result.txt"))
+	assert.Error(err, "unsupported formats are rejected up front")
+
+	savePath, err := parseWatchSavePath(filepath.Join(directory, "results", "{{timestamp}}-{{iteration}}.csv"))
+	if !assert.NoError(err) {
+		return
+	}
+
+	result := &db.QueryResult{
+		Columns: []string{"id"},
+		Rows: []map[string]*db.NullString{
+			{"id": {NullString: sql.NullString{String: "1", Valid: true}}},
+		},
+	}
+	ranAt := time.Date(2024, 5, 1, 14, 3, 22, 0, time.UTC)
+
+	path, err := saveWatchResult(savePath, 3, ranAt, result)
+	assert.NoError(err)
+	assert.Equal(filepath.Join(directory, "results", "20240501T140322Z-3.csv"), path)
+
+	contents, err := os.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("id\n1\n", string(contents))
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/web"
+)
+
+// Results of every query run so far, for the web view
+// Safe to call from any goroutine, as the blocks are read on the UI goroutine
+func (app *App) GetResults() (results []web.Result) {
+	app.tviewApp.QueueUpdate(func() {
+		results = make([]web.Result, len(app.resultBlocks))
+		for idx, block := range app.resultBlocks {
+			results[idx] = web.Result{
+				Query:  block.query,
+				Result: app.getWebResult(block.result),
+				Err:    block.err,
+			}
+			if block.execResult != nil {
+				results[idx].Status = block.execResult.Summary()
+			} else if block.result != nil && block.result.Status != "" {
+				results[idx].Status = block.result.Summary()
+			}
+			if block.collapsed {
+				results[idx].Status = collapsedIndicator
+			}
+		}
+	})
+
+	return results
+}
+
+// Copy of the result as shown in the table, with its values masked in privacy mode
+// Copied as the HTTP handler reads it after the UI goroutine moves on, which may fetch more rows or sort them in place
+func (app *App) getWebResult(result *db.QueryResult) *db.QueryResult {
+	if result == nil {
+		return nil
+	}
+
+	webResult := *result
+	webResult.Columns = slices.Clone(result.Columns)
+	webResult.ColumnTypes = slices.Clone(result.ColumnTypes)
+	webResult.Rows = make([]map[string]*db.NullString, len(result.Rows))
+	for rowIdx, row := range result.Rows {
+		webRow := make(map[string]*db.NullString, len(row))
+		for columnIdx, columnName := range result.Columns {
+			value, ok := row[columnName]
+			if !ok || value == nil {
+				continue
+			}
+
+			webValue := *value
+			if app.privacyMode && webValue.Valid {
+				webValue.String = maskValue(value.String, getDatabaseType(result, columnIdx))
+			}
+			webRow[columnName] = &webValue
+		}
+		webResult.Rows[rowIdx] = webRow
+	}
+
+	return &webResult
+}
+
+// Schema of the current database as last loaded, for the web view
+// Never queries the database, so a page load can't hold up the UI goroutine
+func (app *App) GetSchema() (schema *db.Schema, err error) {
+	app.tviewApp.QueueUpdate(func() {
+		schema = app.db.GetCachedSchema()
+	})
+
+	if schema == nil {
+		return nil, errors.New("Schema isn't loaded yet, it loads when autocompleting or running a query")
+	}
+
+	return schema, nil
+}
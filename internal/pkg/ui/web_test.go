@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppGetSchema(t *testing.T) {
+	assert := assert.New(t)
+
+	database := &fakeDB{}
+	driver := startTestApp(t, database)
+
+	_, err := driver.app.GetSchema()
+	assert.Error(err, "should not load the schema for the web view")
+
+	schema := &db.Schema{Tables: map[string]*db.TableSchema{"users": {}}}
+	database.schema = schema
+
+	cachedSchema, err := driver.app.GetSchema()
+	assert.NoError(err)
+	assert.Same(schema, cachedSchema)
+}
+
+func TestAppGetResultsPrivacyMode(t *testing.T) {
+	assert := assert.New(t)
+
+	result := newTestResult("email", "ada@example.com")
+	result.ColumnTypes = []db.ColumnType{{DatabaseType: "VARCHAR"}}
+	result.Rows = append(result.Rows, map[string]*db.NullString{"email": {}})
+
+	database := &fakeDB{results: map[string]*db.QueryResult{
+		"SELECT email FROM users;": result,
+	}}
+	driver := startTestApp(t, database)
+
+	driver.typeText("SELECT email FROM users;")
+	driver.pressKey(tcell.KeyEnter, 0, tcell.ModNone)
+	driver.waitForScreen("ada@example.com")
+
+	results := driver.app.GetResults()
+	assert.Len(results, 1)
+	assert.Equal("ada@example.com", results[0].Result.Rows[0]["email"].String)
+	// Copied, as fetching more rows or sorting changes the result on the UI goroutine while it's read
+	assert.NotSame(result.Rows[0]["email"], results[0].Result.Rows[0]["email"])
+
+	driver.pressKey(tcell.KeyRune, 'p', tcell.ModAlt)
+	driver.waitForScreen(privacyModeLabel)
+
+	results = driver.app.GetResults()
+	assert.Len(results, 1)
+	assert.Equal(
+		&db.NullString{NullString: sql.NullString{String: "‹varchar, 15›", Valid: true}},
+		results[0].Result.Rows[0]["email"],
+	)
+	assert.False(results[0].Result.Rows[1]["email"].Valid, "NULL should not be masked")
+	assert.Equal("ada@example.com", result.Rows[0]["email"].String, "result itself should not be masked")
+}
@@ -0,0 +1,192 @@
+package web
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+)
+
+//go:embed page.html
+var pageTemplateSource string
+
+var pageTemplate = template.Must(template.New("page").Parse(pageTemplateSource))
+
+// How often the page reloads to show new results
+const refreshSeconds = 5
+
+// A query run in the session, and what it returned
+type Result struct {
+	Query  string
+	Result *db.QueryResult
+	// What a statement which doesn't return rows did, ex: Table created (0.02s)
+	Status string
+	Err    error
+}
+
+// Where the pages get their content from
+// Both are called from HTTP handlers, so must be safe to call from any goroutine
+type Source interface {
+	GetResults() []Result
+	GetSchema() (*db.Schema, error)
+}
+
+// Serve a read-only view of source's results and schema on the address
+// Listening happens before returning, so an address already in use is reported right away
+func Serve(address string, source Source) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Join(
+			fmt.Errorf("Failed to listen on %s", address),
+			err,
+		)
+	}
+
+	// Resolve port 0 to the one picked, as that's what requests will be addressed to
+	host, _, _ := net.SplitHostPort(address)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	go http.Serve(listener, NewHandler(source, net.JoinHostPort(host, port)))
+	return nil
+}
+
+// Only requests addressed to listenAddress, localhost or an IP on the same port are answered
+func NewHandler(source Source, listenAddress string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(writer http.ResponseWriter, _ *http.Request) {
+		renderPage(writer, buildResultsPage(source.GetResults()))
+	})
+	mux.HandleFunc("GET /schema", func(writer http.ResponseWriter, _ *http.Request) {
+		schema, err := source.GetSchema()
+		renderPage(writer, buildSchemaPage(schema, err))
+	})
+
+	return RequireListenHost(listenAddress, mux)
+}
+
+// Refuse requests for other host names, as a page on another site could otherwise
+// read the results by pointing its own domain at this address (DNS rebinding)
+func RequireListenHost(listenAddress string, next http.Handler) http.Handler {
+	listenHost, listenPort, _ := net.SplitHostPort(listenAddress)
+
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if !isAllowedHost(request.Host, listenHost, listenPort) {
+			http.Error(writer, fmt.Sprintf("Host %s is not allowed", request.Host), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
+
+func isAllowedHost(requestHost string, listenHost string, listenPort string) bool {
+	// Browsers leave out the default port
+	host, port, err := net.SplitHostPort(requestHost)
+	if err != nil {
+		host, port = requestHost, "80"
+	}
+	if port != listenPort {
+		return false
+	}
+
+	// An IP can't be rebound, only a name can
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	return strings.EqualFold(host, "localhost") || strings.EqualFold(host, listenHost)
+}
+
+type page struct {
+	Page           string
+	RefreshSeconds int
+	Results        []pageResult
+	Tables         []pageTable
+	SchemaError    string
+	// Set when the schema came from the offline cache, as the database is unreachable
+	SchemaStale string
+}
+
+type pageResult struct {
+	Query   string
+	Status  string
+	Error   string
+	Columns []string
+	// Values in the same order as Columns
+	Rows [][]*db.NullString
+}
+
+type pageTable struct {
+	Name    string
+	Columns []db.ColumnSchema
+}
+
+func buildResultsPage(results []Result) *page {
+	resultsPage := &page{Page: "results", RefreshSeconds: refreshSeconds}
+
+	for _, result := range results {
+		renderedResult := pageResult{Query: result.Query, Status: result.Status}
+
+		if result.Err != nil {
+			renderedResult.Error = result.Err.Error()
+		} else if result.Result != nil {
+			renderedResult.Columns = result.Result.Columns
+
+			for _, row := range result.Result.Rows {
+				values := make([]*db.NullString, len(result.Result.Columns))
+				for columnIdx, columnName := range result.Result.Columns {
+					values[columnIdx] = row[columnName]
+					if values[columnIdx] == nil {
+						values[columnIdx] = &db.NullString{}
+					}
+				}
+
+				renderedResult.Rows = append(renderedResult.Rows, values)
+			}
+		}
+
+		resultsPage.Results = append(resultsPage.Results, renderedResult)
+	}
+
+	return resultsPage
+}
+
+func buildSchemaPage(schema *db.Schema, schemaErr error) *page {
+	schemaPage := &page{Page: "schema", RefreshSeconds: refreshSeconds}
+
+	if schemaErr != nil {
+		schemaPage.SchemaError = schemaErr.Error()
+		return schemaPage
+	}
+
+	if schema.CachedAt != nil {
+		schemaPage.SchemaStale = fmt.Sprintf(
+			"Database unreachable, showing the schema as of %s",
+			schema.CachedAt.Local().Format("2006-01-02 15:04"),
+		)
+	}
+
+	for tableName, table := range schema.Tables {
+		schemaPage.Tables = append(schemaPage.Tables, pageTable{tableName, table.Columns})
+	}
+	slices.SortFunc(schemaPage.Tables, func(a pageTable, b pageTable) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return schemaPage
+}
+
+func renderPage(writer http.ResponseWriter, pageData *page) {
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := pageTemplate.Execute(writer, pageData); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	}
+}
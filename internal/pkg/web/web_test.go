@@ -0,0 +1,182 @@
+package web_test
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/azvaliev/sql/internal/pkg/db"
+	"github.com/azvaliev/sql/internal/pkg/web"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct {
+	results []web.Result
+	schema  *db.Schema
+}
+
+func (source *fakeSource) GetResults() []web.Result {
+	return source.results
+}
+
+func (source *fakeSource) GetSchema() (*db.Schema, error) {
+	if source.schema == nil {
+		return nil, errors.New("Failed to load schema")
+	}
+
+	return source.schema, nil
+}
+
+const listenAddress = "127.0.0.1:8080"
+
+func TestHandler(t *testing.T) {
+	defaultValue := "now()"
+	source := &fakeSource{
+		results: []web.Result{
+			{
+				Query: "SELECT id, name FROM users",
+				Result: &db.QueryResult{
+					Columns: []string{"id", "name"},
+					Rows: []map[string]*db.NullString{
+						{
+							"id":   &db.NullString{NullString: sql.NullString{String: "1", Valid: true}},
+							"name": &db.NullString{NullString: sql.NullString{String: "<b>Ann</b>", Valid: true}},
+						},
+						{
+							"id":   &db.NullString{NullString: sql.NullString{String: "2", Valid: true}},
+							"name": &db.NullString{},
+						},
+					},
+				},
+			},
+			{
+				Query: "SELECT * FROM missing",
+				Err:   errors.New("Query Failed"),
+			},
+			{
+				Query:  "CREATE TABLE foo (id int)",
+				Status: "Table created (0.02s)",
+			},
+		},
+		schema: &db.Schema{
+			Tables: map[string]*db.TableSchema{
+				"users": {
+					Columns: []db.ColumnSchema{{Name: "created_at", Type: "timestamp", Default: &defaultValue}},
+				},
+			},
+		},
+	}
+
+	var tests = []struct {
+		Name             string
+		Path             string
+		Source           *fakeSource
+		ExpectedStatus   int
+		ExpectedContains []string
+	}{
+		{
+			Name:           "Results",
+			Path:           "/",
+			Source:         source,
+			ExpectedStatus: http.StatusOK,
+			ExpectedContains: []string{
+				"&gt; SELECT id, name FROM users",
+				"<th>name</th>",
+				"&lt;b&gt;Ann&lt;/b&gt;",
+				`<td class="null">NULL</td>`,
+				"Query Failed",
+				"<p>Table created (0.02s)</p>",
+			},
+		},
+		{
+			Name:             "No Results",
+			Path:             "/",
+			Source:           &fakeSource{},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"No queries have been run yet"},
+		},
+		{
+			Name:             "Schema",
+			Path:             "/schema",
+			Source:           source,
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"<h3>users</h3>", "<td>created_at</td>", "<td>now()</td>"},
+		},
+		{
+			Name:             "Schema Error",
+			Path:             "/schema",
+			Source:           &fakeSource{},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedContains: []string{"Failed to load schema"},
+		},
+		{
+			Name:           "Unknown Page",
+			Path:           "/missing",
+			Source:         source,
+			ExpectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, test.Path, nil)
+			request.Host = listenAddress
+			web.NewHandler(test.Source, listenAddress).ServeHTTP(recorder, request)
+
+			assert.Equal(test.ExpectedStatus, recorder.Code)
+
+			body, err := io.ReadAll(recorder.Body)
+			assert.NoError(err)
+			for _, expected := range test.ExpectedContains {
+				assert.Contains(string(body), expected)
+			}
+		})
+	}
+}
+
+func TestHandlerHostCheck(t *testing.T) {
+	source := &fakeSource{}
+
+	tests := []struct {
+		Name          string
+		ListenAddress string
+		Host          string
+		Allowed       bool
+	}{
+		{Name: "Listen Address", ListenAddress: listenAddress, Host: "127.0.0.1:8080", Allowed: true},
+		{Name: "Localhost", ListenAddress: listenAddress, Host: "localhost:8080", Allowed: true},
+		{Name: "Other IP", ListenAddress: ":8080", Host: "192.168.1.20:8080", Allowed: true},
+		{Name: "Listen Host Name", ListenAddress: "devbox:8080", Host: "DEVBOX:8080", Allowed: true},
+		{Name: "Rebound Name", ListenAddress: listenAddress, Host: "attacker.example.com:8080", Allowed: false},
+		{Name: "Other Port", ListenAddress: listenAddress, Host: "localhost:9000", Allowed: false},
+		{Name: "Default Port", ListenAddress: "127.0.0.1:80", Host: "localhost", Allowed: true},
+		{Name: "Default Port Elsewhere", ListenAddress: listenAddress, Host: "localhost", Allowed: false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.Name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Host = test.Host
+			web.NewHandler(source, test.ListenAddress).ServeHTTP(recorder, request)
+
+			if test.Allowed {
+				assert.Equal(http.StatusOK, recorder.Code)
+			} else {
+				assert.Equal(http.StatusForbidden, recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long to wait on the webhook, so a slow endpoint never piles up requests or holds up exiting
+const sendTimeout = 5 * time.Second
+
+// Hex characters of the statement's hash sent, enough to tell runs apart
+const queryHashLength = 12
+
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// Sent when a statement running at least the threshold finishes
+// Never includes the query text, only a hash of it
+type Payload struct {
+	QueryHash  string `json:"query_hash"`
+	DurationMs int64  `json:"duration_ms"`
+	// Nil for statements which don't return rows
+	RowCount *int `json:"row_count"`
+	// success or error
+	Status string `json:"status"`
+	// Summary for chat services such as Slack, which post the text field of incoming webhooks
+	Text string `json:"text"`
+}
+
+// POSTs to a webhook when slow statements finish, such as long analytics queries left running
+type Notifier struct {
+	url       string
+	threshold time.Duration
+	pending   sync.WaitGroup
+}
+
+func NewNotifier(url string, threshold time.Duration) *Notifier {
+	return &Notifier{
+		url:       url,
+		threshold: threshold,
+	}
+}
+
+// Notify in the background when the statement ran for at least the threshold
+// rowCount is nil for statements which don't return rows
+func (notifier *Notifier) StatementFinished(statement string, duration time.Duration, rowCount *int, err error) {
+	if duration < notifier.threshold {
+		return
+	}
+
+	payload := NewPayload(statement, duration, rowCount, err)
+
+	notifier.pending.Add(1)
+	go func() {
+		defer notifier.pending.Done()
+
+		// Nowhere to report a failure without interrupting the session, the notification is best effort
+		_ = notifier.send(payload)
+	}()
+}
+
+// Wait for notifications still being sent, so exiting right after a slow statement doesn't drop its notification
+func (notifier *Notifier) Wait() {
+	notifier.pending.Wait()
+}
+
+func NewPayload(statement string, duration time.Duration, rowCount *int, err error) Payload {
+	hash := sha256.Sum256([]byte(strings.TrimSpace(statement)))
+	queryHash := hex.EncodeToString(hash[:])[:queryHashLength]
+
+	status := StatusSuccess
+	text := fmt.Sprintf("Query %s finished in %s", queryHash, duration.Round(time.Second))
+	if err != nil {
+		status = StatusError
+		text = fmt.Sprintf("Query %s failed after %s", queryHash, duration.Round(time.Second))
+	} else if rowCount != nil {
+		text = fmt.Sprintf("%s, %d rows", text, *rowCount)
+	}
+
+	return Payload{
+		QueryHash:  queryHash,
+		DurationMs: duration.Milliseconds(),
+		RowCount:   rowCount,
+		Status:     status,
+		Text:       text,
+	}
+}
+
+func (notifier *Notifier) send(payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("Webhook responded with %s", response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azvaliev/sql/internal/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierStatementFinished(t *testing.T) {
+	assert := assert.New(t)
+
+	var mutex sync.Mutex
+	var sentPayloads []webhook.Payload
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var payload webhook.Payload
+		assert.NoError(json.NewDecoder(request.Body).Decode(&payload))
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		sentPayloads = append(sentPayloads, payload)
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewNotifier(server.URL, time.Minute)
+
+	rowCount := 42
+	notifier.StatementFinished("SELECT 1", time.Second, &rowCount, nil)
+	notifier.StatementFinished("SELECT * FROM events", 2*time.Minute, &rowCount, nil)
+	notifier.Wait()
+
+	if !assert.Len(sentPayloads, 1, "only statements over the threshold are sent") {
+		return
+	}
+
+	payload := sentPayloads[0]
+	assert.Equal(webhook.StatusSuccess, payload.Status)
+	assert.Equal(int64(120000), payload.DurationMs)
+	assert.Equal(&rowCount, payload.RowCount)
+	assert.Len(payload.QueryHash, 12)
+	assert.NotContains(payload.Text, "events", "query text is never sent")
+}
+
+func TestNewPayload(t *testing.T) {
+	assert := assert.New(t)
+
+	payload := webhook.NewPayload("UPDATE foo SET bar = 1", 90*time.Second, nil, errors.New("Query Failed"))
+	assert.Equal(webhook.StatusError, payload.Status)
+	assert.Nil(payload.RowCount)
+	assert.Contains(payload.Text, "failed after 1m30s")
+
+	// Surrounding whitespace doesn't change the hash, so runs of the same statement can be matched up
+	assert.Equal(
+		webhook.NewPayload("SELECT 1", time.Second, nil, nil).QueryHash,
+		webhook.NewPayload("  SELECT 1\n", time.Second, nil, nil).QueryHash,
+	)
+}
@@ -2,30 +2,156 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"runtime/debug"
 
 	"github.com/azvaliev/sql/cmd"
+	"github.com/azvaliev/sql/internal/pkg/bugreport"
+	"github.com/azvaliev/sql/internal/pkg/config"
 	"github.com/azvaliev/sql/internal/pkg/db"
 	"github.com/azvaliev/sql/internal/pkg/db/conn"
+	"github.com/azvaliev/sql/internal/pkg/ephemeral"
+	"github.com/azvaliev/sql/internal/pkg/format"
+	"github.com/azvaliev/sql/internal/pkg/logging"
+	"github.com/azvaliev/sql/internal/pkg/telemetry"
 	"github.com/azvaliev/sql/internal/pkg/ui"
 )
 
 func main() {
-	connOptions := cmd.ParseArgs()
+	if exitCode, isSubcommand := cmd.RunSubcommand(os.Args[1:]); isSubcommand {
+		os.Exit(exitCode)
+	}
+
+	os.Exit(run(cmd.ParseArgs()))
+}
+
+// Returns rather than exiting, so deferred cleanup such as removing an ephemeral database always happens
+func run(args cmd.Args) int {
+	logFile, err := logging.Setup(args.LogLevel, args.LogPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+		return 1
+	}
+	defer logFile.Close()
+
+	if args.EphemeralImage != "" {
+		fmt.Fprintf(os.Stderr, "Starting ephemeral database from %s\n", args.EphemeralImage)
+
+		ephemeralDatabase, err := ephemeral.Start(context.Background(), args.EphemeralImage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			return 1
+		}
+		defer func() {
+			if err := ephemeralDatabase.Terminate(context.Background()); err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			}
+		}()
+
+		args.ConnOptions = ephemeralDatabase.ConnOptions
+	}
+
+	schemaCachePath := args.GetSchemaCachePath()
+
 	connManager, err := conn.CreateConnectionManager(
-		&connOptions,
+		&args.ConnOptions,
 		context.Background(),
 	)
-	dbClient, err := db.CreateDBClient(connManager)
+
+	// Start anyway while the database is unreachable, so the schema can still be browsed from the offline cache
+	isOffline := errors.Is(err, conn.ErrConnectionFailed) && db.HasSchemaCache(schemaCachePath)
+	if isOffline {
+		connManager, err = conn.OpenConnectionManager(&args.ConnOptions, context.Background())
+	}
+
+	var dbClient *db.DBClient
+	if err == nil {
+		dbClient, err = db.CreateDBClient(connManager)
+	}
 
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
-		os.Exit(1)
+		return 1
+	}
+
+	dbClient.SetTransformsEnabled(args.Config.Transform)
+	dbClient.SetIdentifierQuoting(args.Config.QuoteIdentifiers)
+	dbClient.SetRowLimit(args.Config.RowLimit)
+	dbClient.SetResultCacheTTL(args.Config.ResultCacheTTL())
+	dbClient.SetSchemaCachePath(schemaCachePath)
+
+	if policyHook := args.Config.CreatePolicyHook(); policyHook != nil {
+		dbClient.SetPolicyHook(policyHook)
 	}
 
-	app := ui.Init(dbClient)
+	logPath := ""
+	if args.LogLevel != logging.Off {
+		logPath = args.LogPath
+	}
+	bugReporter := bugreport.NewReporter(args.Config, string(args.ConnOptions.Flavor), logPath, config.GetBugReportDirectory())
+	dbClient.SetBugReporter(bugReporter)
+
+	// Crashes leave a bundle behind to attach to an issue, then carry on as usual
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		if path, err := bugReporter.WriteCrashReport(dbClient.RecentStatements(), recovered, debug.Stack()); err == nil {
+			fmt.Fprintf(os.Stderr, "Wrote a bug report to %s, please attach it to an issue\n", path)
+		}
+
+		panic(recovered)
+	}()
+
+	webhookNotifier := args.Config.CreateWebhookNotifier()
+	if webhookNotifier != nil {
+		dbClient.SetWebhook(webhookNotifier)
+		defer webhookNotifier.Wait()
+	}
+
+	// Statements from -e or stdin are run without the interactive application, for scripts and cron jobs
+	if args.Execute != "" {
+		defer dbClient.Destroy()
+		renderer, err := format.NewRenderer(os.Stdout, args.OutputFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			return 1
+		}
+
+		return cmd.RunStatements(dbClient, args.Execute, renderer, args.Config.StopOnError, os.Stderr)
+	}
+
+	// Keep the offline cache up to date with the schema as of connecting
+	if !isOffline {
+		dbClient.RefreshSchema()
+	}
+
+	telemetryRecorder := telemetry.NewRecorder(
+		args.Config.Telemetry.Enabled,
+		args.Config.Telemetry.Endpoint,
+		string(args.ConnOptions.Flavor),
+	)
+	dbClient.SetTelemetry(telemetryRecorder)
+
+	if args.MetricsAddress != "" {
+		if err = cmd.ServeMetrics(args.MetricsAddress, args.MetricsAllowRemote, dbClient); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+			return 1
+		}
+	}
+
+	app := ui.Init(dbClient, args.Config)
 	if err = app.Run(); err != nil {
 		panic(err)
 	}
+
+	if err = telemetryRecorder.Send(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err.Error())
+	}
+
+	return 0
 }